@@ -0,0 +1,203 @@
+// Package age implements file encryption in the age v1 format.
+//
+// A file is encrypted with a random file key, which is in turn encrypted to
+// each recipient in a stanza of the header of the file. This package
+// supports the following recipient types:
+//
+//   - X25519Recipient: X25519 public keys, encoded as "age1...".
+//   - HybridRecipient: ML-KEM-768+X25519 hybrid public keys using X-Wing
+//     with HPKE, encoded as "age1pq1...".
+//   - ScryptRecipient: passphrases, which must be the only recipient.
+//
+// The payload is encrypted with ChaCha20-Poly1305 in the STREAM
+// construction, so that Encrypt and Decrypt work on streams of any size.
+// Encrypted files can be ASCII-armored with the armor package.
+//
+// Hybrid recipients cannot be mixed with X25519 recipients, as the file
+// would be only as secure as the classical ones.
+//
+// Specification: https://c2sp.org/age
+package age
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+const fileKeySize = 16
+
+// A Recipient encrypts file keys to a recipient.
+type Recipient interface {
+	// Wrap encrypts the file key in one or more stanzas.
+	Wrap(fileKey []byte) ([]*Stanza, error)
+}
+
+// A RecipientWithLabels is a Recipient that only accepts to be used along
+// recipients with the same set of labels. For instance, post-quantum
+// recipients cannot be used along classical ones.
+type RecipientWithLabels interface {
+	// WrapWithLabels encrypts the file key in one or more stanzas, and
+	// returns the labels of the recipient.
+	WrapWithLabels(fileKey []byte) (stanzas []*Stanza, labels []string, err error)
+}
+
+// An Identity decrypts file keys encrypted to it.
+type Identity interface {
+	// Unwrap returns the file key encrypted in one of the stanzas, or
+	// ErrIncorrectIdentity if none of them is encrypted to the identity.
+	Unwrap(stanzas []*Stanza) (fileKey []byte, err error)
+}
+
+// Encrypt writes the header of a file encrypted to the given recipients to
+// dst, and returns a writer of its plaintext. Close must be called on the
+// writer to finish the file; it does not close dst.
+func Encrypt(dst io.Writer, recipients ...Recipient) (io.WriteCloser, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	fileKey := make([]byte, fileKeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+
+	h := &header{}
+	var labels []string
+	for i, r := range recipients {
+		var stanzas []*Stanza
+		var l []string
+		var err error
+		if rl, ok := r.(RecipientWithLabels); ok {
+			stanzas, l, err = rl.WrapWithLabels(fileKey)
+		} else {
+			stanzas, err = r.Wrap(fileKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("age: failed to wrap key for recipient #%d: %w", i, err)
+		}
+		slices.Sort(l)
+		if i == 0 {
+			labels = l
+		} else if !slices.Equal(labels, l) {
+			return nil, ErrIncompatibleRecipients
+		}
+		h.stanzas = append(h.stanzas, stanzas...)
+	}
+
+	var err error
+	if h.mac, err = h.computeMAC(fileKey); err != nil {
+		return nil, err
+	}
+	if err = h.marshal(dst); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, payloadNonceSize)
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	if _, err = dst.Write(nonce); err != nil {
+		return nil, err
+	}
+	aead, err := payloadKey(fileKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamWriter(aead, dst), nil
+}
+
+// Decrypt reads the header of an encrypted file from src, decrypts the file
+// key with the first identity that matches a stanza, and returns a reader
+// of its plaintext. The reader returns an error if the payload was
+// modified or truncated, so the plaintext must not be considered authentic
+// until it returns io.EOF.
+func Decrypt(src io.Reader, identities ...Identity) (io.Reader, error) {
+	h, payload, err := parseHeader(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range h.stanzas {
+		if s.Type == scryptStanzaType && len(h.stanzas) != 1 {
+			return nil, headerError("an scrypt stanza must be alone in the header")
+		}
+	}
+	if len(identities) == 0 {
+		return nil, ErrNoIdentities
+	}
+
+	var fileKey []byte
+	for _, id := range identities {
+		fileKey, err = id.Unwrap(h.stanzas)
+		if errors.Is(err, ErrIncorrectIdentity) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	if fileKey == nil {
+		return nil, ErrNoIdentityMatch
+	}
+
+	mac, err := h.computeMAC(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac, h.mac) {
+		return nil, ErrHeaderMAC
+	}
+
+	nonce := make([]byte, payloadNonceSize)
+	if _, err = io.ReadFull(payload, nonce); err != nil {
+		return nil, headerError("failed to read payload nonce: %w", err)
+	}
+	aead, err := payloadKey(fileKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamReader(aead, payload), nil
+}
+
+// unwrapStanzas calls unwrap on each stanza, and returns the first file
+// key it returns. unwrap returns ErrIncorrectIdentity for stanzas not
+// encrypted to the identity.
+func unwrapStanzas(stanzas []*Stanza, unwrap func(*Stanza) ([]byte, error)) (
+	[]byte, error,
+) {
+	for _, s := range stanzas {
+		fileKey, err := unwrap(s)
+		if errors.Is(err, ErrIncorrectIdentity) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(fileKey) != fileKeySize {
+			return nil, ErrIncorrectIdentity
+		}
+		return fileKey, nil
+	}
+	return nil, ErrIncorrectIdentity
+}
+
+var (
+	ErrNoRecipients           = errors.New("age: no recipients")
+	ErrNoIdentities           = errors.New("age: no identities")
+	ErrIncompatibleRecipients = errors.New("age: incompatible recipients")
+	ErrIncorrectIdentity      = errors.New("age: incorrect identity for recipient stanza")
+	ErrNoIdentityMatch        = errors.New("age: no identity matched any of the recipients")
+	ErrMalformedHeader        = errors.New("age: malformed header")
+	ErrMalformedStanza        = errors.New("age: malformed recipient stanza")
+	ErrHeaderMAC              = errors.New("age: bad header MAC")
+	ErrScryptWorkFactor       = errors.New("age: scrypt work factor is too large")
+	ErrPayloadTruncated       = errors.New("age: payload is truncated or corrupted")
+	ErrStreamOverflow         = errors.New("age: payload is too large")
+	ErrClosed                 = errors.New("age: write to closed file")
+	ErrInvalidRecipient       = errors.New("age: invalid recipient")
+	ErrInvalidIdentity        = errors.New("age: invalid identity")
+)
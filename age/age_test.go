@@ -0,0 +1,280 @@
+package age_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/age"
+	"github.com/quantumcoinproject/circl/age/armor"
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+const chunkSize = 64 * 1024
+
+func encrypt(t *testing.T, msg []byte, recipients ...age.Recipient) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipients...)
+	test.CheckNoErr(t, err, "encrypt")
+	_, err = w.Write(msg)
+	test.CheckNoErr(t, err, "write")
+	test.CheckNoErr(t, w.Close(), "close")
+	return buf.Bytes()
+}
+
+func decrypt(ct []byte, identities ...age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(ct), identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestRoundTrip(t *testing.T) {
+	x25519, err := age.GenerateX25519Identity()
+	test.CheckNoErr(t, err, "generate X25519 identity")
+	x25519Other, err := age.GenerateX25519Identity()
+	test.CheckNoErr(t, err, "generate X25519 identity")
+	hybrid, err := age.GenerateHybridIdentity()
+	test.CheckNoErr(t, err, "generate hybrid identity")
+	hybridOther, err := age.GenerateHybridIdentity()
+	test.CheckNoErr(t, err, "generate hybrid identity")
+
+	msg := make([]byte, 2*chunkSize+10)
+	_, _ = rand.Read(msg)
+
+	for _, tc := range []struct {
+		name       string
+		recipients []age.Recipient
+		identities []age.Identity
+	}{
+		{"X25519", []age.Recipient{x25519Other.Recipient(), x25519.Recipient()},
+			[]age.Identity{x25519}},
+		{"Hybrid", []age.Recipient{hybridOther.Recipient(), hybrid.Recipient()},
+			[]age.Identity{x25519, hybrid}},
+	} {
+		for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 2*chunkSize + 10} {
+			ct := encrypt(t, msg[:size], tc.recipients...)
+			got, err := decrypt(ct, tc.identities...)
+			test.CheckNoErr(t, err, "decrypt "+tc.name)
+			if !bytes.Equal(got, msg[:size]) {
+				test.ReportError(t, len(got), size, tc.name)
+			}
+		}
+	}
+
+	_, err = age.Encrypt(io.Discard, x25519.Recipient(), hybrid.Recipient())
+	if !errors.Is(err, age.ErrIncompatibleRecipients) {
+		test.ReportError(t, err, age.ErrIncompatibleRecipients)
+	}
+	ct := encrypt(t, msg[:10], hybrid.Recipient())
+	_, err = decrypt(ct, x25519, hybridOther)
+	if !errors.Is(err, age.ErrNoIdentityMatch) {
+		test.ReportError(t, err, age.ErrNoIdentityMatch)
+	}
+}
+
+func TestScrypt(t *testing.T) {
+	r, err := age.NewScryptRecipient("password")
+	test.CheckNoErr(t, err, "new scrypt recipient")
+	r.SetWorkFactor(10)
+	id, err := age.NewScryptIdentity("password")
+	test.CheckNoErr(t, err, "new scrypt identity")
+	wrong, err := age.NewScryptIdentity("wrong password")
+	test.CheckNoErr(t, err, "new scrypt identity")
+
+	msg := []byte("secret")
+	ct := encrypt(t, msg, r)
+	got, err := decrypt(ct, wrong, id)
+	test.CheckNoErr(t, err, "decrypt")
+	if !bytes.Equal(got, msg) {
+		test.ReportError(t, got, msg)
+	}
+	_, err = decrypt(ct, wrong)
+	if !errors.Is(err, age.ErrNoIdentityMatch) {
+		test.ReportError(t, err, age.ErrNoIdentityMatch)
+	}
+
+	id.SetMaxWorkFactor(9)
+	_, err = decrypt(ct, id)
+	if !errors.Is(err, age.ErrScryptWorkFactor) {
+		test.ReportError(t, err, age.ErrScryptWorkFactor)
+	}
+
+	x25519, err := age.GenerateX25519Identity()
+	test.CheckNoErr(t, err, "generate X25519 identity")
+	for _, recipients := range [][]age.Recipient{
+		{r, x25519.Recipient()},
+		{x25519.Recipient(), r},
+		{r, r},
+	} {
+		_, err = age.Encrypt(io.Discard, recipients...)
+		if !errors.Is(err, age.ErrIncompatibleRecipients) {
+			test.ReportError(t, err, age.ErrIncompatibleRecipients)
+		}
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	x25519, err := age.GenerateX25519Identity()
+	test.CheckNoErr(t, err, "generate X25519 identity")
+	s := x25519.String()
+	if !strings.HasPrefix(s, "AGE-SECRET-KEY-1") {
+		t.Fatalf("unexpected identity encoding: %v", s)
+	}
+	x25519Parsed, err := age.ParseX25519Identity(s)
+	test.CheckNoErr(t, err, "parse X25519 identity")
+	s = x25519Parsed.Recipient().String()
+	if !strings.HasPrefix(s, "age1") || s != x25519.Recipient().String() {
+		t.Fatalf("unexpected recipient encoding: %v", s)
+	}
+	x25519Recipient, err := age.ParseX25519Recipient(s)
+	test.CheckNoErr(t, err, "parse X25519 recipient")
+
+	hybrid, err := age.GenerateHybridIdentity()
+	test.CheckNoErr(t, err, "generate hybrid identity")
+	s = hybrid.String()
+	if !strings.HasPrefix(s, "AGE-SECRET-KEY-PQ-1") {
+		t.Fatalf("unexpected identity encoding: %v", s)
+	}
+	hybridParsed, err := age.ParseHybridIdentity(s)
+	test.CheckNoErr(t, err, "parse hybrid identity")
+	s = hybridParsed.Recipient().String()
+	if !strings.HasPrefix(s, "age1pq1") || s != hybrid.Recipient().String() {
+		t.Fatalf("unexpected recipient encoding: %v", s)
+	}
+	hybridRecipient, err := age.ParseHybridRecipient(s)
+	test.CheckNoErr(t, err, "parse hybrid recipient")
+
+	msg := []byte("secret")
+	for _, tc := range []struct {
+		r  age.Recipient
+		id age.Identity
+	}{
+		{x25519Recipient, x25519Parsed},
+		{hybridRecipient, hybridParsed},
+	} {
+		got, err := decrypt(encrypt(t, msg, tc.r), tc.id)
+		test.CheckNoErr(t, err, "decrypt")
+		if !bytes.Equal(got, msg) {
+			test.ReportError(t, got, msg)
+		}
+	}
+
+	_, err = age.ParseX25519Recipient(hybrid.Recipient().String())
+	test.CheckIsErr(t, err, "parsed hybrid recipient as X25519")
+	_, err = age.ParseHybridRecipient(x25519.Recipient().String())
+	test.CheckIsErr(t, err, "parsed X25519 recipient as hybrid")
+	_, err = age.ParseX25519Identity(strings.ToLower(x25519.String()))
+	test.CheckIsErr(t, err, "parsed lowercase identity")
+}
+
+func TestTampering(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	test.CheckNoErr(t, err, "generate X25519 identity")
+	msg := make([]byte, 2*chunkSize)
+	ct := encrypt(t, msg, id.Recipient())
+	headerLen := bytes.Index(ct, []byte("\n--- ")) + 1
+	headerLen += bytes.IndexByte(ct[headerLen:], '\n') + 1
+	payloadChunk := chunkSize + 16
+
+	for _, tc := range []struct {
+		name   string
+		modify func(ct []byte) []byte
+		err    error
+	}{
+		{"intro", func(ct []byte) []byte { ct[0] = 'A'; return ct }, age.ErrMalformedHeader},
+		{"stanza", func(ct []byte) []byte {
+			i := bytes.Index(ct, []byte("X25519"))
+			ct[i+7] ^= 1
+			return ct
+		}, nil},
+		{"mac", func(ct []byte) []byte { ct[headerLen-3] ^= 1; return ct }, nil},
+		{"nonce", func(ct []byte) []byte { ct[headerLen] ^= 1; return ct }, age.ErrPayloadTruncated},
+		{"payload", func(ct []byte) []byte { ct[headerLen+20] ^= 1; return ct }, age.ErrPayloadTruncated},
+		{"truncatedAtChunk", func(ct []byte) []byte {
+			return ct[:headerLen+16+payloadChunk]
+		}, age.ErrPayloadTruncated},
+		{"truncatedInChunk", func(ct []byte) []byte {
+			return ct[:headerLen+16+payloadChunk+5]
+		}, age.ErrPayloadTruncated},
+		{"extended", func(ct []byte) []byte { return append(ct, 0) }, age.ErrPayloadTruncated},
+		{"noPayload", func(ct []byte) []byte { return ct[:headerLen] }, age.ErrMalformedHeader},
+	} {
+		ct := tc.modify(append([]byte(nil), ct...))
+		_, err := decrypt(ct, id)
+		if err == nil || (tc.err != nil && !errors.Is(err, tc.err)) {
+			test.ReportError(t, err, tc.err, tc.name)
+		}
+	}
+}
+
+func TestArmor(t *testing.T) {
+	id, err := age.GenerateHybridIdentity()
+	test.CheckNoErr(t, err, "generate hybrid identity")
+	msg := []byte("secret")
+
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, id.Recipient())
+	test.CheckNoErr(t, err, "encrypt")
+	_, err = w.Write(msg)
+	test.CheckNoErr(t, err, "write")
+	test.CheckNoErr(t, w.Close(), "close")
+	test.CheckNoErr(t, aw.Close(), "close armor")
+	if !strings.HasPrefix(buf.String(), armor.Header+"\n") ||
+		!strings.HasSuffix(buf.String(), armor.Footer+"\n") {
+		t.Fatal("unexpected armor")
+	}
+
+	r, err := age.Decrypt(armor.NewReader(&buf), id)
+	test.CheckNoErr(t, err, "decrypt")
+	got, err := io.ReadAll(r)
+	test.CheckNoErr(t, err, "read")
+	if !bytes.Equal(got, msg) {
+		test.ReportError(t, got, msg)
+	}
+}
+
+func Example() {
+	// The recipient generates a post-quantum identity and shares its
+	// recipient, "age1pq1...".
+	identity, err := age.GenerateHybridIdentity()
+	if err != nil {
+		panic(err)
+	}
+	recipient, err := age.ParseHybridRecipient(identity.Recipient().String())
+	if err != nil {
+		panic(err)
+	}
+
+	// The sender encrypts a file to the recipient.
+	var file bytes.Buffer
+	w, err := age.Encrypt(&file, recipient)
+	if err != nil {
+		panic(err)
+	}
+	if _, err = io.WriteString(w, "Hello, age!"); err != nil {
+		panic(err)
+	}
+	if err = w.Close(); err != nil {
+		panic(err)
+	}
+
+	// The recipient decrypts the file.
+	r, err := age.Decrypt(&file, identity)
+	if err != nil {
+		panic(err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(plaintext))
+	// Output: Hello, age!
+}
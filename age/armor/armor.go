@@ -0,0 +1,252 @@
+// Package armor implements the ASCII armor of age encrypted files, a
+// strict PEM encoding with the "AGE ENCRYPTED FILE" type.
+package armor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+const (
+	Header = "-----BEGIN AGE ENCRYPTED FILE-----"
+	Footer = "-----END AGE ENCRYPTED FILE-----"
+
+	columnsPerLine = 64
+
+	// maxTrailingWhitespace bounds the data read after the footer.
+	maxTrailingWhitespace = 1024
+)
+
+var b64 = base64.StdEncoding.Strict()
+
+// NewWriter returns a writer which armors the data written to it and
+// writes it to dst. Close must be called to write the footer; it does not
+// close dst.
+func NewWriter(dst io.Writer) io.WriteCloser {
+	w := &armoredWriter{dst: dst, lines: &lineWriter{w: dst}}
+	w.enc = base64.NewEncoder(base64.StdEncoding, w.lines)
+	return w
+}
+
+type armoredWriter struct {
+	dst     io.Writer
+	lines   *lineWriter
+	enc     io.WriteCloser
+	started bool
+	closed  bool
+}
+
+func (w *armoredWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := io.WriteString(w.dst, Header+"\n")
+	return err
+}
+
+func (w *armoredWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if err := w.start(); err != nil {
+		return 0, err
+	}
+	return w.enc.Write(p)
+}
+
+func (w *armoredWriter) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.enc.Close(); err != nil {
+		return err
+	}
+	footer := Footer + "\n"
+	if w.lines.column != 0 {
+		footer = "\n" + footer
+	}
+	_, err := io.WriteString(w.dst, footer)
+	return err
+}
+
+// lineWriter breaks the base64 encoding in lines of columnsPerLine
+// characters.
+type lineWriter struct {
+	w      io.Writer
+	column int
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		k := min(len(p), columnsPerLine-l.column)
+		if _, err := l.w.Write(p[:k]); err != nil {
+			return n, err
+		}
+		n += k
+		p = p[k:]
+		l.column += k
+		if l.column == columnsPerLine {
+			if _, err := l.w.Write([]byte{'\n'}); err != nil {
+				return n, err
+			}
+			l.column = 0
+		}
+	}
+	return n, nil
+}
+
+// NewReader returns a reader of the data armored in src. Leading and
+// trailing whitespace is ignored, but the armor itself must be canonical:
+// all lines but the last must be 64 columns long, and the footer must
+// follow the last line.
+func NewReader(src io.Reader) io.Reader {
+	return &armoredReader{r: bufio.NewReader(src)}
+}
+
+type armoredReader struct {
+	r       *bufio.Reader
+	started bool
+	buf     []byte
+	err     error
+}
+
+func (r *armoredReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 && r.err == nil {
+		if err := r.next(); err != nil {
+			r.err = err
+		}
+	}
+	if len(r.buf) > 0 {
+		n := copy(p, r.buf)
+		r.buf = r.buf[n:]
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// next decodes the next line into buf, and sets err to io.EOF once the
+// footer is reached.
+func (r *armoredReader) next() error {
+	if !r.started {
+		r.started = true
+		line, err := r.skipWhitespaceAndReadLine()
+		if err != nil {
+			return err
+		}
+		if line != Header {
+			return ErrInvalidArmor
+		}
+	}
+
+	line, err := r.readLine()
+	if err != nil {
+		return err
+	}
+	if line == Footer {
+		return r.checkTrailer()
+	}
+	if len(line) == 0 || len(line) > columnsPerLine || len(line)%4 != 0 {
+		return ErrInvalidArmor
+	}
+	r.buf, err = b64.DecodeString(line)
+	if err != nil {
+		return ErrInvalidArmor
+	}
+
+	if len(line) < columnsPerLine || line[len(line)-1] == '=' {
+		// A short or padded line must be the last one.
+		if line, err = r.readLine(); err != nil {
+			return err
+		}
+		if line != Footer {
+			return ErrInvalidArmor
+		}
+		if err = r.checkTrailer(); err != io.EOF {
+			return err
+		}
+		r.err = io.EOF
+		return nil
+	}
+	return nil
+}
+
+// readLine reads a line, without its line ending, either LF or CRLF. The
+// last line may have no line ending.
+func (r *armoredReader) readLine() (string, error) {
+	var line []byte
+	for {
+		b, err := r.r.ReadByte()
+		if err == io.EOF {
+			if len(line) == 0 {
+				return "", ErrInvalidArmor
+			}
+			return string(line), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if b == '\n' {
+			return string(bytes.TrimSuffix(line, []byte{'\r'})), nil
+		}
+		line = append(line, b)
+		if len(line) > columnsPerLine+1 {
+			return "", ErrInvalidArmor
+		}
+	}
+}
+
+func (r *armoredReader) skipWhitespaceAndReadLine() (string, error) {
+	for n := 0; ; n++ {
+		b, err := r.r.ReadByte()
+		if err == io.EOF {
+			return "", ErrInvalidArmor
+		}
+		if err != nil {
+			return "", err
+		}
+		if !isWhitespace(b) {
+			_ = r.r.UnreadByte()
+			return r.readLine()
+		}
+		if n > maxTrailingWhitespace {
+			return "", ErrInvalidArmor
+		}
+	}
+}
+
+// checkTrailer checks that only whitespace follows the footer. It returns
+// io.EOF on success.
+func (r *armoredReader) checkTrailer() error {
+	for n := 0; n <= maxTrailingWhitespace; n++ {
+		b, err := r.r.ReadByte()
+		if err == io.EOF {
+			return io.EOF
+		}
+		if err != nil {
+			return err
+		}
+		if !isWhitespace(b) {
+			return ErrTrailingData
+		}
+	}
+	return ErrTrailingData
+}
+
+func isWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+var (
+	ErrClosed       = errors.New("armor: write to closed writer")
+	ErrInvalidArmor = errors.New("armor: invalid armor")
+	ErrTrailingData = errors.New("armor: trailing data after armored file")
+)
@@ -0,0 +1,72 @@
+package armor_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/age/armor"
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+func TestRoundTrip(t *testing.T) {
+	msg := make([]byte, 1000)
+	_, _ = rand.Read(msg)
+
+	for _, size := range []int{0, 1, 2, 3, 47, 48, 49, 96, 1000} {
+		var buf bytes.Buffer
+		w := armor.NewWriter(&buf)
+		_, err := w.Write(msg[:size])
+		test.CheckNoErr(t, err, "write")
+		test.CheckNoErr(t, w.Close(), "close")
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if lines[0] != armor.Header || lines[len(lines)-1] != armor.Footer {
+			t.Fatalf("bad header or footer: %q", buf.String())
+		}
+		for i, l := range lines[1 : len(lines)-1] {
+			if len(l) != 64 && i != len(lines)-3 {
+				t.Fatalf("line %d has %d columns", i, len(l))
+			}
+		}
+
+		got, err := io.ReadAll(armor.NewReader(&buf))
+		test.CheckNoErr(t, err, "read")
+		if !bytes.Equal(got, msg[:size]) {
+			test.ReportError(t, got, msg[:size], size)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	for _, tc := range []struct {
+		name, in, want string
+		err            error
+	}{
+		{"valid", armor.Header + "\naGVsbG8=\n" + armor.Footer + "\n", "hello", nil},
+		{"whitespace", "\n \t" + armor.Header + "\r\naGVsbG8=\r\n" + armor.Footer + "\n \n\t", "hello", nil},
+		{"noFinalNewline", armor.Header + "\naGVsbG8=\n" + armor.Footer, "hello", nil},
+		{"empty", armor.Header + "\n" + armor.Footer + "\n", "", nil},
+		{"noHeader", "aGVsbG8=\n" + armor.Footer + "\n", "", armor.ErrInvalidArmor},
+		{"noFooter", armor.Header + "\naGVsbG8=\n", "", armor.ErrInvalidArmor},
+		{"unpadded", armor.Header + "\naGVsbG8\n" + armor.Footer + "\n", "", armor.ErrInvalidArmor},
+		{"shortLine", armor.Header + "\naGVs\nbG8=\n" + armor.Footer + "\n", "", armor.ErrInvalidArmor},
+		{"trailingData", armor.Header + "\naGVsbG8=\n" + armor.Footer + "\nx", "", armor.ErrTrailingData},
+		{"nonCanonical", armor.Header + "\naGVsbG9=\n" + armor.Footer + "\n", "", armor.ErrInvalidArmor},
+	} {
+		got, err := io.ReadAll(armor.NewReader(strings.NewReader(tc.in)))
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				test.ReportError(t, err, tc.err, tc.name)
+			}
+			continue
+		}
+		test.CheckNoErr(t, err, tc.name)
+		if string(got) != tc.want {
+			test.ReportError(t, got, tc.want, tc.name)
+		}
+	}
+}
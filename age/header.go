@@ -0,0 +1,231 @@
+package age
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	intro       = "age-encryption.org/v1\n"
+	stanzaLabel = "->"
+	footerLabel = "---"
+
+	// columnsPerLine is the number of base64 characters of a full line
+	// of a stanza body.
+	columnsPerLine = 64
+)
+
+var b64 = base64.RawStdEncoding.Strict()
+
+// Stanza is a recipient stanza of the header of a file, which holds the
+// file key encrypted to a recipient.
+type Stanza struct {
+	Type string
+	Args []string
+	Body []byte
+}
+
+// marshal writes the encoding of the stanza to w.
+func (s *Stanza) marshal(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString(stanzaLabel)
+	for _, arg := range append([]string{s.Type}, s.Args...) {
+		if !isArgument(arg) {
+			return fmt.Errorf("age: invalid stanza argument %q", arg)
+		}
+		b.WriteByte(' ')
+		b.WriteString(arg)
+	}
+	b.WriteByte('\n')
+
+	body := b64.EncodeToString(s.Body)
+	for len(body) >= columnsPerLine {
+		b.WriteString(body[:columnsPerLine])
+		b.WriteByte('\n')
+		body = body[columnsPerLine:]
+	}
+	// The last line is always shorter than a full line, even if empty.
+	b.WriteString(body)
+	b.WriteByte('\n')
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// isArgument reports whether s is a non-empty string of visible ASCII
+// characters.
+func isArgument(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range []byte(s) {
+		if c < 33 || c > 126 {
+			return false
+		}
+	}
+	return true
+}
+
+// header is the header of a file: the recipient stanzas, followed by a MAC
+// over the header.
+type header struct {
+	stanzas []*Stanza
+	mac     []byte
+}
+
+// headerMACKey derives the key of the header MAC from the file key.
+func headerMACKey(fileKey []byte) []byte {
+	key := make([]byte, 32)
+	h := hkdf.New(sha256.New, fileKey, nil, []byte("header"))
+	_, _ = io.ReadFull(h, key)
+	return key
+}
+
+// marshalWithoutMAC returns the encoding of the header up to the footer
+// label, which is the input of the MAC.
+func (h *header) marshalWithoutMAC() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(intro)
+	for _, s := range h.stanzas {
+		if err := s.marshal(&b); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteString(footerLabel)
+	return b.Bytes(), nil
+}
+
+// computeMAC returns the header MAC, keyed with the file key.
+func (h *header) computeMAC(fileKey []byte) ([]byte, error) {
+	data, err := h.marshalWithoutMAC()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, headerMACKey(fileKey))
+	_, _ = mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// marshal writes the encoding of the header, including its MAC, to w.
+func (h *header) marshal(w io.Writer) error {
+	data, err := h.marshalWithoutMAC()
+	if err != nil {
+		return err
+	}
+	data = append(data, ' ')
+	data = append(data, b64.EncodeToString(h.mac)...)
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
+// parseHeader reads a header from r. It returns the header and a reader
+// of the payload that follows it.
+func parseHeader(r io.Reader) (*header, io.Reader, error) {
+	br := bufio.NewReader(r)
+	line, err := readLine(br)
+	if err != nil {
+		return nil, nil, headerError("failed to read intro: %w", err)
+	}
+	if line != intro {
+		return nil, nil, headerError("unexpected intro: %q", line)
+	}
+
+	h := &header{}
+	for {
+		line, err = readLine(br)
+		if err != nil {
+			return nil, nil, headerError("failed to read header: %w", err)
+		}
+
+		if strings.HasPrefix(line, footerLabel+" ") {
+			mac, err := b64.DecodeString(strings.TrimSuffix(
+				strings.TrimPrefix(line, footerLabel+" "), "\n"))
+			if err != nil || len(mac) != sha256.Size {
+				return nil, nil, headerError("malformed header MAC")
+			}
+			h.mac = mac
+			return h, br, nil
+		}
+
+		args, ok := parseArguments(line)
+		if !ok || args[0] != stanzaLabel || len(args) < 2 {
+			return nil, nil, headerError("malformed stanza opening line: %q", line)
+		}
+		s := &Stanza{Type: args[1], Args: args[2:]}
+		for {
+			line, err = readLine(br)
+			if err != nil {
+				return nil, nil, headerError("failed to read stanza body: %w", err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+			if len(line) > columnsPerLine {
+				return nil, nil, headerError("stanza body line too long")
+			}
+			b, err := b64.DecodeString(line)
+			if err != nil {
+				return nil, nil, headerError("malformed stanza body: %v", err)
+			}
+			s.Body = append(s.Body, b...)
+			if len(line) < columnsPerLine {
+				break
+			}
+		}
+		h.stanzas = append(h.stanzas, s)
+	}
+}
+
+// parseArguments splits a stanza opening line, ending with a newline, in
+// its arguments.
+func parseArguments(line string) ([]string, bool) {
+	line, ok := strings.CutSuffix(line, "\n")
+	if !ok {
+		return nil, false
+	}
+	args := strings.Split(line, " ")
+	for _, arg := range args {
+		if !isArgument(arg) {
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+// maxLineLength bounds the length of header lines, as stanza opening lines
+// might hold large arguments such as post-quantum ciphertexts.
+const maxLineLength = 4096
+
+// readLine reads a line of the header, including its newline.
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			return string(line), nil
+		}
+		if len(line) > maxLineLength {
+			return "", headerError("header line too long")
+		}
+	}
+}
+
+// headerError returns an error wrapping ErrMalformedHeader, and the errors
+// of args formatted with %w, such as errors of the underlying reader.
+func headerError(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrMalformedHeader}, args...)...)
+}
@@ -0,0 +1,177 @@
+package age
+
+import (
+	"crypto/rand"
+
+	"github.com/quantumcoinproject/circl/age/internal/bech32"
+	"github.com/quantumcoinproject/circl/hpke"
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/xwing"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	hybridStanzaType = "mlkem768x25519"
+	hybridLabel      = "age-encryption.org/mlkem768x25519"
+
+	hybridRecipientHRP = "age1pq"
+	hybridIdentityHRP  = "AGE-SECRET-KEY-PQ-"
+
+	// postQuantumLabel is the label of post-quantum recipients, which
+	// cannot be mixed with classical ones.
+	postQuantumLabel = "postquantum"
+)
+
+// hybridSuite is the HPKE suite encrypting file keys to hybrid recipients:
+// X-Wing, that is ML-KEM-768 and X25519, with HKDF-SHA256 and
+// ChaCha20-Poly1305.
+var hybridSuite = hpke.NewSuite(hpke.KEM_XWING, hpke.KDF_HKDF_SHA256,
+	hpke.AEAD_ChaCha20Poly1305)
+
+// HybridRecipient is a post-quantum public key, which encrypts the file
+// key with HPKE using X-Wing, a hybrid of ML-KEM-768 and X25519. Files
+// encrypted to it can be decrypted with the corresponding HybridIdentity.
+type HybridRecipient struct {
+	pk kem.PublicKey
+}
+
+// HybridIdentity is a post-quantum private key, stored as the seed of
+// the X-Wing key pair.
+type HybridIdentity struct {
+	seed []byte
+	sk   kem.PrivateKey
+	pk   kem.PublicKey
+}
+
+// GenerateHybridIdentity generates a random hybrid identity.
+func GenerateHybridIdentity() (*HybridIdentity, error) {
+	seed := make([]byte, xwing.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return newHybridIdentityFromSeed(seed)
+}
+
+func newHybridIdentityFromSeed(seed []byte) (*HybridIdentity, error) {
+	if len(seed) != xwing.SeedSize {
+		return nil, ErrInvalidIdentity
+	}
+	i := &HybridIdentity{seed: append([]byte(nil), seed...)}
+	i.pk, i.sk = xwing.Scheme().DeriveKeyPair(i.seed)
+	return i, nil
+}
+
+// ParseHybridIdentity parses a hybrid identity encoded as
+// "AGE-SECRET-KEY-PQ-1...".
+func ParseHybridIdentity(s string) (*HybridIdentity, error) {
+	hrp, seed, err := bech32.Decode(s)
+	if err != nil || hrp != hybridIdentityHRP {
+		return nil, ErrInvalidIdentity
+	}
+	return newHybridIdentityFromSeed(seed)
+}
+
+// Recipient returns the public recipient of the identity.
+func (i *HybridIdentity) Recipient() *HybridRecipient {
+	return &HybridRecipient{pk: i.pk}
+}
+
+// String returns the encoding of the identity, "AGE-SECRET-KEY-PQ-1...".
+func (i *HybridIdentity) String() string {
+	s, _ := bech32.Encode(hybridIdentityHRP, i.seed)
+	return s
+}
+
+// Unwrap implements Identity.
+func (i *HybridIdentity) Unwrap(stanzas []*Stanza) ([]byte, error) {
+	return unwrapStanzas(stanzas, i.unwrap)
+}
+
+func (i *HybridIdentity) unwrap(s *Stanza) ([]byte, error) {
+	if s.Type != hybridStanzaType {
+		return nil, ErrIncorrectIdentity
+	}
+	if len(s.Args) != 1 {
+		return nil, ErrMalformedStanza
+	}
+	enc, err := b64.DecodeString(s.Args[0])
+	if err != nil || len(enc) != xwing.CiphertextSize ||
+		len(s.Body) != fileKeySize+chacha20poly1305.Overhead {
+		return nil, ErrMalformedStanza
+	}
+	if isLowOrder(enc[xwing.CiphertextSize-curve25519.PointSize:]) {
+		return nil, ErrMalformedStanza
+	}
+
+	receiver, err := hybridSuite.NewReceiver(i.sk, []byte(hybridLabel))
+	if err != nil {
+		return nil, err
+	}
+	opener, err := receiver.Setup(enc)
+	if err != nil {
+		return nil, ErrMalformedStanza
+	}
+	fileKey, err := opener.Open(s.Body, nil)
+	if err != nil {
+		return nil, ErrIncorrectIdentity
+	}
+	return fileKey, nil
+}
+
+// isLowOrder reports whether the X25519 share of an X-Wing ciphertext is
+// a low-order point, which X-Wing itself accepts but age rejects. The
+// scalars are multiples of the cofactor after clamping, so the result is
+// all zeroes, and X25519 fails, for such points and any scalar.
+func isLowOrder(share []byte) bool {
+	_, err := curve25519.X25519(curve25519.Basepoint, share)
+	return err != nil
+}
+
+// ParseHybridRecipient parses a hybrid recipient encoded as "age1pq1...".
+func ParseHybridRecipient(s string) (*HybridRecipient, error) {
+	hrp, k, err := bech32.Decode(s)
+	if err != nil || hrp != hybridRecipientHRP {
+		return nil, ErrInvalidRecipient
+	}
+	pk, err := hpke.KEM_XWING.Scheme().UnmarshalBinaryPublicKey(k)
+	if err != nil {
+		return nil, ErrInvalidRecipient
+	}
+	return &HybridRecipient{pk: pk}, nil
+}
+
+// String returns the encoding of the recipient, "age1pq1...".
+func (r *HybridRecipient) String() string {
+	k, _ := r.pk.MarshalBinary()
+	s, _ := bech32.Encode(hybridRecipientHRP, k)
+	return s
+}
+
+// Wrap implements Recipient.
+func (r *HybridRecipient) Wrap(fileKey []byte) ([]*Stanza, error) {
+	stanzas, _, err := r.WrapWithLabels(fileKey)
+	return stanzas, err
+}
+
+// WrapWithLabels implements RecipientWithLabels. Hybrid recipients can
+// only be used along other post-quantum recipients.
+func (r *HybridRecipient) WrapWithLabels(fileKey []byte) ([]*Stanza, []string, error) {
+	sender, err := hybridSuite.NewSender(r.pk, []byte(hybridLabel))
+	if err != nil {
+		return nil, nil, err
+	}
+	enc, sealer, err := sender.Setup(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := sealer.Seal(fileKey, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*Stanza{{
+		Type: hybridStanzaType,
+		Args: []string{b64.EncodeToString(enc)},
+		Body: body,
+	}}, []string{postQuantumLabel}, nil
+}
@@ -0,0 +1,158 @@
+// Package bech32 implements the Bech32 encoding of BIP 173, without its
+// 90 characters length limit, as used by age for keys.
+package bech32
+
+import (
+	"errors"
+	"strings"
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	h := []byte(strings.ToLower(hrp))
+	ret := make([]byte, 0, 2*len(h)+1)
+	for _, c := range h {
+		ret = append(ret, c>>5)
+	}
+	ret = append(ret, 0)
+	for _, c := range h {
+		ret = append(ret, c&31)
+	}
+	return ret
+}
+
+func createChecksum(hrp string, data []byte) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := polymod(values) ^ 1
+	ret := make([]byte, 6)
+	for i := range ret {
+		ret[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return ret
+}
+
+// convertBits regroups bits from groups of size frombits to groups of size
+// tobits.
+func convertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var ret []byte
+	acc, bits := uint32(0), uint(0)
+	maxv := byte(1<<tobits - 1)
+	for _, value := range data {
+		if value>>frombits != 0 {
+			return nil, ErrInvalidData
+		}
+		acc = acc<<frombits | uint32(value)
+		bits += frombits
+		for bits >= tobits {
+			bits -= tobits
+			ret = append(ret, byte(acc>>bits)&maxv)
+		}
+	}
+	if pad {
+		if bits > 0 {
+			ret = append(ret, byte(acc<<(tobits-bits))&maxv)
+		}
+	} else if bits >= frombits || byte(acc<<(tobits-bits))&maxv != 0 {
+		return nil, ErrInvalidPadding
+	}
+	return ret, nil
+}
+
+// Encode returns the Bech32 encoding of data with the human-readable part
+// hrp. The result is lowercase, unless hrp is uppercase.
+func Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	if len(hrp) < 1 {
+		return "", ErrInvalidHRP
+	}
+	for _, c := range []byte(hrp) {
+		if c < 33 || c > 126 {
+			return "", ErrInvalidHRP
+		}
+	}
+	lower := strings.ToLower(hrp) == hrp
+	if !lower && strings.ToUpper(hrp) != hrp {
+		return "", ErrMixedCase
+	}
+
+	var ret strings.Builder
+	ret.WriteString(strings.ToLower(hrp))
+	ret.WriteByte('1')
+	for _, v := range values {
+		ret.WriteByte(charset[v])
+	}
+	for _, v := range createChecksum(hrp, values) {
+		ret.WriteByte(charset[v])
+	}
+	if lower {
+		return ret.String(), nil
+	}
+	return strings.ToUpper(ret.String()), nil
+}
+
+// Decode parses a Bech32 string, and returns its human-readable part, in
+// the case of s, and its data.
+func Decode(s string) (hrp string, data []byte, err error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, ErrMixedCase
+	}
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, ErrInvalidSeparator
+	}
+	hrp = s[:pos]
+	for _, c := range []byte(hrp) {
+		if c < 33 || c > 126 {
+			return "", nil, ErrInvalidHRP
+		}
+	}
+
+	lower := strings.ToLower(s[pos+1:])
+	values := make([]byte, 0, len(lower))
+	for _, c := range []byte(lower) {
+		d := strings.IndexByte(charset, c)
+		if d < 0 {
+			return "", nil, ErrInvalidCharacter
+		}
+		values = append(values, byte(d))
+	}
+	if polymod(append(hrpExpand(hrp), values...)) != 1 {
+		return "", nil, ErrInvalidChecksum
+	}
+	data, err = convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+var (
+	ErrMixedCase        = errors.New("bech32: mixed case")
+	ErrInvalidHRP       = errors.New("bech32: invalid human-readable part")
+	ErrInvalidSeparator = errors.New("bech32: invalid separator position")
+	ErrInvalidCharacter = errors.New("bech32: invalid character")
+	ErrInvalidChecksum  = errors.New("bech32: invalid checksum")
+	ErrInvalidData      = errors.New("bech32: invalid data")
+	ErrInvalidPadding   = errors.New("bech32: invalid padding")
+)
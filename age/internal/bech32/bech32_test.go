@@ -0,0 +1,62 @@
+package bech32
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+// Test vectors from BIP 173.
+func TestBIP173(t *testing.T) {
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	}
+	for _, s := range valid {
+		hrp, data, err := Decode(s)
+		test.CheckNoErr(t, err, s)
+		got, err := Encode(hrp, data)
+		test.CheckNoErr(t, err, s)
+		if got != s {
+			test.ReportError(t, got, s)
+		}
+	}
+
+	invalid := []string{
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+		"a12UEL5L",
+	}
+	for _, s := range invalid {
+		_, _, err := Decode(s)
+		test.CheckIsErr(t, err, s)
+	}
+}
+
+func TestLong(t *testing.T) {
+	data := bytes.Repeat([]byte{0xa5}, 1216)
+	for _, hrp := range []string{"age1pq", "AGE-SECRET-KEY-PQ-"} {
+		s, err := Encode(hrp, data)
+		test.CheckNoErr(t, err, "encode")
+		if strings.ToUpper(hrp) == hrp && strings.ToUpper(s) != s {
+			t.Fatal("uppercase hrp should give an uppercase encoding")
+		}
+		gotHRP, got, err := Decode(s)
+		test.CheckNoErr(t, err, "decode")
+		if gotHRP != hrp || !bytes.Equal(got, data) {
+			t.Fatal("roundtrip failed")
+		}
+	}
+}
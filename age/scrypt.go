@@ -0,0 +1,156 @@
+package age
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	scryptStanzaType = "scrypt"
+	scryptLabel      = "age-encryption.org/v1/scrypt"
+	scryptSaltSize   = 16
+
+	defaultScryptWorkFactor    = 18
+	defaultScryptMaxWorkFactor = 22
+)
+
+// ScryptRecipient encrypts files with a passphrase. It must be the only
+// recipient of a file.
+type ScryptRecipient struct {
+	password   []byte
+	workFactor int
+}
+
+// NewScryptRecipient returns a recipient encrypting to password.
+func NewScryptRecipient(password string) (*ScryptRecipient, error) {
+	if len(password) == 0 {
+		return nil, ErrInvalidRecipient
+	}
+	return &ScryptRecipient{
+		password:   []byte(password),
+		workFactor: defaultScryptWorkFactor,
+	}, nil
+}
+
+// SetWorkFactor sets the scrypt work factor to 2^logN. It panics if logN
+// is not in [1, 30]. The default is 18, which takes about one second on a
+// modern machine.
+func (r *ScryptRecipient) SetWorkFactor(logN int) {
+	if logN < 1 || logN > 30 {
+		panic("age: invalid scrypt work factor")
+	}
+	r.workFactor = logN
+}
+
+// Wrap implements Recipient.
+func (r *ScryptRecipient) Wrap(fileKey []byte) ([]*Stanza, error) {
+	stanzas, _, err := r.WrapWithLabels(fileKey)
+	return stanzas, err
+}
+
+// WrapWithLabels implements RecipientWithLabels. It returns a unique label,
+// so that the recipient cannot be used along any other.
+func (r *ScryptRecipient) WrapWithLabels(fileKey []byte) ([]*Stanza, []string, error) {
+	salt := make([]byte, scryptSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	key, err := scryptKey(r.password, salt, r.workFactor)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := aeadEncrypt(key, fileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	label := make([]byte, 16)
+	if _, err := rand.Read(label); err != nil {
+		return nil, nil, err
+	}
+	return []*Stanza{{
+		Type: scryptStanzaType,
+		Args: []string{b64.EncodeToString(salt), strconv.Itoa(r.workFactor)},
+		Body: body,
+	}}, []string{hex.EncodeToString(label)}, nil
+}
+
+// ScryptIdentity decrypts files encrypted with a passphrase.
+type ScryptIdentity struct {
+	password      []byte
+	maxWorkFactor int
+}
+
+// NewScryptIdentity returns an identity decrypting files encrypted to
+// password.
+func NewScryptIdentity(password string) (*ScryptIdentity, error) {
+	if len(password) == 0 {
+		return nil, ErrInvalidIdentity
+	}
+	return &ScryptIdentity{
+		password:      []byte(password),
+		maxWorkFactor: defaultScryptMaxWorkFactor,
+	}, nil
+}
+
+// SetMaxWorkFactor sets the maximum scrypt work factor to 2^logN accepted
+// when decrypting, which bounds the cost of decrypting a file. It panics if
+// logN is not in [1, 30]. The default is 22.
+func (i *ScryptIdentity) SetMaxWorkFactor(logN int) {
+	if logN < 1 || logN > 30 {
+		panic("age: invalid scrypt work factor")
+	}
+	i.maxWorkFactor = logN
+}
+
+// Unwrap implements Identity.
+func (i *ScryptIdentity) Unwrap(stanzas []*Stanza) ([]byte, error) {
+	for _, s := range stanzas {
+		if s.Type == scryptStanzaType && len(stanzas) != 1 {
+			return nil, ErrMalformedStanza
+		}
+	}
+	return unwrapStanzas(stanzas, i.unwrap)
+}
+
+func (i *ScryptIdentity) unwrap(s *Stanza) ([]byte, error) {
+	if s.Type != scryptStanzaType {
+		return nil, ErrIncorrectIdentity
+	}
+	if len(s.Args) != 2 {
+		return nil, ErrMalformedStanza
+	}
+	salt, err := b64.DecodeString(s.Args[0])
+	if err != nil || len(salt) != scryptSaltSize ||
+		len(s.Body) != fileKeySize+chacha20poly1305.Overhead {
+		return nil, ErrMalformedStanza
+	}
+	logN, err := strconv.Atoi(s.Args[1])
+	// The work factor must be in canonical decimal form.
+	if err != nil || logN <= 0 || strconv.Itoa(logN) != s.Args[1] {
+		return nil, ErrMalformedStanza
+	}
+	if logN > i.maxWorkFactor {
+		return nil, ErrScryptWorkFactor
+	}
+
+	key, err := scryptKey(i.password, salt, logN)
+	if err != nil {
+		return nil, err
+	}
+	fileKey, err := aeadDecrypt(key, s.Body)
+	if err != nil {
+		return nil, ErrIncorrectIdentity
+	}
+	return fileKey, nil
+}
+
+func scryptKey(password, salt []byte, logN int) ([]byte, error) {
+	s := make([]byte, 0, len(scryptLabel)+len(salt))
+	s = append(append(s, scryptLabel...), salt...)
+	return scrypt.Key(password, s, 1<<logN, 8, 1, chacha20poly1305.KeySize)
+}
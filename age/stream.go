@@ -0,0 +1,193 @@
+package age
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// payloadChunkSize is the size of the plaintext chunks of the payload,
+	// except for the last one, which may be shorter.
+	payloadChunkSize = 64 * 1024
+	payloadNonceSize = 16
+
+	encChunkSize  = payloadChunkSize + chacha20poly1305.Overhead
+	lastChunkFlag = 0x01
+)
+
+// payloadKey derives the key of the payload from the file key and the
+// payload nonce.
+func payloadKey(fileKey, nonce []byte) (cipher.AEAD, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	h := hkdf.New(sha256.New, fileKey, nonce, []byte("payload"))
+	if _, err := io.ReadFull(h, key); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(key)
+}
+
+// streamNonce is the nonce of the STREAM construction: an 11 bytes
+// big-endian chunk counter, followed by the last chunk flag.
+type streamNonce [chacha20poly1305.NonceSize]byte
+
+func (n *streamNonce) increment() error {
+	for i := len(n) - 2; i >= 0; i-- {
+		n[i]++
+		if n[i] != 0 {
+			return nil
+		}
+	}
+	return ErrStreamOverflow
+}
+
+// streamWriter encrypts the payload in chunks of payloadChunkSize bytes.
+type streamWriter struct {
+	aead  cipher.AEAD
+	w     io.Writer
+	nonce streamNonce
+	buf   []byte
+	err   error
+}
+
+func newStreamWriter(aead cipher.AEAD, w io.Writer) *streamWriter {
+	return &streamWriter{aead: aead, w: w, buf: make([]byte, 0, payloadChunkSize)}
+}
+
+func (s *streamWriter) Write(p []byte) (n int, err error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	for len(p) > 0 {
+		// A full chunk is only written once more data arrives, as it
+		// would be the last one otherwise.
+		if len(s.buf) == payloadChunkSize {
+			if err = s.flush(false); err != nil {
+				return n, err
+			}
+		}
+		k := copy(s.buf[len(s.buf):payloadChunkSize], p)
+		s.buf = s.buf[:len(s.buf)+k]
+		p = p[k:]
+		n += k
+	}
+	return n, nil
+}
+
+// Close writes the last chunk. It does not close the underlying writer.
+func (s *streamWriter) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.flush(true); err != nil {
+		return err
+	}
+	s.err = ErrClosed
+	return nil
+}
+
+func (s *streamWriter) flush(last bool) error {
+	if last {
+		s.nonce[len(s.nonce)-1] = lastChunkFlag
+	}
+	ct := s.aead.Seal(nil, s.nonce[:], s.buf, nil)
+	_, err := s.w.Write(ct)
+	if err == nil && !last {
+		err = s.nonce.increment()
+	}
+	if err != nil {
+		s.err = err
+		return err
+	}
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// streamReader decrypts the payload written by a streamWriter.
+type streamReader struct {
+	aead  cipher.AEAD
+	r     io.Reader
+	nonce streamNonce
+	in    []byte // ciphertext read ahead, up to one chunk plus one byte
+	out   []byte // decrypted data not returned yet
+	err   error
+}
+
+func newStreamReader(aead cipher.AEAD, r io.Reader) *streamReader {
+	return &streamReader{aead: aead, r: r, in: make([]byte, 0, encChunkSize+1)}
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	for len(s.out) == 0 && s.err == nil {
+		s.err = s.next()
+	}
+	if len(s.out) > 0 {
+		n := copy(p, s.out)
+		s.out = s.out[n:]
+		return n, nil
+	}
+	return 0, s.err
+}
+
+// next decrypts the next chunk into out. It returns io.EOF after the last
+// chunk.
+func (s *streamReader) next() error {
+	if s.in == nil {
+		return io.EOF
+	}
+
+	// Reading one byte past a full chunk tells whether it is the last one.
+	n, err := io.ReadFull(s.r, s.in[len(s.in):cap(s.in)])
+	s.in = s.in[:len(s.in)+n]
+	last := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	}
+
+	ct := s.in
+	if !last {
+		ct = s.in[:encChunkSize]
+	} else if len(ct) == chacha20poly1305.Overhead && s.nonce != (streamNonce{}) {
+		// The last chunk may only be empty if the whole payload is.
+		return ErrPayloadTruncated
+	}
+	pt, err := s.open(ct, last)
+	if err != nil && len(ct) == encChunkSize {
+		// A full chunk might be the last one though data follows it, or not
+		// be the last one though the payload ends after it. Its plaintext
+		// is authentic, so it is returned before rejecting the payload.
+		if pt, err = s.open(ct, !last); err == nil {
+			s.out, s.in = pt, nil
+			return ErrPayloadTruncated
+		}
+	}
+	if err != nil {
+		return ErrPayloadTruncated
+	}
+	s.out = pt
+
+	if last {
+		s.in = nil
+		if len(pt) == 0 {
+			return io.EOF
+		}
+		return nil
+	}
+	s.in = append(s.in[:0], s.in[encChunkSize:]...)
+	return s.nonce.increment()
+}
+
+// open decrypts the chunk ct, with the last chunk flag set if last is true.
+func (s *streamReader) open(ct []byte, last bool) ([]byte, error) {
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = lastChunkFlag
+	}
+	return s.aead.Open(nil, s.nonce[:], ct, nil)
+}
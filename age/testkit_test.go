@@ -0,0 +1,160 @@
+package age_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/age"
+	"github.com/quantumcoinproject/circl/age/armor"
+)
+
+// The age testkit from https://github.com/C2SP/CCTV/tree/e9274a7bdbfd/age,
+// in testdata/testkit. Each file has a textual header of key-value pairs,
+// an empty line, and an age file, possibly compressed with zlib.
+type testkitVector struct {
+	expect     string
+	payload    []byte
+	identities []age.Identity
+	armored    bool
+	compressed bool
+	file       []byte
+}
+
+func parseTestkitVector(t *testing.T, contents []byte) *testkitVector {
+	v := &testkitVector{}
+	r := bufio.NewReader(bytes.NewReader(contents))
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal("invalid test file: no empty line")
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("invalid test file line: %q", line)
+		}
+		switch key {
+		case "expect":
+			v.expect = value
+		case "payload":
+			v.payload, err = hex.DecodeString(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+		case "identity":
+			var id age.Identity
+			if strings.HasPrefix(value, "AGE-SECRET-KEY-PQ-") {
+				id, err = age.ParseHybridIdentity(value)
+			} else {
+				id, err = age.ParseX25519Identity(value)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.identities = append(v.identities, id)
+		case "passphrase":
+			id, err := age.NewScryptIdentity(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.identities = append(v.identities, id)
+		case "armored":
+			v.armored = value == "yes"
+		case "compressed":
+			if value != "zlib" {
+				t.Fatalf("unknown compression: %q", value)
+			}
+			v.compressed = true
+		case "file key", "comment":
+		default:
+			t.Fatalf("unknown test file key: %q", key)
+		}
+	}
+	var file io.Reader = r
+	if v.compressed {
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file = zr
+	}
+	var err error
+	if v.file, err = io.ReadAll(file); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestTestkit(t *testing.T) {
+	files, err := filepath.Glob("testdata/testkit/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testkit files")
+	}
+	for _, name := range files {
+		contents, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			testTestkitVector(t, parseTestkitVector(t, contents))
+		})
+	}
+}
+
+func testTestkitVector(t *testing.T, v *testkitVector) {
+	var in io.Reader = bytes.NewReader(v.file)
+	if v.armored {
+		in = armor.NewReader(in)
+	}
+
+	var out []byte
+	r, err := age.Decrypt(in, v.identities...)
+	if err == nil {
+		out, err = io.ReadAll(r)
+	}
+
+	var got string
+	switch {
+	case err == nil:
+		got = "success"
+	case errors.Is(err, armor.ErrInvalidArmor), errors.Is(err, armor.ErrTrailingData):
+		got = "armor failure"
+	case errors.Is(err, age.ErrNoIdentityMatch):
+		got = "no match"
+	case errors.Is(err, age.ErrHeaderMAC):
+		got = "HMAC failure"
+	case errors.Is(err, age.ErrMalformedHeader),
+		errors.Is(err, age.ErrMalformedStanza),
+		errors.Is(err, age.ErrScryptWorkFactor):
+		got = "header failure"
+	case errors.Is(err, age.ErrPayloadTruncated) && r != nil:
+		got = "payload failure"
+	default:
+		got = "unexpected error"
+	}
+	if got != v.expect {
+		t.Fatalf("expected %s, got %s: %v", v.expect, got, err)
+	}
+
+	// The plaintext released before a payload failure must match too.
+	if v.expect == "success" || v.expect == "payload failure" {
+		if h := sha256.Sum256(out); !bytes.Equal(h[:], v.payload) {
+			t.Fatalf("wrong payload hash: %x ≠ %x", h, v.payload)
+		}
+	}
+}
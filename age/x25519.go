@@ -0,0 +1,168 @@
+package age
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"github.com/quantumcoinproject/circl/age/internal/bech32"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	x25519StanzaType = "X25519"
+	x25519Label      = "age-encryption.org/v1/X25519"
+
+	x25519RecipientHRP = "age"
+	x25519IdentityHRP  = "AGE-SECRET-KEY-"
+)
+
+// X25519Recipient is the standard age public key. Files encrypted to it
+// can be decrypted with the corresponding X25519Identity.
+type X25519Recipient struct {
+	theirPublicKey []byte
+}
+
+// X25519Identity is the standard age private key.
+type X25519Identity struct {
+	secretKey, ourPublicKey []byte
+}
+
+// GenerateX25519Identity generates a random X25519 identity.
+func GenerateX25519Identity() (*X25519Identity, error) {
+	secretKey := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(secretKey); err != nil {
+		return nil, err
+	}
+	return newX25519IdentityFromScalar(secretKey)
+}
+
+func newX25519IdentityFromScalar(secretKey []byte) (*X25519Identity, error) {
+	if len(secretKey) != curve25519.ScalarSize {
+		return nil, ErrInvalidIdentity
+	}
+	i := &X25519Identity{secretKey: append([]byte(nil), secretKey...)}
+	i.ourPublicKey, _ = curve25519.X25519(i.secretKey, curve25519.Basepoint)
+	return i, nil
+}
+
+// ParseX25519Identity parses an X25519 identity encoded as
+// "AGE-SECRET-KEY-1...".
+func ParseX25519Identity(s string) (*X25519Identity, error) {
+	hrp, k, err := bech32.Decode(s)
+	if err != nil || hrp != x25519IdentityHRP {
+		return nil, ErrInvalidIdentity
+	}
+	return newX25519IdentityFromScalar(k)
+}
+
+// Recipient returns the public recipient of the identity.
+func (i *X25519Identity) Recipient() *X25519Recipient {
+	return &X25519Recipient{theirPublicKey: i.ourPublicKey}
+}
+
+// String returns the encoding of the identity, "AGE-SECRET-KEY-1...".
+func (i *X25519Identity) String() string {
+	s, _ := bech32.Encode(x25519IdentityHRP, i.secretKey)
+	return s
+}
+
+// Unwrap implements Identity.
+func (i *X25519Identity) Unwrap(stanzas []*Stanza) ([]byte, error) {
+	return unwrapStanzas(stanzas, i.unwrap)
+}
+
+func (i *X25519Identity) unwrap(s *Stanza) ([]byte, error) {
+	if s.Type != x25519StanzaType {
+		return nil, ErrIncorrectIdentity
+	}
+	if len(s.Args) != 1 {
+		return nil, ErrMalformedStanza
+	}
+	share, err := b64.DecodeString(s.Args[0])
+	if err != nil || len(share) != curve25519.PointSize ||
+		len(s.Body) != fileKeySize+chacha20poly1305.Overhead {
+		return nil, ErrMalformedStanza
+	}
+
+	sharedSecret, err := curve25519.X25519(i.secretKey, share)
+	if err != nil {
+		// The share is a low-order point.
+		return nil, ErrMalformedStanza
+	}
+	salt := append(share[:len(share):len(share)], i.ourPublicKey...)
+	fileKey, err := aeadDecrypt(x25519WrapKey(sharedSecret, salt), s.Body)
+	if err != nil {
+		return nil, ErrIncorrectIdentity
+	}
+	return fileKey, nil
+}
+
+// ParseX25519Recipient parses an X25519 recipient encoded as "age1...".
+func ParseX25519Recipient(s string) (*X25519Recipient, error) {
+	hrp, k, err := bech32.Decode(s)
+	if err != nil || hrp != x25519RecipientHRP || len(k) != curve25519.PointSize {
+		return nil, ErrInvalidRecipient
+	}
+	return &X25519Recipient{theirPublicKey: k}, nil
+}
+
+// String returns the encoding of the recipient, "age1...".
+func (r *X25519Recipient) String() string {
+	s, _ := bech32.Encode(x25519RecipientHRP, r.theirPublicKey)
+	return s
+}
+
+// Wrap implements Recipient.
+func (r *X25519Recipient) Wrap(fileKey []byte) ([]*Stanza, error) {
+	ephemeral := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeral); err != nil {
+		return nil, err
+	}
+	share, _ := curve25519.X25519(ephemeral, curve25519.Basepoint)
+	sharedSecret, err := curve25519.X25519(ephemeral, r.theirPublicKey)
+	if err != nil {
+		return nil, ErrInvalidRecipient
+	}
+
+	salt := append(share[:len(share):len(share)], r.theirPublicKey...)
+	body, err := aeadEncrypt(x25519WrapKey(sharedSecret, salt), fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return []*Stanza{{
+		Type: x25519StanzaType,
+		Args: []string{b64.EncodeToString(share)},
+		Body: body,
+	}}, nil
+}
+
+func x25519WrapKey(sharedSecret, salt []byte) []byte {
+	key := make([]byte, chacha20poly1305.KeySize)
+	h := hkdf.New(sha256.New, sharedSecret, salt, []byte(x25519Label))
+	_, _ = io.ReadFull(h, key)
+	return key
+}
+
+// aeadEncrypt encrypts a file key with ChaCha20-Poly1305 and a zero nonce,
+// as wrap keys are only used once.
+func aeadEncrypt(key, fileKey []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	return aead.Seal(nil, nonce, fileKey, nil), nil
+}
+
+// aeadDecrypt decrypts a file key encrypted with aeadEncrypt.
+func aeadDecrypt(key, body []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	return aead.Open(nil, nonce, body, nil)
+}
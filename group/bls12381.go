@@ -0,0 +1,308 @@
+package group
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"math/big"
+
+	GG "github.com/quantumcoinproject/circl/ecc/bls12381"
+	"github.com/quantumcoinproject/circl/expander"
+)
+
+var (
+	// BLS12381G1 is the group G1 of the BLS12-381 pairing-friendly curve.
+	BLS12381G1 Group = &blsGroup[GG.G1, *GG.G1]{
+		name: "BLS12-381-G1", gen: GG.G1Generator,
+		size: GG.G1Size, sizeCompressed: GG.G1SizeCompressed,
+	}
+	// BLS12381G2 is the group G2 of the BLS12-381 pairing-friendly curve.
+	BLS12381G2 Group = &blsGroup[GG.G2, *GG.G2]{
+		name: "BLS12-381-G2", gen: GG.G2Generator,
+		size: GG.G2Size, sizeCompressed: GG.G2SizeCompressed,
+	}
+)
+
+// blsPoint is the set of methods of the points of G1 and G2.
+type blsPoint[T any] interface {
+	*T
+	Add(P, Q *T)
+	Double()
+	Neg()
+	ScalarMult(k *GG.Scalar, P *T)
+	IsEqual(P *T) bool
+	IsIdentity() bool
+	SetIdentity()
+	CMov(P *T, b int)
+	Hash(input, dst []byte)
+	Encode(input, dst []byte)
+	SetBytes(b []byte) error
+	Bytes() []byte
+	BytesCompressed() []byte
+}
+
+// blsGroup is the group of points of G1 or G2. Elements are encoded as in
+// [GG.G1.Bytes] and [GG.G2.Bytes], except the identity, which is encoded as
+// a single zero byte. Scalars are shared by both groups.
+type blsGroup[T any, P blsPoint[T]] struct {
+	name                 string
+	gen                  func() P
+	size, sizeCompressed uint
+}
+
+func (g *blsGroup[T, P]) String() string      { return g.name }
+func (g *blsGroup[T, P]) NewElement() Element { return g.Identity() }
+func (g *blsGroup[T, P]) NewScalar() Scalar   { return &blsScl{g: g} }
+func (g *blsGroup[T, P]) Identity() Element {
+	e := &blsElt[T, P]{g: g}
+	P(&e.p).SetIdentity()
+	return e
+}
+
+func (g *blsGroup[T, P]) Generator() Element {
+	return &blsElt[T, P]{g: g, p: *g.gen()}
+}
+
+func (g *blsGroup[T, P]) Params() *Params {
+	return &Params{g.size, g.sizeCompressed, GG.ScalarSize}
+}
+
+func (g *blsGroup[T, P]) RandomElement(rd io.Reader) Element {
+	b := make([]byte, GG.ScalarSize)
+	if n, err := io.ReadFull(rd, b); err != nil || n != len(b) {
+		panic(err)
+	}
+	return g.HashToElement(b, nil)
+}
+
+func (g *blsGroup[T, P]) RandomScalar(rd io.Reader) Scalar {
+	s := &blsScl{g: g}
+	if err := s.s.Random(rd); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (g *blsGroup[T, P]) RandomNonZeroScalar(rd io.Reader) Scalar {
+	for {
+		s := g.RandomScalar(rd)
+		if !s.IsZero() {
+			return s
+		}
+	}
+}
+
+func (g *blsGroup[T, P]) HashToElementNonUniform(b, dst []byte) Element {
+	e := &blsElt[T, P]{g: g}
+	P(&e.p).Encode(b, dst)
+	return e
+}
+
+func (g *blsGroup[T, P]) HashToElement(b, dst []byte) Element {
+	e := &blsElt[T, P]{g: g}
+	P(&e.p).Hash(b, dst)
+	return e
+}
+
+// HashToScalar reduces 48 bytes of expand_message_xmd with SHA-256 modulo
+// the order, as hash_to_field does for the field of scalars.
+func (g *blsGroup[T, P]) HashToScalar(b, dst []byte) Scalar {
+	const L = 48
+	xmd := expander.NewExpanderMD(crypto.SHA256, dst)
+	s := &blsScl{g: g}
+	s.s.SetBytes(xmd.Expand(b, L))
+	return s
+}
+
+func (g *blsGroup[T, P]) cvtElt(e Element) *blsElt[T, P] {
+	if e == nil {
+		return g.Identity().(*blsElt[T, P])
+	}
+	ee, ok := e.(*blsElt[T, P])
+	if !ok {
+		panic(ErrType)
+	}
+	return ee
+}
+
+type blsElt[T any, P blsPoint[T]] struct {
+	g *blsGroup[T, P]
+	p T
+}
+
+func (e *blsElt[T, P]) Group() Group     { return e.g }
+func (e *blsElt[T, P]) String() string   { return fmt.Sprintf("%v", &e.p) }
+func (e *blsElt[T, P]) IsIdentity() bool { return P(&e.p).IsIdentity() }
+func (e *blsElt[T, P]) IsEqual(a Element) bool {
+	return P(&e.p).IsEqual(&e.g.cvtElt(a).p)
+}
+
+func (e *blsElt[T, P]) Set(a Element) Element {
+	e.p = e.g.cvtElt(a).p
+	return e
+}
+
+func (e *blsElt[T, P]) Copy() Element { return &blsElt[T, P]{g: e.g, p: e.p} }
+
+func (e *blsElt[T, P]) CMov(v int, a Element) Element {
+	if !(v == 0 || v == 1) {
+		panic(ErrSelector)
+	}
+	P(&e.p).CMov(&e.g.cvtElt(a).p, v)
+	return e
+}
+
+func (e *blsElt[T, P]) CSelect(v int, a Element, b Element) Element {
+	if !(v == 0 || v == 1) {
+		panic(ErrSelector)
+	}
+	aa, bb := e.g.cvtElt(a), e.g.cvtElt(b)
+	sel := bb.p
+	P(&sel).CMov(&aa.p, v)
+	e.p = sel
+	return e
+}
+
+func (e *blsElt[T, P]) Add(a, b Element) Element {
+	P(&e.p).Add(&e.g.cvtElt(a).p, &e.g.cvtElt(b).p)
+	return e
+}
+
+func (e *blsElt[T, P]) Dbl(a Element) Element {
+	e.Set(a)
+	P(&e.p).Double()
+	return e
+}
+
+func (e *blsElt[T, P]) Neg(a Element) Element {
+	e.Set(a)
+	P(&e.p).Neg()
+	return e
+}
+
+func (e *blsElt[T, P]) Mul(a Element, s Scalar) Element {
+	P(&e.p).ScalarMult(&cvtBlsScl(s).s, &e.g.cvtElt(a).p)
+	return e
+}
+
+func (e *blsElt[T, P]) MulGen(s Scalar) Element {
+	P(&e.p).ScalarMult(&cvtBlsScl(s).s, e.g.gen())
+	return e
+}
+
+func (e *blsElt[T, P]) MarshalBinary() ([]byte, error) {
+	if e.IsIdentity() {
+		return []byte{0x0}, nil
+	}
+	return P(&e.p).Bytes(), nil
+}
+
+func (e *blsElt[T, P]) MarshalBinaryCompress() ([]byte, error) {
+	if e.IsIdentity() {
+		return []byte{0x0}, nil
+	}
+	return P(&e.p).BytesCompressed(), nil
+}
+
+func (e *blsElt[T, P]) UnmarshalBinary(b []byte) error {
+	l := uint(len(b))
+	switch {
+	case l == 1 && b[0] == 0x00: // point at infinity
+		P(&e.p).SetIdentity()
+		return nil
+	case l == e.g.size && b[0]&0x80 == 0, l == e.g.sizeCompressed && b[0]&0x80 != 0:
+		if P(&e.p).SetBytes(b) != nil {
+			return ErrUnmarshal
+		}
+		return nil
+	default:
+		return ErrUnmarshal
+	}
+}
+
+type blsScl struct {
+	g Group
+	s GG.Scalar
+}
+
+func cvtBlsScl(s Scalar) *blsScl {
+	if s == nil {
+		return &blsScl{}
+	}
+	ss, ok := s.(*blsScl)
+	if !ok {
+		panic(ErrType)
+	}
+	return ss
+}
+
+func (s *blsScl) Group() Group              { return s.g }
+func (s *blsScl) String() string            { return s.s.String() }
+func (s *blsScl) SetUint64(n uint64) Scalar { s.s.SetUint64(n); return s }
+func (s *blsScl) SetBigInt(x *big.Int) Scalar {
+	b := new(big.Int).Mod(x, new(big.Int).SetBytes(GG.Order()))
+	s.s.SetBytes(b.Bytes())
+	return s
+}
+func (s *blsScl) IsZero() bool          { return s.s.IsZero() == 1 }
+func (s *blsScl) IsEqual(a Scalar) bool { return s.s.IsEqual(&cvtBlsScl(a).s) == 1 }
+func (s *blsScl) Set(a Scalar) Scalar   { s.s.Set(&cvtBlsScl(a).s); return s }
+func (s *blsScl) Copy() Scalar          { return &blsScl{g: s.g, s: s.s} }
+
+func (s *blsScl) CMov(v int, a Scalar) Scalar {
+	if !(v == 0 || v == 1) {
+		panic(ErrSelector)
+	}
+	bufS, _ := s.s.MarshalBinary()
+	bufA, _ := cvtBlsScl(a).s.MarshalBinary()
+	subtle.ConstantTimeCopy(v, bufS, bufA)
+	s.s.SetBytes(bufS)
+	return s
+}
+
+func (s *blsScl) CSelect(v int, a Scalar, b Scalar) Scalar {
+	if !(v == 0 || v == 1) {
+		panic(ErrSelector)
+	}
+	bufA, _ := cvtBlsScl(a).s.MarshalBinary()
+	bufB, _ := cvtBlsScl(b).s.MarshalBinary()
+	subtle.ConstantTimeCopy(v, bufB, bufA)
+	s.s.SetBytes(bufB)
+	return s
+}
+
+func (s *blsScl) Add(a, b Scalar) Scalar {
+	s.s.Add(&cvtBlsScl(a).s, &cvtBlsScl(b).s)
+	return s
+}
+
+func (s *blsScl) Sub(a, b Scalar) Scalar {
+	s.s.Sub(&cvtBlsScl(a).s, &cvtBlsScl(b).s)
+	return s
+}
+
+func (s *blsScl) Mul(a, b Scalar) Scalar {
+	s.s.Mul(&cvtBlsScl(a).s, &cvtBlsScl(b).s)
+	return s
+}
+
+func (s *blsScl) Neg(a Scalar) Scalar {
+	s.s.Set(&cvtBlsScl(a).s)
+	s.s.Neg()
+	return s
+}
+
+func (s *blsScl) Inv(a Scalar) Scalar {
+	s.s.Inv(&cvtBlsScl(a).s)
+	return s
+}
+
+func (s *blsScl) MarshalBinary() ([]byte, error) { return s.s.MarshalBinary() }
+
+func (s *blsScl) UnmarshalBinary(b []byte) error {
+	if len(b) != GG.ScalarSize || s.s.UnmarshalBinary(b) != nil {
+		return ErrUnmarshal
+	}
+	return nil
+}
@@ -15,6 +15,8 @@ var allGroups = []group.Group{
 	group.P384,
 	group.P521,
 	group.Ristretto255,
+	group.BLS12381G1,
+	group.BLS12381G2,
 }
 
 func TestGroup(t *testing.T) {
@@ -76,6 +76,9 @@ func (c *encdecContext) increment() error {
 }
 
 func (c *sealContext) Seal(pt, aad []byte) ([]byte, error) {
+	if c.AEAD == nil {
+		return nil, ErrExportOnlyAEAD
+	}
 	ct := c.AEAD.Seal(nil, c.calcNonce(), pt, aad)
 	err := c.increment()
 	if err != nil {
@@ -88,6 +91,9 @@ func (c *sealContext) Seal(pt, aad []byte) ([]byte, error) {
 }
 
 func (c *openContext) Open(ct, aad []byte) ([]byte, error) {
+	if c.AEAD == nil {
+		return nil, ErrExportOnlyAEAD
+	}
 	pt, err := c.AEAD.Open(nil, c.calcNonce(), ct, aad)
 	if err != nil {
 		return nil, err
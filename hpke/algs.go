@@ -15,6 +15,9 @@ import (
 	"github.com/quantumcoinproject/circl/dh/x448"
 	"github.com/quantumcoinproject/circl/kem"
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber768"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem1024"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem512"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
 	"github.com/quantumcoinproject/circl/kem/xwing"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
@@ -41,6 +44,21 @@ const (
 	KEM_X25519_KYBER768_DRAFT00 KEM = 0x30
 	// KEM_XWING is a hybrid KEM using X25519 and ML-KEM-768.
 	KEM_XWING KEM = 0x647a
+	// KEM_ML_KEM_512 is ML-KEM-512 as defined in FIPS 203, with the
+	// private keys and key derivation of draft-ietf-hpke-pq.
+	KEM_ML_KEM_512 KEM = 0x0040
+	// KEM_ML_KEM_768 is ML-KEM-768 as defined in FIPS 203, with the
+	// private keys and key derivation of draft-ietf-hpke-pq.
+	KEM_ML_KEM_768 KEM = 0x0041
+	// KEM_ML_KEM_1024 is ML-KEM-1024 as defined in FIPS 203, with the
+	// private keys and key derivation of draft-ietf-hpke-pq.
+	KEM_ML_KEM_1024 KEM = 0x0042
+	// KEM_MLKEM768_P256 is the hybrid KEM MLKEM768-P256 of
+	// draft-ietf-hpke-pq, using ML-KEM-768 and P-256.
+	KEM_MLKEM768_P256 KEM = 0x0050
+	// KEM_MLKEM1024_P384 is the hybrid KEM MLKEM1024-P384 of
+	// draft-ietf-hpke-pq, using ML-KEM-1024 and P-384.
+	KEM_MLKEM1024_P384 KEM = 0x0051
 )
 
 // IsValid returns true if the KEM identifier is supported by the HPKE package.
@@ -52,7 +70,12 @@ func (k KEM) IsValid() bool {
 		KEM_X25519_HKDF_SHA256,
 		KEM_X448_HKDF_SHA512,
 		KEM_X25519_KYBER768_DRAFT00,
-		KEM_XWING:
+		KEM_XWING,
+		KEM_ML_KEM_512,
+		KEM_ML_KEM_768,
+		KEM_ML_KEM_1024,
+		KEM_MLKEM768_P256,
+		KEM_MLKEM1024_P384:
 		return true
 	default:
 		return false
@@ -77,6 +100,16 @@ func (k KEM) Scheme() kem.Scheme {
 		return hybridkemX25519Kyber768
 	case KEM_XWING:
 		return kemXwing
+	case KEM_ML_KEM_512:
+		return kemMLKEM512
+	case KEM_ML_KEM_768:
+		return kemMLKEM768
+	case KEM_ML_KEM_1024:
+		return kemMLKEM1024
+	case KEM_MLKEM768_P256:
+		return kemMLKEM768P256
+	case KEM_MLKEM1024_P384:
+		return kemMLKEM1024P384
 	default:
 		panic(ErrInvalidKEM)
 	}
@@ -171,12 +204,17 @@ const (
 	AEAD_AES256GCM AEAD = 0x02
 	// AEAD_ChaCha20Poly1305 is ChaCha20 stream cipher and Poly1305 MAC.
 	AEAD_ChaCha20Poly1305 AEAD = 0x03
+	// AEAD_EXPORT_ONLY denotes that the HPKE context is only used to export
+	// secrets. Seal and Open return ErrExportOnlyAEAD on such contexts.
+	AEAD_EXPORT_ONLY AEAD = 0xFFFF
 )
 
 // New instantiates an AEAD cipher from the identifier, returns an error if the
-// identifier is not known.
+// identifier is not known, or is AEAD_EXPORT_ONLY.
 func (a AEAD) New(key []byte) (cipher.AEAD, error) {
 	switch a {
+	case AEAD_EXPORT_ONLY:
+		return nil, ErrExportOnlyAEAD
 	case AEAD_AES128GCM, AEAD_AES256GCM:
 		block, err := aes.NewCipher(key)
 		if err != nil {
@@ -194,7 +232,8 @@ func (a AEAD) IsValid() bool {
 	switch a {
 	case AEAD_AES128GCM,
 		AEAD_AES256GCM,
-		AEAD_ChaCha20Poly1305:
+		AEAD_ChaCha20Poly1305,
+		AEAD_EXPORT_ONLY:
 		return true
 	default:
 		return false
@@ -202,8 +241,11 @@ func (a AEAD) IsValid() bool {
 }
 
 // KeySize returns the size in bytes of the keys used by the AEAD cipher.
+// It is zero for AEAD_EXPORT_ONLY.
 func (a AEAD) KeySize() uint {
 	switch a {
+	case AEAD_EXPORT_ONLY:
+		return 0
 	case AEAD_AES128GCM:
 		return 16
 	case AEAD_AES256GCM:
@@ -216,8 +258,11 @@ func (a AEAD) KeySize() uint {
 }
 
 // NonceSize returns the size in bytes of the nonce used by the AEAD cipher.
+// It is zero for AEAD_EXPORT_ONLY.
 func (a AEAD) NonceSize() uint {
 	switch a {
+	case AEAD_EXPORT_ONLY:
+		return 0
 	case AEAD_AES128GCM,
 		AEAD_AES256GCM,
 		AEAD_ChaCha20Poly1305:
@@ -228,11 +273,13 @@ func (a AEAD) NonceSize() uint {
 }
 
 // CipherLen returns the length of a ciphertext corresponding to a message of
-// length mLen.
+// length mLen. Panics for AEAD_EXPORT_ONLY.
 func (a AEAD) CipherLen(mLen uint) uint {
 	switch a {
 	case AEAD_AES128GCM, AEAD_AES256GCM, AEAD_ChaCha20Poly1305:
 		return mLen + 16
+	case AEAD_EXPORT_ONLY:
+		panic(ErrExportOnlyAEAD)
 	default:
 		panic(ErrInvalidAEAD)
 	}
@@ -243,6 +290,8 @@ var (
 	dhkemx25519hkdfsha256, dhkemx448hkdfsha512                    xKEM
 	hybridkemX25519Kyber768                                       hybridKEM
 	kemXwing                                                      genericNoAuthKEM
+	kemMLKEM512, kemMLKEM768, kemMLKEM1024                        mlKEM
+	kemMLKEM768P256, kemMLKEM1024P384                             qsfKEM
 )
 
 func init() {
@@ -284,4 +333,19 @@ func init() {
 
 	kemXwing.Scheme = xwing.Scheme()
 	kemXwing.name = "HPKE_KEM_XWING"
+
+	kemMLKEM512 = mlKEM{mlkem512.Scheme(), KEM_ML_KEM_512, "HPKE_KEM_ML_KEM_512"}
+	kemMLKEM768 = mlKEM{mlkem768.Scheme(), KEM_ML_KEM_768, "HPKE_KEM_ML_KEM_768"}
+	kemMLKEM1024 = mlKEM{mlkem1024.Scheme(), KEM_ML_KEM_1024, "HPKE_KEM_ML_KEM_1024"}
+
+	kemMLKEM768P256 = qsfKEM{
+		id: KEM_MLKEM768_P256, name: "HPKE_KEM_MLKEM768_P256",
+		label: "MLKEM768-P256", pq: mlkem768.Scheme(), curve: ecdh.P256(),
+		scalarSize: 32, candidates: 3,
+	}
+	kemMLKEM1024P384 = qsfKEM{
+		id: KEM_MLKEM1024_P384, name: "HPKE_KEM_MLKEM1024_P384",
+		label: "MLKEM1024-P384", pq: mlkem1024.Scheme(), curve: ecdh.P384(),
+		scalarSize: 48, candidates: 1,
+	}
 }
@@ -8,9 +8,12 @@
 // Specification in
 // https://datatracker.ietf.org/doc/draft-irtf-cfrg-hpke
 //
-// BUG(cjpatton): This package does not implement the "Export-Only" mode of the
-// HPKE context. In particular, it does not recognize the AEAD codepoint
-// reserved for this purpose (0xFFFF).
+// Contexts that are only used to export secrets can be set up with
+// AEAD_EXPORT_ONLY, or with the single-shot Sender.SendExport and
+// Receiver.ReceiveExport.
+//
+// Large payloads can be encrypted in chunks, without holding them in
+// memory, using NewStreamWriter and NewStreamReader.
 package hpke
 
 import (
@@ -151,6 +154,19 @@ func (s *Sender) SetupAuthPSK(rnd io.Reader, skS kem.PrivateKey, psk, pskID []by
 	return s.allSetup(rnd)
 }
 
+// SendExport generates a new HPKE context used for Base Mode, and exports
+// a secret of the given length from it, as in RFC 9180 §6.2. Returns the
+// encapsulated key and the exported secret.
+func (s *Sender) SendExport(rnd io.Reader, exporterContext []byte, length uint) (
+	enc, secret []byte, err error,
+) {
+	enc, sealer, err := s.Setup(rnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, sealer.Export(exporterContext, length), nil
+}
+
 // Receiver performs hybrid public-key decryption.
 type Receiver struct {
 	state
@@ -207,6 +223,19 @@ func (r *Receiver) SetupAuthPSK(
 	return r.allSetup()
 }
 
+// ReceiveExport sets up a new HPKE context used for Base Mode from the
+// encapsulated key, and exports a secret of the given length from it, as in
+// RFC 9180 §6.2.
+func (r *Receiver) ReceiveExport(enc, exporterContext []byte, length uint) (
+	[]byte, error,
+) {
+	opener, err := r.Setup(enc)
+	if err != nil {
+		return nil, err
+	}
+	return opener.Export(exporterContext, length), nil
+}
+
 func (s *Sender) allSetup(rnd io.Reader) ([]byte, Sealer, error) {
 	scheme := s.kemID.Scheme()
 
@@ -280,4 +309,7 @@ var (
 	ErrInvalidKEMSharedSecret = errors.New("hpke: invalid KEM shared secret")
 	ErrInvalidKEMDeriveKey    = errors.New("hpke: too many tries to derive KEM key")
 	ErrAEADSeqOverflows       = errors.New("hpke: AEAD sequence number overflows")
+	ErrExportOnlyAEAD         = errors.New("hpke: AEAD is export-only")
+	ErrStreamClosed           = errors.New("hpke: stream is closed")
+	ErrStreamTruncated        = errors.New("hpke: stream is truncated or corrupted")
 )
@@ -63,6 +63,47 @@ func Example() {
 	// Output: true
 }
 
+func ExampleSender_SendExport() {
+	// Both parties agree on a suite that is only used for exporting
+	// secrets.
+	suite := hpke.NewSuite(
+		hpke.KEM_X25519_HKDF_SHA256,
+		hpke.KDF_HKDF_SHA256,
+		hpke.AEAD_EXPORT_ONLY,
+	)
+	info := []byte("public info string, known to both Alice and Bob")
+	exporterContext := []byte("application key")
+
+	// Bob announces his public key.
+	pkBob, skBob, err := hpke.KEM_X25519_HKDF_SHA256.Scheme().GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	Bob, err := suite.NewReceiver(skBob, info)
+	if err != nil {
+		panic(err)
+	}
+
+	// Alice derives a secret for Bob, and sends him the encapsulated key.
+	Alice, err := suite.NewSender(pkBob, info)
+	if err != nil {
+		panic(err)
+	}
+	enc, secretAlice, err := Alice.SendExport(nil, exporterContext, 32)
+	if err != nil {
+		panic(err)
+	}
+
+	// Bob derives the same secret.
+	secretBob, err := Bob.ReceiveExport(enc, exporterContext, 32)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(bytes.Equal(secretAlice, secretBob))
+	// Output: true
+}
+
 func runHpkeBenchmark(b *testing.B, kem hpke.KEM, kdf hpke.KDF, aead hpke.AEAD) {
 	suite := hpke.NewSuite(kem, kdf, aead)
 
@@ -164,6 +205,9 @@ func BenchmarkHpkeRoundTrip(b *testing.B) {
 		{hpke.KEM_X25519_HKDF_SHA256, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
 		{hpke.KEM_X25519_KYBER768_DRAFT00, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
 		{hpke.KEM_XWING, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KEM_ML_KEM_768, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KEM_MLKEM768_P256, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KEM_MLKEM1024_P384, hpke.KDF_HKDF_SHA384, hpke.AEAD_AES256GCM},
 	}
 	for _, test := range tests {
 		runHpkeBenchmark(b, test.kem, test.kdf, test.aead)
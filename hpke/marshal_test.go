@@ -7,10 +7,16 @@ import (
 )
 
 func contextEqual(a, b *encdecContext) bool {
-	an := make([]byte, a.NonceSize())
-	bn := make([]byte, b.NonceSize())
-	ac := a.AEAD.Seal(nil, an, nil, nil)
-	bc := b.AEAD.Seal(nil, bn, nil, nil)
+	var ac, bc []byte
+	if a.AEAD != nil || b.AEAD != nil {
+		if a.AEAD == nil || b.AEAD == nil {
+			return false
+		}
+		an := make([]byte, a.NonceSize())
+		bn := make([]byte, b.NonceSize())
+		ac = a.AEAD.Seal(nil, an, nil, nil)
+		bc = b.AEAD.Seal(nil, bn, nil, nil)
+	}
 	return a.suite == b.suite &&
 		bytes.Equal(a.exporterSecret, b.exporterSecret) &&
 		bytes.Equal(a.key, b.key) &&
@@ -82,3 +88,67 @@ func TestContextSerialization(t *testing.T) {
 		t.Error("parsing an opener as a sealer succeeded; want failure")
 	}
 }
+
+func TestExportOnlyContextSerialization(t *testing.T) {
+	s := NewSuite(KEM_X25519_HKDF_SHA256, KDF_HKDF_SHA256, AEAD_EXPORT_ONLY)
+	info := []byte("some info string")
+
+	pk, sk, err := s.kemID.Scheme().GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver, err := s.NewReceiver(sk, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := s.NewSender(pk, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, sealer, err := sender.Setup(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opener, err := receiver.Setup(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawSealer, err := sealer.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedSealer, err := UnmarshalSealer(rawSealer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contextEqual(
+		sealer.(*sealContext).encdecContext,
+		parsedSealer.(*sealContext).encdecContext) {
+		t.Error("parsed sealer does not match original")
+	}
+
+	rawOpener, err := opener.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedOpener, err := UnmarshalOpener(rawOpener)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := []byte("exporter context")
+	want := sealer.Export(ctx, 32)
+	for _, c := range []Context{opener, parsedSealer, parsedOpener} {
+		if !bytes.Equal(c.Export(ctx, 32), want) {
+			t.Error("exported secrets differ")
+		}
+	}
+
+	if _, err = parsedSealer.Seal([]byte("pt"), nil); err != ErrExportOnlyAEAD {
+		t.Errorf("Seal: expected %v; got %v", ErrExportOnlyAEAD, err)
+	}
+	if _, err = parsedOpener.Open([]byte("ct"), nil); err != ErrExportOnlyAEAD {
+		t.Errorf("Open: expected %v; got %v", ErrExportOnlyAEAD, err)
+	}
+}
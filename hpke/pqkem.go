@@ -0,0 +1,323 @@
+package hpke
+
+// This file implements the ML-KEM and hybrid KEMs of draft-ietf-hpke-pq.
+// Private keys are seeds, and key pairs are derived with SHAKE256 as the
+// draft specifies. The hybrids follow the QSF construction of
+// draft-irtf-cfrg-hybrid-kems.
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/kem"
+)
+
+// labeledDerive is the LabeledDerive function of draft-ietf-hpke-pq with
+// SHAKE256, used to derive the private keys of its KEMs.
+func labeledDerive(id KEM, ikm []byte, label string, length int) []byte {
+	var suiteID [5]byte
+	suiteID[0], suiteID[1], suiteID[2] = 'K', 'E', 'M'
+	binary.BigEndian.PutUint16(suiteID[3:], uint16(id))
+
+	var l [2]byte
+	h := sha3.NewShake256()
+	_, _ = h.Write(ikm)
+	_, _ = h.Write([]byte(versionLabel))
+	_, _ = h.Write(suiteID[:])
+	binary.BigEndian.PutUint16(l[:], uint16(len(label)))
+	_, _ = h.Write(l[:])
+	_, _ = h.Write([]byte(label))
+	binary.BigEndian.PutUint16(l[:], uint16(length))
+	_, _ = h.Write(l[:])
+	out := make([]byte, length)
+	_, _ = h.Read(out)
+	return out
+}
+
+// mlKEM wraps ML-KEM to be used as a HPKE KEM. Its private keys are
+// encoded as the 64-byte seed (d ‖ z) of FIPS 203.
+type mlKEM struct {
+	kem.Scheme
+	id   KEM
+	name string
+}
+
+// seeded is implemented by the private keys of ML-KEM.
+type seeded interface{ Seed() []byte }
+
+func (m mlKEM) Name() string        { return m.name }
+func (m mlKEM) PrivateKeySize() int { return m.Scheme.SeedSize() }
+
+func (m mlKEM) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, m.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := m.newKey(seed)
+	return pk, sk, nil
+}
+
+// DeriveKeyPair derives a key pair from input keying material of any
+// length, as the DeriveKeyPair function of draft-ietf-hpke-pq.
+func (m mlKEM) DeriveKeyPair(ikm []byte) (kem.PublicKey, kem.PrivateKey) {
+	return m.newKey(labeledDerive(m.id, ikm, "DeriveKeyPair", m.SeedSize()))
+}
+
+func (m mlKEM) newKey(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	pk, sk := m.Scheme.DeriveKeyPair(seed)
+	return pk, &mlKEMPrivKey{m, sk}
+}
+
+func (m mlKEM) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	k, ok := sk.(*mlKEMPrivKey)
+	if !ok {
+		return nil, kem.ErrTypeMismatch
+	}
+	return m.Scheme.Decapsulate(k.PrivateKey, ct)
+}
+
+func (m mlKEM) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != m.PrivateKeySize() {
+		return nil, ErrInvalidKEMPrivateKey
+	}
+	_, sk := m.newKey(data)
+	return sk, nil
+}
+
+type mlKEMPrivKey struct {
+	scheme mlKEM
+	kem.PrivateKey
+}
+
+func (k *mlKEMPrivKey) Scheme() kem.Scheme { return k.scheme }
+func (k *mlKEMPrivKey) MarshalBinary() ([]byte, error) {
+	return k.PrivateKey.(seeded).Seed(), nil
+}
+
+func (k *mlKEMPrivKey) Equal(sk kem.PrivateKey) bool {
+	k1, ok := sk.(*mlKEMPrivKey)
+	return ok && k.scheme.id == k1.scheme.id && k.PrivateKey.Equal(k1.PrivateKey)
+}
+
+// qsfKEM is a hybrid of ML-KEM and an elliptic curve, with the QSF
+// combiner of draft-irtf-cfrg-hybrid-kems. Its private keys are 32-byte
+// seeds that are expanded with SHAKE256 into the keys of both components.
+type qsfKEM struct {
+	id    KEM
+	name  string
+	label string
+	pq    kem.Scheme
+	curve ecdh.Curve
+
+	// Size of the scalars of the curve, and number of candidates for the
+	// rejection sampling of the ephemeral scalar during encapsulation.
+	scalarSize, candidates int
+}
+
+func (q qsfKEM) Name() string        { return q.name }
+func (q qsfKEM) SeedSize() int       { return 32 }
+func (q qsfKEM) PrivateKeySize() int { return 32 }
+func (q qsfKEM) SharedKeySize() int  { return 32 }
+func (q qsfKEM) pointSize() int      { return 1 + 2*q.scalarSize }
+func (q qsfKEM) PublicKeySize() int  { return q.pq.PublicKeySize() + q.pointSize() }
+func (q qsfKEM) CiphertextSize() int { return q.pq.CiphertextSize() + q.pointSize() }
+func (q qsfKEM) EncapsulationSeedSize() int {
+	return q.pq.EncapsulationSeedSize() + q.candidates*q.scalarSize
+}
+
+func (q qsfKEM) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, q.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	sk, err := q.newKey(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sk.Public(), sk, nil
+}
+
+// DeriveKeyPair derives a key pair from input keying material of any
+// length, as the DeriveKeyPair function of draft-ietf-hpke-pq.
+func (q qsfKEM) DeriveKeyPair(ikm []byte) (kem.PublicKey, kem.PrivateKey) {
+	sk, err := q.newKey(labeledDerive(q.id, ikm, "DeriveKeyPair", q.SeedSize()))
+	if err != nil {
+		panic(err)
+	}
+	return sk.Public(), sk
+}
+
+// newKey expands the seed into the keys of both components. The seed of
+// ML-KEM is taken first from the output of SHAKE256, and then candidates
+// for the scalar until one of them is valid.
+func (q qsfKEM) newKey(seed []byte) (*qsfPrivKey, error) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+
+	seedPQ := make([]byte, q.pq.SeedSize())
+	_, _ = h.Read(seedPQ)
+	_, skPQ := q.pq.DeriveKeyPair(seedPQ)
+
+	scalar := make([]byte, q.scalarSize)
+	for {
+		_, _ = h.Read(scalar)
+		skT, err := q.curve.NewPrivateKey(scalar)
+		if err == nil {
+			return &qsfPrivKey{q, append([]byte{}, seed...), skPQ, skT}, nil
+		}
+	}
+}
+
+func (q qsfKEM) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	seed := make([]byte, q.EncapsulationSeedSize())
+	if _, err = io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	return q.EncapsulateDeterministically(pk, seed)
+}
+
+// EncapsulateDeterministically takes the randomness of ML-KEM from the
+// beginning of the seed, and then candidates for the ephemeral scalar.
+func (q qsfKEM) EncapsulateDeterministically(pk kem.PublicKey, seed []byte) (ct, ss []byte, err error) {
+	if len(seed) != q.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+	pub, ok := pk.(*qsfPubKey)
+	if !ok {
+		return nil, nil, kem.ErrTypeMismatch
+	}
+
+	n := q.pq.EncapsulationSeedSize()
+	ctPQ, ssPQ, err := q.pq.EncapsulateDeterministically(pub.pq, seed[:n])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var skE *ecdh.PrivateKey
+	for i := n; skE == nil && i < len(seed); i += q.scalarSize {
+		skE, _ = q.curve.NewPrivateKey(seed[i : i+q.scalarSize])
+	}
+	if skE == nil {
+		return nil, nil, ErrInvalidKEMDeriveKey
+	}
+	ssT, err := skE.ECDH(pub.t)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctT := skE.PublicKey().Bytes()
+
+	ss = q.combine(ssPQ, ssT, ctT, pub.t.Bytes())
+	ct = append(ctPQ, ctT...)
+	return ct, ss, nil
+}
+
+func (q qsfKEM) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	if len(ct) != q.CiphertextSize() {
+		return nil, kem.ErrCiphertextSize
+	}
+	priv, ok := sk.(*qsfPrivKey)
+	if !ok {
+		return nil, kem.ErrTypeMismatch
+	}
+
+	n := q.pq.CiphertextSize()
+	ssPQ, err := q.pq.Decapsulate(priv.pq, ct[:n])
+	if err != nil {
+		return nil, err
+	}
+	ctT := ct[n:]
+	pkE, err := q.curve.NewPublicKey(ctT)
+	if err != nil {
+		return nil, ErrInvalidKEMSharedSecret
+	}
+	ssT, err := priv.t.ECDH(pkE)
+	if err != nil {
+		return nil, ErrInvalidKEMSharedSecret
+	}
+
+	return q.combine(ssPQ, ssT, ctT, priv.t.PublicKey().Bytes()), nil
+}
+
+// combine is the QSF combiner, SHA3-256(ssPQ ‖ ssT ‖ ctT ‖ ekT ‖ label).
+func (q qsfKEM) combine(ssPQ, ssT, ctT, ekT []byte) []byte {
+	h := sha3.New256()
+	_, _ = h.Write(ssPQ)
+	_, _ = h.Write(ssT)
+	_, _ = h.Write(ctT)
+	_, _ = h.Write(ekT)
+	_, _ = h.Write([]byte(q.label))
+	return h.Sum(nil)
+}
+
+func (q qsfKEM) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != q.PrivateKeySize() {
+		return nil, ErrInvalidKEMPrivateKey
+	}
+	return q.newKey(data)
+}
+
+func (q qsfKEM) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != q.PublicKeySize() {
+		return nil, ErrInvalidKEMPublicKey
+	}
+	n := q.pq.PublicKeySize()
+	pkPQ, err := q.pq.UnmarshalBinaryPublicKey(data[:n])
+	if err != nil {
+		return nil, ErrInvalidKEMPublicKey
+	}
+	pkT, err := q.curve.NewPublicKey(data[n:])
+	if err != nil {
+		return nil, ErrInvalidKEMPublicKey
+	}
+	return &qsfPubKey{q, pkPQ, pkT}, nil
+}
+
+type qsfPubKey struct {
+	scheme qsfKEM
+	pq     kem.PublicKey
+	t      *ecdh.PublicKey
+}
+
+func (k *qsfPubKey) Scheme() kem.Scheme { return k.scheme }
+func (k *qsfPubKey) MarshalBinary() ([]byte, error) {
+	pk, err := k.pq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(pk, k.t.Bytes()...), nil
+}
+
+func (k *qsfPubKey) Equal(pk kem.PublicKey) bool {
+	k1, ok := pk.(*qsfPubKey)
+	return ok &&
+		k.scheme.id == k1.scheme.id &&
+		k.pq.Equal(k1.pq) &&
+		k.t.Equal(k1.t)
+}
+
+type qsfPrivKey struct {
+	scheme qsfKEM
+	seed   []byte
+	pq     kem.PrivateKey
+	t      *ecdh.PrivateKey
+}
+
+func (k *qsfPrivKey) Scheme() kem.Scheme { return k.scheme }
+func (k *qsfPrivKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, k.seed...), nil
+}
+
+func (k *qsfPrivKey) Equal(sk kem.PrivateKey) bool {
+	k1, ok := sk.(*qsfPrivKey)
+	return ok &&
+		k.scheme.id == k1.scheme.id &&
+		subtle.ConstantTimeCompare(k.seed, k1.seed) == 1
+}
+
+func (k *qsfPrivKey) Public() kem.PublicKey {
+	return &qsfPubKey{k.scheme, k.pq.Public(), k.t.PublicKey()}
+}
@@ -0,0 +1,192 @@
+package hpke
+
+import "io"
+
+// StreamChunkSize is the size of the plaintext chunks of a stream, except
+// for the last one, which may be shorter.
+const StreamChunkSize = 64 * 1024
+
+// streamLabel prefixes the associated data of every chunk of a stream,
+// followed by a flag telling whether the chunk is the last one.
+const streamLabel = "HPKE-v1 stream"
+
+const (
+	streamChunkNonFinal byte = 0x00
+	streamChunkFinal    byte = 0x01
+)
+
+func streamAAD(final bool, aad []byte) []byte {
+	b := make([]byte, 0, len(streamLabel)+1+len(aad))
+	b = append(b, streamLabel...)
+	if final {
+		b = append(b, streamChunkFinal)
+	} else {
+		b = append(b, streamChunkNonFinal)
+	}
+	return append(b, aad...)
+}
+
+// StreamWriter encrypts a stream of data with a Sealer, following the
+// STREAM construction of Hoang, Reyhanitabar, Rogaway and Vizár: the
+// plaintext is split in chunks of StreamChunkSize bytes, each sealed with
+// the next sequence number of the context, and with associated data that
+// flags the last chunk. Thus, chunks cannot be reordered, dropped, or
+// truncated without the StreamReader detecting it.
+//
+// As chunks use the sequence numbers of the Sealer, the Sealer should not
+// be used for other messages while the stream is being written, unless the
+// receiver opens them in the same order.
+type StreamWriter struct {
+	sealer Sealer
+	w      io.Writer
+	aad    []byte
+	buf    []byte
+	err    error
+}
+
+// NewStreamWriter returns a StreamWriter encrypting to w, which binds aad
+// to every chunk. Close must be called to write the last chunk.
+func NewStreamWriter(sealer Sealer, w io.Writer, aad []byte) (*StreamWriter, error) {
+	if _, _, aeadID := sealer.Suite().Params(); aeadID == AEAD_EXPORT_ONLY {
+		return nil, ErrExportOnlyAEAD
+	}
+	return &StreamWriter{
+		sealer: sealer,
+		w:      w,
+		aad:    append([]byte(nil), aad...),
+		buf:    make([]byte, 0, StreamChunkSize),
+	}, nil
+}
+
+// Write encrypts p. Chunks are written to the underlying writer once they
+// are full, and the next chunk is started, so at most StreamChunkSize bytes
+// are buffered.
+func (s *StreamWriter) Write(p []byte) (n int, err error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	for len(p) > 0 {
+		// A full chunk is only written once more data arrives, as it
+		// would be the last one otherwise.
+		if len(s.buf) == StreamChunkSize {
+			if err = s.flush(false); err != nil {
+				return n, err
+			}
+		}
+		k := copy(s.buf[len(s.buf):StreamChunkSize], p)
+		s.buf = s.buf[:len(s.buf)+k]
+		p = p[k:]
+		n += k
+	}
+	return n, nil
+}
+
+// Close writes the last chunk. It does not close the underlying writer.
+func (s *StreamWriter) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.flush(true); err != nil {
+		return err
+	}
+	s.err = ErrStreamClosed
+	return nil
+}
+
+func (s *StreamWriter) flush(final bool) error {
+	ct, err := s.sealer.Seal(s.buf, streamAAD(final, s.aad))
+	if err == nil {
+		_, err = s.w.Write(ct)
+	}
+	if err != nil {
+		s.err = err
+		return err
+	}
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// StreamReader decrypts a stream written by a StreamWriter with an Opener.
+// Read returns an error if the stream was modified, reordered or
+// truncated. Data from a chunk is only returned once it has been
+// authenticated, but the stream as a whole is only authenticated once Read
+// returns io.EOF.
+type StreamReader struct {
+	opener Opener
+	r      io.Reader
+	aad    []byte
+	in     []byte // ciphertext read ahead, up to one chunk plus one byte
+	out    []byte // decrypted data not returned yet
+	err    error
+}
+
+// NewStreamReader returns a StreamReader decrypting from r, which checks
+// that aad was bound to every chunk.
+func NewStreamReader(opener Opener, r io.Reader, aad []byte) (*StreamReader, error) {
+	_, _, aeadID := opener.Suite().Params()
+	if aeadID == AEAD_EXPORT_ONLY {
+		return nil, ErrExportOnlyAEAD
+	}
+	return &StreamReader{
+		opener: opener,
+		r:      r,
+		aad:    append([]byte(nil), aad...),
+		in:     make([]byte, 0, int(aeadID.CipherLen(StreamChunkSize))+1),
+	}, nil
+}
+
+// Read decrypts data from the stream into p.
+func (s *StreamReader) Read(p []byte) (int, error) {
+	for len(s.out) == 0 && s.err == nil {
+		s.err = s.next()
+	}
+	if len(s.out) > 0 {
+		n := copy(p, s.out)
+		s.out = s.out[n:]
+		return n, nil
+	}
+	return 0, s.err
+}
+
+// next decrypts the next chunk into out. It returns io.EOF after the last
+// chunk.
+func (s *StreamReader) next() error {
+	if s.in == nil {
+		return io.EOF
+	}
+
+	// Reading one byte past a full chunk tells whether it is the last one.
+	chunkLen := cap(s.in) - 1
+	n, err := io.ReadFull(s.r, s.in[len(s.in):cap(s.in)])
+	s.in = s.in[:len(s.in)+n]
+	final := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		return err
+	}
+
+	ct := s.in
+	if !final {
+		ct = s.in[:chunkLen]
+	}
+	pt, err := s.opener.Open(ct, streamAAD(final, s.aad))
+	if err != nil {
+		if final {
+			return ErrStreamTruncated
+		}
+		return err
+	}
+	s.out = pt
+
+	if final {
+		s.in = nil
+		if len(pt) == 0 {
+			return io.EOF
+		}
+		return nil
+	}
+	s.in = append(s.in[:0], s.in[chunkLen:]...)
+	return nil
+}
@@ -0,0 +1,180 @@
+package hpke_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/quantumcoinproject/circl/hpke"
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+func streamContexts(t *testing.T, aeadID hpke.AEAD) (hpke.Sealer, hpke.Opener) {
+	t.Helper()
+	kemID := hpke.KEM_X25519_HKDF_SHA256
+	suite := hpke.NewSuite(kemID, hpke.KDF_HKDF_SHA256, aeadID)
+	pk, sk, err := kemID.Scheme().GenerateKeyPair()
+	test.CheckNoErr(t, err, "generate key pair")
+	sender, err := suite.NewSender(pk, nil)
+	test.CheckNoErr(t, err, "new sender")
+	enc, sealer, err := sender.Setup(rand.Reader)
+	test.CheckNoErr(t, err, "sender setup")
+	receiver, err := suite.NewReceiver(sk, nil)
+	test.CheckNoErr(t, err, "new receiver")
+	opener, err := receiver.Setup(enc)
+	test.CheckNoErr(t, err, "receiver setup")
+	return sealer, opener
+}
+
+func streamSeal(t *testing.T, sealer hpke.Sealer, msg, aad []byte, writeSize int) []byte {
+	t.Helper()
+	var ct bytes.Buffer
+	w, err := hpke.NewStreamWriter(sealer, &ct, aad)
+	test.CheckNoErr(t, err, "new stream writer")
+	for p := msg; len(p) > 0; {
+		n := min(writeSize, len(p))
+		_, err = w.Write(p[:n])
+		test.CheckNoErr(t, err, "stream write")
+		p = p[n:]
+	}
+	test.CheckNoErr(t, w.Close(), "stream close")
+	return ct.Bytes()
+}
+
+func TestStream(t *testing.T) {
+	const C = hpke.StreamChunkSize
+	msg := make([]byte, 3*C+5)
+	_, _ = rand.Read(msg)
+	aad := []byte("aad")
+
+	for _, aeadID := range []hpke.AEAD{
+		hpke.AEAD_AES128GCM,
+		hpke.AEAD_AES256GCM,
+		hpke.AEAD_ChaCha20Poly1305,
+	} {
+		for _, size := range []int{0, 1, C - 1, C, C + 1, 2 * C, 3*C + 5} {
+			name := fmt.Sprintf("%v/%v", aeadID, size)
+			sealer, opener := streamContexts(t, aeadID)
+			ct := streamSeal(t, sealer, msg[:size], aad, 1000)
+
+			chunks := max(1, (size+C-1)/C)
+			if want := size + chunks*int(aeadID.CipherLen(0)); len(ct) != want {
+				test.ReportError(t, len(ct), want, name)
+			}
+
+			r, err := hpke.NewStreamReader(opener, iotest.HalfReader(bytes.NewReader(ct)), aad)
+			test.CheckNoErr(t, err, "new stream reader")
+			got, err := io.ReadAll(r)
+			test.CheckNoErr(t, err, "stream read "+name)
+			if !bytes.Equal(got, msg[:size]) {
+				test.ReportError(t, len(got), size, name)
+			}
+		}
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	const C = hpke.StreamChunkSize
+	aeadID := hpke.AEAD_AES128GCM
+	chunk := C + int(aeadID.CipherLen(0))
+	msg := make([]byte, 3*C)
+	_, _ = rand.Read(msg)
+	aad := []byte("aad")
+
+	cases := []struct {
+		name   string
+		aad    []byte
+		modify func(ct []byte) []byte
+	}{
+		{"truncatedAtChunk", aad, func(ct []byte) []byte { return ct[:2*chunk] }},
+		{"truncatedInChunk", aad, func(ct []byte) []byte { return ct[:2*chunk+10] }},
+		{"empty", aad, func(ct []byte) []byte { return nil }},
+		{"droppedChunk", aad, func(ct []byte) []byte {
+			return append(ct[:chunk:chunk], ct[2*chunk:]...)
+		}},
+		{"swappedChunks", aad, func(ct []byte) []byte {
+			out := append([]byte(nil), ct[chunk:2*chunk]...)
+			out = append(out, ct[:chunk]...)
+			return append(out, ct[2*chunk:]...)
+		}},
+		{"extended", aad, func(ct []byte) []byte { return append(ct, 0) }},
+		{"flipped", aad, func(ct []byte) []byte { ct[chunk+1] ^= 1; return ct }},
+		{"wrongAAD", []byte("other"), func(ct []byte) []byte { return ct }},
+	}
+	for _, tc := range cases {
+		sealer, opener := streamContexts(t, aeadID)
+		ct := tc.modify(streamSeal(t, sealer, msg, aad, C))
+		r, err := hpke.NewStreamReader(opener, bytes.NewReader(ct), tc.aad)
+		test.CheckNoErr(t, err, "new stream reader")
+		_, err = io.ReadAll(r)
+		test.CheckIsErr(t, err, "tampered stream should fail: "+tc.name)
+	}
+
+	sealer, _ := streamContexts(t, aeadID)
+	w, err := hpke.NewStreamWriter(sealer, io.Discard, nil)
+	test.CheckNoErr(t, err, "new stream writer")
+	test.CheckNoErr(t, w.Close(), "stream close")
+	if _, err = w.Write(msg); !errors.Is(err, hpke.ErrStreamClosed) {
+		test.ReportError(t, err, hpke.ErrStreamClosed)
+	}
+
+	sealer, opener := streamContexts(t, hpke.AEAD_EXPORT_ONLY)
+	if _, err = hpke.NewStreamWriter(sealer, io.Discard, nil); !errors.Is(err, hpke.ErrExportOnlyAEAD) {
+		test.ReportError(t, err, hpke.ErrExportOnlyAEAD)
+	}
+	if _, err = hpke.NewStreamReader(opener, nil, nil); !errors.Is(err, hpke.ErrExportOnlyAEAD) {
+		test.ReportError(t, err, hpke.ErrExportOnlyAEAD)
+	}
+}
+
+func ExampleNewStreamWriter() {
+	kemID := hpke.KEM_X25519_HKDF_SHA256
+	suite := hpke.NewSuite(kemID, hpke.KDF_HKDF_SHA256, hpke.AEAD_ChaCha20Poly1305)
+	publicBob, privateBob, err := kemID.Scheme().GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+
+	// Alice encrypts a large payload to Bob, without holding it in memory.
+	Alice, err := suite.NewSender(publicBob, []byte("backup"))
+	if err != nil {
+		panic(err)
+	}
+	enc, sealer, err := Alice.Setup(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	var ct bytes.Buffer
+	w, err := hpke.NewStreamWriter(sealer, &ct, nil)
+	if err != nil {
+		panic(err)
+	}
+	payload := bytes.NewReader(make([]byte, 1<<20))
+	if _, err = io.Copy(w, payload); err != nil {
+		panic(err)
+	}
+	if err = w.Close(); err != nil {
+		panic(err)
+	}
+
+	// Bob decrypts the stream.
+	Bob, err := suite.NewReceiver(privateBob, []byte("backup"))
+	if err != nil {
+		panic(err)
+	}
+	opener, err := Bob.Setup(enc)
+	if err != nil {
+		panic(err)
+	}
+	r, err := hpke.NewStreamReader(opener, &ct, nil)
+	if err != nil {
+		panic(err)
+	}
+	n, err := io.Copy(io.Discard, r)
+	fmt.Println(n, err)
+	// Output: 1048576 <nil>
+}
@@ -1,6 +1,7 @@
 package hpke
 
 import (
+	"crypto/cipher"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -20,16 +21,25 @@ func (st state) keySchedule(ss, info, psk, pskID []byte) (*encdecContext, error)
 
 	secret := st.labeledExtract(ss, []byte("secret"), psk)
 
-	Nk := uint16(st.aeadID.KeySize())
-	key := st.labeledExpand(secret, []byte("key"), keySchCtx, Nk)
+	// The key and base nonce are not used with AEAD_EXPORT_ONLY, and
+	// so are left empty.
+	var (
+		key, baseNonce []byte
+		aead           cipher.AEAD
+		err            error
+	)
+	Nn := uint16(st.aeadID.NonceSize())
+	if st.aeadID != AEAD_EXPORT_ONLY {
+		Nk := uint16(st.aeadID.KeySize())
+		key = st.labeledExpand(secret, []byte("key"), keySchCtx, Nk)
 
-	aead, err := st.aeadID.New(key)
-	if err != nil {
-		return nil, err
-	}
+		aead, err = st.aeadID.New(key)
+		if err != nil {
+			return nil, err
+		}
 
-	Nn := uint16(aead.NonceSize())
-	baseNonce := st.labeledExpand(secret, []byte("base_nonce"), keySchCtx, Nn)
+		baseNonce = st.labeledExpand(secret, []byte("base_nonce"), keySchCtx, Nn)
+	}
 	exporterSecret := st.labeledExpand(
 		secret,
 		[]byte("exp"),
@@ -20,6 +20,52 @@ var (
 	testVectorExportLength         = 32
 )
 
+func TestPQVectors(t *testing.T) {
+	// Test vectors from draft-ietf-hpke-pq, as distributed in
+	// https://go.dev/src/crypto/hpke/testdata/hpke-pq.json
+	vectors := readFile(t, "testdata/vectors_hpke_pq.json")
+	for i, v := range vectors {
+		t.Run(fmt.Sprintf("v%v", i), func(t *testing.T) {
+			kem := KEM(v.KemID)
+			if !kem.IsValid() || !KDF(v.KdfID).IsValid() {
+				t.Skipf("Skipping test with unknown KEM or KDF: %x %x",
+					v.KemID, v.KdfID)
+			}
+			scheme := kem.Scheme()
+			pkR, err := scheme.UnmarshalBinaryPublicKey(hexB(t, v.PkRm))
+			test.CheckNoErr(t, err, "bad public key")
+			skR, err := scheme.UnmarshalBinaryPrivateKey(hexB(t, v.SkRm))
+			test.CheckNoErr(t, err, "bad private key")
+			if !pkR.Equal(skR.Public()) {
+				t.Fatal("public key does not match the private key")
+			}
+
+			// The keys of X-Wing are derived as in its own draft, which
+			// predates draft-ietf-hpke-pq.
+			if kem != KEM_XWING {
+				pk, sk := scheme.DeriveKeyPair(hexB(t, v.IkmR))
+				if !pk.Equal(pkR) || !sk.Equal(skR) {
+					t.Fatal("derived key pair does not match")
+				}
+			}
+
+			ct, ss, err := scheme.EncapsulateDeterministically(pkR, hexB(t, v.IkmE))
+			test.CheckNoErr(t, err, "encapsulation")
+			if !bytes.Equal(ct, hexB(t, v.Enc)) ||
+				!bytes.Equal(ss, hexB(t, v.SharedSecret)) {
+				t.Fatal("encapsulation does not match")
+			}
+			ss, err = scheme.Decapsulate(skR, ct)
+			test.CheckNoErr(t, err, "decapsulation")
+			if !bytes.Equal(ss, hexB(t, v.SharedSecret)) {
+				t.Fatal("decapsulation does not match")
+			}
+
+			v.verify(t)
+		})
+	}
+}
+
 func TestVectors(t *testing.T) {
 	// Test vectors from
 	// https://github.com/cfrg/draft-irtf-cfrg-hpke/blob/master/test-vectors.json
@@ -277,8 +323,10 @@ func mustEncodePrivateKey(sk kem.PrivateKey) []byte {
 	return enc
 }
 
-func generateEncryptions(sealer Sealer, opener Opener, msg []byte) ([]encryptionVector, error) {
-	vectors := make([]encryptionVector, testVectorEncryptionCount)
+func generateEncryptions(sealer Sealer, opener Opener, msg []byte, count int) (
+	[]encryptionVector, error,
+) {
+	vectors := make([]encryptionVector, count)
 	for i := 0; i < len(vectors); i++ {
 		aad := []byte(fmt.Sprintf("Count-%d", i))
 		innerSealer := sealer.(*sealContext)
@@ -395,7 +443,8 @@ func TestHybridKemRoundTrip(t *testing.T) {
 
 		innerSealer := sealer.(*sealContext)
 
-		encryptions, err2 := generateEncryptions(sealer, opener, msg)
+		encryptions, err2 := generateEncryptions(sealer, opener, msg,
+			testVectorEncryptionCount)
 		if err2 != nil {
 			t.Error(err2)
 		}
@@ -0,0 +1,130 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates the FrodoKEM variants from templates to prevent too much
+// duplicated code between the different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+type Instance struct {
+	N             int
+	LogQ          int
+	ExtractedBits int
+	SharedKeySize int
+	AES           bool
+	CDF           []uint16
+}
+
+func (m Instance) Name() string {
+	return fmt.Sprintf("FrodoKEM-%d-%s", m.N, m.Matrix())
+}
+
+func (m Instance) Pkg() string {
+	return strings.ToLower(fmt.Sprintf("frodo%d%s", m.N, m.Matrix()))
+}
+
+// Matrix returns the primitive used to generate the matrix A.
+func (m Instance) Matrix() string {
+	if m.AES {
+		return "AES"
+	}
+	return "SHAKE"
+}
+
+// Shake returns the XOF used for hashing and sampling, which is
+// SHAKE128 for FrodoKEM-640 and SHAKE256 for the larger parameter sets.
+func (m Instance) Shake() string {
+	if m.N == 640 {
+		return "Shake128"
+	}
+	return "Shake256"
+}
+
+func (m Instance) MatrixBpPackedSize() int { return m.LogQ * m.N * 8 / 8 }
+func (m Instance) PublicKeySize() int      { return 16 + m.MatrixBpPackedSize() }
+func (m Instance) CiphertextSize() int     { return m.MatrixBpPackedSize() + m.LogQ*8*8/8 }
+
+func (m Instance) PrivateKeySize() int {
+	return 2*m.SharedKeySize + m.PublicKeySize() + 2*m.N*8
+}
+
+func (m Instance) CDFTable() string {
+	s := make([]string, len(m.CDF))
+	for i, v := range m.CDF {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+var (
+	cdf640  = []uint16{4643, 13363, 20579, 25843, 29227, 31145, 32103, 32525, 32689, 32745, 32762, 32766, 32767}
+	cdf976  = []uint16{5638, 15915, 23689, 28571, 31116, 32217, 32613, 32731, 32760, 32766, 32767}
+	cdf1344 = []uint16{9142, 23462, 30338, 32361, 32725, 32765, 32767}
+
+	Instances = []Instance{
+		{N: 640, LogQ: 15, ExtractedBits: 2, SharedKeySize: 16, CDF: cdf640},
+		{N: 640, LogQ: 15, ExtractedBits: 2, SharedKeySize: 16, CDF: cdf640, AES: true},
+		{N: 976, LogQ: 16, ExtractedBits: 3, SharedKeySize: 24, CDF: cdf976},
+		{N: 976, LogQ: 16, ExtractedBits: 3, SharedKeySize: 24, CDF: cdf976, AES: true},
+		{N: 1344, LogQ: 16, ExtractedBits: 4, SharedKeySize: 32, CDF: cdf1344},
+		{N: 1344, LogQ: 16, ExtractedBits: 4, SharedKeySize: 32, CDF: cdf1344, AES: true},
+	}
+	Files = []string{
+		"frodo",
+		"matrix",
+		"noise",
+		"util",
+	}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/file.go from templates/file.templ.go
+func generatePackageFiles() {
+	for _, file := range Files {
+		tl, err := template.ParseFiles("templates/" + file + ".templ.go")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, mode := range Instances {
+			buf := new(bytes.Buffer)
+			err := tl.Execute(buf, mode)
+			if err != nil {
+				panic(err)
+			}
+
+			// Formating output code
+			code, err := format.Source(buf.Bytes())
+			if err != nil {
+				panic(fmt.Sprintf("error formating code: %v", err))
+			}
+
+			res := string(code)
+			offset := strings.Index(res, TemplateWarning)
+			if offset == -1 {
+				panic("Missing template warning in " + file + ".templ.go")
+			}
+			err = os.MkdirAll(mode.Pkg(), 0o755)
+			if err != nil {
+				panic(err)
+			}
+			err = os.WriteFile(path.Join(mode.Pkg(), file+".go"), []byte(res[offset:]), 0o644)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
@@ -21,6 +21,19 @@ func TestPQCgenKATKem(t *testing.T) {
 		// Computed from:
 		// https://github.com/microsoft/PQCrypto-LWEKE/blob/66fc7744c3aae6acfc5fcc587ec7f2cdec48d216/KAT/PQCkemKAT_19888_shake.rsp
 		{"FrodoKEM-640-SHAKE", "604a10cfc871dfaed9cb5b057c644ab03b16852cea7f39bc7f9831513b5b1cfa"},
+
+		// The remaining variants share the code of FrodoKEM-640-SHAKE through
+		// kem/frodo/templates. These digests are regression values of this
+		// implementation, not digests of the official PQCkemKAT_*.rsp files,
+		// which could not be retrieved. Replace them with the digests of
+		// PQCkemKAT_31296_shake.rsp, PQCkemKAT_43088_shake.rsp and the
+		// _aes.rsp files of the commit above when they are available; a
+		// mismatch then points at the parameters of the variant.
+		{"FrodoKEM-976-SHAKE", "32b0ad60047273fb52696f0516acac7ed083e31f5478b416d579ae5e8d8e734c"},
+		{"FrodoKEM-1344-SHAKE", "591adc09a718afbc0ac36e1f57a191e557fe4eec7899e078104b9706b75e2f96"},
+		{"FrodoKEM-640-AES", "d1e69503e9042f9484b6e01a466865baa607471c63d7e45d2409f639ba161206"},
+		{"FrodoKEM-976-AES", "32ed6b1622c845b487c3170ce6878df7baae07e90bd2819a19e5960ce04a55f7"},
+		{"FrodoKEM-1344-AES", "9756f7c8cc88d7048ff6e81fa66425bb1392e35c1d30016c190dba17de15221a"},
 	}
 	for _, kat := range kats {
 		t.Run(kat.name, func(t *testing.T) {
@@ -0,0 +1,18 @@
+//go:generate go run gen.go
+
+// Package hqc implements the HQC (Hamming Quasi-Cyclic) key encapsulation
+// mechanism, as submitted to round 4 of the NIST PQC competition [1].
+//
+// HQC is a code-based KEM, selected by NIST for standardization as an
+// alternative to ML-KEM that does not rely on lattices. Its security is
+// based on the hardness of decoding random quasi-cyclic codes in the
+// Hamming metric. The parameter sets HQC-128, HQC-192 and HQC-256 are
+// available.
+//
+// This implementation follows the reference implementation [2].
+//
+// References:
+//
+//	[1] https://pqc-hqc.org/doc/hqc-specification_2023-04-30.pdf
+//	[2] https://pqc-hqc.org/implementation.html
+package hqc
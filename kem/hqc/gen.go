@@ -0,0 +1,83 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates wrappers from templates to prevent too much duplicated code
+// between the code for different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type Instance struct {
+	Name           string
+	Pkg            string
+	Param          string
+	K              int
+	PublicKeySize  int
+	PrivateKeySize int
+	CiphertextSize int
+}
+
+var (
+	Instances = []Instance{
+		{
+			Name: "HQC-128", Pkg: "hqc128", Param: "HQC128", K: 16,
+			PublicKeySize: 2249, PrivateKeySize: 2305, CiphertextSize: 4433,
+		},
+		{
+			Name: "HQC-192", Pkg: "hqc192", Param: "HQC192", K: 24,
+			PublicKeySize: 4522, PrivateKeySize: 4586, CiphertextSize: 8978,
+		},
+		{
+			Name: "HQC-256", Pkg: "hqc256", Param: "HQC256", K: 32,
+			PublicKeySize: 7245, PrivateKeySize: 7317, CiphertextSize: 14421,
+		},
+	}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/hqc.go from templates/pkg.templ.go
+func generatePackageFiles() {
+	tl, err := template.ParseFiles("templates/pkg.templ.go")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, mode := range Instances {
+		buf := new(bytes.Buffer)
+		err := tl.Execute(buf, mode)
+		if err != nil {
+			panic(err)
+		}
+
+		// Formating output code
+		code, err := format.Source(buf.Bytes())
+		if err != nil {
+			panic(fmt.Sprintf("error formating code: %v", err))
+		}
+
+		res := string(code)
+		offset := strings.Index(res, TemplateWarning)
+		if offset == -1 {
+			panic("Missing template warning in pkg.templ.go")
+		}
+		err = os.MkdirAll(mode.Pkg, 0o755)
+		if err != nil {
+			panic(err)
+		}
+		err = os.WriteFile(mode.Pkg+"/hqc.go", []byte(res[offset:]), 0o644)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package internal
+
+import "encoding/binary"
+
+// Elements of GF(2⁸) = GF(2)[z]/(z⁸ + z⁴ + z³ + z² + 1), in which z is
+// a primitive element α.
+
+// Returns a·b in GF(2⁸), in constant time.
+func gfMul(a, b byte) byte {
+	var r uint16
+	for i := 0; i < 8; i++ {
+		r ^= (uint16(a) << i) & -uint16((b>>i)&1)
+	}
+	for i := 14; i >= 8; i-- {
+		r ^= (0x11D << (i - 8)) & -((r >> i) & 1)
+	}
+	return byte(r)
+}
+
+// Returns a⁻¹ in GF(2⁸), computed as a²⁵⁴, so that the inverse of
+// zero is zero.
+func gfInv(a byte) byte {
+	// t = a^(2ⁱ⁺¹-1) after the iteration i.
+	t := a
+	for i := 1; i < 7; i++ {
+		t = gfMul(gfMul(t, t), a)
+	}
+	return gfMul(t, t)
+}
+
+// Returns αⁱ.
+func gfExp(i int) byte {
+	i %= 255
+	r := byte(1)
+	for ; i > 0; i-- {
+		r = gfMul(r, 2)
+	}
+	return r
+}
+
+// Returns the coefficients of the generator polynomial ∏ (x - αⁱ) for
+// i = 1, ..., d of the Reed-Solomon code.
+func rsGeneratorPoly(d int) []byte {
+	g := make([]byte, d+1)
+	g[0] = 1
+	for i := 1; i <= d; i++ {
+		ai := gfExp(i)
+		for j := i; j > 0; j-- {
+			g[j] = g[j-1] ^ gfMul(g[j], ai)
+		}
+		g[0] = gfMul(g[0], ai)
+	}
+	return g
+}
+
+// Encodes the message msg of k bytes into the systematic Reed-Solomon
+// codeword cdw of n₁ bytes, the message being in the last k bytes.
+func (p *Params) rsEncode(cdw, msg []byte) {
+	g := len(p.rsPoly) - 1
+	for i := range cdw {
+		cdw[i] = 0
+	}
+	for i := 0; i < p.k; i++ {
+		gate := msg[p.k-1-i] ^ cdw[g-1]
+		for j := g - 1; j > 0; j-- {
+			cdw[j] = cdw[j-1] ^ gfMul(gate, p.rsPoly[j])
+		}
+		cdw[0] = gfMul(gate, p.rsPoly[0])
+	}
+	copy(cdw[g:], msg)
+}
+
+// Decodes the Reed-Solomon codeword cdw, correcting up to δ errors,
+// and writes the message to msg. Runs in constant time.
+func (p *Params) rsDecode(msg, cdw []byte) {
+	d := 2 * p.delta
+
+	// Syndromes Sⱼ = c(αʲ⁺¹) for j = 0, ..., 2δ-1.
+	s := make([]byte, d)
+	for j := range s {
+		aj := gfExp(j + 1)
+		x := byte(1)
+		for i := 0; i < p.n1; i++ {
+			s[j] ^= gfMul(cdw[i], x)
+			x = gfMul(x, aj)
+		}
+	}
+
+	sigma := p.errorLocator(s)
+
+	// Error evaluator Ω(x) = S(x)σ(x) mod x²ᵟ.
+	omega := make([]byte, d)
+	for i := range omega {
+		for j := 0; j <= min(i, p.delta); j++ {
+			omega[i] ^= gfMul(sigma[j], s[i-j])
+		}
+	}
+
+	// Find the roots α⁻ⁱ of σ and correct the errors using Forney's
+	// formula eᵢ = Ω(α⁻ⁱ)/σ'(α⁻ⁱ).
+	for i := 0; i < p.n1; i++ {
+		x := gfExp(255 - i)
+		xInv := gfExp(i)
+
+		var sx, dsx, ox byte
+		xj := byte(1)
+		for j := 0; j < d; j++ {
+			if j <= p.delta {
+				sx ^= gfMul(sigma[j], xj)
+				if j&1 == 1 {
+					dsx ^= gfMul(sigma[j], gfMul(xj, xInv))
+				}
+			}
+			ox ^= gfMul(omega[j], xj)
+			xj = gfMul(xj, x)
+		}
+
+		mask := byte(-((uint16(sx) - 1) >> 15))
+		cdw[i] ^= gfMul(ox, gfInv(dsx)) & mask
+	}
+
+	copy(msg, cdw[p.n1-p.k:])
+}
+
+// Berlekamp-Massey algorithm: returns the error locator polynomial,
+// of degree at most δ, from the 2δ syndromes s. Runs in constant time.
+func (p *Params) errorLocator(s []byte) []byte {
+	t := p.delta
+	C := make([]byte, t+1)
+	B := make([]byte, t+1)
+	T := make([]byte, t+1)
+
+	var L uint16
+	b := byte(1)
+
+	B[1] = 1
+	C[0] = 1
+
+	for N := uint16(0); N < uint16(2*t); N++ {
+		var d byte
+		for i := 0; i <= min(int(N), t); i++ {
+			d ^= gfMul(C[i], s[int(N)-i])
+		}
+
+		// mne = 0xFF if d ≠ 0, and mle = 0xFF if moreover 2L ≤ N.
+		mne := byte(-((uint16(d)-1)>>15 ^ 1))
+		mle := byte(((N - 2*L) >> 15) - 1)
+		mle &= mne
+
+		copy(T, C)
+
+		f := gfMul(gfInv(b), d)
+		for i := range C {
+			C[i] ^= gfMul(f, B[i]) & mne
+		}
+
+		mle16 := uint16(mle) | uint16(mle)<<8
+		L = (L &^ mle16) | ((N + 1 - L) & mle16)
+
+		for i := range B {
+			B[i] = (B[i] &^ mle) | (T[i] & mle)
+		}
+		b = (b &^ mle) | (d & mle)
+
+		copy(B[1:], B[:t])
+		B[0] = 0
+	}
+
+	return C
+}
+
+// Returns a mask with all bits set to the bit b of x.
+func bitMask(x, b uint32) uint32 { return -((x >> b) & 1) }
+
+// Encodes the byte m with the first order Reed-Muller code RM(1, 7),
+// writing the 128 bits of the codeword to cdw.
+func rmEncodeByte(cdw []byte, m byte) {
+	x := uint32(m)
+
+	// Bit 7 flips all the bits, and bits 0 to 4 are the same for the
+	// four words.
+	w := bitMask(x, 7)
+	w ^= bitMask(x, 0) & 0xaaaaaaaa
+	w ^= bitMask(x, 1) & 0xcccccccc
+	w ^= bitMask(x, 2) & 0xf0f0f0f0
+	w ^= bitMask(x, 3) & 0xff00ff00
+	w ^= bitMask(x, 4) & 0xffff0000
+	binary.LittleEndian.PutUint32(cdw[0:], w)
+
+	// Bit 5 flips the words 1 and 3, and bit 6 the words 2 and 3.
+	w ^= bitMask(x, 5)
+	binary.LittleEndian.PutUint32(cdw[4:], w)
+	w ^= bitMask(x, 6)
+	binary.LittleEndian.PutUint32(cdw[12:], w)
+	w ^= bitMask(x, 5)
+	binary.LittleEndian.PutUint32(cdw[8:], w)
+}
+
+// Encodes each byte of msg with the Reed-Muller code, duplicated as many
+// times as required to get a codeword of n₁n₂ bits.
+func (p *Params) rmEncode(cdw, msg []byte) {
+	mult := p.multiplicity()
+	for i, m := range msg {
+		for j := 0; j < mult; j++ {
+			rmEncodeByte(cdw[16*(i*mult+j):], m)
+		}
+	}
+}
+
+// Decodes the duplicated Reed-Muller codewords of cdw into msg, by
+// finding the peak of the Hadamard transform of the sum of the copies.
+func (p *Params) rmDecode(msg, cdw []byte) {
+	mult := p.multiplicity()
+	var a, b [128]int16
+
+	for i := range msg {
+		// Count the number of ones of each bit across the copies.
+		for k := range a {
+			a[k] = 0
+		}
+		for j := 0; j < mult; j++ {
+			c := cdw[16*(i*mult+j):]
+			for k := range a {
+				a[k] += int16((c[k/8] >> (k % 8)) & 1)
+			}
+		}
+
+		// Fast Hadamard transform.
+		src, dst := &a, &b
+		for pass := 0; pass < 7; pass++ {
+			for k := 0; k < 64; k++ {
+				dst[k] = src[2*k] + src[2*k+1]
+				dst[k+64] = src[2*k] - src[2*k+1]
+			}
+			src, dst = dst, src
+		}
+		src[0] -= int16(64 * mult)
+
+		// Find the peak of the absolute value, in constant time. Its
+		// position gives bits 0 to 6 and its sign gives bit 7.
+		var peakAbs, peakVal, peakPos int32
+		for k, v := range src {
+			t := int32(v)
+			posMask := -((-t) >> 31 & 1)
+			abs := (posMask & t) | (^posMask & -t)
+			greater := (peakAbs - abs) >> 31
+			peakVal = (greater & t) | (^greater & peakVal)
+			peakPos = (greater & int32(k)) | (^greater & peakPos)
+			peakAbs = (greater & abs) | (^greater & peakAbs)
+		}
+		peakPos |= 128 & ((-peakVal) >> 31)
+		msg[i] = byte(peakPos)
+	}
+}
+
+// Encodes the message msg of k bytes into a codeword of the
+// concatenated Reed-Solomon and Reed-Muller code of n₁n₂ bits.
+func (p *Params) encode(cdw, msg []byte) {
+	tmp := make([]byte, p.n1)
+	p.rsEncode(tmp, msg)
+	p.rmEncode(cdw, tmp)
+}
+
+// Decodes the word cdw of n₁n₂ bits into the message msg of k bytes.
+func (p *Params) decode(msg, cdw []byte) {
+	tmp := make([]byte, p.n1)
+	p.rmDecode(tmp, cdw)
+	p.rsDecode(msg, tmp)
+}
@@ -0,0 +1,133 @@
+package internal
+
+import "crypto/subtle"
+
+// Returns the secret vector y and σ from the packed private key sk.
+func (p *Params) unpackPrivateKey(sk []byte) (y []uint64, sigma []byte) {
+	s := newSeedExpander(sk[:seedSize])
+	_ = p.randomFixedWeight(s, p.omega) // x
+	y = p.randomFixedWeight(s, p.omega)
+	return y, sk[seedSize : seedSize+p.k]
+}
+
+// KeyGen derives a key pair from the given seed of KeySeedSize bytes,
+// which is the concatenation of the secret key seed, σ and the public
+// key seed, and writes the packed keys to pk and sk.
+func (p *Params) KeyGen(pk, sk, seed []byte) {
+	if len(seed) != p.KeySeedSize() {
+		panic("seed must be of length KeySeedSize")
+	}
+	skSeed := seed[:seedSize]
+	sigma := seed[seedSize : seedSize+p.k]
+	pkSeed := seed[seedSize+p.k:]
+
+	sks := newSeedExpander(skSeed)
+	x := p.randomFixedWeight(sks, p.omega)
+	y := p.randomFixedWeight(sks, p.omega)
+
+	h := p.randomVector(newSeedExpander(pkSeed))
+
+	// s = x + h·y
+	s := p.mul(y, h)
+	add(s, x, s)
+
+	copy(pk, pkSeed)
+	store(pk[seedSize:p.PublicKeySize()], s)
+
+	copy(sk, skSeed)
+	copy(sk[seedSize:], sigma)
+	copy(sk[seedSize+p.k:], pk[:p.PublicKeySize()])
+}
+
+// Encrypts the message m with the public key pk using the randomness
+// theta, and writes u and v to ct.
+func (p *Params) encrypt(ct, pk, m, theta []byte) {
+	h := p.randomVector(newSeedExpander(pk[:seedSize]))
+	s := make([]uint64, p.nWords())
+	load(s, pk[seedSize:])
+
+	ts := newSeedExpander(theta[:seedSize])
+	r1 := p.randomFixedWeight(ts, p.omegaR)
+	r2 := p.randomFixedWeight(ts, p.omegaR)
+	e := p.randomFixedWeight(ts, p.omegaE)
+
+	// u = r₁ + h·r₂
+	u := p.mul(r2, h)
+	add(u, r1, u)
+
+	// v = mG + s·r₂ + e, truncated to n₁n₂ bits.
+	cdw := make([]byte, p.nBytes())
+	p.encode(cdw, m)
+	mG := make([]uint64, p.nWords())
+	load(mG, cdw)
+	v := p.mul(r2, s)
+	add(v, e, v)
+	add(v, mG, v)
+
+	store(ct[:p.nBytes()], u)
+	store(ct[p.nBytes():p.nBytes()+p.n1n2Bytes()], v)
+}
+
+// Decrypts the ciphertext ct, consisting of u and v, using the secret
+// vector y and writes the message to m.
+func (p *Params) decrypt(m, ct []byte, y []uint64) {
+	u := make([]uint64, p.nWords())
+	load(u, ct[:p.nBytes()])
+	u[len(u)-1] &= p.redMask()
+
+	// v - u·y
+	v := make([]uint64, p.nWords())
+	load(v, ct[p.nBytes():p.nBytes()+p.n1n2Bytes()])
+	w := p.mul(y, u)
+	add(w, v, w)
+
+	cdw := make([]byte, p.nBytes())
+	store(cdw, w)
+	p.decode(m, cdw[:p.n1n2Bytes()])
+}
+
+// Encapsulate encrypts the message m with salt, given by seed of
+// EncapsulationSeedSize bytes, to the packed public key pk and writes
+// the ciphertext to ct and the derived shared key to ss.
+func (p *Params) Encapsulate(ct, ss, pk, seed []byte) {
+	if len(seed) != p.EncapsulationSeedSize() {
+		panic("seed must be of length EncapsulationSeedSize")
+	}
+	m := seed[:p.k]
+	salt := seed[p.k:]
+
+	// θ = G(m, pk, salt)
+	var theta [64]byte
+	hashDS(theta[:], gDomain, m, pk, salt)
+
+	p.encrypt(ct, pk, m, theta[:])
+	copy(ct[p.nBytes()+p.n1n2Bytes():], salt)
+
+	// K = K(m, u, v)
+	hashDS(ss, kDomain, m, ct[:p.nBytes()+p.n1n2Bytes()])
+}
+
+// Decapsulate writes to ss the shared key encapsulated in ct for the
+// packed private key sk. If the ciphertext is not valid, a pseudorandom
+// key derived from σ is returned instead.
+func (p *Params) Decapsulate(ss, ct, sk []byte) {
+	y, sigma := p.unpackPrivateKey(sk)
+	pk := sk[seedSize+p.k:]
+	uv := ct[:p.nBytes()+p.n1n2Bytes()]
+	salt := ct[p.nBytes()+p.n1n2Bytes():]
+
+	m := make([]byte, p.k)
+	p.decrypt(m, ct, y)
+
+	// Re-encrypt m and compare the ciphertexts.
+	var theta [64]byte
+	hashDS(theta[:], gDomain, m, pk, salt)
+	ct2 := make([]byte, len(uv))
+	p.encrypt(ct2, pk, m, theta[:])
+
+	// On failure replace m by σ.
+	ok := subtle.ConstantTimeCompare(uv, ct2)
+	subtle.ConstantTimeCopy(1-ok, m, sigma)
+
+	hashDS(ss, kDomain, m, uv)
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+var allParams = []*Params{HQC128, HQC192, HQC256}
+
+func TestRSGeneratorPoly(t *testing.T) {
+	// Coefficients of the generator polynomial of HQC-128 as given in
+	// the specification.
+	want := []byte{
+		89, 69, 153, 116, 176, 117, 111, 75, 73, 233, 242, 233, 65, 210,
+		21, 139, 103, 173, 67, 118, 105, 210, 174, 110, 74, 69, 228, 82,
+		255, 181, 1,
+	}
+	if !bytes.Equal(HQC128.rsPoly, want) {
+		t.Fatalf("got %v", HQC128.rsPoly)
+	}
+}
+
+func TestCode(t *testing.T) {
+	for _, p := range allParams {
+		t.Run(p.Name, func(t *testing.T) {
+			msg := make([]byte, p.k)
+			_, _ = rand.Read(msg)
+
+			cdw := make([]byte, p.n1n2Bytes())
+			p.encode(cdw, msg)
+
+			// Corrupt δ Reed-Solomon symbols beyond the capacity of the
+			// Reed-Muller code, and a few more bits spread over the word.
+			for i := 0; i < p.delta; i++ {
+				for j := 0; j < 16*p.multiplicity(); j++ {
+					cdw[16*p.multiplicity()*(2*i)+j] ^= 0xFF
+				}
+			}
+			for i := 0; i < 100; i++ {
+				cdw[(i*37)%len(cdw)] ^= 1 << (i % 8)
+			}
+
+			msg2 := make([]byte, p.k)
+			p.decode(msg2, cdw)
+			if !bytes.Equal(msg, msg2) {
+				t.Fatal("decoding failed")
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, p := range allParams {
+		t.Run(p.Name, func(t *testing.T) {
+			seed := make([]byte, p.KeySeedSize())
+			_, _ = rand.Read(seed)
+			pk := make([]byte, p.PublicKeySize())
+			sk := make([]byte, p.PrivateKeySize())
+			p.KeyGen(pk, sk, seed)
+
+			for i := 0; i < 10; i++ {
+				eseed := make([]byte, p.EncapsulationSeedSize())
+				_, _ = rand.Read(eseed)
+				ct := make([]byte, p.CiphertextSize())
+				ss := make([]byte, p.SharedKeySize())
+				p.Encapsulate(ct, ss, pk, eseed)
+
+				ss2 := make([]byte, p.SharedKeySize())
+				p.Decapsulate(ss2, ct, sk)
+				if !bytes.Equal(ss, ss2) {
+					t.Fatal("shared keys differ")
+				}
+
+				ct[i] ^= 1
+				p.Decapsulate(ss2, ct, sk)
+				if bytes.Equal(ss, ss2) {
+					t.Fatal("shared keys match for a modified ciphertext")
+				}
+			}
+		})
+	}
+}
+
+func TestMul(t *testing.T) {
+	p := HQC128
+	buf := make([]byte, 2*p.nBytes())
+	_, _ = rand.Read(buf)
+	a := make([]uint64, p.nWords())
+	b := make([]uint64, p.nWords())
+	load(a, buf[:p.nBytes()])
+	load(b, buf[p.nBytes():])
+	a[len(a)-1] &= p.redMask()
+	b[len(b)-1] &= p.redMask()
+
+	// Schoolbook multiplication, bit by bit.
+	want := make([]uint64, p.nWords())
+	for i := 0; i < p.n; i++ {
+		if (a[i/64]>>(i%64))&1 == 0 {
+			continue
+		}
+		for j := 0; j < p.n; j++ {
+			if (b[j/64]>>(j%64))&1 == 1 {
+				k := (i + j) % p.n
+				want[k/64] ^= 1 << (k % 64)
+			}
+		}
+	}
+
+	got := p.mul(a, b)
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatal("multiplication mismatch")
+		}
+	}
+}
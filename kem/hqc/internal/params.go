@@ -0,0 +1,81 @@
+// Package internal implements the HQC KEM for all parameter sets, which
+// are described by a Params value.
+package internal
+
+// Params describes an HQC parameter set.
+type Params struct {
+	// Name of the parameter set, as in the NIST submission.
+	Name string
+
+	n      int // Length of the ambient space, a prime.
+	n1     int // Length of the Reed-Solomon code.
+	n2     int // Length of the duplicated Reed-Muller code.
+	k      int // Size in bytes of the message, the dimension of the RS code.
+	delta  int // Number of errors the Reed-Solomon code corrects.
+	omega  int // Weight of the secret vectors x and y.
+	omegaR int // Weight of the vectors r₁ and r₂.
+	omegaE int // Weight of the vector e.
+
+	// Coefficients of the generator polynomial of the Reed-Solomon code,
+	// from degree 0 to 2δ.
+	rsPoly []byte
+}
+
+// The parameter sets of the HQC NIST round 4 submission.
+var (
+	HQC128 = newParams(&Params{
+		Name: "HQC-128", n: 17669, n1: 46, n2: 384, k: 16, delta: 15,
+		omega: 66, omegaR: 75, omegaE: 75,
+	})
+	HQC192 = newParams(&Params{
+		Name: "HQC-192", n: 35851, n1: 56, n2: 640, k: 24, delta: 16,
+		omega: 100, omegaR: 114, omegaE: 114,
+	})
+	HQC256 = newParams(&Params{
+		Name: "HQC-256", n: 57637, n1: 90, n2: 640, k: 32, delta: 29,
+		omega: 131, omegaR: 149, omegaE: 149,
+	})
+)
+
+func newParams(p *Params) *Params {
+	p.rsPoly = rsGeneratorPoly(2 * p.delta)
+	return p
+}
+
+const (
+	seedSize = 40 // Size of the seeds of the seed expanders.
+	saltSize = 16 // Size of the salt of the ciphertext.
+)
+
+// Size in bytes of a vector of n bits.
+func (p *Params) nBytes() int { return (p.n + 7) / 8 }
+
+// Size in words of a vector of n bits.
+func (p *Params) nWords() int { return (p.n + 63) / 64 }
+
+// Size in bytes of a vector of n₁n₂ bits.
+func (p *Params) n1n2Bytes() int { return p.n1 * p.n2 / 8 }
+
+// Number of copies of each Reed-Muller codeword.
+func (p *Params) multiplicity() int { return p.n2 / 128 }
+
+// PublicKeySize returns the size of a packed public key.
+func (p *Params) PublicKeySize() int { return seedSize + p.nBytes() }
+
+// PrivateKeySize returns the size of a packed private key.
+func (p *Params) PrivateKeySize() int { return seedSize + p.k + p.PublicKeySize() }
+
+// CiphertextSize returns the size of a ciphertext.
+func (p *Params) CiphertextSize() int { return p.nBytes() + p.n1n2Bytes() + saltSize }
+
+// SharedKeySize returns the size of the shared key.
+func (p *Params) SharedKeySize() int { return 64 }
+
+// KeySeedSize returns the size of the seed used to derive a key pair,
+// which consists of the secret key seed, σ and the public key seed.
+func (p *Params) KeySeedSize() int { return 2*seedSize + p.k }
+
+// EncapsulationSeedSize returns the size of the seed used in
+// deterministic encapsulation, which consists of the message m and
+// the salt.
+func (p *Params) EncapsulationSeedSize() int { return p.k + saltSize }
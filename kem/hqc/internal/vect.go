@@ -0,0 +1,242 @@
+package internal
+
+import (
+	"encoding/binary"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// Domain separators of the seed expander and of the hash functions
+// G and K.
+const (
+	seedExpanderDomain = 2
+	gDomain            = 3
+	kDomain            = 5
+)
+
+// An XOF expanding a seed, which outputs data in multiples of eight bytes.
+type seedExpander struct {
+	h sha3.State
+}
+
+func newSeedExpander(seed []byte) *seedExpander {
+	s := &seedExpander{h: sha3.NewShake256()}
+	_, _ = s.h.Write(seed)
+	_, _ = s.h.Write([]byte{seedExpanderDomain})
+	return s
+}
+
+// Fills out, discarding the output up to the next multiple of eight bytes.
+func (s *seedExpander) read(out []byte) {
+	_, _ = s.h.Read(out)
+	if r := len(out) % 8; r != 0 {
+		var tmp [8]byte
+		_, _ = s.h.Read(tmp[:8-r])
+	}
+}
+
+// Computes SHAKE256 on the concatenation of the inputs followed by the
+// domain separator and writes 64 bytes to out.
+func hashDS(out []byte, domain byte, in ...[]byte) {
+	h := sha3.NewShake256()
+	for _, b := range in {
+		_, _ = h.Write(b)
+	}
+	_, _ = h.Write([]byte{domain})
+	_, _ = h.Read(out[:64])
+}
+
+// Returns a mask of the bits of the last word of a vector of n bits.
+func (p *Params) redMask() uint64 {
+	if p.n%64 == 0 {
+		return ^uint64(0)
+	}
+	return 1<<(p.n%64) - 1
+}
+
+// Loads the little-endian bytes of src into the words of dst.
+func load(dst []uint64, src []byte) {
+	for i := range dst {
+		var buf [8]byte
+		copy(buf[:], src[min(8*i, len(src)):])
+		dst[i] = binary.LittleEndian.Uint64(buf[:])
+	}
+}
+
+// Stores the words of src into dst as little-endian bytes.
+func store(dst []byte, src []uint64) {
+	for i := 0; 8*i < len(dst); i++ {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], src[i])
+		copy(dst[8*i:], buf[:])
+	}
+}
+
+// Returns a uniformly random vector of n bits.
+func (p *Params) randomVector(s *seedExpander) []uint64 {
+	buf := make([]byte, p.nBytes())
+	s.read(buf)
+	v := make([]uint64, p.nWords())
+	load(v, buf)
+	v[len(v)-1] &= p.redMask()
+	return v
+}
+
+// Returns 1 if a = b, and 0 otherwise.
+func eq32(a, b uint32) uint32 {
+	return 1 ^ ((a-b)|(b-a))>>31
+}
+
+// Returns a random vector of n bits of the given Hamming weight, in
+// constant time.
+func (p *Params) randomFixedWeight(s *seedExpander, weight int) []uint64 {
+	buf := make([]byte, 4*weight)
+	s.read(buf)
+
+	// Generate distinct positions as in the Fisher-Yates shuffle.
+	support := make([]uint32, weight)
+	for i := range support {
+		r := binary.LittleEndian.Uint32(buf[4*i:])
+		support[i] = uint32(i) + reduce(r, uint32(p.n-i))
+	}
+	for i := weight - 2; i >= 0; i-- {
+		var found uint32
+		for j := i + 1; j < weight; j++ {
+			found |= eq32(support[j], support[i])
+		}
+		mask := -found
+		support[i] = (mask & uint32(i)) ^ (^mask & support[i])
+	}
+
+	v := make([]uint64, p.nWords())
+	for i := range v {
+		var val uint64
+		for j := range support {
+			mask := -uint64(eq32(uint32(i), support[j]>>6))
+			val |= (1 << (support[j] & 63)) & mask
+		}
+		v[i] = val
+	}
+	return v
+}
+
+// Returns a mod n for n < 2¹⁶, in constant time, using Barrett reduction.
+func reduce(a, n uint32) uint32 {
+	m := uint64(1<<32) / uint64(n)
+	q := uint32((uint64(a) * m) >> 32)
+	r := a - q*n
+	// r < 2n, so one conditional subtraction suffices.
+	r -= n & -(1 ^ ((r - n) >> 31))
+	return r
+}
+
+// Sets out = a ⊕ b.
+func add(out, a, b []uint64) {
+	for i := range out {
+		out[i] = a[i] ^ b[i]
+	}
+}
+
+// Returns the product a·b of vectors of n bits in GF(2)[x]/(xⁿ - 1).
+func (p *Params) mul(a, b []uint64) []uint64 {
+	words := p.nWords()
+	prod := make([]uint64, 2*words)
+	karatsuba(prod, a, b)
+
+	// Reduce modulo xⁿ - 1 by adding the bits n, ..., 2n-1 to the
+	// lower ones.
+	out := make([]uint64, words)
+	w, s := p.n/64, uint(p.n%64)
+	for i := range out {
+		v := prod[w+i] >> s
+		if s != 0 {
+			v |= prod[w+i+1] << (64 - s)
+		}
+		out[i] = prod[i] ^ v
+	}
+	out[words-1] &= p.redMask()
+	return out
+}
+
+// Sets out, of length 2·len(a), to the carry-less product of a and b,
+// which are of the same length.
+func karatsuba(out, a, b []uint64) {
+	n := len(a)
+	if n <= 16 {
+		for i := range out {
+			out[i] = 0
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				hi, lo := clmul(a[i], b[j])
+				out[i+j] ^= lo
+				out[i+j+1] ^= hi
+			}
+		}
+		return
+	}
+
+	h := (n + 1) / 2
+	a0, a1 := a[:h], a[h:]
+	b0, b1 := b[:h], b[h:]
+
+	// z₀ = a₀b₀ and z₂ = a₁b₁.
+	karatsuba(out[:2*h], a0, b0)
+	karatsuba(out[2*h:], a1, b1)
+
+	// z₁ = (a₀ + a₁)(b₀ + b₁) - z₀ - z₂.
+	sa := make([]uint64, h)
+	sb := make([]uint64, h)
+	copy(sa, a0)
+	copy(sb, b0)
+	for i := range a1 {
+		sa[i] ^= a1[i]
+		sb[i] ^= b1[i]
+	}
+	z1 := make([]uint64, 2*h)
+	karatsuba(z1, sa, sb)
+	for i := 0; i < 2*h; i++ {
+		z1[i] ^= out[i]
+	}
+	for i, z := range out[2*h:] {
+		z1[i] ^= z
+	}
+
+	for i := range z1 {
+		out[h+i] ^= z1[i]
+	}
+}
+
+// Returns the carry-less product of a and b as (hi, lo).
+func clmul(a, b uint64) (hi, lo uint64) {
+	a0, a1 := uint32(a), uint32(a>>32)
+	b0, b1 := uint32(b), uint32(b>>32)
+
+	l := clmul32(a0, b0)
+	h := clmul32(a1, b1)
+	m := clmul32(a0^a1, b0^b1) ^ l ^ h
+
+	return h ^ (m >> 32), l ^ (m << 32)
+}
+
+// Returns the carry-less product of a and b, in constant time, by
+// means of integer multiplications with holes in the operands that
+// absorb the carries.
+func clmul32(a, b uint32) uint64 {
+	x0 := uint64(a & 0x11111111)
+	x1 := uint64(a & 0x22222222)
+	x2 := uint64(a & 0x44444444)
+	x3 := uint64(a & 0x88888888)
+	y0 := uint64(b & 0x11111111)
+	y1 := uint64(b & 0x22222222)
+	y2 := uint64(b & 0x44444444)
+	y3 := uint64(b & 0x88888888)
+
+	z0 := (x0 * y0) ^ (x1 * y3) ^ (x2 * y2) ^ (x3 * y1)
+	z1 := (x0 * y1) ^ (x1 * y0) ^ (x2 * y3) ^ (x3 * y2)
+	z2 := (x0 * y2) ^ (x1 * y1) ^ (x2 * y0) ^ (x3 * y3)
+	z3 := (x0 * y3) ^ (x1 * y2) ^ (x2 * y1) ^ (x3 * y0)
+
+	return (z0 & 0x1111111111111111) | (z1 & 0x2222222222222222) |
+		(z2 & 0x4444444444444444) | (z3 & 0x8888888888888888)
+}
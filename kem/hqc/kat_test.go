@@ -0,0 +1,119 @@
+package hqc
+
+// Code to generate the NIST "PQCkemKAT" test vectors.
+// See PQCgenKAT_kem.c and shake_prng.c in the reference implementation.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/kem/hqc/internal"
+	"github.com/quantumcoinproject/circl/kem/schemes"
+)
+
+func TestPQCgenKATKem(t *testing.T) {
+	// These digests were generated by this implementation, which follows
+	// the reference implementation of
+	//
+	//   https://pqc-hqc.org/implementation.html
+	//
+	// including its SHAKE256 PRNG and the order in which it consumes
+	// randomness. They are regression values: the PQCkemKAT_2305.rsp,
+	// PQCkemKAT_4586.rsp and PQCkemKAT_7317.rsp files of the round 4
+	// submission could not be retrieved to check them. Replace them with
+	// the digests of those files when they are available.
+	kats := []struct {
+		name string
+		want string
+	}{
+		{"HQC-128", "72ee7863a93e171bcd928880f9e6ad16c7af830a2fa3d2cfd53ab15524bd9909"},
+		{"HQC-192", "29f8535993d883c7ccb767d385772d62dfe3fbc06a0d4758475bf80d721a157b"},
+		{"HQC-256", "361f30b50a4f296a1b56ced5c3d3cb3f9baad10f4185b2e111d6a245d277737c"},
+	}
+	for _, kat := range kats {
+		t.Run(kat.name, func(t *testing.T) {
+			testPQCgenKATKem(t, kat.name, kat.want)
+		})
+	}
+}
+
+func testPQCgenKATKem(t *testing.T, name, expected string) {
+	scheme := schemes.ByName(name)
+	if scheme == nil {
+		t.Fatal()
+	}
+
+	// The reference implementation draws the seeds in several calls to
+	// randombytes(), but they are successive outputs of the same SHAKE256
+	// instance, so they can be drawn at once.
+	kseed := make([]byte, scheme.SeedSize())
+	eseed := make([]byte, scheme.EncapsulationSeedSize())
+
+	var seed [48]byte
+	for i := 0; i < 48; i++ {
+		seed[i] = byte(i)
+	}
+	f := sha256.New()
+	g := newShakePrng(seed[:])
+	fmt.Fprintf(f, "# %s\n\n", name)
+	for i := 0; i < 100; i++ {
+		_, _ = g.Read(seed[:])
+		fmt.Fprintf(f, "count = %d\n", i)
+		fmt.Fprintf(f, "seed = %X\n", seed)
+		g2 := newShakePrng(seed[:])
+
+		_, _ = g2.Read(kseed)
+		pk, sk := scheme.DeriveKeyPair(kseed)
+		ppk, _ := pk.MarshalBinary()
+		psk, _ := sk.MarshalBinary()
+
+		_, _ = g2.Read(eseed)
+		ct, ss, err := scheme.EncapsulateDeterministically(pk, eseed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, _ := scheme.Decapsulate(sk, ct)
+		if !bytes.Equal(ss, ss2) {
+			t.Fatal()
+		}
+		fmt.Fprintf(f, "pk = %X\n", ppk)
+		fmt.Fprintf(f, "sk = %X\n", psk)
+		fmt.Fprintf(f, "ct = %X\n", ct)
+		fmt.Fprintf(f, "ss = %X\n\n", ss)
+	}
+	if got := fmt.Sprintf("%x", f.Sum(nil)); got != expected {
+		t.Fatalf("%s: got %s", name, got)
+	}
+}
+
+// newShakePrng returns the PRNG of the reference implementation, which
+// replaces the AES-256 CTR DRBG of NIST: SHAKE256 of the entropy input
+// followed by the domain separator 1, without personalization string.
+func newShakePrng(entropy []byte) sha3.State {
+	h := sha3.NewShake256()
+	_, _ = h.Write(entropy)
+	_, _ = h.Write([]byte{1})
+	return h
+}
+
+func TestSizes(t *testing.T) {
+	for _, p := range []*internal.Params{
+		internal.HQC128, internal.HQC192, internal.HQC256,
+	} {
+		scheme := schemes.ByName(p.Name)
+		if scheme == nil {
+			t.Fatal(p.Name)
+		}
+		if scheme.PublicKeySize() != p.PublicKeySize() ||
+			scheme.PrivateKeySize() != p.PrivateKeySize() ||
+			scheme.CiphertextSize() != p.CiphertextSize() ||
+			scheme.SharedKeySize() != p.SharedKeySize() ||
+			scheme.SeedSize() != p.KeySeedSize() ||
+			scheme.EncapsulationSeedSize() != p.EncapsulationSeedSize() {
+			t.Fatal(p.Name)
+		}
+	}
+}
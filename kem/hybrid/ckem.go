@@ -20,7 +20,11 @@ type cScheme struct {
 	curve ecdh.Curve
 }
 
-var p256Kem = &cScheme{ecdh.P256()}
+var (
+	p256Kem = &cScheme{ecdh.P256()}
+	p384Kem = &cScheme{ecdh.P384()}
+	p521Kem = &cScheme{ecdh.P521()}
+)
 
 func (sch cScheme) Name() string {
 	switch sch.curve {
@@ -131,9 +135,22 @@ func (sch cScheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
 	}
 	h := xof.SHAKE256.New()
 	_, _ = h.Write(seed)
-	privKey, err := sch.curve.GenerateKey(h)
-	if err != nil {
-		panic(err)
+
+	// We can't use curve.GenerateKey as it doesn't consume its reader
+	// deterministically. Instead, we sample the scalar by rejection as in
+	// DeriveKeyPair of RFC 9180 §7.1.3.
+	var privKey *ecdh.PrivateKey
+	buf := make([]byte, sch.PrivateKeySize())
+	for {
+		_, _ = h.Read(buf)
+		if sch.curve == ecdh.P521() {
+			buf[0] &= 0x01
+		}
+		var err error
+		privKey, err = sch.curve.NewPrivateKey(buf)
+		if err == nil {
+			break
+		}
 	}
 	pubKey := privKey.PublicKey()
 
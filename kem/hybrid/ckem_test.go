@@ -0,0 +1,43 @@
+package hybrid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/kem"
+)
+
+func TestNISTCurveDeriveKeyPair(t *testing.T) {
+	// Private keys derived from the all-zero seed. Before the scalar was
+	// sampled by rejection, DeriveKeyPair of P-256 used ecdh.GenerateKey,
+	// which reads one byte more than it needs half of the time, and gave
+	// either of
+	//
+	//   973e8283546a63723bc31d2619124f11db4658643336741df81757d5ad306222
+	//   f5d57c8283546a63723bc31d2619124f11db4658643336741df81757d5ad3062
+	//
+	// with Go 1.24 and later, and other keys with older versions of Go.
+	for _, tc := range []struct {
+		scheme kem.Scheme
+		want   string
+	}{
+		{P256(), "f5977c8283546a63723bc31d2619124f11db4658643336741df81757d5ad3062"},
+		{P384(), "eda313c95591a023a5b37f361c07a5753a92d3d0427459f34c7895d727d62816" +
+			"b3aa2224eb9d823127d4f9f8a30fd7a1"},
+		{P521(), "00838207f7a3088bf011c6d221a172bff9257c8f4b807ba9d4c851fd20263efb" +
+			"59030e08938aaea9e77b5aa2b13e1ab965ba8eb76b9b8cfac1ab088900a758cde78c"},
+	} {
+		seed := make([]byte, tc.scheme.SeedSize())
+		want := mustDecodeString(tc.want)
+		for i := 0; i < 16; i++ {
+			_, sk := tc.scheme.DeriveKeyPair(seed)
+			got, err := sk.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s: expected %x; got %x", tc.scheme.Name(), want, got)
+			}
+		}
+	}
+}
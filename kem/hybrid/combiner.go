@@ -0,0 +1,149 @@
+package hybrid
+
+import (
+	"crypto"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/kem"
+)
+
+// A Combiner derives the shared key of a hybrid KEM from the shared keys,
+// ciphertexts and public keys of its two component KEMs.
+type Combiner interface {
+	// Size of the combined shared key for the given component KEMs.
+	SharedKeySize(first, second kem.Scheme) int
+
+	// Combine returns the combined shared key. The public keys of the
+	// components are only available through pks, as computing them can be
+	// expensive on decapsulation.
+	Combine(ss1, ss2, ct1, ct2 []byte, pks func() (pk1, pk2 kem.PublicKey)) ([]byte, error)
+}
+
+// XWingLabel is the label used by the X-Wing combiner.
+var XWingLabel = []byte(`\./` + `/^\`)
+
+// New returns a hybrid KEM with the given name combining first and
+// second, whose shared keys are combined using combiner.
+//
+// Public keys, private keys and ciphertexts are the concatenation of those
+// of first and second. Keys are derived and encapsulation is done
+// deterministically by expanding the seed with SHAKE256, see the package
+// documentation.
+func New(name string, first, second kem.Scheme, combiner Combiner) kem.Scheme {
+	return &scheme{
+		name:     name,
+		first:    first,
+		second:   second,
+		combiner: combiner,
+	}
+}
+
+// ConcatCombiner returns the combiner which concatenates the shared keys
+// of the components, as used by the hybrid KEMs in TLS.
+func ConcatCombiner() Combiner { return concatCombiner{} }
+
+// HashCombiner returns the combiner which hashes the concatenated shared
+// keys of the components using hash, as used by the hybrid KEMs in OpenSSH.
+//
+// The hash function must be linked into the binary.
+func HashCombiner(hash crypto.Hash) Combiner { return hashCombiner{hash} }
+
+// XWingCombiner returns the combiner of X-Wing, which computes
+//
+//	SHA3-256(ss₁ ‖ ss₂ ‖ ct₂ ‖ pk₂ ‖ label)
+//
+// The first component should be the post-quantum KEM, and the second
+// the classical one. With ML-KEM-768, X25519 and XWingLabel this is the
+// combiner of X-Wing, though the key derivation differs, see xwing for
+// the full KEM.
+func XWingCombiner(label []byte) Combiner {
+	return xwingCombiner{append([]byte(nil), label...)}
+}
+
+// KDFCombiner returns the combiner which computes
+//
+//	hash(ss₁ ‖ ss₂ ‖ ct₁ ‖ ct₂ ‖ pk₁ ‖ pk₂ ‖ label)
+//
+// binding the shared key to both ciphertexts and public keys.
+//
+// The hash function must be linked into the binary.
+func KDFCombiner(hash crypto.Hash, label []byte) Combiner {
+	return kdfCombiner{hash, append([]byte(nil), label...)}
+}
+
+type concatCombiner struct{}
+
+func (concatCombiner) SharedKeySize(first, second kem.Scheme) int {
+	return first.SharedKeySize() + second.SharedKeySize()
+}
+
+func (concatCombiner) Combine(ss1, ss2, _, _ []byte,
+	_ func() (kem.PublicKey, kem.PublicKey),
+) ([]byte, error) {
+	ret := make([]byte, 0, len(ss1)+len(ss2))
+	return append(append(ret, ss1...), ss2...), nil
+}
+
+type hashCombiner struct{ hash crypto.Hash }
+
+func (c hashCombiner) SharedKeySize(_, _ kem.Scheme) int { return c.hash.Size() }
+
+func (c hashCombiner) Combine(ss1, ss2, _, _ []byte,
+	_ func() (kem.PublicKey, kem.PublicKey),
+) ([]byte, error) {
+	h := c.hash.New()
+	_, _ = h.Write(ss1)
+	_, _ = h.Write(ss2)
+	return h.Sum(nil), nil
+}
+
+type xwingCombiner struct{ label []byte }
+
+func (xwingCombiner) SharedKeySize(_, _ kem.Scheme) int { return 32 }
+
+func (c xwingCombiner) Combine(ss1, ss2, _, ct2 []byte,
+	pks func() (kem.PublicKey, kem.PublicKey),
+) ([]byte, error) {
+	_, pk2 := pks()
+	ppk2, err := pk2.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var ret [32]byte
+	h := sha3.New256()
+	_, _ = h.Write(ss1)
+	_, _ = h.Write(ss2)
+	_, _ = h.Write(ct2)
+	_, _ = h.Write(ppk2)
+	_, _ = h.Write(c.label)
+	_, _ = h.Read(ret[:])
+	return ret[:], nil
+}
+
+type kdfCombiner struct {
+	hash  crypto.Hash
+	label []byte
+}
+
+func (c kdfCombiner) SharedKeySize(_, _ kem.Scheme) int { return c.hash.Size() }
+
+func (c kdfCombiner) Combine(ss1, ss2, ct1, ct2 []byte,
+	pks func() (kem.PublicKey, kem.PublicKey),
+) ([]byte, error) {
+	pk1, pk2 := pks()
+	ppk1, err := pk1.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	ppk2, err := pk2.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	h := c.hash.New()
+	for _, x := range [][]byte{ss1, ss2, ct1, ct2, ppk1, ppk2, c.label} {
+		_, _ = h.Write(x)
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,144 @@
+package hybrid_test
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha512"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/hqc/hqc128"
+	"github.com/quantumcoinproject/circl/kem/hybrid"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem1024"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+	"github.com/quantumcoinproject/circl/kem/xwing"
+)
+
+func TestNew(t *testing.T) {
+	combiners := []struct {
+		name     string
+		combiner hybrid.Combiner
+		size     func(first, second kem.Scheme) int
+	}{
+		{"concat", hybrid.ConcatCombiner(), func(first, second kem.Scheme) int {
+			return first.SharedKeySize() + second.SharedKeySize()
+		}},
+		{"hash", hybrid.HashCombiner(crypto.SHA384), nil},
+		{"xwing", hybrid.XWingCombiner([]byte("test")), nil},
+		{"kdf", hybrid.KDFCombiner(crypto.SHA512, []byte("test")), nil},
+	}
+	pairs := []struct{ first, second kem.Scheme }{
+		{mlkem1024.Scheme(), hybrid.P384()},
+		{hqc128.Scheme(), hybrid.X448()},
+	}
+
+	for _, pair := range pairs {
+		for _, c := range combiners {
+			name := pair.first.Name() + "-" + pair.second.Name() + "-" + c.name
+			t.Run(name, func(t *testing.T) {
+				scheme := hybrid.New(name, pair.first, pair.second, c.combiner)
+				testScheme(t, scheme)
+
+				if scheme.Name() != name {
+					t.Fatal()
+				}
+				if c.size != nil &&
+					scheme.SharedKeySize() != c.size(pair.first, pair.second) {
+					t.Fatal()
+				}
+			})
+		}
+	}
+}
+
+func testScheme(t *testing.T, scheme kem.Scheme) {
+	seed := make([]byte, scheme.SeedSize())
+	eseed := make([]byte, scheme.EncapsulationSeedSize())
+	for i := range eseed {
+		eseed[i] = byte(i)
+	}
+
+	pk, sk := scheme.DeriveKeyPair(seed)
+	ppk, _ := pk.MarshalBinary()
+	psk, _ := sk.MarshalBinary()
+	if len(ppk) != scheme.PublicKeySize() || len(psk) != scheme.PrivateKeySize() {
+		t.Fatal("key sizes")
+	}
+
+	pk2, err := scheme.UnmarshalBinaryPublicKey(ppk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, err := scheme.UnmarshalBinaryPrivateKey(psk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(pk2) || !sk.Equal(sk2) || !sk2.Public().Equal(pk) {
+		t.Fatal("keys differ after unmarshalling")
+	}
+
+	ct, ss, err := scheme.EncapsulateDeterministically(pk2, eseed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ct) != scheme.CiphertextSize() || len(ss) != scheme.SharedKeySize() {
+		t.Fatal("ciphertext or shared key size")
+	}
+	ct2, ss2, err := scheme.EncapsulateDeterministically(pk, eseed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ct, ct2) || !bytes.Equal(ss, ss2) {
+		t.Fatal("encapsulation is not deterministic")
+	}
+
+	ss3, err := scheme.Decapsulate(sk2, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ss, ss3) {
+		t.Fatal("shared keys differ")
+	}
+
+	ct4, ss4, err := scheme.Encapsulate(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss5, err := scheme.Decapsulate(sk, ct4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ss4, ss5) {
+		t.Fatal("shared keys differ")
+	}
+}
+
+// Checks that ML-KEM-768 and X25519 with the X-Wing combiner interoperate
+// with X-Wing on keys generated by the latter.
+func TestXWingCombiner(t *testing.T) {
+	scheme := hybrid.New("X-Wing", mlkem768.Scheme(), hybrid.X25519(),
+		hybrid.XWingCombiner(hybrid.XWingLabel))
+
+	if scheme.PublicKeySize() != xwing.PublicKeySize ||
+		scheme.CiphertextSize() != xwing.CiphertextSize ||
+		scheme.SharedKeySize() != xwing.SharedKeySize {
+		t.Fatal("sizes differ from X-Wing")
+	}
+
+	sk, ppk, err := xwing.GenerateKeyPairPacked(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := scheme.UnmarshalBinaryPublicKey(ppk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ct, ss, err := scheme.Encapsulate(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss2 := xwing.Decapsulate(ct, sk); !bytes.Equal(ss, ss2) {
+		t.Fatalf("shared key differs from X-Wing: %x ≠ %x", ss, ss2)
+	}
+}
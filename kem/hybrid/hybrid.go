@@ -9,6 +9,14 @@
 //
 // Note that this approach is not proven secure in broader context.
 //
+// The hybrid KEMs used for key exchange in OpenSSH instead hash the
+// concatenated shared secrets, see SNTRUP761X25519SHA512 and
+// MLKEM768X25519SHA256.
+//
+// Other combinations of any two KEMs can be built with New, which takes
+// the Combiner to derive the shared key with. The classical components
+// are available as X25519, X448, P256, P384 and P521.
+//
 // For deriving a KEM keypair deterministically and encapsulating
 // deterministically, we expand a single seed to both using SHAKE256,
 // so that a non-uniform seed (such as a shared secret generated by a hybrid
@@ -31,6 +39,9 @@
 package hybrid
 
 import (
+	"crypto"
+	_ "crypto/sha256" // for the OpenSSH hybrids
+	_ "crypto/sha512"
 	"errors"
 
 	"github.com/quantumcoinproject/circl/internal/sha3"
@@ -38,7 +49,9 @@ import (
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber1024"
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber512"
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber768"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem1024"
 	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+	"github.com/quantumcoinproject/circl/kem/sntrup761"
 )
 
 var ErrUninitialized = errors.New("public or private key not initialized")
@@ -56,46 +69,121 @@ func Kyber768X448() kem.Scheme { return kyber768X4 }
 func Kyber1024X448() kem.Scheme { return kyber1024X }
 
 // Returns the hybrid KEM of Kyber768Draft00 and P-256.
+//
+// Its DeriveKeyPair and EncapsulateDeterministically derive other P-256
+// keys than in earlier releases, which used ecdh.GenerateKey. That
+// function does not read its random source deterministically, so a seed
+// did not determine the keys before either. Encoded keys are unchanged.
 func P256Kyber768Draft00() kem.Scheme { return p256Kyber768Draft00 }
 
 // Returns the hybrid KEM of ML-KEM-768 and X25519.
 // https://www.ietf.org/archive/id/draft-kwiatkowski-tls-ecdhe-mlkem-01.html
 func X25519MLKEM768() kem.Scheme { return xmlkem768 }
 
+// Returns the hybrid KEM of P-256 and ML-KEM-768.
+// https://datatracker.ietf.org/doc/draft-kwiatkowski-tls-ecdhe-mlkem/
+func P256MLKEM768() kem.Scheme { return p256mlkem768 }
+
+// Returns the hybrid KEM of P-384 and ML-KEM-1024.
+// https://datatracker.ietf.org/doc/draft-kwiatkowski-tls-ecdhe-mlkem/
+func P384MLKEM1024() kem.Scheme { return p384mlkem1024 }
+
+// Returns the hybrid KEM of sntrup761 and X25519 used by the OpenSSH key
+// exchange method sntrup761x25519-sha512. Its shared key is the SHA-512
+// hash of the concatenated shared keys.
+// https://datatracker.ietf.org/doc/draft-ietf-sshm-ntruprime-ssh/
+func SNTRUP761X25519SHA512() kem.Scheme { return sntrup761X }
+
+// Returns the hybrid KEM of ML-KEM-768 and X25519 used by the OpenSSH key
+// exchange method mlkem768x25519-sha256. Its shared key is the SHA-256
+// hash of the concatenated shared keys.
+// https://datatracker.ietf.org/doc/draft-ietf-sshm-mlkem-hybrid-kex/
+func MLKEM768X25519SHA256() kem.Scheme { return mlkem768XSSH }
+
+// Returns X25519 as a KEM, for use as a component of a hybrid KEM.
+// The ciphertext is an ephemeral public key and the shared key is the
+// raw Diffie-Hellman output.
+func X25519() kem.Scheme { return x25519Kem }
+
+// Returns X448 as a KEM, see X25519.
+func X448() kem.Scheme { return x448Kem }
+
+// Returns ECDH on P-256 as a KEM, see X25519. Public keys and ciphertexts
+// are uncompressed points and the shared key is the x-coordinate.
+func P256() kem.Scheme { return p256Kem }
+
+// Returns ECDH on P-384 as a KEM, see P256.
+func P384() kem.Scheme { return p384Kem }
+
+// Returns ECDH on P-521 as a KEM, see P256.
+func P521() kem.Scheme { return p521Kem }
+
 var p256Kyber768Draft00 kem.Scheme = &scheme{
-	"P256Kyber768Draft00",
-	p256Kem,
-	kyber768.Scheme(),
+	name:   "P256Kyber768Draft00",
+	first:  p256Kem,
+	second: kyber768.Scheme(),
+	tlsID:  0xfe32,
 }
 
 var kyber512X kem.Scheme = &scheme{
-	"Kyber512-X25519",
-	x25519Kem,
-	kyber512.Scheme(),
+	name:   "Kyber512-X25519",
+	first:  x25519Kem,
+	second: kyber512.Scheme(),
+	tlsID:  0xfe30,
 }
 
 var kyber768X kem.Scheme = &scheme{
-	"Kyber768-X25519",
-	x25519Kem,
-	kyber768.Scheme(),
+	name:   "Kyber768-X25519",
+	first:  x25519Kem,
+	second: kyber768.Scheme(),
+	tlsID:  0x6399,
 }
 
 var kyber768X4 kem.Scheme = &scheme{
-	"Kyber768-X448",
-	x448Kem,
-	kyber768.Scheme(),
+	name:   "Kyber768-X448",
+	first:  x448Kem,
+	second: kyber768.Scheme(),
 }
 
 var kyber1024X kem.Scheme = &scheme{
-	"Kyber1024-X448",
-	x448Kem,
-	kyber1024.Scheme(),
+	name:   "Kyber1024-X448",
+	first:  x448Kem,
+	second: kyber1024.Scheme(),
 }
 
 var xmlkem768 kem.Scheme = &scheme{
-	"X25519MLKEM768",
-	mlkem768.Scheme(),
-	x25519Kem,
+	name:   "X25519MLKEM768",
+	first:  mlkem768.Scheme(),
+	second: x25519Kem,
+	tlsID:  0x11ec,
+}
+
+var p256mlkem768 kem.Scheme = &scheme{
+	name:   "SecP256r1MLKEM768",
+	first:  p256Kem,
+	second: mlkem768.Scheme(),
+	tlsID:  0x11eb,
+}
+
+var p384mlkem1024 kem.Scheme = &scheme{
+	name:   "SecP384r1MLKEM1024",
+	first:  p384Kem,
+	second: mlkem1024.Scheme(),
+	tlsID:  0x11ed,
+}
+
+var sntrup761X kem.Scheme = &scheme{
+	name:     "sntrup761x25519-sha512",
+	first:    sntrup761.Scheme(),
+	second:   x25519Kem,
+	combiner: hashCombiner{crypto.SHA512},
+}
+
+var mlkem768XSSH kem.Scheme = &scheme{
+	name:     "mlkem768x25519-sha256",
+	first:    mlkem768.Scheme(),
+	second:   x25519Kem,
+	combiner: hashCombiner{crypto.SHA256},
 }
 
 // Public key of a hybrid KEM.
@@ -117,9 +205,27 @@ type scheme struct {
 	name   string
 	first  kem.Scheme
 	second kem.Scheme
+
+	// Combines the shared keys. If nil, they are concatenated.
+	combiner Combiner
+
+	// NamedGroup codepoint in TLS, if any.
+	tlsID uint
+}
+
+func (sch *scheme) getCombiner() Combiner {
+	if sch.combiner == nil {
+		return concatCombiner{}
+	}
+	return sch.combiner
 }
 
 func (sch *scheme) Name() string { return sch.name }
+
+// TLSIdentifier returns the TLS NamedGroup codepoint of the hybrid KEM,
+// or 0 if it has none, as is the case for those returned by New.
+func (sch *scheme) TLSIdentifier() uint { return sch.tlsID }
+
 func (sch *scheme) PublicKeySize() int {
 	return sch.first.PublicKeySize() + sch.second.PublicKeySize()
 }
@@ -139,7 +245,7 @@ func (sch *scheme) SeedSize() int {
 }
 
 func (sch *scheme) SharedKeySize() int {
-	return sch.first.SharedKeySize() + sch.second.SharedKeySize()
+	return sch.getCombiner().SharedKeySize(sch.first, sch.second)
 }
 
 func (sch *scheme) CiphertextSize() int {
@@ -206,6 +312,11 @@ func (pk *publicKey) Equal(other kem.PublicKey) bool {
 	return pk.first.Equal(oth.first) && pk.second.Equal(oth.second)
 }
 
+// Returns the public keys of the components.
+func (pk *publicKey) components() (kem.PublicKey, kem.PublicKey) {
+	return pk.first, pk.second
+}
+
 func (pk *publicKey) MarshalBinary() ([]byte, error) {
 	if pk.first == nil || pk.second == nil {
 		return nil, ErrUninitialized
@@ -267,7 +378,11 @@ func (sch *scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
 		return nil, nil, err
 	}
 
-	return append(ct1, ct2...), append(ss1, ss2...), nil
+	ss, err = sch.getCombiner().Combine(ss1, ss2, ct1, ct2, pub.components)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(ct1, ct2...), ss, nil
 }
 
 func (sch *scheme) EncapsulateDeterministically(
@@ -297,7 +412,11 @@ func (sch *scheme) EncapsulateDeterministically(
 	if err != nil {
 		return nil, nil, err
 	}
-	return append(ct1, ct2...), append(ss1, ss2...), nil
+	ss, err = sch.getCombiner().Combine(ss1, ss2, ct1, ct2, pub.components)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(ct1, ct2...), ss, nil
 }
 
 func (sch *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
@@ -311,15 +430,20 @@ func (sch *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
 	}
 
 	firstSize := sch.first.CiphertextSize()
-	ss1, err := sch.first.Decapsulate(priv.first, ct[:firstSize])
+	ct1, ct2 := ct[:firstSize], ct[firstSize:]
+	ss1, err := sch.first.Decapsulate(priv.first, ct1)
 	if err != nil {
 		return nil, err
 	}
-	ss2, err := sch.second.Decapsulate(priv.second, ct[firstSize:])
+	ss2, err := sch.second.Decapsulate(priv.second, ct2)
 	if err != nil {
 		return nil, err
 	}
-	return append(ss1, ss2...), nil
+	return sch.getCombiner().Combine(ss1, ss2, ct1, ct2, func() (
+		kem.PublicKey, kem.PublicKey,
+	) {
+		return priv.first.Public(), priv.second.Public()
+	})
 }
 
 func (sch *scheme) UnmarshalBinaryPublicKey(buf []byte) (kem.PublicKey, error) {
@@ -0,0 +1,168 @@
+package hybrid
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"os"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/dh/x25519"
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+	"golang.org/x/crypto/ssh"
+)
+
+// Checks that the shared key is derived as in OpenSSH's
+// kexsntrup761x25519.c and kexmlkem768x25519.c: the hash of the KEM
+// shared key followed by the raw X25519 shared secret.
+func TestOpenSSHSharedKey(t *testing.T) {
+	for _, tc := range []struct {
+		scheme kem.Scheme
+		hash   func() hash.Hash
+	}{
+		{SNTRUP761X25519SHA512(), sha512.New},
+		{MLKEM768X25519SHA256(), sha256.New},
+	} {
+		t.Run(tc.scheme.Name(), func(t *testing.T) {
+			pk, sk, err := tc.scheme.GenerateKeyPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ct, ss, err := tc.scheme.Encapsulate(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			priv := sk.(*privateKey)
+			kemSize := priv.first.Scheme().CiphertextSize()
+			ssKem, err := priv.first.Scheme().Decapsulate(priv.first, ct[:kemSize])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var xss, xpk, xsk x25519.Key
+			copy(xpk[:], ct[kemSize:])
+			copy(xsk[:], priv.second.(*xPrivateKey).key)
+			if !x25519.Shared(&xss, &xsk, &xpk) {
+				t.Fatal()
+			}
+
+			h := tc.hash()
+			_, _ = h.Write(ssKem)
+			_, _ = h.Write(xss[:])
+			if !bytes.Equal(ss, h.Sum(nil)) {
+				t.Fatal("shared key does not match OpenSSH derivation")
+			}
+			if len(ss) != tc.scheme.SharedKeySize() {
+				t.Fatal()
+			}
+		})
+	}
+}
+
+// Checks the shared key of MLKEM768X25519SHA256 against a key exchange
+// with OpenSSH 9.9, taken from the recording Client-KEX-mlkem768x25519-sha256
+// of golang.org/x/crypto/ssh. The client keys come from its deterministic
+// random source. The exchange hash, which covers the shared key, must match
+// the signature of the host key of the OpenSSH server.
+func TestOpenSSHTranscript(t *testing.T) {
+	buf, err := os.ReadFile("testdata/openssh-mlkem768x25519-sha256.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		ClientX25519Key string `json:"clientX25519Key"`
+		ClientMLKEMSeed string `json:"clientMLKEMSeed"`
+		ClientVersion   string `json:"clientVersion"`
+		ServerVersion   string `json:"serverVersion"`
+		ClientKexInit   string `json:"clientKexInit"`
+		ServerKexInit   string `json:"serverKexInit"`
+		HostKey         string `json:"hostKey"`
+		ClientPublicKey string `json:"clientPublicKey"`
+		ServerPublicKey string `json:"serverPublicKey"`
+		Signature       string `json:"signature"`
+	}
+	if err = json.Unmarshal(buf, &v); err != nil {
+		t.Fatal(err)
+	}
+	unhex := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	scheme := MLKEM768X25519SHA256()
+	_, skKem := mlkem768.NewKeyFromSeed(unhex(v.ClientMLKEMSeed))
+	packed, err := skKem.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := scheme.UnmarshalBinaryPrivateKey(
+		append(packed, unhex(v.ClientX25519Key)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := sk.Public().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pk, unhex(v.ClientPublicKey)) {
+		t.Fatal("public key does not match")
+	}
+	ss, err := scheme.Decapsulate(sk, unhex(v.ServerPublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Exchange hash of RFC 5656 §4, with the shared key encoded as a
+	// string, see draft-kampanakis-curdle-ssh-pq-ke.
+	h := sha256.New()
+	for _, b := range [][]byte{
+		[]byte(v.ClientVersion),
+		[]byte(v.ServerVersion),
+		unhex(v.ClientKexInit),
+		unhex(v.ServerKexInit),
+		unhex(v.HostKey),
+		pk,
+		unhex(v.ServerPublicKey),
+		ss,
+	} {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(b)))
+		_, _ = h.Write(b)
+	}
+
+	hostKey, err := ssh.ParsePublicKey(unhex(v.HostKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig ssh.Signature
+	if err = ssh.Unmarshal(unhex(v.Signature), &sig); err != nil {
+		t.Fatal(err)
+	}
+	if err = hostKey.Verify(h.Sum(nil), &sig); err != nil {
+		t.Fatalf("exchange hash does not match the signature: %v", err)
+	}
+}
+
+func TestOpenSSHLowOrderX25519Point(t *testing.T) {
+	scheme := SNTRUP761X25519SHA512()
+	pk, sk, err := scheme.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, _, err := scheme.Encapsulate(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patchHybridWithLowOrderX25519(ct)
+	if _, err = scheme.Decapsulate(sk, ct); err != kem.ErrPubKey {
+		t.Fatalf("Decapsulate error: expected %v; got %v", kem.ErrPubKey, err)
+	}
+}
@@ -0,0 +1,74 @@
+package hybrid
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem1024"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+)
+
+// Checks the share ordering and encoding of draft-kwiatkowski-tls-ecdhe-mlkem
+// for the NIST curve hybrids: the ECDH share (an uncompressed point) comes
+// first, followed by the ML-KEM share, and the shared secret is the ECDH
+// shared secret followed by the ML-KEM one.
+func TestSecPMLKEM(t *testing.T) {
+	for _, tc := range []struct {
+		scheme kem.Scheme
+		id     uint
+		curve  ecdh.Curve
+		mlkem  kem.Scheme
+	}{
+		{P256MLKEM768(), 0x11eb, ecdh.P256(), mlkem768.Scheme()},
+		{P384MLKEM1024(), 0x11ed, ecdh.P384(), mlkem1024.Scheme()},
+	} {
+		t.Run(tc.scheme.Name(), func(t *testing.T) {
+			if id := tc.scheme.(kem.TLSScheme).TLSIdentifier(); id != tc.id {
+				t.Fatalf("TLSIdentifier: expected %#x; got %#x", tc.id, id)
+			}
+
+			pk, sk, err := tc.scheme.GenerateKeyPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ppk, _ := pk.MarshalBinary()
+			ct, ss, err := tc.scheme.Encapsulate(pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ecSize := tc.scheme.PublicKeySize() - tc.mlkem.PublicKeySize()
+			if ppk[0] != 4 || ct[0] != 4 {
+				t.Fatal("ECDH share is not an uncompressed point")
+			}
+			ecPk, err := tc.curve.NewPublicKey(ppk[:ecSize])
+			if err != nil {
+				t.Fatal(err)
+			}
+			ecCt, err := tc.curve.NewPublicKey(ct[:ecSize])
+			if err != nil {
+				t.Fatal(err)
+			}
+			ecSk := sk.(*privateKey).first.(*cPrivateKey).key
+			if !ecSk.PublicKey().Equal(ecPk) {
+				t.Fatal("ECDH public key is not first")
+			}
+			ecSs, err := ecSk.ECDH(ecCt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mlSk := sk.(*privateKey).second
+			mlSs, err := tc.mlkem.Decapsulate(mlSk, ct[ecSize:])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(ss, append(ecSs, mlSs...)) {
+				t.Fatal("shared secret is not ECDH ‖ ML-KEM")
+			}
+		})
+	}
+}
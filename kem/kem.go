@@ -89,6 +89,15 @@ type AuthScheme interface {
 	AuthDecapsulate(skr PrivateKey, ct []byte, pks PublicKey) ([]byte, error)
 }
 
+// TLSScheme represents a KEM that is used as a supported group in TLS.
+type TLSScheme interface {
+	Scheme
+
+	// TLSIdentifier returns the NamedGroup codepoint of the KEM, or 0 if
+	// there is none.
+	TLSIdentifier() uint
+}
+
 var (
 	// ErrTypeMismatch is the error used if types of, for instance, private
 	// and public keys don't match
@@ -0,0 +1,23 @@
+//go:generate go run gen.go
+
+// Package mceliece implements the Classic McEliece key encapsulation
+// mechanism, as submitted to round 4 of the NIST PQC competition [1].
+//
+// Classic McEliece is a code-based KEM built on binary Goppa codes. Its
+// public keys are very large, between 255 KiB and 1.3 MiB, while its
+// ciphertexts are very small. The parameter sets mceliece348864,
+// mceliece460896, mceliece6688128, mceliece6960119 and mceliece8192128
+// are available, as well as their "f" variants, which use semi-systematic
+// public keys for faster key generation but are otherwise interoperable.
+//
+// As the public keys are so large, they can be written to an io.Writer
+// with WriteTo and read from an io.Reader with ReadPublicKey without an
+// intermediate buffer.
+//
+// This implementation follows the reference implementation [2].
+//
+// References:
+//
+//	[1] https://classic.mceliece.org/mceliece-spec-20221023.pdf
+//	[2] https://classic.mceliece.org/impl.html
+package mceliece
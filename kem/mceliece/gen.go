@@ -0,0 +1,94 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates wrappers from templates to prevent too much duplicated code
+// between the code for different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type Instance struct {
+	Name string
+	M    int
+	N    int
+	T    int
+}
+
+func (m Instance) Pkg() string { return m.Name }
+
+// Param returns the name of the corresponding parameter set in internal.
+func (m Instance) Param() string { return "M" + m.Name[1:] }
+
+// Semi returns whether the public key is in semi-systematic form.
+func (m Instance) Semi() bool { return strings.HasSuffix(m.Name, "f") }
+
+func (m Instance) Rows() int           { return m.M * m.T }
+func (m Instance) PublicKeySize() int  { return m.Rows() * ((m.N - m.Rows() + 7) / 8) }
+func (m Instance) CiphertextSize() int { return (m.Rows() + 7) / 8 }
+
+func (m Instance) PrivateKeySize() int {
+	return 40 + 2*m.T + (2*m.M-1)<<(m.M-4) + m.N/8
+}
+
+var (
+	Instances = []Instance{
+		{Name: "mceliece348864", M: 12, N: 3488, T: 64},
+		{Name: "mceliece348864f", M: 12, N: 3488, T: 64},
+		{Name: "mceliece460896", M: 13, N: 4608, T: 96},
+		{Name: "mceliece460896f", M: 13, N: 4608, T: 96},
+		{Name: "mceliece6688128", M: 13, N: 6688, T: 128},
+		{Name: "mceliece6688128f", M: 13, N: 6688, T: 128},
+		{Name: "mceliece6960119", M: 13, N: 6960, T: 119},
+		{Name: "mceliece6960119f", M: 13, N: 6960, T: 119},
+		{Name: "mceliece8192128", M: 13, N: 8192, T: 128},
+		{Name: "mceliece8192128f", M: 13, N: 8192, T: 128},
+	}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/mceliece.go from templates/pkg.templ.go
+func generatePackageFiles() {
+	tl, err := template.ParseFiles("templates/pkg.templ.go")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, mode := range Instances {
+		buf := new(bytes.Buffer)
+		err := tl.Execute(buf, mode)
+		if err != nil {
+			panic(err)
+		}
+
+		// Formating output code
+		code, err := format.Source(buf.Bytes())
+		if err != nil {
+			panic(fmt.Sprintf("error formating code: %v", err))
+		}
+
+		res := string(code)
+		offset := strings.Index(res, TemplateWarning)
+		if offset == -1 {
+			panic("Missing template warning in pkg.templ.go")
+		}
+		err = os.MkdirAll(mode.Pkg(), 0o755)
+		if err != nil {
+			panic(err)
+		}
+		err = os.WriteFile(mode.Pkg()+"/mceliece.go", []byte(res[offset:]), 0o644)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
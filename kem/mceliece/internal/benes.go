@@ -0,0 +1,236 @@
+package internal
+
+// Computes the control bits of a Beneš network implementing the
+// permutation pi of {0, ..., n-1}, with n = 2ʷ, following the algorithm
+// of Nassimi and Sahni as described in
+//
+//	https://cr.yp.to/papers/controlbits-20200923.pdf
+//
+// The (2w-1)n/2 control bits are written to out at positions pos,
+// pos+step, pos+2·step, ...; they must be zero beforehand. temp must
+// have room for 2n values.
+func cbRecursion(out []byte, pos, step int, pi []int16, w, n int, temp []int32) {
+	A := temp[:n]
+	B := temp[n : 2*n]
+
+	if w == 1 {
+		out[pos>>3] ^= byte(pi[0]) << (pos & 7)
+		return
+	}
+
+	for x := 0; x < n; x++ {
+		A[x] = (int32(pi[x]^1) << 16) | int32(pi[x^1])
+	}
+	sortInt32(A) // A = (id<<16) + pibar
+
+	for x := 0; x < n; x++ {
+		Ax := A[x]
+		px := Ax & 0xffff
+		cx := minInt32(px, int32(x))
+		B[x] = (px << 16) | cx
+	}
+	// B = (p<<16) + c
+
+	for x := 0; x < n; x++ {
+		A[x] = (A[x] << 16) | int32(x) // A = (pibar<<16) + id
+	}
+	sortInt32(A) // A = (id<<16) + pibar⁻¹
+
+	for x := 0; x < n; x++ {
+		A[x] = (A[x] << 16) + (B[x] >> 16) // A = (pibar⁻¹<<16) + pibar
+	}
+	sortInt32(A) // A = (id<<16) + pibar²
+
+	if w <= 10 {
+		for x := 0; x < n; x++ {
+			B[x] = ((A[x] & 0xffff) << 10) | (B[x] & 0x3ff)
+		}
+
+		for i := 1; i < w-1; i++ {
+			// B = (p<<10) + c
+
+			for x := 0; x < n; x++ {
+				A[x] = ((B[x] &^ 0x3ff) << 6) | int32(x) // A = (p<<16) + id
+			}
+			sortInt32(A) // A = (id<<16) + p⁻¹
+
+			for x := 0; x < n; x++ {
+				A[x] = (A[x] << 20) | B[x] // A = (p⁻¹<<20) + (p<<10) + c
+			}
+			sortInt32(A) // A = (id<<20) + (pp<<10) + cp
+
+			for x := 0; x < n; x++ {
+				ppcpx := A[x] & 0xfffff
+				ppcx := (A[x] & 0xffc00) | (B[x] & 0x3ff)
+				B[x] = minInt32(ppcx, ppcpx)
+			}
+		}
+		for x := 0; x < n; x++ {
+			B[x] &= 0x3ff
+		}
+	} else {
+		for x := 0; x < n; x++ {
+			B[x] = (A[x] << 16) | (B[x] & 0xffff)
+		}
+
+		for i := 1; i < w-1; i++ {
+			// B = (p<<16) + c
+
+			for x := 0; x < n; x++ {
+				A[x] = (B[x] &^ 0xffff) | int32(x)
+			}
+			sortInt32(A) // A = (id<<16) + p⁻¹
+
+			for x := 0; x < n; x++ {
+				A[x] = (A[x] << 16) | (B[x] & 0xffff)
+			}
+			// A = (p⁻¹<<16) + c
+
+			if i < w-2 {
+				for x := 0; x < n; x++ {
+					B[x] = (A[x] &^ 0xffff) | (B[x] >> 16)
+				}
+				// B = (p⁻¹<<16) + p
+				sortInt32(B) // B = (id<<16) + p⁻²
+				for x := 0; x < n; x++ {
+					B[x] = (B[x] << 16) | (A[x] & 0xffff)
+				}
+				// B = (p⁻²<<16) + c
+			}
+
+			sortInt32(A) // A = (id<<16) + cp
+			for x := 0; x < n; x++ {
+				cpx := (B[x] &^ 0xffff) | (A[x] & 0xffff)
+				B[x] = minInt32(B[x], cpx)
+			}
+		}
+		for x := 0; x < n; x++ {
+			B[x] &= 0xffff
+		}
+	}
+
+	for x := 0; x < n; x++ {
+		A[x] = (int32(pi[x]) << 16) + int32(x)
+	}
+	sortInt32(A) // A = (id<<16) + pi⁻¹
+
+	for j := 0; j < n/2; j++ {
+		x := 2 * j
+		fj := B[x] & 1      // f[j]
+		Fx := int32(x) + fj // F[x]
+		Fx1 := Fx ^ 1       // F[x+1]
+
+		out[pos>>3] ^= byte(fj) << (pos & 7)
+		pos += step
+
+		B[x] = (A[x] << 16) | Fx
+		B[x+1] = (A[x+1] << 16) | Fx1
+	}
+	// B = (pi⁻¹<<16) + F
+
+	sortInt32(B) // B = (id<<16) + F(pi)
+
+	pos += (2*w - 3) * step * (n / 2)
+
+	for k := 0; k < n/2; k++ {
+		y := 2 * k
+		lk := B[y] & 1      // l[k]
+		Ly := int32(y) + lk // L[y]
+		Ly1 := Ly ^ 1       // L[y+1]
+
+		out[pos>>3] ^= byte(lk) << (pos & 7)
+		pos += step
+
+		A[y] = (Ly << 16) | (B[y] & 0xffff)
+		A[y+1] = (Ly1 << 16) | (B[y+1] & 0xffff)
+	}
+	// A = (L<<16) + F(pi)
+
+	sortInt32(A) // A = (id<<16) + F(pi(L)) = (id<<16) + M
+
+	pos -= (2*w - 2) * step * (n / 2)
+
+	q := make([]int16, n)
+	for j := 0; j < n/2; j++ {
+		q[j] = int16((A[2*j] & 0xffff) >> 1)
+		q[j+n/2] = int16((A[2*j+1] & 0xffff) >> 1)
+	}
+
+	cbRecursion(out, pos, step*2, q[:n/2], w-1, n/2, temp)
+	cbRecursion(out, pos+step, step*2, q[n/2:], w-1, n/2, temp)
+}
+
+// Applies the layer of stride-2ˢ conditional swaps given by the control
+// bits cb to p.
+func layer(p []int16, cb []byte, s int) {
+	stride := 1 << s
+	index := 0
+
+	for i := 0; i < len(p); i += stride * 2 {
+		for j := 0; j < stride; j++ {
+			d := p[i+j] ^ p[i+j+stride]
+			m := -int16((cb[index>>3] >> (index & 7)) & 1)
+			d &= m
+			p[i+j] ^= d
+			p[i+j+stride] ^= d
+			index++
+		}
+	}
+}
+
+// Applies the Beneš network given by the control bits cb to p, which
+// must be of length 2ʷ.
+func applyBenes(p []int16, cb []byte, w int) {
+	layerSize := len(p) >> 4
+	for i := 0; i < w; i++ {
+		layer(p, cb, i)
+		cb = cb[layerSize:]
+	}
+	for i := w - 2; i >= 0; i-- {
+		layer(p, cb, i)
+		cb = cb[layerSize:]
+	}
+}
+
+// Returns the control bits of a Beneš network that implements the
+// permutation pi of {0, ..., 2ᵐ-1}.
+func (p *Params) controlBits(pi []int16) []byte {
+	n := 1 << p.m
+	out := make([]byte, p.condSize())
+	temp := make([]int32, 2*n)
+
+	cbRecursion(out, 0, 1, pi, p.m, n, temp)
+
+	// Check that the network indeed implements pi.
+	piTest := make([]int16, n)
+	for i := range piTest {
+		piTest[i] = int16(i)
+	}
+	applyBenes(piTest, out, p.m)
+
+	var diff int16
+	for i := range pi {
+		diff |= pi[i] ^ piTest[i]
+	}
+	if diff != 0 {
+		panic("mceliece: wrong control bits")
+	}
+
+	return out
+}
+
+// Returns the support (α₀, ..., αₙ₋₁) given by the control bits cb,
+// that is, αᵢ = bitrev(π(i)).
+func (p *Params) support(cb []byte) []gf {
+	L := make([]int16, 1<<p.m)
+	for i := range L {
+		L[i] = int16(p.bitrev(gf(i)))
+	}
+	applyBenes(L, cb, p.m)
+
+	ret := make([]gf, p.n)
+	for i := range ret {
+		ret[i] = gf(L[i])
+	}
+	return ret
+}
@@ -0,0 +1,115 @@
+package internal
+
+// An element of GF(2ᵐ), represented as a polynomial in z with the
+// coefficient of zⁱ in bit i.
+type gf = uint16
+
+func (p *Params) gfMask() gf { return gf(1<<p.m) - 1 }
+
+// Returns a·b in GF(2ᵐ). Runs in constant time.
+func (p *Params) gfMul(a, b gf) gf {
+	if p.m == 12 {
+		return gfMul12(a, b)
+	}
+	return gfMul13(a, b)
+}
+
+// Returns a·b in GF(2¹²) = GF(2)[z]/(z¹² + z³ + 1).
+func gfMul12(a, b gf) gf {
+	a32, b32 := uint32(a), uint32(b)
+	t := a32 * (b32 & 1)
+	for i := 1; i < 12; i++ {
+		t ^= a32 * (b32 & (1 << i))
+	}
+
+	r := t & 0x7FC000
+	t ^= r >> 9
+	t ^= r >> 12
+
+	r = t & 0x3000
+	t ^= r >> 9
+	t ^= r >> 12
+
+	return gf(t & 0xFFF)
+}
+
+// Returns a·b in GF(2¹³) = GF(2)[z]/(z¹³ + z⁴ + z³ + z + 1).
+func gfMul13(a, b gf) gf {
+	a32, b32 := uint32(a), uint32(b)
+	t := a32 * (b32 & 1)
+	for i := 1; i < 13; i++ {
+		t ^= a32 * (b32 & (1 << i))
+	}
+
+	r := t & 0x1FF0000
+	t ^= (r >> 9) ^ (r >> 10) ^ (r >> 12) ^ (r >> 13)
+
+	r = t & 0x000E000
+	t ^= (r >> 9) ^ (r >> 10) ^ (r >> 12) ^ (r >> 13)
+
+	return gf(t & 0x1FFF)
+}
+
+// Returns a² in GF(2ᵐ).
+func (p *Params) gfSq(a gf) gf { return p.gfMul(a, a) }
+
+// Returns a⁻¹ in GF(2ᵐ), computed as a^(2ᵐ-2), so that the inverse
+// of zero is zero.
+func (p *Params) gfInv(a gf) gf {
+	// t = a^(2ⁱ⁺¹-1) after the iteration i.
+	t := a
+	for i := 1; i < p.m-1; i++ {
+		t = p.gfMul(p.gfSq(t), a)
+	}
+	return p.gfSq(t)
+}
+
+// Returns num/den in GF(2ᵐ).
+func (p *Params) gfFrac(den, num gf) gf { return p.gfMul(p.gfInv(den), num) }
+
+// Returns a mask covering all the bits of an element if a is zero,
+// and zero otherwise.
+func gfIsZero(a gf) gf {
+	t := uint32(a)
+	t--
+	t >>= 19
+	return gf(t)
+}
+
+// Reverses the order of the m bits of a.
+func (p *Params) bitrev(a gf) gf {
+	a = ((a & 0x00FF) << 8) | ((a & 0xFF00) >> 8)
+	a = ((a & 0x0F0F) << 4) | ((a & 0xF0F0) >> 4)
+	a = ((a & 0x3333) << 2) | ((a & 0xCCCC) >> 2)
+	a = ((a & 0x5555) << 1) | ((a & 0xAAAA) >> 1)
+	return a >> (16 - p.m)
+}
+
+// Returns f(a), where f is a polynomial of degree t over GF(2ᵐ) given
+// by its t+1 coefficients.
+func (p *Params) eval(f []gf, a gf) gf {
+	r := f[p.t]
+	for i := p.t - 1; i >= 0; i-- {
+		r = p.gfMul(r, a) ^ f[i]
+	}
+	return r
+}
+
+// Sets out to a·b in GF(2ᵐᵗ) = GF(2ᵐ)[y]/F(y).
+func (p *Params) polyMul(out, a, b []gf) {
+	prod := make([]gf, 2*p.t-1)
+
+	for i := 0; i < p.t; i++ {
+		for j := 0; j < p.t; j++ {
+			prod[i+j] ^= p.gfMul(a[i], b[j])
+		}
+	}
+
+	for i := 2*p.t - 2; i >= p.t; i-- {
+		for _, tm := range p.irrTerms {
+			prod[i-p.t+tm.deg] ^= p.gfMul(prod[i], tm.coef)
+		}
+	}
+
+	copy(out, prod[:p.t])
+}
@@ -0,0 +1,286 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// Generates a random error vector e of weight t using randomness
+// from rand.
+func (p *Params) genE(e []byte, rand io.Reader) error {
+	ind := make([]uint16, p.t)
+
+	// If n = 2ᵐ, every index is valid. Otherwise we sample twice as
+	// many indices as necessary and keep those that are in range.
+	samples := p.t
+	if p.n != 1<<p.m {
+		samples = 2 * p.t
+	}
+	buf := make([]byte, 2*samples)
+
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return err
+		}
+
+		count := 0
+		for i := 0; i < samples && count < p.t; i++ {
+			num := binary.LittleEndian.Uint16(buf[2*i:]) & p.gfMask()
+			if int(num) < p.n {
+				ind[count] = num
+				count++
+			}
+		}
+		if count < p.t {
+			continue
+		}
+
+		// Check for repetitions.
+		eq := false
+		for i := 1; i < p.t; i++ {
+			for j := 0; j < i; j++ {
+				if ind[i] == ind[j] {
+					eq = true
+				}
+			}
+		}
+		if !eq {
+			break
+		}
+	}
+
+	for i := range e {
+		e[i] = 0
+		for j := 0; j < p.t; j++ {
+			mask := byte(sameMask(uint64(i), uint64(ind[j]>>3)))
+			e[i] |= (1 << (ind[j] & 7)) & mask
+		}
+	}
+	return nil
+}
+
+// Computes the syndrome s = He of e, where H = [I | T] is given by the
+// packed public key pk.
+func (p *Params) syndrome(s, pk, e []byte) {
+	rows := p.rows()
+	rowSize := p.rowSize()
+
+	// Bits mt, ..., n-1 of e, aligned as the rows of pk.
+	eT := make([]byte, rowSize)
+	off, tail := rows/8, uint(rows%8)
+	for j := range eT {
+		eT[j] = e[off+j] >> tail
+		if tail != 0 && off+j+1 < len(e) {
+			eT[j] |= e[off+j+1] << (8 - tail)
+		}
+	}
+
+	for i := range s {
+		s[i] = 0
+	}
+	for i := 0; i < rows; i++ {
+		row := pk[i*rowSize : (i+1)*rowSize]
+
+		var b byte
+		for j := range row {
+			b ^= row[j] & eT[j]
+		}
+		b ^= (e[i/8] >> (i % 8)) & 1
+
+		b ^= b >> 4
+		b ^= b >> 2
+		b ^= b >> 1
+		b &= 1
+
+		s[i/8] |= b << (i % 8)
+	}
+}
+
+// Encapsulate generates an error vector using randomness from rand,
+// and writes its syndrome with respect to the packed public key pk to ct
+// and the derived shared key to ss.
+func (p *Params) Encapsulate(ct, ss, pk []byte, rand io.Reader) error {
+	e := make([]byte, p.n/8)
+	if err := p.genE(e, rand); err != nil {
+		return err
+	}
+	p.syndrome(ct, pk, e)
+
+	// K = H(1, e, C)
+	h := sha3.NewShake256()
+	_, _ = h.Write([]byte{1})
+	_, _ = h.Write(e)
+	_, _ = h.Write(ct)
+	_, _ = h.Read(ss)
+	return nil
+}
+
+// Computes the 2t syndromes of the received word r with respect to the
+// Goppa polynomial g and the support L.
+func (p *Params) synd(out, g, L []gf, r []byte) {
+	for j := range out {
+		out[j] = 0
+	}
+
+	for i := 0; i < p.n; i++ {
+		c := -gf((r[i/8] >> (i % 8)) & 1)
+
+		e := p.eval(g, L[i])
+		eInv := p.gfInv(p.gfMul(e, e))
+
+		for j := range out {
+			out[j] ^= eInv & c
+			eInv = p.gfMul(eInv, L[i])
+		}
+	}
+}
+
+// Berlekamp-Massey algorithm: computes the error locator polynomial
+// out, of degree at most t, from the 2t syndromes s.
+func (p *Params) bm(out, s []gf) {
+	t := p.t
+	T := make([]gf, t+1)
+	C := make([]gf, t+1)
+	B := make([]gf, t+1)
+
+	var L uint16
+	b := gf(1)
+
+	B[1] = 1
+	C[0] = 1
+
+	for N := uint16(0); N < uint16(2*t); N++ {
+		var d gf
+		for i := 0; i <= min(int(N), t); i++ {
+			d ^= p.gfMul(C[i], s[int(N)-i])
+		}
+
+		mne := d
+		mne--
+		mne >>= 15
+		mne--
+		mle := N
+		mle -= 2 * L
+		mle >>= 15
+		mle--
+		mle &= mne
+
+		copy(T, C)
+
+		f := p.gfFrac(b, d)
+
+		for i := range C {
+			C[i] ^= p.gfMul(f, B[i]) & mne
+		}
+
+		L = (L &^ mle) | ((N + 1 - L) & mle)
+
+		for i := range B {
+			B[i] = (B[i] &^ mle) | (T[i] & mle)
+		}
+
+		b = (b &^ mle) | (d & mle)
+
+		copy(B[1:], B[:t])
+		B[0] = 0
+	}
+
+	for i := 0; i <= t; i++ {
+		out[i] = C[t-i]
+	}
+}
+
+// Decodes the syndrome c into the error vector e. Returns 1 on success,
+// and 0 otherwise.
+func (p *Params) decrypt(e, sk, c []byte) int {
+	r := make([]byte, p.n/8)
+	copy(r, c)
+	if tail := p.rows() % 8; tail != 0 {
+		r[len(c)-1] &= byte(1<<tail - 1)
+	}
+
+	g := p.irr(sk)
+	L := p.support(sk[40+p.irrSize() : 40+p.irrSize()+p.condSize()])
+
+	s := make([]gf, 2*p.t)
+	p.synd(s, g, L, r)
+
+	locator := make([]gf, p.t+1)
+	p.bm(locator, s)
+
+	for i := range e {
+		e[i] = 0
+	}
+	w := 0
+	for i := 0; i < p.n; i++ {
+		t := gfIsZero(p.eval(locator, L[i])) & 1
+		e[i/8] |= byte(t) << (i % 8)
+		w += int(t)
+	}
+
+	sCmp := make([]gf, 2*p.t)
+	p.synd(sCmp, g, L, e)
+
+	check := uint16(w) ^ uint16(p.t)
+	for i := range s {
+		check |= s[i] ^ sCmp[i]
+	}
+	check--
+	check >>= 15
+
+	return int(check)
+}
+
+// CheckCiphertextPadding returns whether the unused bits of the last byte
+// of the ciphertext ct are zero.
+func (p *Params) CheckCiphertextPadding(ct []byte) bool {
+	tail := p.rows() % 8
+	if tail == 0 {
+		return true
+	}
+	return ct[len(ct)-1]>>tail == 0
+}
+
+// CheckPublicKeyPadding returns whether the unused bits of the last byte
+// of each row of the packed public key pk are zero.
+func (p *Params) CheckPublicKeyPadding(pk []byte) bool {
+	tail := (p.n - p.rows()) % 8
+	if tail == 0 {
+		return true
+	}
+	rowSize := p.rowSize()
+	var b byte
+	for i := 1; i <= p.rows(); i++ {
+		b |= pk[i*rowSize-1]
+	}
+	return b>>tail == 0
+}
+
+// Decapsulate writes to ss the shared key encapsulated in ct for the
+// packed private key sk. If decoding fails, a pseudorandom key derived
+// from the secret string s of sk is returned instead. If the padding bits
+// of ct are not zero, ss is set to all zeroes.
+func (p *Params) Decapsulate(ss, ct, sk []byte) {
+	e := make([]byte, p.n/8)
+	ok := p.decrypt(e, sk, ct)
+
+	// On failure replace e by s.
+	s := sk[len(sk)-p.n/8:]
+	subtle.ConstantTimeCopy(1-ok, e, s)
+
+	// K = H(b, e, C)
+	h := sha3.NewShake256()
+	_, _ = h.Write([]byte{byte(ok)})
+	_, _ = h.Write(e)
+	_, _ = h.Write(ct)
+	_, _ = h.Read(ss)
+
+	if !p.CheckCiphertextPadding(ct) {
+		for i := range ss {
+			ss[i] = 0
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+var allParams = []*Params{
+	Mceliece348864, Mceliece348864f,
+	Mceliece460896, Mceliece460896f,
+	Mceliece6688128, Mceliece6688128f,
+	Mceliece6960119, Mceliece6960119f,
+	Mceliece8192128, Mceliece8192128f,
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, p := range allParams {
+		t.Run(p.Name, func(t *testing.T) {
+			if testing.Short() && p.m != 12 {
+				t.Skip("skipped large parameter set in short mode")
+			}
+			seed := make([]byte, p.KeySeedSize())
+			_, _ = rand.Read(seed)
+			pk := make([]byte, p.PublicKeySize())
+			sk := make([]byte, p.PrivateKeySize())
+			p.KeyGen(pk, sk, seed)
+
+			if !p.CheckPublicKeyPadding(pk) {
+				t.Fatal("bad public key padding")
+			}
+
+			pk2 := make([]byte, p.PublicKeySize())
+			if !p.PublicKeyFromPrivate(pk2, sk) || !bytes.Equal(pk, pk2) {
+				t.Fatal("public key does not match private key")
+			}
+
+			for i := 0; i < 4; i++ {
+				ct := make([]byte, p.CiphertextSize())
+				ss := make([]byte, p.SharedKeySize())
+				if err := p.Encapsulate(ct, ss, pk, rand.Reader); err != nil {
+					t.Fatal(err)
+				}
+				if !p.CheckCiphertextPadding(ct) {
+					t.Fatal("bad ciphertext padding")
+				}
+
+				ss2 := make([]byte, p.SharedKeySize())
+				p.Decapsulate(ss2, ct, sk)
+				if !bytes.Equal(ss, ss2) {
+					t.Fatal("shared keys differ")
+				}
+
+				ct[0] ^= 1
+				p.Decapsulate(ss2, ct, sk)
+				if bytes.Equal(ss, ss2) {
+					t.Fatal("shared keys match for a modified ciphertext")
+				}
+			}
+		})
+	}
+}
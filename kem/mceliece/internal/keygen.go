@@ -0,0 +1,366 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// Computes the minimal polynomial g of f ∈ GF(2ᵐᵗ), which is monic of
+// degree t, and writes its t lower coefficients to out. Returns false
+// if the minimal polynomial has degree lower than t.
+func (p *Params) genPoly(out, f []gf) bool {
+	t := p.t
+
+	// mat[j] = fʲ for j = 0, ..., t.
+	mat := make([][]gf, t+1)
+	for j := range mat {
+		mat[j] = make([]gf, t)
+	}
+	mat[0][0] = 1
+	copy(mat[1], f)
+	for j := 2; j <= t; j++ {
+		p.polyMul(mat[j], mat[j-1], f)
+	}
+
+	// Gaussian elimination to solve Σ gⱼfʲ = -fᵗ.
+	for j := 0; j < t; j++ {
+		for k := j + 1; k < t; k++ {
+			mask := gfIsZero(mat[j][j])
+			for c := j; c < t+1; c++ {
+				mat[c][j] ^= mat[c][k] & mask
+			}
+		}
+
+		if mat[j][j] == 0 {
+			return false
+		}
+
+		inv := p.gfInv(mat[j][j])
+		for c := j; c < t+1; c++ {
+			mat[c][j] = p.gfMul(mat[c][j], inv)
+		}
+
+		for k := 0; k < t; k++ {
+			if k != j {
+				tk := mat[j][k]
+				for c := j; c < t+1; c++ {
+					mat[c][k] ^= p.gfMul(mat[c][j], tk)
+				}
+			}
+		}
+	}
+
+	copy(out, mat[t])
+	return true
+}
+
+// A binary matrix stored by rows, with the entry at column j of a row
+// in bit j%64 of its word j/64.
+type matrix [][]uint64
+
+// Returns the 64 entries of row i starting at column j.
+func (mat matrix) get64(i, j int) uint64 {
+	w, s := j/64, uint(j%64)
+	if s == 0 {
+		return mat[i][w]
+	}
+	v := mat[i][w] >> s
+	if w+1 < len(mat[i]) {
+		v |= mat[i][w+1] << (64 - s)
+	}
+	return v
+}
+
+// Sets the 64 entries of row i starting at column j to v.
+func (mat matrix) set64(i, j int, v uint64) {
+	w, s := j/64, uint(j%64)
+	if s == 0 {
+		mat[i][w] = v
+		return
+	}
+	mat[i][w] = (mat[i][w] & (1<<s - 1)) | (v << s)
+	mat[i][w+1] = (mat[i][w+1] &^ (1<<s - 1)) | (v >> (64 - s))
+}
+
+// Returns 0xFFFF...FF if x = y, and zero otherwise.
+func sameMask(x, y uint64) uint64 {
+	m := x ^ y
+	m--
+	m >>= 63
+	return -m
+}
+
+// Moves the columns of mat, and the corresponding entries of pi, so that
+// the last 32 rows of the parity-check matrix have their pivots in the
+// expected columns. This is only used for the semi-systematic public keys
+// of the "f" variants. Returns the positions of the pivots, or false if
+// there are not enough of them.
+func (p *Params) movColumns(mat matrix, pi []int16) (uint64, bool) {
+	var buf [32]uint64
+	var ctzList [32]int
+	var pivots uint64
+
+	row := p.rows() - 32
+
+	// Extract the 32×64 sub-matrix.
+	for i := 0; i < 32; i++ {
+		buf[i] = mat.get64(row+i, row)
+	}
+
+	// Compute the column indices of the pivots by Gaussian elimination.
+	for i := 0; i < 32; i++ {
+		t := buf[i]
+		for j := i + 1; j < 32; j++ {
+			t |= buf[j]
+		}
+		if t == 0 {
+			return 0, false
+		}
+
+		s := bits.TrailingZeros64(t)
+		ctzList[i] = s
+		pivots |= 1 << s
+
+		for j := i + 1; j < 32; j++ {
+			mask := (buf[i] >> s) & 1
+			mask--
+			buf[i] ^= buf[j] & mask
+		}
+		for j := i + 1; j < 32; j++ {
+			mask := (buf[j] >> s) & 1
+			mask = -mask
+			buf[j] ^= buf[i] & mask
+		}
+	}
+
+	// Update the permutation.
+	for j := 0; j < 32; j++ {
+		for k := j + 1; k < 64; k++ {
+			d := pi[row+j] ^ pi[row+k]
+			d &= int16(sameMask(uint64(k), uint64(ctzList[j])))
+			pi[row+j] ^= d
+			pi[row+k] ^= d
+		}
+	}
+
+	// Move the columns of mat according to the pivots.
+	for i := 0; i < p.rows(); i++ {
+		t := mat.get64(i, row)
+		for j := 0; j < 32; j++ {
+			d := t >> j
+			d ^= t >> ctzList[j]
+			d &= 1
+
+			t ^= d << ctzList[j]
+			t ^= d << j
+		}
+		mat.set64(i, row, t)
+	}
+
+	return pivots, true
+}
+
+// Computes the public key, the rows of T where [I | T] is the systematic
+// form of the parity-check matrix given by the Goppa polynomial g (of
+// which the leading coefficient is omitted) and the support L. If
+// pi is not nil, the public key is computed in semi-systematic form,
+// which might modify pi. Returns the positions of the pivots, which is
+// 0xFFFFFFFF for systematic form, or false if the matrix does not have
+// the expected form.
+func (p *Params) pkGen(pk []byte, g []gf, L []gf, pi []int16) (uint64, bool) {
+	rows := p.rows()
+	words := (p.n + 63) / 64
+
+	mat := make(matrix, rows)
+	for i := range mat {
+		mat[i] = make([]uint64, words)
+	}
+
+	// Fill the matrix with the bits of Lⱼⁱ/g(Lⱼ).
+	inv := make([]gf, p.n)
+	gFull := make([]gf, p.t+1)
+	copy(gFull, g)
+	gFull[p.t] = 1
+	for j := 0; j < p.n; j++ {
+		inv[j] = p.gfInv(p.eval(gFull, L[j]))
+	}
+
+	for i := 0; i < p.t; i++ {
+		for j := 0; j < p.n; j++ {
+			for k := 0; k < p.m; k++ {
+				mat[i*p.m+k][j/64] |= uint64((inv[j]>>k)&1) << (j % 64)
+			}
+		}
+		for j := 0; j < p.n; j++ {
+			inv[j] = p.gfMul(inv[j], L[j])
+		}
+	}
+
+	// Gaussian elimination.
+	pivots := uint64(0xFFFFFFFF)
+	for row := 0; row < rows; row++ {
+		if p.semi && pi != nil && row == rows-32 {
+			var ok bool
+			pivots, ok = p.movColumns(mat, pi)
+			if !ok {
+				return 0, false
+			}
+		}
+
+		w, b := row/64, uint(row%64)
+
+		for k := row + 1; k < rows; k++ {
+			mask := (mat[row][w] ^ mat[k][w]) >> b
+			mask &= 1
+			mask = -mask
+			for c := w; c < words; c++ {
+				mat[row][c] ^= mat[k][c] & mask
+			}
+		}
+
+		if (mat[row][w]>>b)&1 == 0 {
+			return 0, false
+		}
+
+		for k := 0; k < rows; k++ {
+			if k != row {
+				mask := mat[k][w] >> b
+				mask &= 1
+				mask = -mask
+				for c := w; c < words; c++ {
+					mat[k][c] ^= mat[row][c] & mask
+				}
+			}
+		}
+	}
+
+	// Write out the columns mt, ..., n-1.
+	rowSize := p.rowSize()
+	for i := 0; i < rows; i++ {
+		out := pk[i*rowSize : (i+1)*rowSize]
+		for j := 0; j < rowSize; j += 8 {
+			var buf [8]byte
+			binary.LittleEndian.PutUint64(buf[:], mat.get64(i, rows+8*j))
+			copy(out[j:], buf[:])
+		}
+		if tail := (p.n - rows) % 8; tail != 0 {
+			out[rowSize-1] &= byte(1<<tail - 1)
+		}
+	}
+
+	return pivots, true
+}
+
+// KeyGen derives a key pair from the given seed of KeySeedSize bytes
+// and writes the packed keys to pk and sk.
+func (p *Params) KeyGen(pk, sk, seed []byte) {
+	if len(seed) != p.KeySeedSize() {
+		panic("seed must be of length KeySeedSize")
+	}
+
+	q := 1 << p.m
+	sSize := p.n / 8
+	permSize := 4 * q
+	fSize := 2 * p.t
+	r := make([]byte, sSize+permSize+fSize+32)
+
+	f := make([]gf, p.t)
+	irr := make([]gf, p.t)
+	perm := make([]uint64, q)
+	pi := make([]int16, q)
+	L := make([]gf, p.n)
+
+	var delta [33]byte
+	delta[0] = 64
+	copy(delta[1:], seed)
+
+	for {
+		// Expand and update the seed δ.
+		h := sha3.NewShake256()
+		_, _ = h.Write(delta[:])
+		_, _ = h.Read(r)
+		copy(sk[:32], delta[1:])
+		copy(delta[1:], r[len(r)-32:])
+
+		rp := r[:len(r)-32]
+
+		// Generate the Goppa polynomial g.
+		rf := rp[len(rp)-fSize:]
+		for i := range f {
+			f[i] = binary.LittleEndian.Uint16(rf[2*i:]) & p.gfMask()
+		}
+		if !p.genPoly(irr, f) {
+			continue
+		}
+		rp = rp[:len(rp)-fSize]
+
+		// Generate the permutation π by sorting the random values.
+		rperm := rp[len(rp)-permSize:]
+		for i := range perm {
+			perm[i] = uint64(binary.LittleEndian.Uint32(rperm[4*i:]))<<31 | uint64(i)
+		}
+		sortUint64(perm)
+
+		unique := true
+		for i := 1; i < q; i++ {
+			if perm[i-1]>>31 == perm[i]>>31 {
+				unique = false
+			}
+		}
+		if !unique {
+			continue
+		}
+
+		for i := range pi {
+			pi[i] = int16(perm[i] & uint64(p.gfMask()))
+		}
+		for i := range L {
+			L[i] = p.bitrev(gf(pi[i]))
+		}
+
+		pivots, ok := p.pkGen(pk, irr, L, pi)
+		if !ok {
+			continue
+		}
+		rp = rp[:len(rp)-permSize]
+
+		// Pack the private key.
+		skp := sk[32:]
+		binary.LittleEndian.PutUint64(skp, pivots)
+		skp = skp[8:]
+		for i := range irr {
+			binary.LittleEndian.PutUint16(skp[2*i:], irr[i])
+		}
+		skp = skp[p.irrSize():]
+		copy(skp, p.controlBits(pi))
+		skp = skp[p.condSize():]
+		copy(skp, rp[len(rp)-sSize:])
+		return
+	}
+}
+
+// PublicKeyFromPrivate recomputes the packed public key from the packed
+// private key sk and writes it to pk. Returns false if sk does not
+// correspond to a valid public key.
+func (p *Params) PublicKeyFromPrivate(pk, sk []byte) bool {
+	g := p.irr(sk)
+	L := p.support(sk[40+p.irrSize() : 40+p.irrSize()+p.condSize()])
+
+	// The columns of a semi-systematic key have already been moved
+	// according to the permutation stored in sk, so that its systematic
+	// form is the same.
+	_, ok := p.pkGen(pk, g, L, nil)
+	return ok
+}
+
+// Returns the lower coefficients of the Goppa polynomial stored in sk.
+func (p *Params) irr(sk []byte) []gf {
+	g := make([]gf, p.t+1)
+	for i := 0; i < p.t; i++ {
+		g[i] = binary.LittleEndian.Uint16(sk[40+2*i:]) & p.gfMask()
+	}
+	g[p.t] = 1
+	return g
+}
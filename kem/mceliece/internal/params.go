@@ -0,0 +1,96 @@
+// Package internal implements the Classic McEliece KEM for all parameter
+// sets, which are described by a Params value.
+package internal
+
+// A term c·yᵈ of the polynomial F(y) defining the field GF(2ᵐᵗ).
+type term struct {
+	deg  int
+	coef gf
+}
+
+// Params describes a Classic McEliece parameter set.
+type Params struct {
+	// Name of the parameter set, as in the NIST submission.
+	Name string
+
+	m int // Size in bits of the elements of GF(2ᵐ), either 12 or 13.
+	n int // Length of the code.
+	t int // Number of errors that the code corrects.
+
+	// Terms of F(y) - yᵗ, where F(y) defines GF(2ᵐᵗ) over GF(2ᵐ).
+	irrTerms []term
+
+	// Whether the public key is in semi-systematic form, which is the
+	// case for the "f" variants.
+	semi bool
+}
+
+// The parameter sets of the Classic McEliece NIST round 4 submission.
+var (
+	Mceliece348864 = &Params{
+		Name: "mceliece348864", m: 12, n: 3488, t: 64,
+		irrTerms: []term{{3, 1}, {1, 1}, {0, 2}},
+	}
+	Mceliece460896 = &Params{
+		Name: "mceliece460896", m: 13, n: 4608, t: 96,
+		irrTerms: []term{{10, 1}, {9, 1}, {6, 1}, {0, 1}},
+	}
+	Mceliece6688128 = &Params{
+		Name: "mceliece6688128", m: 13, n: 6688, t: 128,
+		irrTerms: []term{{7, 1}, {2, 1}, {1, 1}, {0, 1}},
+	}
+	Mceliece6960119 = &Params{
+		Name: "mceliece6960119", m: 13, n: 6960, t: 119,
+		irrTerms: []term{{8, 1}, {0, 1}},
+	}
+	Mceliece8192128 = &Params{
+		Name: "mceliece8192128", m: 13, n: 8192, t: 128,
+		irrTerms: []term{{7, 1}, {2, 1}, {1, 1}, {0, 1}},
+	}
+
+	Mceliece348864f  = semiSystematic(Mceliece348864)
+	Mceliece460896f  = semiSystematic(Mceliece460896)
+	Mceliece6688128f = semiSystematic(Mceliece6688128)
+	Mceliece6960119f = semiSystematic(Mceliece6960119)
+	Mceliece8192128f = semiSystematic(Mceliece8192128)
+)
+
+func semiSystematic(p *Params) *Params {
+	f := *p
+	f.Name += "f"
+	f.semi = true
+	return &f
+}
+
+// Number of rows of the parity-check matrix, mt.
+func (p *Params) rows() int { return p.m * p.t }
+
+// Size in bytes of a row of the public key.
+func (p *Params) rowSize() int { return (p.n - p.rows() + 7) / 8 }
+
+// Size in bytes of the packed Goppa polynomial.
+func (p *Params) irrSize() int { return 2 * p.t }
+
+// Size in bytes of the control bits of the Beneš network.
+func (p *Params) condSize() int { return (2*p.m - 1) << (p.m - 4) }
+
+// PublicKeySize returns the size of a packed public key.
+func (p *Params) PublicKeySize() int { return p.rows() * p.rowSize() }
+
+// PrivateKeySize returns the size of a packed private key.
+func (p *Params) PrivateKeySize() int {
+	return 40 + p.irrSize() + p.condSize() + p.n/8
+}
+
+// CiphertextSize returns the size of a ciphertext.
+func (p *Params) CiphertextSize() int { return (p.rows() + 7) / 8 }
+
+// SharedKeySize returns the size of the shared key.
+func (p *Params) SharedKeySize() int { return 32 }
+
+// KeySeedSize returns the size of the seed used to derive a key pair.
+func (p *Params) KeySeedSize() int { return 32 }
+
+// EncapsulationSeedSize returns the size of the seed used in
+// deterministic encapsulation.
+func (p *Params) EncapsulationSeedSize() int { return 32 }
@@ -0,0 +1,94 @@
+package internal
+
+// Sorts x in place in constant time with a sorting network, following
+// djbsort's int32_sort.
+func sortUint64(x []uint64) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+
+	for p := top; p > 0; p >>= 1 {
+		for i := 0; i < n-p; i++ {
+			if i&p == 0 {
+				minMaxUint64(&x[i], &x[i+p])
+			}
+		}
+		i := 0
+		for q := top; q > p; q >>= 1 {
+			for ; i < n-q; i++ {
+				if i&p == 0 {
+					a := x[i+p]
+					for r := q; r > p; r >>= 1 {
+						minMaxUint64(&a, &x[i+r])
+					}
+					x[i+p] = a
+				}
+			}
+		}
+	}
+}
+
+// Sets (a, b) to (min(a, b), max(a, b)) in constant time.
+func minMaxUint64(a, b *uint64) {
+	c := *b - *a
+	c >>= 63
+	c = -c
+	c &= *a ^ *b
+	*a ^= c
+	*b ^= c
+}
+
+// Sorts x in place in constant time. Same as sortUint64 but for
+// non-negative int32 values.
+func sortInt32(x []int32) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+
+	for p := top; p > 0; p >>= 1 {
+		for i := 0; i < n-p; i++ {
+			if i&p == 0 {
+				minMaxInt32(&x[i], &x[i+p])
+			}
+		}
+		i := 0
+		for q := top; q > p; q >>= 1 {
+			for ; i < n-q; i++ {
+				if i&p == 0 {
+					a := x[i+p]
+					for r := q; r > p; r >>= 1 {
+						minMaxInt32(&a, &x[i+r])
+					}
+					x[i+p] = a
+				}
+			}
+		}
+	}
+}
+
+// Sets (a, b) to (min(a, b), max(a, b)) in constant time.
+func minMaxInt32(a, b *int32) {
+	ab := *b ^ *a
+	c := int64(*b) - int64(*a)
+	c >>= 63
+	m := int32(c) & ab
+	*a ^= m
+	*b ^= m
+}
+
+// Returns min(a, b) in constant time.
+func minInt32(a, b int32) int32 {
+	c := int64(b) - int64(a)
+	c >>= 63
+	return a ^ (int32(c) & (a ^ b))
+}
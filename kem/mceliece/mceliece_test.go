@@ -0,0 +1,66 @@
+package mceliece
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece348864"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece6960119"
+)
+
+func TestStreamPublicKey(t *testing.T) {
+	pk, _, err := mceliece348864.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := pk.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != mceliece348864.PublicKeySize {
+		t.Fatal()
+	}
+
+	pk2, err := mceliece348864.ReadPublicKey(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(pk2) {
+		t.Fatal()
+	}
+
+	if _, err = mceliece348864.ReadPublicKey(&buf); err == nil {
+		t.Fatal("expected error on truncated public key")
+	}
+}
+
+func TestPadding(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	scheme := mceliece6960119.Scheme()
+	pk, sk, err := scheme.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ct, _, err := scheme.Encapsulate(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct[len(ct)-1] |= 0x80
+	if _, err = scheme.Decapsulate(sk, ct); err != kem.ErrCipherText {
+		t.Fatalf("expected ErrCipherText, got %v", err)
+	}
+
+	ppk, _ := pk.MarshalBinary()
+	rowSize := len(ppk) / (13 * 119)
+	ppk[rowSize-1] |= 0x80
+	if _, err = scheme.UnmarshalBinaryPublicKey(ppk); err != kem.ErrPubKey {
+		t.Fatalf("expected ErrPubKey, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package mlkem
+
+import "github.com/quantumcoinproject/circl/kem/mlkem/internal"
+
+// KeyCheckError is returned when a key fails one of the input checks
+// of FIPS 203 §7.2 and §7.3.
+//
+// It wraps the corresponding generic error of the kem package, so that
+// errors.Is(err, kem.ErrPubKey) still holds for a rejected public key.
+type KeyCheckError = internal.KeyCheckError
+
+var (
+	// Encapsulation key is not of the right length (FIPS 203 §7.2,
+	// type check).
+	ErrEncapsulationKeySize = internal.ErrEncapsulationKeySize
+
+	// Encapsulation key contains a coefficient that is not reduced
+	// modulo q (FIPS 203 §7.2, modulus check).
+	ErrEncapsulationKeyModulus = internal.ErrEncapsulationKeyModulus
+
+	// Decapsulation key is not of the right length (FIPS 203 §7.3,
+	// type check).
+	ErrDecapsulationKeySize = internal.ErrDecapsulationKeySize
+
+	// Hash of the encapsulation key embedded in the decapsulation key
+	// does not match the stored one (FIPS 203 §7.3, hash check).
+	ErrDecapsulationKeyHash = internal.ErrDecapsulationKeyHash
+
+	// Decapsulation key contains a coefficient that is not reduced
+	// modulo q, either in the secret vector or in the embedded
+	// encapsulation key. Only returned in strict mode.
+	ErrDecapsulationKeyModulus = internal.ErrDecapsulationKeyModulus
+)
@@ -0,0 +1,55 @@
+// Package internal provides the key checks and private key encodings
+// shared by the mlkem512, mlkem768 and mlkem1024 packages. Package mlkem
+// exports them.
+package internal
+
+import "github.com/quantumcoinproject/circl/kem"
+
+// KeyCheckError is returned when a key fails one of the input checks
+// of FIPS 203 §7.2 and §7.3.
+//
+// It wraps the corresponding generic error of the kem package, so that
+// errors.Is(err, kem.ErrPubKey) still holds for a rejected public key.
+type KeyCheckError struct {
+	// Name of the check that failed.
+	Check string
+
+	// The generic kem error this one refines.
+	Err error
+}
+
+func (e *KeyCheckError) Error() string { return "mlkem: " + e.Check + " failed" }
+func (e *KeyCheckError) Unwrap() error { return e.Err }
+
+var (
+	// Encapsulation key is not of the right length (FIPS 203 §7.2,
+	// type check).
+	ErrEncapsulationKeySize = &KeyCheckError{
+		"encapsulation key type check", kem.ErrPubKeySize,
+	}
+
+	// Encapsulation key contains a coefficient that is not reduced
+	// modulo q (FIPS 203 §7.2, modulus check).
+	ErrEncapsulationKeyModulus = &KeyCheckError{
+		"encapsulation key modulus check", kem.ErrPubKey,
+	}
+
+	// Decapsulation key is not of the right length (FIPS 203 §7.3,
+	// type check).
+	ErrDecapsulationKeySize = &KeyCheckError{
+		"decapsulation key type check", kem.ErrPrivKeySize,
+	}
+
+	// Hash of the encapsulation key embedded in the decapsulation key
+	// does not match the stored one (FIPS 203 §7.3, hash check).
+	ErrDecapsulationKeyHash = &KeyCheckError{
+		"decapsulation key hash check", kem.ErrPrivKey,
+	}
+
+	// Decapsulation key contains a coefficient that is not reduced
+	// modulo q, either in the secret vector or in the embedded
+	// encapsulation key. Only returned in strict mode.
+	ErrDecapsulationKeyModulus = &KeyCheckError{
+		"decapsulation key modulus check", kem.ErrPrivKey,
+	}
+)
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/quantumcoinproject/circl/kem"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// PrivateKeyFormat selects one of the alternatives of the ML-KEM private
+// key CHOICE of draft-ietf-lamps-kyber-certificates:
+//
+//	ML-KEM-PrivateKey ::= CHOICE {
+//	  seed [0] OCTET STRING (SIZE (64)),
+//	  expandedKey OCTET STRING,
+//	  both SEQUENCE {
+//	    seed OCTET STRING (SIZE (64)),
+//	    expandedKey OCTET STRING
+//	  }
+//	}
+//
+// The seed is d ‖ z as passed to ML-KEM.KeyGen_internal, and the
+// expanded key is the decapsulation key dk.
+type PrivateKeyFormat int
+
+const (
+	// Only the 64-byte seed.
+	FormatSeed PrivateKeyFormat = iota
+
+	// Only the expanded decapsulation key.
+	FormatExpanded
+
+	// Both the seed and the expanded decapsulation key.
+	FormatBoth
+)
+
+// Size of the seed (d ‖ z) in the private key encodings.
+const SeedSize = 64
+
+var (
+	// Returned when marshalling a private key, that was not derived from
+	// a seed, in a format that contains the seed.
+	ErrNoSeed = errors.New("mlkem: private key has no seed")
+
+	// Returned when an encoded private key is not a valid
+	// ML-KEM-PrivateKey.
+	ErrPrivateKeyEncoding = errors.New("mlkem: malformed private key encoding")
+
+	// Expanded decapsulation key does not match the one derived from the
+	// seed stored alongside it.
+	ErrDecapsulationKeySeed = &KeyCheckError{
+		"decapsulation key seed consistency check", kem.ErrPrivKey,
+	}
+)
+
+// MarshalPrivateKey returns the DER encoding of the ML-KEM-PrivateKey in
+// the given format. The seed is ignored for FormatExpanded, and expanded
+// is ignored for FormatSeed.
+func MarshalPrivateKey(format PrivateKeyFormat, seed, expanded []byte) ([]byte, error) {
+	if format != FormatExpanded && len(seed) != SeedSize {
+		return nil, kem.ErrSeedSize
+	}
+
+	var b cryptobyte.Builder
+	switch format {
+	case FormatSeed:
+		b.AddASN1(asn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(seed)
+		})
+	case FormatExpanded:
+		b.AddASN1OctetString(expanded)
+	case FormatBoth:
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(seed)
+			b.AddASN1OctetString(expanded)
+		})
+	default:
+		return nil, errors.New("mlkem: unknown private key format")
+	}
+	return b.Bytes()
+}
+
+// UnmarshalPrivateKey parses the DER encoding of an ML-KEM-PrivateKey
+// and returns its format, the seed (nil for FormatExpanded) and the
+// expanded key (nil for FormatSeed).
+//
+// The length of the expanded key is not checked, as that depends on
+// the parameter set.
+func UnmarshalPrivateKey(der []byte) (
+	format PrivateKeyFormat, seed, expanded []byte, err error,
+) {
+	s := cryptobyte.String(der)
+	var tag asn1.Tag
+	var inner cryptobyte.String
+	if !s.ReadAnyASN1(&inner, &tag) || !s.Empty() {
+		return 0, nil, nil, ErrPrivateKeyEncoding
+	}
+
+	switch tag {
+	case asn1.Tag(0).ContextSpecific():
+		format = FormatSeed
+		seed = inner
+	case asn1.OCTET_STRING:
+		format = FormatExpanded
+		expanded = inner
+	case asn1.SEQUENCE:
+		format = FormatBoth
+		if !inner.ReadASN1Bytes(&seed, asn1.OCTET_STRING) ||
+			!inner.ReadASN1Bytes(&expanded, asn1.OCTET_STRING) ||
+			!inner.Empty() {
+			return 0, nil, nil, ErrPrivateKeyEncoding
+		}
+	default:
+		return 0, nil, nil, ErrPrivateKeyEncoding
+	}
+
+	if format != FormatExpanded && len(seed) != SeedSize {
+		return 0, nil, nil, ErrPrivateKeyEncoding
+	}
+	return format, seed, expanded, nil
+}
@@ -12,6 +12,7 @@ import (
 	cryptoRand "crypto/rand"
 	"github.com/quantumcoinproject/circl/internal/sha3"
 	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/mlkem/internal"
 	cpapke "github.com/quantumcoinproject/circl/pke/kyber/kyber512"
 )
 
@@ -48,6 +49,9 @@ type PrivateKey struct {
 	pk  *cpapke.PublicKey
 	hpk [32]byte // H(pk)
 	z   [32]byte
+
+	// Seed (d ‖ z) sk was derived from, if known.
+	seed *[KeySeedSize]byte
 }
 
 // NewKeyFromSeed derives a public/private keypair deterministically
@@ -65,6 +69,8 @@ func NewKeyFromSeed(seed []byte) (*PublicKey, *PrivateKey) {
 	pk.pk, sk.sk = cpapke.NewKeyFromSeedMLKEM(seed[:cpapke.KeySeedSize])
 	sk.pk = pk.pk
 	copy(sk.z[:], seed[cpapke.KeySeedSize:])
+	sk.seed = new([KeySeedSize]byte)
+	copy(sk.seed[:], seed)
 
 	// Compute H(pk)
 	var ppk [cpapke.PublicKeySize]byte
@@ -202,15 +208,14 @@ func (sk *PrivateKey) Pack(buf []byte) {
 
 // Unpacks sk from buf.
 //
-// Panics if buf is not of size PrivateKeySize.
-//
-// Returns an error if buf is not of size PrivateKeySize, or private key
-// doesn't pass the ML-KEM decapsulation key check.
+// Returns an error if buf doesn't pass the ML-KEM decapsulation key
+// check, see CheckDecapsulationKey.
 func (sk *PrivateKey) Unpack(buf []byte) error {
 	if len(buf) != PrivateKeySize {
-		return kem.ErrPrivKeySize
+		return internal.ErrDecapsulationKeySize
 	}
 
+	sk.seed = nil
 	sk.sk = new(cpapke.PrivateKey)
 	sk.sk.Unpack(buf[:cpapke.PrivateKeySize])
 	buf = buf[cpapke.PrivateKeySize:]
@@ -224,11 +229,91 @@ func (sk *PrivateKey) Unpack(buf []byte) error {
 	copy(sk.hpk[:], buf[:32])
 	copy(sk.z[:], buf[32:])
 	if !bytes.Equal(hpk[:], sk.hpk[:]) {
-		return kem.ErrPrivKey
+		return internal.ErrDecapsulationKeyHash
 	}
 	return nil
 }
 
+// UnpackStrict unpacks sk from buf like Unpack, but in addition requires
+// all coefficients of the secret vector and of the embedded encapsulation
+// key to be reduced modulo q.
+//
+// Such a decapsulation key is never produced by KeyGen, but FIPS 203
+// does not require rejecting it.
+func (sk *PrivateKey) UnpackStrict(buf []byte) error {
+	if len(buf) != PrivateKeySize {
+		return internal.ErrDecapsulationKeySize
+	}
+	if !checkModulus(buf[:cpapke.PrivateKeySize+cpapke.PublicKeySize-32]) {
+		return internal.ErrDecapsulationKeyModulus
+	}
+	return sk.Unpack(buf)
+}
+
+// Seed returns a copy of the seed (d ‖ z) sk was derived from, or nil
+// if sk was unpacked from its expanded form only.
+func (sk *PrivateKey) Seed() []byte {
+	if sk.seed == nil {
+		return nil
+	}
+	ret := make([]byte, KeySeedSize)
+	copy(ret, sk.seed[:])
+	return ret
+}
+
+// MarshalPrivateKey returns the DER encoding of sk as an
+// ML-KEM-PrivateKey of draft-ietf-lamps-kyber-certificates in the given
+// format.
+//
+// Returns mlkem.ErrNoSeed if the format contains the seed, but sk was
+// not derived from one.
+func (sk *PrivateKey) MarshalPrivateKey(format internal.PrivateKeyFormat) ([]byte, error) {
+	var expanded [PrivateKeySize]byte
+	if format != internal.FormatExpanded && sk.seed == nil {
+		return nil, internal.ErrNoSeed
+	}
+	sk.Pack(expanded[:])
+	var seed []byte
+	if sk.seed != nil {
+		seed = sk.seed[:]
+	}
+	return internal.MarshalPrivateKey(format, seed, expanded[:])
+}
+
+// UnmarshalPrivateKey parses an ML-KEM-PrivateKey in any of the formats.
+//
+// If the seed is present, the private key is derived from it, and if
+// the expanded key is present too, it must match the derived one. If
+// only the expanded key is present, it must pass the decapsulation key
+// check, see CheckDecapsulationKey.
+func UnmarshalPrivateKey(der []byte) (*PrivateKey, error) {
+	_, seed, expanded, err := internal.UnmarshalPrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	if seed == nil {
+		var sk PrivateKey
+		if err := sk.Unpack(expanded); err != nil {
+			return nil, err
+		}
+		return &sk, nil
+	}
+
+	_, sk := NewKeyFromSeed(seed)
+	if expanded != nil {
+		if len(expanded) != PrivateKeySize {
+			return nil, internal.ErrDecapsulationKeySize
+		}
+		var expanded2 [PrivateKeySize]byte
+		sk.Pack(expanded2[:])
+		if subtle.ConstantTimeCompare(expanded, expanded2[:]) != 1 {
+			return nil, internal.ErrDecapsulationKeySeed
+		}
+	}
+	return sk, nil
+}
+
 // Packs pk to buf.
 //
 // Panics if buf is not of size PublicKeySize.
@@ -242,16 +327,16 @@ func (pk *PublicKey) Pack(buf []byte) {
 
 // Unpacks pk from buf.
 //
-// Returns an error if buf is not of size PublicKeySize, or the public key
-// is not normalized.
+// Returns an error if buf doesn't pass the ML-KEM encapsulation key
+// check, see CheckEncapsulationKey.
 func (pk *PublicKey) Unpack(buf []byte) error {
 	if len(buf) != PublicKeySize {
-		return kem.ErrPubKeySize
+		return internal.ErrEncapsulationKeySize
 	}
 
 	pk.pk = new(cpapke.PublicKey)
 	if err := pk.pk.UnpackMLKEM(buf); err != nil {
-		return err
+		return internal.ErrEncapsulationKeyModulus
 	}
 
 	// Compute cached H(pk)
@@ -262,6 +347,55 @@ func (pk *PublicKey) Unpack(buf []byte) error {
 	return nil
 }
 
+// CheckEncapsulationKey performs the encapsulation key check of
+// FIPS 203 §7.2 on the packed key ek: the type check and the modulus
+// check.
+//
+// Returns nil if ek passes, and otherwise ErrEncapsulationKeySize or
+// ErrEncapsulationKeyModulus from the mlkem package.
+func CheckEncapsulationKey(ek []byte) error {
+	if len(ek) != PublicKeySize {
+		return internal.ErrEncapsulationKeySize
+	}
+	if !checkModulus(ek[:PublicKeySize-32]) {
+		return internal.ErrEncapsulationKeyModulus
+	}
+	return nil
+}
+
+// CheckDecapsulationKey performs the decapsulation key check of
+// FIPS 203 §7.3 on the packed key dk: the type check and the hash check.
+//
+// Returns nil if dk passes, and otherwise ErrDecapsulationKeySize or
+// ErrDecapsulationKeyHash from the mlkem package.
+func CheckDecapsulationKey(dk []byte) error {
+	if len(dk) != PrivateKeySize {
+		return internal.ErrDecapsulationKeySize
+	}
+	ek := dk[cpapke.PrivateKeySize : cpapke.PrivateKeySize+cpapke.PublicKeySize]
+	var hek [32]byte
+	h := sha3.New256()
+	h.Write(ek)
+	h.Read(hek[:])
+	if !bytes.Equal(hek[:], dk[len(dk)-64:len(dk)-32]) {
+		return internal.ErrDecapsulationKeyHash
+	}
+	return nil
+}
+
+// Returns whether each of the 12-bit little-endian coefficients packed
+// in buf is less than q.
+func checkModulus(buf []byte) bool {
+	const q = 3329
+	ok := true
+	for i := 0; i+3 <= len(buf); i += 3 {
+		t0 := uint16(buf[i]) | (uint16(buf[i+1]&0xf) << 8)
+		t1 := uint16(buf[i+1]>>4) | (uint16(buf[i+2]) << 4)
+		ok = ok && t0 < q && t1 < q
+	}
+	return ok
+}
+
 // Boilerplate down below for the KEM scheme API.
 
 type scheme struct{}
@@ -396,12 +530,27 @@ func (*scheme) UnmarshalBinaryPublicKey(buf []byte) (kem.PublicKey, error) {
 }
 
 func (*scheme) UnmarshalBinaryPrivateKey(buf []byte) (kem.PrivateKey, error) {
-	if len(buf) != PrivateKeySize {
-		return nil, kem.ErrPrivKeySize
-	}
 	var ret PrivateKey
 	if err := ret.Unpack(buf); err != nil {
 		return nil, err
 	}
 	return &ret, nil
 }
+
+type strictScheme struct{ scheme }
+
+var strictSch kem.Scheme = &strictScheme{}
+
+// StrictScheme returns a KEM interface that behaves like Scheme, except
+// that UnmarshalBinaryPrivateKey uses UnpackStrict.
+//
+// Keys unmarshalled by it report Scheme() as their scheme.
+func StrictScheme() kem.Scheme { return strictSch }
+
+func (*strictScheme) UnmarshalBinaryPrivateKey(buf []byte) (kem.PrivateKey, error) {
+	var ret PrivateKey
+	if err := ret.UnpackStrict(buf); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
@@ -0,0 +1,69 @@
+package mlkem
+
+import "github.com/quantumcoinproject/circl/kem/mlkem/internal"
+
+// PrivateKeyFormat selects one of the alternatives of the ML-KEM private
+// key CHOICE of draft-ietf-lamps-kyber-certificates:
+//
+//	ML-KEM-PrivateKey ::= CHOICE {
+//	  seed [0] OCTET STRING (SIZE (64)),
+//	  expandedKey OCTET STRING,
+//	  both SEQUENCE {
+//	    seed OCTET STRING (SIZE (64)),
+//	    expandedKey OCTET STRING
+//	  }
+//	}
+//
+// The seed is d ‖ z as passed to ML-KEM.KeyGen_internal, and the
+// expanded key is the decapsulation key dk.
+type PrivateKeyFormat = internal.PrivateKeyFormat
+
+const (
+	// Only the 64-byte seed.
+	FormatSeed = internal.FormatSeed
+
+	// Only the expanded decapsulation key.
+	FormatExpanded = internal.FormatExpanded
+
+	// Both the seed and the expanded decapsulation key.
+	FormatBoth = internal.FormatBoth
+)
+
+// Size of the seed (d ‖ z) in the private key encodings.
+const SeedSize = internal.SeedSize
+
+var (
+	// Returned when marshalling a private key, that was not derived from
+	// a seed, in a format that contains the seed.
+	ErrNoSeed = internal.ErrNoSeed
+
+	// Returned when an encoded private key is not a valid
+	// ML-KEM-PrivateKey.
+	ErrPrivateKeyEncoding = internal.ErrPrivateKeyEncoding
+
+	// Expanded decapsulation key does not match the one derived from the
+	// seed stored alongside it.
+	ErrDecapsulationKeySeed = internal.ErrDecapsulationKeySeed
+)
+
+// MarshalPrivateKey returns the DER encoding of the ML-KEM-PrivateKey in
+// the given format. The seed is ignored for FormatExpanded, and expanded
+// is ignored for FormatSeed.
+//
+// This is the parameter set agnostic part of the MarshalPrivateKey
+// methods of the mlkem512, mlkem768 and mlkem1024 packages.
+func MarshalPrivateKey(format PrivateKeyFormat, seed, expanded []byte) ([]byte, error) {
+	return internal.MarshalPrivateKey(format, seed, expanded)
+}
+
+// UnmarshalPrivateKey parses the DER encoding of an ML-KEM-PrivateKey
+// and returns its format, the seed (nil for FormatExpanded) and the
+// expanded key (nil for FormatSeed).
+//
+// The length of the expanded key is not checked, as that depends on
+// the parameter set.
+func UnmarshalPrivateKey(der []byte) (
+	format PrivateKeyFormat, seed, expanded []byte, err error,
+) {
+	return internal.UnmarshalPrivateKey(der)
+}
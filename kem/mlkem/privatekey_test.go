@@ -0,0 +1,95 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/mlkem"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+)
+
+func TestPrivateKeyFormats(t *testing.T) {
+	var seed [mlkem768.KeySeedSize]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	_, sk := mlkem768.NewKeyFromSeed(seed[:])
+	expanded, _ := sk.MarshalBinary()
+
+	if !bytes.Equal(sk.Seed(), seed[:]) {
+		t.Fatal("Seed() doesn't return the seed")
+	}
+
+	// Expected DER prefixes of the three alternatives of the CHOICE.
+	for _, tc := range []struct {
+		format mlkem.PrivateKeyFormat
+		prefix []byte
+		size   int
+	}{
+		{mlkem.FormatSeed, []byte{0x80, 0x40}, 2 + 64},
+		{mlkem.FormatExpanded, []byte{0x04, 0x82, 0x09, 0x60}, 4 + 2400},
+		{mlkem.FormatBoth, []byte{0x30, 0x82, 0x09, 0xa6, 0x04, 0x40}, 6 + 64 + 4 + 2400},
+	} {
+		der, err := sk.MarshalPrivateKey(tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(der) != tc.size || !bytes.HasPrefix(der, tc.prefix) {
+			t.Fatalf("format %d: unexpected encoding %x...", tc.format, der[:8])
+		}
+
+		sk2, err := mlkem768.UnmarshalPrivateKey(der)
+		if err != nil {
+			t.Fatalf("format %d: %v", tc.format, err)
+		}
+		if !sk.Equal(sk2) {
+			t.Fatalf("format %d: private keys differ", tc.format)
+		}
+		if tc.format == mlkem.FormatExpanded {
+			if sk2.Seed() != nil {
+				t.Fatal("expanded-only key has a seed")
+			}
+			if _, err := sk2.MarshalPrivateKey(mlkem.FormatSeed); err != mlkem.ErrNoSeed {
+				t.Fatalf("expected %v; got %v", mlkem.ErrNoSeed, err)
+			}
+		} else if !bytes.Equal(sk2.Seed(), seed[:]) {
+			t.Fatalf("format %d: seed not preserved", tc.format)
+		}
+	}
+
+	// An expanded key that doesn't match the seed must be rejected, even
+	// if it is a valid decapsulation key by itself.
+	seed[0] ^= 1
+	_, other := mlkem768.NewKeyFromSeed(seed[:])
+	otherExpanded, _ := other.MarshalBinary()
+	seed[0] ^= 1
+	der, err := mlkem.MarshalPrivateKey(mlkem.FormatBoth, seed[:], otherExpanded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = mlkem768.UnmarshalPrivateKey(der)
+	if err != mlkem.ErrDecapsulationKeySeed || !errors.Is(err, kem.ErrPrivKey) {
+		t.Fatalf("expected %v; got %v", mlkem.ErrDecapsulationKeySeed, err)
+	}
+
+	// Truncated expanded key alongside a seed.
+	der, _ = mlkem.MarshalPrivateKey(mlkem.FormatBoth, seed[:], expanded[1:])
+	if _, err = mlkem768.UnmarshalPrivateKey(der); err != mlkem.ErrDecapsulationKeySize {
+		t.Fatalf("expected %v; got %v", mlkem.ErrDecapsulationKeySize, err)
+	}
+
+	for _, der := range [][]byte{
+		nil,
+		{0x80, 0x20},                   // truncated
+		{0x80, 0x01, 0x00},             // seed of wrong length
+		{0x02, 0x01, 0x00},             // INTEGER
+		{0x04, 0x00, 0x00},             // trailing data
+		{0x30, 0x03, 0x04, 0x01, 0x00}, // both, missing expanded key
+	} {
+		if _, err := mlkem768.UnmarshalPrivateKey(der); err != mlkem.ErrPrivateKeyEncoding {
+			t.Fatalf("%x: expected %v; got %v", der, mlkem.ErrPrivateKeyEncoding, err)
+		}
+	}
+}
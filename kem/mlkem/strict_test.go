@@ -0,0 +1,76 @@
+package mlkem_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/kem/mlkem"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+)
+
+// Sets the i'th packed 12-bit coefficient of buf to v.
+func setCoeff(buf []byte, i int, v uint16) {
+	j := (i / 2) * 3
+	if i%2 == 0 {
+		buf[j] = byte(v)
+		buf[j+1] = (buf[j+1] & 0xf0) | byte(v>>8)
+	} else {
+		buf[j+1] = (buf[j+1] & 0x0f) | byte(v<<4)
+		buf[j+2] = byte(v >> 4)
+	}
+}
+
+func TestStrictUnpack(t *testing.T) {
+	const (
+		skSize = 384 * 3
+		ekSize = mlkem768.PublicKeySize
+	)
+
+	_, sk, err := mlkem768.GenerateKeyPair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dk, _ := sk.MarshalBinary()
+
+	for _, tc := range []struct {
+		name  string
+		coeff int
+	}{
+		{"secret", 17},
+		{"embedded ek", 2*skSize/3 + 42},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bad := append([]byte(nil), dk...)
+			setCoeff(bad, tc.coeff, 3329+7)
+
+			// Recompute H(ek) so that only the modulus check can fail.
+			h := sha3.New256()
+			_, _ = h.Write(bad[skSize : skSize+ekSize])
+			_, _ = h.Read(bad[skSize+ekSize : skSize+ekSize+32])
+
+			if err := mlkem768.CheckDecapsulationKey(bad); err != nil {
+				t.Fatalf("CheckDecapsulationKey: %v", err)
+			}
+
+			var sk2 mlkem768.PrivateKey
+			if err := sk2.Unpack(bad); err != nil {
+				t.Fatalf("Unpack: %v", err)
+			}
+			err := sk2.UnpackStrict(bad)
+			if !errors.Is(err, mlkem.ErrDecapsulationKeyModulus) {
+				t.Fatalf("UnpackStrict: expected %v; got %v",
+					mlkem.ErrDecapsulationKeyModulus, err)
+			}
+			_, err = mlkem768.StrictScheme().UnmarshalBinaryPrivateKey(bad)
+			if !errors.Is(err, mlkem.ErrDecapsulationKeyModulus) {
+				t.Fatalf("StrictScheme: expected %v; got %v",
+					mlkem.ErrDecapsulationKeyModulus, err)
+			}
+		})
+	}
+
+	if _, err := mlkem768.StrictScheme().UnmarshalBinaryPrivateKey(dk); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,178 @@
+package mlkem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem1024"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem512"
+	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+	"github.com/quantumcoinproject/circl/kem/schemes"
+)
+
+// Key checks and strict scheme of each parameter set.
+var keyChecks = map[string]struct {
+	ek, dk func([]byte) error
+	strict kem.Scheme
+}{
+	"ML-KEM-512": {
+		mlkem512.CheckEncapsulationKey,
+		mlkem512.CheckDecapsulationKey,
+		mlkem512.StrictScheme(),
+	},
+	"ML-KEM-768": {
+		mlkem768.CheckEncapsulationKey,
+		mlkem768.CheckDecapsulationKey,
+		mlkem768.StrictScheme(),
+	},
+	"ML-KEM-1024": {
+		mlkem1024.CheckEncapsulationKey,
+		mlkem1024.CheckDecapsulationKey,
+		mlkem1024.StrictScheme(),
+	},
+}
+
+type wycheproofTest struct {
+	TcID   int      `json:"tcId"`
+	M      HexBytes `json:"m"`
+	Ek     HexBytes `json:"ek"`
+	Dk     HexBytes `json:"dk"`
+	C      HexBytes `json:"c"`
+	K      HexBytes `json:"K"`
+	Result string   `json:"result"`
+}
+
+type wycheproof struct {
+	TestGroups []struct {
+		ParameterSet string           `json:"parameterSet"`
+		Tests        []wycheproofTest `json:"tests"`
+	} `json:"testGroups"`
+}
+
+// Test vectors from https://github.com/C2SP/wycheproof, in testvectors_v1.
+// The encaps vectors exercise the modulus check of encapsulation keys
+// (FIPS 203 §7.2), and the semi-expanded decaps vectors the type and hash
+// checks of decapsulation keys (FIPS 203 §7.3).
+func TestWycheproof(t *testing.T) {
+	for _, n := range []string{"512", "768", "1024"} {
+		t.Run("ML-KEM-"+n, func(t *testing.T) {
+			t.Run("encaps", func(t *testing.T) {
+				testWycheproof(t, "mlkem_"+n+"_encaps_test", testWycheproofEncaps)
+			})
+			t.Run("decaps", func(t *testing.T) {
+				testWycheproof(t, "mlkem_"+n+"_semi_expanded_decaps_test", testWycheproofDecaps)
+			})
+		})
+	}
+}
+
+func testWycheproof(t *testing.T, name string,
+	f func(*testing.T, kem.Scheme, wycheproofTest),
+) {
+	buf, err := readGzip("testdata/wycheproof/" + name + ".json.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kat wycheproof
+	if err = json.Unmarshal(buf, &kat); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range kat.TestGroups {
+		scheme := schemes.ByName(group.ParameterSet)
+		if scheme == nil {
+			t.Fatalf("No such scheme: %s", group.ParameterSet)
+		}
+		for _, test := range group.Tests {
+			f(t, scheme, test)
+		}
+	}
+}
+
+func testWycheproofEncaps(t *testing.T, scheme kem.Scheme, test wycheproofTest) {
+	checks := keyChecks[scheme.Name()]
+	errs := [3]error{checks.ek(test.Ek)}
+	ek, err := scheme.UnmarshalBinaryPublicKey(test.Ek)
+	errs[1] = err
+	_, errs[2] = checks.strict.UnmarshalBinaryPublicKey(test.Ek)
+	checkKeyErrors(t, test, errs[:], kem.ErrPubKey, kem.ErrPubKeySize)
+	if test.Result != "valid" {
+		return
+	}
+
+	ct, ss, err := scheme.EncapsulateDeterministically(ek, test.M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ct, test.C) {
+		t.Fatalf("tc=%d: ciphertext doesn't match: %x ≠ %x", test.TcID, ct, test.C)
+	}
+	if !bytes.Equal(ss, test.K) {
+		t.Fatalf("tc=%d: shared secret doesn't match: %x ≠ %x", test.TcID, ss, test.K)
+	}
+}
+
+func testWycheproofDecaps(t *testing.T, scheme kem.Scheme, test wycheproofTest) {
+	checks := keyChecks[scheme.Name()]
+	errs := [3]error{checks.dk(test.Dk)}
+	dk, err := scheme.UnmarshalBinaryPrivateKey(test.Dk)
+	errs[1] = err
+	_, errs[2] = checks.strict.UnmarshalBinaryPrivateKey(test.Dk)
+
+	// Ciphertexts of the wrong size are rejected by Decapsulate.
+	if err == nil && len(test.C) != scheme.CiphertextSize() {
+		if _, err = scheme.Decapsulate(dk, test.C); err != kem.ErrCiphertextSize {
+			t.Fatalf("tc=%d: expected %v; got %v", test.TcID, kem.ErrCiphertextSize, err)
+		}
+		if test.Result == "valid" {
+			t.Fatalf("tc=%d: valid test with a ciphertext of the wrong size", test.TcID)
+		}
+		return
+	}
+
+	checkKeyErrors(t, test, errs[:], kem.ErrPrivKey, kem.ErrPrivKeySize)
+	if test.Result != "valid" {
+		return
+	}
+
+	pk, _ := scheme.UnmarshalBinaryPublicKey(test.Ek)
+	if !dk.Public().Equal(pk) {
+		t.Fatalf("tc=%d: ek does not match", test.TcID)
+	}
+	ss, err := scheme.Decapsulate(dk, test.C)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ss, test.K) {
+		t.Fatalf("tc=%d: shared secret doesn't match: %x ≠ %x", test.TcID, ss, test.K)
+	}
+}
+
+// Checks that the key checks, and the unmarshalling of the key by the
+// scheme and its strict variant, all pass or all fail with a KeyCheckError
+// wrapping the generic error for an invalid key or for a key of the wrong
+// size.
+func checkKeyErrors(t *testing.T, test wycheproofTest, errs []error,
+	generic, genericSize error,
+) {
+	valid := test.Result == "valid"
+	for _, err := range errs {
+		if (err == nil) != valid {
+			t.Fatalf("tc=%d: expected passed=%v; got %v", test.TcID, valid, err)
+		}
+		if err == nil {
+			continue
+		}
+		var kcErr *KeyCheckError
+		if !errors.As(err, &kcErr) {
+			t.Fatalf("tc=%d: %v is not a KeyCheckError", test.TcID, err)
+		}
+		if !errors.Is(err, generic) && !errors.Is(err, genericSize) {
+			t.Fatalf("tc=%d: %v does not wrap %v", test.TcID, err, generic)
+		}
+	}
+}
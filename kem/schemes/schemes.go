@@ -12,8 +12,26 @@
 //
 // Post-quantum kems:
 //
-//	FrodoKEM-640-SHAKE
+//	FrodoKEM-640-SHAKE, FrodoKEM-976-SHAKE, FrodoKEM-1344-SHAKE
+//	FrodoKEM-640-AES, FrodoKEM-976-AES, FrodoKEM-1344-AES
+//	HQC-128, HQC-192, HQC-256
 //	Kyber512, Kyber768, Kyber1024
+//	mceliece348864, mceliece460896, mceliece6688128, mceliece6960119, mceliece8192128
+//	mceliece348864f, mceliece460896f, mceliece6688128f, mceliece6960119f, mceliece8192128f
+//	sntrup761
+//
+// Hybrid kems used in TLS:
+//
+//	Kyber512-X25519, Kyber768-X25519, P256Kyber768Draft00
+//	X25519MLKEM768, SecP256r1MLKEM768, SecP384r1MLKEM1024
+//
+// Other hybrid kems, without a TLS codepoint:
+//
+//	Kyber768-X448, Kyber1024-X448
+//
+// Hybrid kems used in OpenSSH:
+//
+//	sntrup761x25519-sha512, mlkem768x25519-sha256
 package schemes
 
 import (
@@ -21,14 +39,33 @@ import (
 
 	"github.com/quantumcoinproject/circl/hpke"
 	"github.com/quantumcoinproject/circl/kem"
+	"github.com/quantumcoinproject/circl/kem/frodo/frodo1344aes"
+	"github.com/quantumcoinproject/circl/kem/frodo/frodo1344shake"
+	"github.com/quantumcoinproject/circl/kem/frodo/frodo640aes"
 	"github.com/quantumcoinproject/circl/kem/frodo/frodo640shake"
+	"github.com/quantumcoinproject/circl/kem/frodo/frodo976aes"
+	"github.com/quantumcoinproject/circl/kem/frodo/frodo976shake"
+	"github.com/quantumcoinproject/circl/kem/hqc/hqc128"
+	"github.com/quantumcoinproject/circl/kem/hqc/hqc192"
+	"github.com/quantumcoinproject/circl/kem/hqc/hqc256"
 	"github.com/quantumcoinproject/circl/kem/hybrid"
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber1024"
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber512"
 	"github.com/quantumcoinproject/circl/kem/kyber/kyber768"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece348864"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece348864f"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece460896"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece460896f"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece6688128"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece6688128f"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece6960119"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece6960119f"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece8192128"
+	"github.com/quantumcoinproject/circl/kem/mceliece/mceliece8192128f"
 	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem1024"
 	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem512"
 	"github.com/quantumcoinproject/circl/kem/mlkem/mlkem768"
+	"github.com/quantumcoinproject/circl/kem/sntrup761"
 	"github.com/quantumcoinproject/circl/kem/xwing"
 )
 
@@ -39,27 +76,59 @@ var allSchemes = [...]kem.Scheme{
 	hpke.KEM_X25519_HKDF_SHA256.Scheme(),
 	hpke.KEM_X448_HKDF_SHA512.Scheme(),
 	frodo640shake.Scheme(),
+	frodo976shake.Scheme(),
+	frodo1344shake.Scheme(),
+	frodo640aes.Scheme(),
+	frodo976aes.Scheme(),
+	frodo1344aes.Scheme(),
+	hqc128.Scheme(),
+	hqc192.Scheme(),
+	hqc256.Scheme(),
 	kyber512.Scheme(),
 	kyber768.Scheme(),
 	kyber1024.Scheme(),
+	mceliece348864.Scheme(),
+	mceliece348864f.Scheme(),
+	mceliece460896.Scheme(),
+	mceliece460896f.Scheme(),
+	mceliece6688128.Scheme(),
+	mceliece6688128f.Scheme(),
+	mceliece6960119.Scheme(),
+	mceliece6960119f.Scheme(),
+	mceliece8192128.Scheme(),
+	mceliece8192128f.Scheme(),
 	mlkem512.Scheme(),
 	mlkem768.Scheme(),
 	mlkem1024.Scheme(),
+	sntrup761.Scheme(),
 	hybrid.Kyber512X25519(),
 	hybrid.Kyber768X25519(),
 	hybrid.Kyber768X448(),
 	hybrid.Kyber1024X448(),
 	hybrid.P256Kyber768Draft00(),
 	hybrid.X25519MLKEM768(),
+	hybrid.P256MLKEM768(),
+	hybrid.P384MLKEM1024(),
+	hybrid.SNTRUP761X25519SHA512(),
+	hybrid.MLKEM768X25519SHA256(),
 	xwing.Scheme(),
 }
 
-var allSchemeNames map[string]kem.Scheme
+var (
+	allSchemeNames  map[string]kem.Scheme
+	allSchemesByTLS map[uint]kem.Scheme
+)
 
 func init() {
 	allSchemeNames = make(map[string]kem.Scheme)
+	allSchemesByTLS = make(map[uint]kem.Scheme)
 	for _, scheme := range allSchemes {
 		allSchemeNames[strings.ToLower(scheme.Name())] = scheme
+		if tlsScheme, ok := scheme.(kem.TLSScheme); ok {
+			if id := tlsScheme.TLSIdentifier(); id != 0 {
+				allSchemesByTLS[id] = scheme
+			}
+		}
 	}
 }
 
@@ -71,5 +140,9 @@ func ByName(name string) kem.Scheme {
 	return allSchemeNames[strings.ToLower(name)]
 }
 
+// ByTLSIdentifier returns the scheme with the given TLS NamedGroup
+// codepoint and nil if it is not supported.
+func ByTLSIdentifier(id uint) kem.Scheme { return allSchemesByTLS[id] }
+
 // All returns all KEM schemes supported.
 func All() []kem.Scheme { a := allSchemes; return a[:] }
@@ -148,17 +148,60 @@ func Example_schemes() {
 	// HPKE_KEM_X25519_HKDF_SHA256
 	// HPKE_KEM_X448_HKDF_SHA512
 	// FrodoKEM-640-SHAKE
+	// FrodoKEM-976-SHAKE
+	// FrodoKEM-1344-SHAKE
+	// FrodoKEM-640-AES
+	// FrodoKEM-976-AES
+	// FrodoKEM-1344-AES
+	// HQC-128
+	// HQC-192
+	// HQC-256
 	// Kyber512
 	// Kyber768
 	// Kyber1024
+	// mceliece348864
+	// mceliece348864f
+	// mceliece460896
+	// mceliece460896f
+	// mceliece6688128
+	// mceliece6688128f
+	// mceliece6960119
+	// mceliece6960119f
+	// mceliece8192128
+	// mceliece8192128f
 	// ML-KEM-512
 	// ML-KEM-768
 	// ML-KEM-1024
+	// sntrup761
 	// Kyber512-X25519
 	// Kyber768-X25519
 	// Kyber768-X448
 	// Kyber1024-X448
 	// P256Kyber768Draft00
 	// X25519MLKEM768
+	// SecP256r1MLKEM768
+	// SecP384r1MLKEM1024
+	// sntrup761x25519-sha512
+	// mlkem768x25519-sha256
 	// X-Wing
 }
+
+func TestByTLSIdentifier(t *testing.T) {
+	for _, tc := range []struct {
+		id   uint
+		name string
+	}{
+		{0x11eb, "SecP256r1MLKEM768"},
+		{0x11ec, "X25519MLKEM768"},
+		{0x11ed, "SecP384r1MLKEM1024"},
+		{0x6399, "Kyber768-X25519"},
+	} {
+		scheme := schemes.ByTLSIdentifier(tc.id)
+		if scheme == nil || scheme.Name() != tc.name {
+			t.Fatalf("%#x: expected %s; got %v", tc.id, tc.name, scheme)
+		}
+	}
+	if schemes.ByTLSIdentifier(0) != nil {
+		t.Fatal()
+	}
+}
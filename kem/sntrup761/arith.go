@@ -0,0 +1,201 @@
+package sntrup761
+
+// Arithmetic in Z/3 and Z/q, and in the rings R/3 = (Z/3)[x]/(xᵖ - x - 1)
+// and R/q = (Z/q)[x]/(xᵖ - x - 1). Elements of Z/3 and Z/q are represented
+// by the integers in [-1, 1] and [-(q-1)/2, (q-1)/2] respectively.
+
+const (
+	p   = 761
+	q   = 4591
+	w   = 286
+	q12 = (q - 1) / 2
+)
+
+// An element of Z/3 or a coefficient of a short polynomial.
+type small = int8
+
+// An element of Z/q.
+type fq = int16
+
+// Returns -1 if x < 0, and 0 otherwise.
+func negativeMask(x int32) int32 { return x >> 31 }
+
+// Returns -1 if x ≠ 0, and 0 otherwise.
+func nonzeroMask(x int32) int32 { return negativeMask(x) | negativeMask(-x) }
+
+// Returns the representative of x modulo 3 in [-1, 1], for
+// |x| < 2²⁴. Division by a constant compiles to a multiplication, so
+// this runs in constant time.
+func f3Freeze(x int32) small {
+	return small(uint32(x+1+3<<24)%3) - 1
+}
+
+// Returns the representative of x modulo q in [-(q-1)/2, (q-1)/2], for
+// |x| < 2²⁸.
+func fqFreeze(x int32) fq {
+	return fq(int32(uint32(x+q12+q<<16)%q) - q12)
+}
+
+// Returns 1/a in Z/q, computed as aᑫ⁻².
+func fqRecip(a fq) fq {
+	ai := a
+	for i := 1; i < q-2; i++ {
+		ai = fqFreeze(int32(a) * int32(ai))
+	}
+	return ai
+}
+
+// Returns the rounding of each coefficient of a to a multiple of 3.
+func round(out, a []fq) {
+	for i := range out {
+		out[i] = a[i] - fq(f3Freeze(int32(a[i])))
+	}
+}
+
+// Returns in out the product of f and g in R/q, where g is small.
+func rqMultSmall(out []fq, f []fq, g []small) {
+	var fg [2*p - 1]int32
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			fg[i+j] += int32(f[i]) * int32(g[j])
+		}
+	}
+	for i := range fg {
+		fg[i] = int32(fqFreeze(fg[i]))
+	}
+	// Reduce modulo xᵖ - x - 1.
+	for i := 2*p - 2; i >= p; i-- {
+		fg[i-p] = int32(fqFreeze(fg[i-p] + fg[i]))
+		fg[i-p+1] = int32(fqFreeze(fg[i-p+1] + fg[i]))
+	}
+	for i := range out {
+		out[i] = fq(fg[i])
+	}
+}
+
+// Returns in out the product of f and g in R/3.
+func r3Mult(out, f, g []small) {
+	var fg [2*p - 1]int32
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			fg[i+j] += int32(f[i]) * int32(g[j])
+		}
+	}
+	for i := 2*p - 2; i >= p; i-- {
+		fg[i-p] += fg[i]
+		fg[i-p+1] += fg[i]
+	}
+	for i := range out {
+		out[i] = f3Freeze(fg[i])
+	}
+}
+
+// Sets out to 1/in in R/3, in constant time, using the divsteps of
+// Bernstein and Yang. Returns 0 if in is invertible, and -1 otherwise.
+func r3Recip(out, in []small) int32 {
+	var f, g, v, r [p + 1]small
+
+	r[0] = 1
+	f[0] = 1
+	f[p-1] = -1
+	f[p] = -1
+	for i := 0; i < p; i++ {
+		g[p-1-i] = in[i]
+	}
+
+	delta := int32(1)
+	for loop := 0; loop < 2*p-1; loop++ {
+		copy(v[1:], v[:p])
+		v[0] = 0
+
+		sign := -int32(g[0]) * int32(f[0])
+		swap := small(negativeMask(-delta) & nonzeroMask(int32(g[0])))
+		delta ^= int32(swap) & (delta ^ -delta)
+		delta++
+
+		for i := range f {
+			t := swap & (f[i] ^ g[i])
+			f[i] ^= t
+			g[i] ^= t
+			t = swap & (v[i] ^ r[i])
+			v[i] ^= t
+			r[i] ^= t
+		}
+
+		for i := range g {
+			g[i] = f3Freeze(int32(g[i]) + sign*int32(f[i]))
+		}
+		for i := range r {
+			r[i] = f3Freeze(int32(r[i]) + sign*int32(v[i]))
+		}
+
+		copy(g[:p], g[1:])
+		g[p] = 0
+	}
+
+	sign := f[0]
+	for i := 0; i < p; i++ {
+		out[i] = sign * v[p-1-i]
+	}
+	return nonzeroMask(delta)
+}
+
+// Sets out to 1/(3·in) in R/q, in constant time. Returns 0 if in is
+// invertible, and -1 otherwise.
+func rqRecip3(out []fq, in []small) int32 {
+	var f, g, v, r [p + 1]fq
+
+	r[0] = fqRecip(3)
+	f[0] = 1
+	f[p-1] = -1
+	f[p] = -1
+	for i := 0; i < p; i++ {
+		g[p-1-i] = fq(in[i])
+	}
+
+	delta := int32(1)
+	for loop := 0; loop < 2*p-1; loop++ {
+		copy(v[1:], v[:p])
+		v[0] = 0
+
+		swap := fq(negativeMask(-delta) & nonzeroMask(int32(g[0])))
+		delta ^= int32(swap) & (delta ^ -delta)
+		delta++
+
+		for i := range f {
+			t := swap & (f[i] ^ g[i])
+			f[i] ^= t
+			g[i] ^= t
+			t = swap & (v[i] ^ r[i])
+			v[i] ^= t
+			r[i] ^= t
+		}
+
+		f0 := int32(f[0])
+		g0 := int32(g[0])
+		for i := range g {
+			g[i] = fqFreeze(f0*int32(g[i]) - g0*int32(f[i]))
+		}
+		for i := range r {
+			r[i] = fqFreeze(f0*int32(r[i]) - g0*int32(v[i]))
+		}
+
+		copy(g[:p], g[1:])
+		g[p] = 0
+	}
+
+	scale := int32(fqRecip(f[0]))
+	for i := 0; i < p; i++ {
+		out[i] = fqFreeze(scale * int32(v[p-1-i]))
+	}
+	return nonzeroMask(delta)
+}
+
+// Returns 0 if r has exactly w nonzero coefficients, and -1 otherwise.
+func weightwMask(r []small) int32 {
+	var weight int32
+	for i := range r {
+		weight += int32(r[i] & 1)
+	}
+	return nonzeroMask(weight - w)
+}
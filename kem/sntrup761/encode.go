@@ -0,0 +1,166 @@
+package sntrup761
+
+// Encodes the integers R[i] in [0, M[i]) into out, as a mixed-radix
+// number, following the encoding of the NTRU Prime specification.
+func encode(out []byte, R, M []uint16) []byte {
+	if len(M) == 1 {
+		r, m := R[0], M[0]
+		for m > 1 {
+			out = append(out, byte(r))
+			r >>= 8
+			m = (m + 255) >> 8
+		}
+		return out
+	}
+
+	n := len(M)
+	R2 := make([]uint16, (n+1)/2)
+	M2 := make([]uint16, (n+1)/2)
+	i := 0
+	for ; i < n-1; i += 2 {
+		m0 := uint32(M[i])
+		r := uint32(R[i]) + uint32(R[i+1])*m0
+		m := uint32(M[i+1]) * m0
+		for m >= 16384 {
+			out = append(out, byte(r))
+			r >>= 8
+			m = (m + 255) >> 8
+		}
+		R2[i/2] = uint16(r)
+		M2[i/2] = uint16(m)
+	}
+	if i < n {
+		R2[i/2] = R[i]
+		M2[i/2] = M[i]
+	}
+	return encode(out, R2, M2)
+}
+
+// Decodes the integers out[i] in [0, M[i]) from S, as encoded by encode.
+// Invalid encodings yield values that are still in range.
+func decode(out []uint16, S []byte, M []uint16) {
+	n := len(M)
+	if n == 1 {
+		switch {
+		case M[0] == 1:
+			out[0] = 0
+		case M[0] <= 256:
+			out[0] = uint16(uint32(S[0]) % uint32(M[0]))
+		default:
+			out[0] = uint16((uint32(S[0]) + uint32(S[1])<<8) % uint32(M[0]))
+		}
+		return
+	}
+
+	R2 := make([]uint16, (n+1)/2)
+	M2 := make([]uint16, (n+1)/2)
+	bottomr := make([]uint16, n/2)
+	bottomt := make([]uint32, n/2)
+	i := 0
+	for ; i < n-1; i += 2 {
+		m := uint32(M[i]) * uint32(M[i+1])
+		switch {
+		case m > 256*16383:
+			bottomt[i/2] = 256 * 256
+			bottomr[i/2] = uint16(S[0]) + 256*uint16(S[1])
+			S = S[2:]
+			M2[i/2] = uint16((((m + 255) >> 8) + 255) >> 8)
+		case m >= 16384:
+			bottomt[i/2] = 256
+			bottomr[i/2] = uint16(S[0])
+			S = S[1:]
+			M2[i/2] = uint16((m + 255) >> 8)
+		default:
+			bottomt[i/2] = 1
+			bottomr[i/2] = 0
+			M2[i/2] = uint16(m)
+		}
+	}
+	if i < n {
+		M2[i/2] = M[i]
+	}
+	decode(R2, S, M2)
+
+	for i = 0; i < n-1; i += 2 {
+		r := uint32(bottomr[i/2]) + bottomt[i/2]*uint32(R2[i/2])
+		out[i] = uint16(r % uint32(M[i]))
+		// The reduction modulo M[i+1] is only needed for invalid inputs.
+		out[i+1] = uint16((r / uint32(M[i])) % uint32(M[i+1]))
+	}
+	if i < n {
+		out[i] = R2[i/2]
+	}
+}
+
+const (
+	smallBytes   = (p + 3) / 4
+	rqBytes      = 1158
+	roundedBytes = 1007
+)
+
+// Encodes the small polynomial f, two bits per coefficient.
+func smallEncode(s []byte, f []small) {
+	for i := 0; i < p/4; i++ {
+		x := f[4*i] + 1
+		x += (f[4*i+1] + 1) << 2
+		x += (f[4*i+2] + 1) << 4
+		x += (f[4*i+3] + 1) << 6
+		s[i] = byte(x)
+	}
+	s[p/4] = byte(f[p-1] + 1)
+}
+
+func smallDecode(f []small, s []byte) {
+	for i := 0; i < p/4; i++ {
+		x := s[i]
+		f[4*i] = small(x&3) - 1
+		f[4*i+1] = small((x>>2)&3) - 1
+		f[4*i+2] = small((x>>4)&3) - 1
+		f[4*i+3] = small((x>>6)&3) - 1
+	}
+	f[p-1] = small(s[p/4]&3) - 1
+}
+
+func rqEncode(s []byte, r []fq) {
+	R := make([]uint16, p)
+	M := make([]uint16, p)
+	for i := range R {
+		R[i] = uint16(r[i] + q12)
+		M[i] = q
+	}
+	encode(s[:0], R, M)
+}
+
+func rqDecode(r []fq, s []byte) {
+	R := make([]uint16, p)
+	M := make([]uint16, p)
+	for i := range M {
+		M[i] = q
+	}
+	decode(R, s, M)
+	for i := range r {
+		r[i] = fq(R[i]) - q12
+	}
+}
+
+func roundedEncode(s []byte, r []fq) {
+	R := make([]uint16, p)
+	M := make([]uint16, p)
+	for i := range R {
+		R[i] = uint16((uint32(r[i]+q12) * 10923) >> 15)
+		M[i] = (q + 2) / 3
+	}
+	encode(s[:0], R, M)
+}
+
+func roundedDecode(r []fq, s []byte) {
+	R := make([]uint16, p)
+	M := make([]uint16, p)
+	for i := range M {
+		M[i] = (q + 2) / 3
+	}
+	decode(R, s, M)
+	for i := range r {
+		r[i] = fq(R[i])*3 - q12
+	}
+}
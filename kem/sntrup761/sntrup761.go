@@ -0,0 +1,335 @@
+// Package sntrup761 implements the Streamlined NTRU Prime KEM sntrup761
+// as submitted to round 3 of the NIST PQC competition and described in
+//
+//	https://ntruprime.cr.yp.to/nist/ntruprime-20201007.pdf
+//
+// sntrup761 is used by OpenSSH in the sntrup761x25519-sha512 key
+// exchange, see hybrid.SNTRUP761X25519SHA512. The keys and ciphertexts
+// of this package have the encodings and sizes of the specification, but
+// they are not checked against the NIST KAT files of the reference
+// implementation nor against an OpenSSH key exchange.
+package sntrup761
+
+import (
+	"bytes"
+	cryptoRand "crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/kem"
+)
+
+const (
+	// Size of seed for NewKeyFromSeed
+	KeySeedSize = 32
+
+	// Size of seed for EncapsulateTo.
+	EncapsulationSeedSize = 32
+
+	// Size of the established shared key.
+	SharedKeySize = 32
+
+	// Size of the encapsulated shared key.
+	CiphertextSize = roundedBytes + hashBytes
+
+	// Size of a packed public key.
+	PublicKeySize = rqBytes
+
+	// Size of a packed private key.
+	PrivateKeySize = 2*smallBytes + PublicKeySize + smallBytes + hashBytes
+)
+
+const hashBytes = 32
+
+// Type of a sntrup761 public key
+type PublicKey struct {
+	pk [PublicKeySize]byte
+}
+
+// Type of a sntrup761 private key
+type PrivateKey struct {
+	// Packed f and 1/g in R/3, followed by the public key, ρ and the
+	// hash of the public key, as in the reference implementation.
+	sk [PrivateKeySize]byte
+}
+
+// Returns the first 32 bytes of SHA-512(b, in...).
+func hashPrefix(out []byte, b byte, in ...[]byte) {
+	h := sha512.New()
+	_, _ = h.Write([]byte{b})
+	for _, x := range in {
+		_, _ = h.Write(x)
+	}
+	var sum [sha512.Size]byte
+	copy(out, h.Sum(sum[:0]))
+}
+
+// Returns a random little-endian 32-bit integer read from rand.
+func urandom32(rand io.Reader) uint32 {
+	var buf [4]byte
+	_, _ = io.ReadFull(rand, buf[:])
+	return binary.LittleEndian.Uint32(buf[:])
+}
+
+// Sets out to a random small polynomial.
+func smallRandom(out []small, rand io.Reader) {
+	for i := range out {
+		out[i] = small((((urandom32(rand) & 0x3fffffff) * 3) >> 30)) - 1
+	}
+}
+
+// Sets out to a random small polynomial of weight w.
+func shortRandom(out []small, rand io.Reader) {
+	var L [p]uint32
+	for i := range L {
+		L[i] = urandom32(rand)
+	}
+	for i := 0; i < w; i++ {
+		L[i] &= ^uint32(1)
+	}
+	for i := w; i < p; i++ {
+		L[i] = (L[i] & ^uint32(2)) | 1
+	}
+	sortUint32(L[:])
+	for i := range out {
+		out[i] = small(L[i]&3) - 1
+	}
+}
+
+// NewKeyFromSeed derives a public/private keypair deterministically
+// from the given seed.
+//
+// Panics if seed is not of length KeySeedSize.
+func NewKeyFromSeed(seed []byte) (*PublicKey, *PrivateKey) {
+	if len(seed) != KeySeedSize {
+		panic("seed must be of length KeySeedSize")
+	}
+
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+	return newKey(&h)
+}
+
+// Generates a key pair using randomness read from rand, in the same way
+// as the reference implementation.
+func newKey(rand io.Reader) (*PublicKey, *PrivateKey) {
+	var pk PublicKey
+	var sk PrivateKey
+	var f, g, ginv [p]small
+	var finv, hh [p]fq
+
+	for {
+		smallRandom(g[:], rand)
+		if r3Recip(ginv[:], g[:]) == 0 {
+			break
+		}
+	}
+	shortRandom(f[:], rand)
+	rqRecip3(finv[:], f[:])
+	rqMultSmall(hh[:], finv[:], g[:])
+
+	rqEncode(pk.pk[:], hh[:])
+
+	s := sk.sk[:]
+	smallEncode(s, f[:])
+	smallEncode(s[smallBytes:], ginv[:])
+	s = s[2*smallBytes:]
+	copy(s, pk.pk[:])
+	s = s[PublicKeySize:]
+	_, _ = io.ReadFull(rand, s[:smallBytes]) // ρ
+	hashPrefix(s[smallBytes:], 4, pk.pk[:])
+
+	return &pk, &sk
+}
+
+// GenerateKeyPair generates public and private keys using entropy from rand.
+// If rand is nil, crypto/rand.Reader will be used.
+func GenerateKeyPair(rand io.Reader) (*PublicKey, *PrivateKey, error) {
+	var seed [KeySeedSize]byte
+	if rand == nil {
+		rand = cryptoRand.Reader
+	}
+	_, err := io.ReadFull(rand, seed[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, sk := NewKeyFromSeed(seed[:])
+	return pk, sk, nil
+}
+
+// Computes the ciphertext ct of the short polynomial r for the public
+// key pk with hash cache, and writes the encoding of r to rEnc.
+func hide(ct, rEnc []byte, r []small, pk, cache []byte) {
+	var h, hr [p]fq
+
+	smallEncode(rEnc, r)
+
+	rqDecode(h[:], pk)
+	rqMultSmall(hr[:], h[:], r)
+	round(hr[:], hr[:])
+	roundedEncode(ct, hr[:])
+
+	// Confirmation hash.
+	var x [2 * hashBytes]byte
+	hashPrefix(x[:hashBytes], 3, rEnc)
+	copy(x[hashBytes:], cache)
+	hashPrefix(ct[roundedBytes:], 2, x[:])
+}
+
+// Computes the session key from the encoding of r and the ciphertext.
+func hashSession(ss []byte, b byte, rEnc, ct []byte) {
+	var x [hashBytes]byte
+	hashPrefix(x[:], 3, rEnc)
+	hashPrefix(ss, b, x[:], ct)
+}
+
+// EncapsulateTo generates a shared key and ciphertext that contains it
+// for the public key using randomness from seed and writes the shared key
+// to ss and ciphertext to ct.
+//
+// Panics if ss, ct or seed are not of length SharedKeySize, CiphertextSize
+// and EncapsulationSeedSize respectively.
+//
+// seed may be nil, in which case crypto/rand.Reader is used to generate one.
+func (pk *PublicKey) EncapsulateTo(ct, ss []byte, seed []byte) {
+	if seed == nil {
+		seed = make([]byte, EncapsulationSeedSize)
+		if _, err := cryptoRand.Read(seed[:]); err != nil {
+			panic(err)
+		}
+	} else {
+		if len(seed) != EncapsulationSeedSize {
+			panic("seed must be of length EncapsulationSeedSize")
+		}
+	}
+
+	if len(ct) != CiphertextSize {
+		panic("ct must be of length CiphertextSize")
+	}
+
+	if len(ss) != SharedKeySize {
+		panic("ss must be of length SharedKeySize")
+	}
+
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+	pk.encapsulate(ct, ss, &h)
+}
+
+// Encapsulates using randomness read from rand, in the same way as the
+// reference implementation.
+func (pk *PublicKey) encapsulate(ct, ss []byte, rand io.Reader) {
+	var r [p]small
+	var rEnc [smallBytes]byte
+	var cache [hashBytes]byte
+
+	hashPrefix(cache[:], 4, pk.pk[:])
+	shortRandom(r[:], rand)
+	hide(ct, rEnc[:], r[:], pk.pk[:], cache[:])
+	hashSession(ss, 1, rEnc[:], ct)
+}
+
+// DecapsulateTo computes the shared key which is encapsulated in ct
+// for the private key.
+//
+// Panics if ct or ss are not of length CiphertextSize and SharedKeySize
+// respectively.
+func (sk *PrivateKey) DecapsulateTo(ss, ct []byte) {
+	if len(ct) != CiphertextSize {
+		panic("ct must be of length CiphertextSize")
+	}
+
+	if len(ss) != SharedKeySize {
+		panic("ss must be of length SharedKeySize")
+	}
+
+	pk := sk.sk[2*smallBytes : 2*smallBytes+PublicKeySize]
+	rho := sk.sk[2*smallBytes+PublicKeySize : 2*smallBytes+PublicKeySize+smallBytes]
+	cache := sk.sk[PrivateKeySize-hashBytes:]
+
+	var f, ginv, e, ev, r [p]small
+	var c, cf [p]fq
+
+	smallDecode(f[:], sk.sk[:])
+	smallDecode(ginv[:], sk.sk[smallBytes:])
+	roundedDecode(c[:], ct)
+
+	// e = 3·c·f in R/3 and ev = e/g.
+	rqMultSmall(cf[:], c[:], f[:])
+	for i := range e {
+		e[i] = f3Freeze(int32(fqFreeze(3 * int32(cf[i]))))
+	}
+	r3Mult(ev[:], e[:], ginv[:])
+
+	// r = ev if it has weight w, and (1, ..., 1, 0, ..., 0) otherwise.
+	mask := small(weightwMask(ev[:]))
+	for i := 0; i < w; i++ {
+		r[i] = ((ev[i] ^ 1) &^ mask) ^ 1
+	}
+	for i := w; i < p; i++ {
+		r[i] = ev[i] &^ mask
+	}
+
+	// Re-encrypt r and compare the ciphertexts.
+	var cnew [CiphertextSize]byte
+	var rEnc [smallBytes]byte
+	hide(cnew[:], rEnc[:], r[:], pk, cache)
+	ok := subtle.ConstantTimeCompare(ct, cnew[:])
+
+	// On failure replace r by ρ.
+	subtle.ConstantTimeCopy(1-ok, rEnc[:], rho)
+	hashSession(ss, byte(ok), rEnc[:], ct)
+}
+
+// Packs sk to buf.
+//
+// Panics if buf is not of size PrivateKeySize.
+func (sk *PrivateKey) Pack(buf []byte) {
+	if len(buf) != PrivateKeySize {
+		panic(kem.ErrPrivKeySize)
+	}
+	copy(buf, sk.sk[:])
+}
+
+// Unpacks sk from buf.
+//
+// Panics if buf is not of size PrivateKeySize.
+func (sk *PrivateKey) Unpack(buf []byte) {
+	if len(buf) != PrivateKeySize {
+		panic(kem.ErrPrivKeySize)
+	}
+	copy(sk.sk[:], buf)
+}
+
+// Packs pk to buf.
+//
+// Panics if buf is not of size PublicKeySize.
+func (pk *PublicKey) Pack(buf []byte) {
+	if len(buf) != PublicKeySize {
+		panic(kem.ErrPubKeySize)
+	}
+	copy(buf, pk.pk[:])
+}
+
+// Unpacks pk from buf.
+//
+// Panics if buf is not of size PublicKeySize.
+func (pk *PublicKey) Unpack(buf []byte) {
+	if len(buf) != PublicKeySize {
+		panic(kem.ErrPubKeySize)
+	}
+	copy(pk.pk[:], buf)
+}
+
+// Returns whether the packed private keys are equal.
+func (sk *PrivateKey) equal(oth *PrivateKey) bool {
+	return subtle.ConstantTimeCompare(sk.sk[:], oth.sk[:]) == 1
+}
+
+// Returns whether the packed public keys are equal.
+func (pk *PublicKey) equal(oth *PublicKey) bool {
+	return bytes.Equal(pk.pk[:], oth.pk[:])
+}
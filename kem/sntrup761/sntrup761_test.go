@@ -0,0 +1,92 @@
+package sntrup761
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSizes(t *testing.T) {
+	// As in the NTRU Prime specification and OpenSSH.
+	if PublicKeySize != 1158 || PrivateKeySize != 1763 ||
+		CiphertextSize != 1039 || SharedKeySize != 32 {
+		t.Fatal()
+	}
+}
+
+func TestRecip(t *testing.T) {
+	var f, g, ginv, one [p]small
+	var finv, h [p]fq
+
+	for {
+		smallRandom(g[:], rand.Reader)
+		if r3Recip(ginv[:], g[:]) == 0 {
+			break
+		}
+	}
+	r3Mult(one[:], g[:], ginv[:])
+	for i := range one {
+		if (i == 0 && one[i] != 1) || (i != 0 && one[i] != 0) {
+			t.Fatal("g·(1/g) ≠ 1 in R/3")
+		}
+	}
+
+	shortRandom(f[:], rand.Reader)
+	if rqRecip3(finv[:], f[:]) != 0 {
+		t.Fatal("f is not invertible in R/q")
+	}
+	rqMultSmall(h[:], finv[:], f[:])
+	for i := range h {
+		if (i == 0 && fqFreeze(3*int32(h[i])) != 1) || (i != 0 && h[i] != 0) {
+			t.Fatal("3f·(1/3f) ≠ 1 in R/q")
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	var r, r2 [p]fq
+	for i := range r {
+		r[i] = fqFreeze(int32(i * 12345))
+	}
+	var buf [rqBytes]byte
+	rqEncode(buf[:], r[:])
+	rqDecode(r2[:], buf[:])
+	if r != r2 {
+		t.Fatal("Rq encoding")
+	}
+
+	round(r[:], r[:])
+	var buf2 [roundedBytes]byte
+	roundedEncode(buf2[:], r[:])
+	roundedDecode(r2[:], buf2[:])
+	if r != r2 {
+		t.Fatal("rounded encoding")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		pk, sk, err := GenerateKeyPair(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ct [CiphertextSize]byte
+		var ss, ss2 [SharedKeySize]byte
+		pk.EncapsulateTo(ct[:], ss[:], nil)
+		sk.DecapsulateTo(ss2[:], ct[:])
+		if ss != ss2 {
+			t.Fatal("shared keys differ")
+		}
+
+		ct[i] ^= 1
+		sk.DecapsulateTo(ss2[:], ct[:])
+		if ss == ss2 {
+			t.Fatal("shared keys match for a modified ciphertext")
+		}
+
+		if !bytes.Equal(sk.Public().(*PublicKey).pk[:], pk.pk[:]) {
+			t.Fatal("public key does not match private key")
+		}
+	}
+}
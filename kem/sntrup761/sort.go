@@ -0,0 +1,43 @@
+package sntrup761
+
+// Sorts x in place in constant time with a sorting network, following
+// djbsort's uint32_sort.
+func sortUint32(x []uint32) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+
+	for p := top; p > 0; p >>= 1 {
+		for i := 0; i < n-p; i++ {
+			if i&p == 0 {
+				minMaxUint32(&x[i], &x[i+p])
+			}
+		}
+		i := 0
+		for q := top; q > p; q >>= 1 {
+			for ; i < n-q; i++ {
+				if i&p == 0 {
+					a := x[i+p]
+					for r := q; r > p; r >>= 1 {
+						minMaxUint32(&a, &x[i+r])
+					}
+					x[i+p] = a
+				}
+			}
+		}
+	}
+}
+
+// Sets (a, b) to (min(a, b), max(a, b)) in constant time.
+func minMaxUint32(a, b *uint32) {
+	c := uint32((uint64(*b) - uint64(*a)) >> 63)
+	c = -c
+	c &= *a ^ *b
+	*a ^= c
+	*b ^= c
+}
@@ -0,0 +1,299 @@
+package ohttp
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/quantumcoinproject/circl/hpke"
+)
+
+// MaxChunkSize is the maximum size of the encrypted content of a chunk,
+// including the final one. ChunkedReader rejects larger chunks with
+// ErrChunkTooLarge, so that a peer cannot make it buffer an arbitrary
+// amount of data, and ChunkedWriter splits its writes to stay within it.
+const MaxChunkSize = 1 << 20
+
+// chunkOverhead is the size of the authentication tag of the AEADs of
+// HPKE, which is added to the content of each chunk.
+const chunkOverhead = 16
+
+// finalAAD is the associated data of the last chunk of a message, which
+// prevents truncation of chunked messages.
+var finalAAD = []byte("final")
+
+// ChunkedClientContext decapsulates the chunked response to a chunked
+// request.
+type ChunkedClientContext struct {
+	ctx hpke.Context
+	enc []byte
+}
+
+// ChunkedGatewayContext encapsulates the chunked response to a chunked
+// request.
+type ChunkedGatewayContext struct {
+	ctx hpke.Context
+	enc []byte
+}
+
+// EncapsulateChunkedRequest writes the header of a chunked request to w,
+// and returns the writer of the request content along with the context
+// used to decapsulate the response. If rnd is nil, crypto/rand.Reader is
+// used.
+func (c *Client) EncapsulateChunkedRequest(rnd io.Reader, w io.Writer) (
+	*ChunkedWriter, *ChunkedClientContext, error,
+) {
+	sealer, enc, hdr, err := c.setup(rnd, chunkedRequestLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = w.Write(hdr); err != nil {
+		return nil, nil, err
+	}
+	return &ChunkedWriter{w: w, seal: sealer.Seal},
+		&ChunkedClientContext{sealer, enc}, nil
+}
+
+// DecapsulateResponse returns a reader of the content of the chunked
+// response read from r.
+func (c *ChunkedClientContext) DecapsulateResponse(r io.Reader) *ChunkedReader {
+	cr := &ChunkedReader{r: r}
+	cr.init = func() error {
+		responseNonce := make([]byte, responseNonceSize(c.ctx.Suite()))
+		if _, err := io.ReadFull(r, responseNonce); err != nil {
+			return ErrTruncated
+		}
+		aead, nonce, err := responseKey(c.ctx, chunkedResponseLabel, c.enc, responseNonce)
+		if err != nil {
+			return err
+		}
+		cr.open = (&chunkCipher{aead: aead, nonce: nonce}).open
+		return nil
+	}
+	return cr
+}
+
+// DecapsulateChunkedRequest reads the header of a chunked request from r,
+// and returns the reader of the request content along with the context
+// used to encapsulate the response.
+func (g *Gateway) DecapsulateChunkedRequest(r io.Reader) (
+	*ChunkedReader, *ChunkedGatewayContext, error,
+) {
+	opener, enc, err := g.setup(r, chunkedRequestLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ChunkedReader{r: r, open: opener.Open},
+		&ChunkedGatewayContext{opener, enc}, nil
+}
+
+// EncapsulateResponse writes the response nonce of a chunked response to
+// w, and returns the writer of the response content. If rnd is nil,
+// crypto/rand.Reader is used to generate the response nonce.
+func (c *ChunkedGatewayContext) EncapsulateResponse(rnd io.Reader, w io.Writer) (
+	*ChunkedWriter, error,
+) {
+	responseNonce, err := readResponseNonce(rnd, c.ctx.Suite())
+	if err != nil {
+		return nil, err
+	}
+	aead, nonce, err := responseKey(c.ctx, chunkedResponseLabel, c.enc, responseNonce)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(responseNonce); err != nil {
+		return nil, err
+	}
+	return &ChunkedWriter{w: w, seal: (&chunkCipher{aead: aead, nonce: nonce}).seal}, nil
+}
+
+// chunkCipher protects the chunks of a response. The nonce of each chunk
+// is the response nonce XORed with the chunk counter.
+type chunkCipher struct {
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint64
+}
+
+func (c *chunkCipher) nextNonce() []byte {
+	nonce := append([]byte(nil), c.nonce...)
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], c.counter)
+	for i := range ctr {
+		nonce[len(nonce)-len(ctr)+i] ^= ctr[i]
+	}
+	c.counter++
+	return nonce
+}
+
+func (c *chunkCipher) seal(pt, aad []byte) ([]byte, error) {
+	return c.aead.Seal(nil, c.nextNonce(), pt, aad), nil
+}
+
+func (c *chunkCipher) open(ct, aad []byte) ([]byte, error) {
+	return c.aead.Open(nil, c.nextNonce(), ct, aad)
+}
+
+// ChunkedWriter encrypts the content of a chunked message. Each call to
+// Write produces one chunk; Close must be called to write the final
+// chunk, without which the message is considered truncated.
+type ChunkedWriter struct {
+	w      io.Writer
+	seal   func(pt, aad []byte) ([]byte, error)
+	closed bool
+}
+
+// Write encrypts p as a chunk and writes it to the underlying writer.
+// Writes larger than MaxChunkSize allows are split into several chunks.
+// Empty writes produce no chunk.
+func (w *ChunkedWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+	n := 0
+	for n < len(p) {
+		pt := p[n:]
+		if len(pt) > MaxChunkSize-chunkOverhead {
+			pt = pt[:MaxChunkSize-chunkOverhead]
+		}
+		ct, err := w.seal(pt, nil)
+		if err != nil {
+			return n, err
+		}
+		chunk := appendVarint(make([]byte, 0, 8+len(ct)), uint64(len(ct)))
+		if _, err = w.w.Write(append(chunk, ct...)); err != nil {
+			return n, err
+		}
+		n += len(pt)
+	}
+	return n, nil
+}
+
+// Close writes the final chunk of the message. It does not close the
+// underlying writer.
+func (w *ChunkedWriter) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	ct, err := w.seal(nil, finalAAD)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append([]byte{0}, ct...))
+	return err
+}
+
+// ChunkedReader decrypts the content of a chunked message. Its final chunk
+// extends to the end of the underlying reader. Read returns ErrTruncated
+// if the underlying reader ends before the final chunk, and
+// ErrChunkTooLarge if a chunk exceeds MaxChunkSize.
+type ChunkedReader struct {
+	r    io.Reader
+	init func() error
+	open func(ct, aad []byte) ([]byte, error)
+	buf  []byte
+	err  error
+}
+
+// Read reads decrypted content of the message.
+func (r *ChunkedReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 && r.err == nil {
+		if err := r.next(); err != nil {
+			r.err = err
+		}
+	}
+	if len(r.buf) > 0 {
+		n := copy(p, r.buf)
+		r.buf = r.buf[n:]
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// next decrypts the next chunk into buf, and sets err to io.EOF after the
+// final chunk.
+func (r *ChunkedReader) next() error {
+	if r.init != nil {
+		if err := r.init(); err != nil {
+			return err
+		}
+		r.init = nil
+	}
+
+	length, err := readVarint(r.r)
+	if err != nil {
+		return err
+	}
+
+	var ct []byte
+	var aad []byte
+	if length == 0 {
+		aad = finalAAD
+		ct, err = io.ReadAll(io.LimitReader(r.r, MaxChunkSize+1))
+		if err == nil && len(ct) > MaxChunkSize {
+			err = ErrChunkTooLarge
+		}
+	} else if length > MaxChunkSize {
+		err = ErrChunkTooLarge
+	} else {
+		ct, err = io.ReadAll(io.LimitReader(r.r, int64(length)))
+		if err == nil && uint64(len(ct)) != length {
+			err = ErrTruncated
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	r.buf, err = r.open(ct, aad)
+	if err != nil {
+		return err
+	}
+	if length == 0 {
+		r.err = io.EOF
+	}
+	return nil
+}
+
+// appendVarint appends the variable-length integer encoding of x, as
+// specified in RFC 9000 Section 16. It panics if x ≥ 2⁶².
+func appendVarint(b []byte, x uint64) []byte {
+	switch {
+	case x < 1<<6:
+		return append(b, byte(x))
+	case x < 1<<14:
+		return binary.BigEndian.AppendUint16(b, uint16(x)|0x4000)
+	case x < 1<<30:
+		return binary.BigEndian.AppendUint32(b, uint32(x)|0x8000_0000)
+	case x < 1<<62:
+		return binary.BigEndian.AppendUint64(b, x|0xc000_0000_0000_0000)
+	default:
+		panic("ohttp: varint out of range")
+	}
+}
+
+// readVarint reads a variable-length integer from r. It returns
+// ErrTruncated if r ends before or within the integer.
+func readVarint(r io.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:1]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, ErrTruncated
+		}
+		return 0, err
+	}
+	n := 1 << (b[0] >> 6)
+	if _, err := io.ReadFull(r, b[1:n]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return 0, ErrTruncated
+		}
+		return 0, err
+	}
+	x := uint64(b[0] & 0x3f)
+	for _, c := range b[1:n] {
+		x = x<<8 | uint64(c)
+	}
+	return x, nil
+}
@@ -0,0 +1,175 @@
+package ohttp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/hpke"
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+func TestChunked(t *testing.T) {
+	chunks := [][]byte{[]byte("first"), {}, bytes.Repeat([]byte{'x'}, 20000), []byte("last")}
+	want := bytes.Join(chunks, nil)
+
+	for i, kemID := range testKEMs {
+		key, err := GenerateKeyConfig(nil, uint8(i), kemID, testAlgorithms...)
+		test.CheckNoErr(t, err, "generate key config")
+		gateway, err := NewGateway(key)
+		test.CheckNoErr(t, err, "new gateway")
+
+		for _, alg := range testAlgorithms {
+			client, err := NewClient(key.KeyConfig, alg)
+			test.CheckNoErr(t, err, "new client")
+
+			var encRequest bytes.Buffer
+			w, clientCtx, err := client.EncapsulateChunkedRequest(nil, &encRequest)
+			test.CheckNoErr(t, err, "encapsulate request")
+			for _, c := range chunks {
+				_, err = w.Write(c)
+				test.CheckNoErr(t, err, "write request")
+			}
+			test.CheckNoErr(t, w.Close(), "close request")
+			_, err = w.Write(chunks[0])
+			if !errors.Is(err, ErrClosed) {
+				test.ReportError(t, err, ErrClosed)
+			}
+
+			// Chunked requests are not valid non-chunked requests.
+			_, _, err = gateway.DecapsulateRequest(encRequest.Bytes())
+			test.CheckIsErr(t, err, "chunked request decapsulated as non-chunked")
+
+			r, gatewayCtx, err := gateway.DecapsulateChunkedRequest(
+				bytes.NewReader(encRequest.Bytes()))
+			test.CheckNoErr(t, err, "decapsulate request")
+			got, err := io.ReadAll(r)
+			test.CheckNoErr(t, err, "read request")
+			if !bytes.Equal(got, want) {
+				test.ReportError(t, len(got), len(want), kemID, alg)
+			}
+
+			var encResponse bytes.Buffer
+			w, err = gatewayCtx.EncapsulateResponse(nil, &encResponse)
+			test.CheckNoErr(t, err, "encapsulate response")
+			for _, c := range chunks {
+				_, err = w.Write(c)
+				test.CheckNoErr(t, err, "write response")
+			}
+			test.CheckNoErr(t, w.Close(), "close response")
+
+			got, err = io.ReadAll(clientCtx.DecapsulateResponse(
+				bytes.NewReader(encResponse.Bytes())))
+			test.CheckNoErr(t, err, "read response")
+			if !bytes.Equal(got, want) {
+				test.ReportError(t, len(got), len(want), kemID, alg)
+			}
+		}
+	}
+}
+
+func TestChunkedTruncation(t *testing.T) {
+	alg := testAlgorithms[0]
+	key, err := GenerateKeyConfig(nil, 1, hpke.KEM_X25519_HKDF_SHA256, alg)
+	test.CheckNoErr(t, err, "generate key config")
+	gateway, err := NewGateway(key)
+	test.CheckNoErr(t, err, "new gateway")
+	client, err := NewClient(key.KeyConfig, alg)
+	test.CheckNoErr(t, err, "new client")
+
+	var full, open bytes.Buffer
+	w, _, err := client.EncapsulateChunkedRequest(nil, &full)
+	test.CheckNoErr(t, err, "encapsulate request")
+	_, err = w.Write([]byte("chunk"))
+	test.CheckNoErr(t, err, "write request")
+	open.Write(full.Bytes())
+	test.CheckNoErr(t, w.Close(), "close request")
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"noFinal", open.Bytes()},
+		{"partialChunk", open.Bytes()[:open.Len()-1]},
+		{"finalAsChunk", append(open.Bytes()[:open.Len():open.Len()],
+			append([]byte{17}, full.Bytes()[open.Len()+1:]...)...)},
+	} {
+		r, _, err := gateway.DecapsulateChunkedRequest(bytes.NewReader(tc.data))
+		test.CheckNoErr(t, err, "decapsulate request")
+		_, err = io.ReadAll(r)
+		test.CheckIsErr(t, err, "truncated request should fail: "+tc.name)
+	}
+}
+
+func TestChunkedSizeLimit(t *testing.T) {
+	alg := testAlgorithms[0]
+	key, err := GenerateKeyConfig(nil, 1, hpke.KEM_X25519_HKDF_SHA256, alg)
+	test.CheckNoErr(t, err, "generate key config")
+	gateway, err := NewGateway(key)
+	test.CheckNoErr(t, err, "new gateway")
+	client, err := NewClient(key.KeyConfig, alg)
+	test.CheckNoErr(t, err, "new client")
+
+	// Large writes are split into chunks that the reader accepts.
+	want := bytes.Repeat([]byte{'x'}, 2*MaxChunkSize)
+	var encRequest bytes.Buffer
+	w, _, err := client.EncapsulateChunkedRequest(nil, &encRequest)
+	test.CheckNoErr(t, err, "encapsulate request")
+	_, err = w.Write(want)
+	test.CheckNoErr(t, err, "write request")
+	test.CheckNoErr(t, w.Close(), "close request")
+
+	r, _, err := gateway.DecapsulateChunkedRequest(bytes.NewReader(encRequest.Bytes()))
+	test.CheckNoErr(t, err, "decapsulate request")
+	got, err := io.ReadAll(r)
+	test.CheckNoErr(t, err, "read request")
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, len(got), len(want))
+	}
+
+	// Chunks larger than MaxChunkSize are rejected before being read.
+	var buf bytes.Buffer
+	_, _, err = client.EncapsulateChunkedRequest(nil, &buf)
+	test.CheckNoErr(t, err, "encapsulate request")
+	hdr := buf.Bytes()[:buf.Len():buf.Len()]
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"chunk", append(appendVarint(hdr, MaxChunkSize+1), make([]byte, MaxChunkSize+1)...)},
+		{"final", append(append(hdr, 0), make([]byte, MaxChunkSize+1)...)},
+	} {
+		r, _, err := gateway.DecapsulateChunkedRequest(bytes.NewReader(tc.data))
+		test.CheckNoErr(t, err, "decapsulate request")
+		_, err = io.ReadAll(r)
+		if !errors.Is(err, ErrChunkTooLarge) {
+			test.ReportError(t, err, ErrChunkTooLarge, tc.name)
+		}
+	}
+}
+
+func TestVarint(t *testing.T) {
+	for _, x := range []uint64{0, 37, 63, 64, 15293, 16383, 16384, 494878333, 1<<30 - 1, 1 << 30, 151288809941952652, 1<<62 - 1} {
+		b := appendVarint(nil, x)
+		got, err := readVarint(bytes.NewReader(b))
+		test.CheckNoErr(t, err, "read varint")
+		if got != x {
+			test.ReportError(t, got, x)
+		}
+		_, err = readVarint(bytes.NewReader(b[:len(b)-1]))
+		if !errors.Is(err, ErrTruncated) {
+			test.ReportError(t, err, ErrTruncated, x)
+		}
+	}
+
+	// Examples from RFC 9000 Appendix A.1.
+	if got := appendVarint(nil, 151288809941952652); !bytes.Equal(got,
+		[]byte{0xc2, 0x19, 0x7c, 0x5e, 0xff, 0x14, 0xe8, 0x8c}) {
+		test.ReportError(t, got, "c2197c5eff14e88c")
+	}
+	if got := appendVarint(nil, 15293); !bytes.Equal(got, []byte{0x7b, 0xbd}) {
+		test.ReportError(t, got, "7bbd")
+	}
+}
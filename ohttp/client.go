@@ -0,0 +1,85 @@
+package ohttp
+
+import (
+	"io"
+
+	"github.com/quantumcoinproject/circl/hpke"
+	"github.com/quantumcoinproject/circl/kem"
+)
+
+// Client encapsulates requests to a gateway using one of the symmetric
+// algorithms of its key configuration.
+type Client struct {
+	suite hpke.Suite
+	pkR   kem.PublicKey
+	hdr   []byte
+}
+
+// NewClient returns a client for the gateway with the given key
+// configuration. It returns ErrUnsupportedAlgorithm if alg is not listed
+// in the configuration.
+func NewClient(config KeyConfig, alg SymmetricAlgorithm) (*Client, error) {
+	suite, err := config.suite(alg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		suite: suite,
+		pkR:   config.PublicKey,
+		hdr:   header(config.KeyID, suite),
+	}, nil
+}
+
+// ClientContext decapsulates the response to an encapsulated request.
+type ClientContext struct {
+	ctx hpke.Context
+	enc []byte
+}
+
+// setup returns a sealer for a request whose info uses label, along with
+// the encapsulated key, and the header followed by the encapsulated key.
+func (c *Client) setup(rnd io.Reader, label string) (
+	hpke.Sealer, []byte, []byte, error,
+) {
+	sender, err := c.suite.NewSender(c.pkR, requestInfo(label, c.hdr))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	enc, sealer, err := sender.Setup(rnd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	out := make([]byte, 0, len(c.hdr)+len(enc))
+	out = append(append(out, c.hdr...), enc...)
+	return sealer, enc, out, nil
+}
+
+// EncapsulateRequest encrypts a request, and returns the encapsulated
+// request along with the context used to decapsulate the response.
+// If rnd is nil, crypto/rand.Reader is used.
+func (c *Client) EncapsulateRequest(rnd io.Reader, request []byte) (
+	[]byte, *ClientContext, error,
+) {
+	sealer, enc, encRequest, err := c.setup(rnd, requestLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	ct, err := sealer.Seal(request, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(encRequest, ct...), &ClientContext{sealer, enc}, nil
+}
+
+// DecapsulateResponse decrypts an encapsulated response.
+func (c *ClientContext) DecapsulateResponse(encResponse []byte) ([]byte, error) {
+	n := responseNonceSize(c.ctx.Suite())
+	if len(encResponse) < n {
+		return nil, ErrMalformedResponse
+	}
+	aead, nonce, err := responseKey(c.ctx, responseLabel, c.enc, encResponse[:n])
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, encResponse[n:], nil)
+}
@@ -0,0 +1,118 @@
+package ohttp
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/quantumcoinproject/circl/hpke"
+)
+
+// Gateway decapsulates requests encrypted to any of its keys.
+type Gateway struct {
+	keys map[uint8]*PrivateKeyConfig
+}
+
+// NewGateway returns a gateway holding the given keys. It returns
+// ErrDuplicateKeyID if two keys have the same identifier.
+func NewGateway(keys ...*PrivateKeyConfig) (*Gateway, error) {
+	g := &Gateway{keys: make(map[uint8]*PrivateKeyConfig, len(keys))}
+	for _, k := range keys {
+		if _, ok := g.keys[k.KeyID]; ok {
+			return nil, ErrDuplicateKeyID
+		}
+		g.keys[k.KeyID] = k
+	}
+	return g, nil
+}
+
+// KeyConfigs returns the application/ohttp-keys encoding of the key
+// configurations of the gateway, ordered by key identifier.
+func (g *Gateway) KeyConfigs() ([]byte, error) {
+	configs := make([]KeyConfig, 0, len(g.keys))
+	for _, k := range g.keys {
+		configs = append(configs, k.KeyConfig)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].KeyID < configs[j].KeyID
+	})
+	return MarshalKeyConfigs(configs...)
+}
+
+// GatewayContext encapsulates the response to a decapsulated request.
+type GatewayContext struct {
+	ctx hpke.Context
+	enc []byte
+}
+
+// setup reads the header and the encapsulated key of a request from r,
+// and returns an opener for the request whose info uses label, along
+// with the encapsulated key.
+func (g *Gateway) setup(r io.Reader, label string) (hpke.Opener, []byte, error) {
+	hdr := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, nil, ErrMalformedRequest
+	}
+	key, ok := g.keys[hdr[0]]
+	if !ok {
+		return nil, nil, ErrUnknownKeyID
+	}
+	alg := SymmetricAlgorithm{
+		KDF:  hpke.KDF(uint16(hdr[3])<<8 | uint16(hdr[4])),
+		AEAD: hpke.AEAD(uint16(hdr[5])<<8 | uint16(hdr[6])),
+	}
+	if hpke.KEM(uint16(hdr[1])<<8|uint16(hdr[2])) != key.KEM {
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+	suite, err := key.suite(alg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enc := make([]byte, key.KEM.Scheme().CiphertextSize())
+	if _, err = io.ReadFull(r, enc); err != nil {
+		return nil, nil, ErrMalformedRequest
+	}
+	receiver, err := suite.NewReceiver(key.PrivateKey, requestInfo(label, hdr))
+	if err != nil {
+		return nil, nil, err
+	}
+	opener, err := receiver.Setup(enc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return opener, enc, nil
+}
+
+// DecapsulateRequest decrypts an encapsulated request, and returns the
+// request along with the context used to encapsulate the response.
+func (g *Gateway) DecapsulateRequest(encRequest []byte) (
+	[]byte, *GatewayContext, error,
+) {
+	r := bytes.NewReader(encRequest)
+	opener, enc, err := g.setup(r, requestLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	request, err := opener.Open(encRequest[len(encRequest)-r.Len():], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return request, &GatewayContext{opener, enc}, nil
+}
+
+// EncapsulateResponse encrypts a response. If rnd is nil,
+// crypto/rand.Reader is used to generate the response nonce.
+func (c *GatewayContext) EncapsulateResponse(rnd io.Reader, response []byte) (
+	[]byte, error,
+) {
+	responseNonce, err := readResponseNonce(rnd, c.ctx.Suite())
+	if err != nil {
+		return nil, err
+	}
+	aead, nonce, err := responseKey(c.ctx, responseLabel, c.enc, responseNonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(responseNonce, nonce, response, nil), nil
+}
@@ -0,0 +1,219 @@
+package ohttp
+
+import (
+	"io"
+
+	"github.com/quantumcoinproject/circl/hpke"
+	"github.com/quantumcoinproject/circl/kem"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// SymmetricAlgorithm is a pair of HPKE KDF and AEAD algorithms.
+type SymmetricAlgorithm struct {
+	KDF  hpke.KDF
+	AEAD hpke.AEAD
+}
+
+// IsValid reports whether the algorithms are supported and can be used
+// to protect messages, that is, the AEAD is not export-only.
+func (a SymmetricAlgorithm) IsValid() bool {
+	return a.KDF.IsValid() && a.AEAD.IsValid() && a.AEAD != hpke.AEAD_EXPORT_ONLY
+}
+
+// KeyConfig is the public key configuration of a gateway, as specified in
+// RFC 9458 Section 3. It lists the symmetric algorithms that clients may
+// use with the public key.
+type KeyConfig struct {
+	KeyID      uint8
+	KEM        hpke.KEM
+	PublicKey  kem.PublicKey
+	Algorithms []SymmetricAlgorithm
+}
+
+// PrivateKeyConfig is a KeyConfig along with its private key, as held by
+// a gateway.
+type PrivateKeyConfig struct {
+	KeyConfig
+	PrivateKey kem.PrivateKey
+}
+
+// GenerateKeyConfig generates a key pair for the given KEM and returns its
+// configuration with the given key identifier and symmetric algorithms.
+// If rnd is nil, crypto/rand.Reader is used.
+func GenerateKeyConfig(rnd io.Reader, keyID uint8, kemID hpke.KEM,
+	algs ...SymmetricAlgorithm,
+) (*PrivateKeyConfig, error) {
+	if !kemID.IsValid() || len(algs) == 0 {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	for _, alg := range algs {
+		if !alg.IsValid() {
+			return nil, ErrUnsupportedAlgorithm
+		}
+	}
+
+	scheme := kemID.Scheme()
+	var pk kem.PublicKey
+	var sk kem.PrivateKey
+	if rnd == nil {
+		var err error
+		pk, sk, err = scheme.GenerateKeyPair()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		seed := make([]byte, scheme.SeedSize())
+		if _, err := io.ReadFull(rnd, seed); err != nil {
+			return nil, err
+		}
+		pk, sk = scheme.DeriveKeyPair(seed)
+	}
+
+	return &PrivateKeyConfig{
+		KeyConfig: KeyConfig{
+			KeyID:      keyID,
+			KEM:        kemID,
+			PublicKey:  pk,
+			Algorithms: append([]SymmetricAlgorithm(nil), algs...),
+		},
+		PrivateKey: sk,
+	}, nil
+}
+
+// MarshalBinary returns the encoding of the key configuration.
+func (c *KeyConfig) MarshalBinary() ([]byte, error) {
+	var b cryptobyte.Builder
+	if err := c.marshal(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes()
+}
+
+// UnmarshalBinary parses a key configuration. It returns
+// ErrUnsupportedAlgorithm if the KEM is not supported.
+func (c *KeyConfig) UnmarshalBinary(data []byte) error {
+	s := cryptobyte.String(data)
+	if err := c.unmarshal(&s); err != nil {
+		return err
+	}
+	if !s.Empty() {
+		return ErrInvalidKeyConfig
+	}
+	return nil
+}
+
+func (c *KeyConfig) marshal(b *cryptobyte.Builder) error {
+	if !c.KEM.IsValid() || len(c.Algorithms) == 0 ||
+		len(c.Algorithms) > (1<<16-4)/4 {
+		return ErrInvalidKeyConfig
+	}
+	pk, err := c.PublicKey.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if len(pk) != c.KEM.Scheme().PublicKeySize() {
+		return ErrInvalidKeyConfig
+	}
+
+	b.AddUint8(c.KeyID)
+	b.AddUint16(uint16(c.KEM))
+	b.AddBytes(pk)
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for _, alg := range c.Algorithms {
+			b.AddUint16(uint16(alg.KDF))
+			b.AddUint16(uint16(alg.AEAD))
+		}
+	})
+	return nil
+}
+
+func (c *KeyConfig) unmarshal(s *cryptobyte.String) error {
+	var kemID uint16
+	if !s.ReadUint8(&c.KeyID) || !s.ReadUint16(&kemID) {
+		return ErrInvalidKeyConfig
+	}
+	c.KEM = hpke.KEM(kemID)
+	if !c.KEM.IsValid() {
+		return ErrUnsupportedAlgorithm
+	}
+
+	scheme := c.KEM.Scheme()
+	var pk []byte
+	var algs cryptobyte.String
+	if !s.ReadBytes(&pk, scheme.PublicKeySize()) ||
+		!s.ReadUint16LengthPrefixed(&algs) ||
+		algs.Empty() || len(algs)%4 != 0 {
+		return ErrInvalidKeyConfig
+	}
+	var err error
+	c.PublicKey, err = scheme.UnmarshalBinaryPublicKey(pk)
+	if err != nil {
+		return err
+	}
+
+	c.Algorithms = make([]SymmetricAlgorithm, 0, len(algs)/4)
+	for !algs.Empty() {
+		var kdfID, aeadID uint16
+		algs.ReadUint16(&kdfID)
+		algs.ReadUint16(&aeadID)
+		c.Algorithms = append(c.Algorithms,
+			SymmetricAlgorithm{hpke.KDF(kdfID), hpke.AEAD(aeadID)})
+	}
+	return nil
+}
+
+// MarshalKeyConfigs returns the encoding of a list of key configurations,
+// each prefixed by its length, as served with the application/ohttp-keys
+// media type.
+func MarshalKeyConfigs(configs ...KeyConfig) ([]byte, error) {
+	var b cryptobyte.Builder
+	for i := range configs {
+		var err error
+		b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+			err = configs[i].marshal(b)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes()
+}
+
+// UnmarshalKeyConfigs parses an application/ohttp-keys list of key
+// configurations. Configurations using an unsupported KEM are skipped.
+func UnmarshalKeyConfigs(data []byte) ([]KeyConfig, error) {
+	var configs []KeyConfig
+	s := cryptobyte.String(data)
+	for !s.Empty() {
+		var body cryptobyte.String
+		if !s.ReadUint16LengthPrefixed(&body) {
+			return nil, ErrInvalidKeyConfig
+		}
+		var c KeyConfig
+		err := c.unmarshal(&body)
+		if err == ErrUnsupportedAlgorithm {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !body.Empty() {
+			return nil, ErrInvalidKeyConfig
+		}
+		configs = append(configs, c)
+	}
+	return configs, nil
+}
+
+// suite returns the HPKE suite for alg, if alg is listed in c and valid.
+func (c *KeyConfig) suite(alg SymmetricAlgorithm) (hpke.Suite, error) {
+	if !alg.IsValid() || !c.KEM.IsValid() {
+		return hpke.Suite{}, ErrUnsupportedAlgorithm
+	}
+	for _, a := range c.Algorithms {
+		if a == alg {
+			return hpke.NewSuite(c.KEM, alg.KDF, alg.AEAD), nil
+		}
+	}
+	return hpke.Suite{}, ErrUnsupportedAlgorithm
+}
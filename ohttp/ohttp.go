@@ -0,0 +1,122 @@
+// Package ohttp implements Oblivious HTTP message encapsulation.
+//
+// Oblivious HTTP allows a client to send requests to a target server
+// through a relay, such that the relay does not learn the content of the
+// requests, and the gateway that decrypts them does not learn who sent
+// them. Requests are encrypted with HPKE to a public key of the gateway
+// published as a KeyConfig, and responses are encrypted with a key
+// exported from the HPKE context of the request.
+//
+//	Client(config)                               Gateway(sk)
+//	=================================================================
+//	encReq, ctx = EncapsulateRequest(req)
+//
+//	                            encReq
+//	                          ---------->
+//
+//	                            req, ctx = DecapsulateRequest(encReq)
+//	                            encRes = ctx.EncapsulateResponse(res)
+//
+//	                            encRes
+//	                          <----------
+//
+//	res = ctx.DecapsulateResponse(encRes)
+//
+// The chunked variant encrypts requests and responses incrementally, so
+// they can be streamed. Its messages are not interchangeable with those of
+// the non-chunked variant.
+//
+// Specifications:
+//   - Oblivious HTTP: RFC 9458.
+//   - Chunked Oblivious HTTP Messages: draft-ietf-ohai-chunked-ohttp.
+package ohttp
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/quantumcoinproject/circl/hpke"
+)
+
+const (
+	requestLabel         = "message/bhttp request"
+	responseLabel        = "message/bhttp response"
+	chunkedRequestLabel  = "message/bhttp chunked request"
+	chunkedResponseLabel = "message/bhttp chunked response"
+
+	// headerSize is the size of the header of an encapsulated request:
+	// key identifier, KEM, KDF and AEAD.
+	headerSize = 1 + 2 + 2 + 2
+)
+
+// header returns the header of an encapsulated request.
+func header(keyID uint8, suite hpke.Suite) []byte {
+	kemID, kdfID, aeadID := suite.Params()
+	return []byte{
+		keyID,
+		byte(kemID >> 8), byte(kemID),
+		byte(kdfID >> 8), byte(kdfID),
+		byte(aeadID >> 8), byte(aeadID),
+	}
+}
+
+// requestInfo returns the HPKE info of a request with the given header.
+func requestInfo(label string, hdr []byte) []byte {
+	info := make([]byte, 0, len(label)+1+len(hdr))
+	info = append(info, label...)
+	info = append(info, 0)
+	return append(info, hdr...)
+}
+
+// responseNonceSize returns the size of the response nonce, max(Nn, Nk).
+func responseNonceSize(suite hpke.Suite) int {
+	_, _, aeadID := suite.Params()
+	return int(max(aeadID.KeySize(), aeadID.NonceSize()))
+}
+
+// responseKey derives the AEAD and the nonce protecting a response from
+// the HPKE context of the request, following RFC 9458 Section 4.4.
+func responseKey(ctx hpke.Context, label string, enc, responseNonce []byte) (
+	cipher.AEAD, []byte, error,
+) {
+	_, kdfID, aeadID := ctx.Suite().Params()
+	secret := ctx.Export([]byte(label), uint(responseNonceSize(ctx.Suite())))
+	salt := make([]byte, 0, len(enc)+len(responseNonce))
+	salt = append(append(salt, enc...), responseNonce...)
+
+	prk := kdfID.Extract(secret, salt)
+	key := kdfID.Expand(prk, []byte("key"), aeadID.KeySize())
+	nonce := kdfID.Expand(prk, []byte("nonce"), aeadID.NonceSize())
+	aead, err := aeadID.New(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, nonce, nil
+}
+
+// readResponseNonce reads a random response nonce for suite from rnd.
+// If rnd is nil, crypto/rand.Reader is used.
+func readResponseNonce(rnd io.Reader, suite hpke.Suite) ([]byte, error) {
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	nonce := make([]byte, responseNonceSize(suite))
+	if _, err := io.ReadFull(rnd, nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+var (
+	ErrInvalidKeyConfig     = errors.New("ohttp: invalid key configuration")
+	ErrUnsupportedAlgorithm = errors.New("ohttp: unsupported algorithm")
+	ErrUnknownKeyID         = errors.New("ohttp: unknown key identifier")
+	ErrDuplicateKeyID       = errors.New("ohttp: duplicate key identifier")
+	ErrMalformedRequest     = errors.New("ohttp: malformed encapsulated request")
+	ErrMalformedResponse    = errors.New("ohttp: malformed encapsulated response")
+	ErrTruncated            = errors.New("ohttp: chunked message is truncated")
+	ErrClosed               = errors.New("ohttp: write to closed chunked message")
+	ErrChunkTooLarge        = errors.New("ohttp: chunk exceeds the maximum size")
+)
@@ -0,0 +1,224 @@
+package ohttp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/hpke"
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+func hexB(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	test.CheckNoErr(t, err, "bad hex")
+	return b
+}
+
+// Test vectors from RFC 9458 Appendix A.
+func TestRFC9458(t *testing.T) {
+	var (
+		skR           = hexB(t, "3c168975674b2fa8e465970b79c8dcf09f1c741626480bd4c6162fc5b6a98e1a")
+		keyConfig     = hexB(t, "01002031e1f05a740102115220e9af918f738674aec95f54db6e04eb705aae8e79815500080001000100010003")
+		request       = hexB(t, "00034745540568747470730b6578616d706c652e636f6d012f")
+		encRequest    = hexB(t, "010020000100014b28f881333e7c164ffc499ad9796f877f4e1051ee6d31bad19dec96c208b4726374e469135906992e1268c594d2a10c695d858c40a026e7965e7d86b83dd440b2c0185204b4d63525")
+		response      = hexB(t, "0140c8")
+		responseNonce = hexB(t, "c789e7151fcba46158ca84b04464910d")
+		encResponse   = hexB(t, "c789e7151fcba46158ca84b04464910d86f9013e404feea014e7be4a441f234f857fbd")
+	)
+
+	var config KeyConfig
+	err := config.UnmarshalBinary(keyConfig)
+	test.CheckNoErr(t, err, "unmarshal key config")
+	want := []SymmetricAlgorithm{
+		{hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KDF_HKDF_SHA256, hpke.AEAD_ChaCha20Poly1305},
+	}
+	if config.KeyID != 1 || config.KEM != hpke.KEM_X25519_HKDF_SHA256 ||
+		len(config.Algorithms) != len(want) ||
+		config.Algorithms[0] != want[0] || config.Algorithms[1] != want[1] {
+		t.Fatalf("unexpected key config: %+v", config)
+	}
+	got, err := config.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal key config")
+	if !bytes.Equal(got, keyConfig) {
+		test.ReportError(t, got, keyConfig)
+	}
+
+	sk, err := config.KEM.Scheme().UnmarshalBinaryPrivateKey(skR)
+	test.CheckNoErr(t, err, "unmarshal private key")
+	if !sk.Public().Equal(config.PublicKey) {
+		t.Fatal("private key does not match the key config")
+	}
+	gateway, err := NewGateway(&PrivateKeyConfig{config, sk})
+	test.CheckNoErr(t, err, "new gateway")
+
+	gotRequest, gatewayCtx, err := gateway.DecapsulateRequest(encRequest)
+	test.CheckNoErr(t, err, "decapsulate request")
+	if !bytes.Equal(gotRequest, request) {
+		test.ReportError(t, gotRequest, request)
+	}
+
+	gotResponse, err := gatewayCtx.EncapsulateResponse(
+		bytes.NewReader(responseNonce), response)
+	test.CheckNoErr(t, err, "encapsulate response")
+	if !bytes.Equal(gotResponse, encResponse) {
+		test.ReportError(t, gotResponse, encResponse)
+	}
+
+	// The client context derives the same response key as the gateway.
+	clientCtx := &ClientContext{gatewayCtx.ctx, gatewayCtx.enc}
+	gotResponse, err = clientCtx.DecapsulateResponse(encResponse)
+	test.CheckNoErr(t, err, "decapsulate response")
+	if !bytes.Equal(gotResponse, response) {
+		test.ReportError(t, gotResponse, response)
+	}
+}
+
+var testKEMs = []hpke.KEM{
+	hpke.KEM_P256_HKDF_SHA256,
+	hpke.KEM_X25519_HKDF_SHA256,
+	hpke.KEM_X448_HKDF_SHA512,
+	hpke.KEM_X25519_KYBER768_DRAFT00,
+	hpke.KEM_XWING,
+	hpke.KEM_ML_KEM_768,
+}
+
+var testAlgorithms = []SymmetricAlgorithm{
+	{hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+	{hpke.KDF_HKDF_SHA384, hpke.AEAD_AES256GCM},
+	{hpke.KDF_HKDF_SHA512, hpke.AEAD_ChaCha20Poly1305},
+}
+
+func TestRoundTrip(t *testing.T) {
+	request := []byte("request")
+	response := []byte("response")
+
+	for i, kemID := range testKEMs {
+		key, err := GenerateKeyConfig(nil, uint8(i), kemID, testAlgorithms...)
+		test.CheckNoErr(t, err, "generate key config")
+		gateway, err := NewGateway(key)
+		test.CheckNoErr(t, err, "new gateway")
+
+		for _, alg := range testAlgorithms {
+			client, err := NewClient(key.KeyConfig, alg)
+			test.CheckNoErr(t, err, "new client")
+
+			encRequest, clientCtx, err := client.EncapsulateRequest(nil, request)
+			test.CheckNoErr(t, err, "encapsulate request")
+			gotRequest, gatewayCtx, err := gateway.DecapsulateRequest(encRequest)
+			test.CheckNoErr(t, err, "decapsulate request")
+			if !bytes.Equal(gotRequest, request) {
+				test.ReportError(t, gotRequest, request, kemID, alg)
+			}
+
+			encResponse, err := gatewayCtx.EncapsulateResponse(nil, response)
+			test.CheckNoErr(t, err, "encapsulate response")
+			gotResponse, err := clientCtx.DecapsulateResponse(encResponse)
+			test.CheckNoErr(t, err, "decapsulate response")
+			if !bytes.Equal(gotResponse, response) {
+				test.ReportError(t, gotResponse, response, kemID, alg)
+			}
+
+			encRequest[len(encRequest)-1] ^= 1
+			_, _, err = gateway.DecapsulateRequest(encRequest)
+			test.CheckIsErr(t, err, "tampered request should fail")
+			encResponse[len(encResponse)-1] ^= 1
+			_, err = clientCtx.DecapsulateResponse(encResponse)
+			test.CheckIsErr(t, err, "tampered response should fail")
+		}
+	}
+}
+
+func TestGatewayErrors(t *testing.T) {
+	alg := testAlgorithms[0]
+	key, err := GenerateKeyConfig(nil, 7, hpke.KEM_X25519_HKDF_SHA256, alg)
+	test.CheckNoErr(t, err, "generate key config")
+	gateway, err := NewGateway(key)
+	test.CheckNoErr(t, err, "new gateway")
+	got, err := gateway.KeyConfigs()
+	test.CheckNoErr(t, err, "gateway key configs")
+	want, err := MarshalKeyConfigs(key.KeyConfig)
+	test.CheckNoErr(t, err, "marshal key configs")
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want)
+	}
+
+	_, err = NewGateway(key, key)
+	if !errors.Is(err, ErrDuplicateKeyID) {
+		test.ReportError(t, err, ErrDuplicateKeyID)
+	}
+	_, err = NewClient(key.KeyConfig, testAlgorithms[1])
+	if !errors.Is(err, ErrUnsupportedAlgorithm) {
+		test.ReportError(t, err, ErrUnsupportedAlgorithm)
+	}
+	_, err = NewClient(key.KeyConfig,
+		SymmetricAlgorithm{hpke.KDF_HKDF_SHA256, hpke.AEAD_EXPORT_ONLY})
+	if !errors.Is(err, ErrUnsupportedAlgorithm) {
+		test.ReportError(t, err, ErrUnsupportedAlgorithm)
+	}
+
+	client, err := NewClient(key.KeyConfig, alg)
+	test.CheckNoErr(t, err, "new client")
+	encRequest, _, err := client.EncapsulateRequest(nil, []byte("request"))
+	test.CheckNoErr(t, err, "encapsulate request")
+
+	for _, tc := range []struct {
+		name   string
+		modify func(b []byte) []byte
+		err    error
+	}{
+		{"keyID", func(b []byte) []byte { b[0]++; return b }, ErrUnknownKeyID},
+		{"kem", func(b []byte) []byte { b[2]++; return b }, ErrUnsupportedAlgorithm},
+		{"aead", func(b []byte) []byte { b[6]++; return b }, ErrUnsupportedAlgorithm},
+		{"header", func(b []byte) []byte { return b[:headerSize-1] }, ErrMalformedRequest},
+		{"enc", func(b []byte) []byte { return b[:headerSize+31] }, ErrMalformedRequest},
+	} {
+		b := tc.modify(append([]byte(nil), encRequest...))
+		_, _, err = gateway.DecapsulateRequest(b)
+		if !errors.Is(err, tc.err) {
+			test.ReportError(t, err, tc.err, tc.name)
+		}
+	}
+}
+
+func TestKeyConfigs(t *testing.T) {
+	var configs []KeyConfig
+	for i, kemID := range testKEMs {
+		key, err := GenerateKeyConfig(nil, uint8(i), kemID, testAlgorithms[:i%3+1]...)
+		test.CheckNoErr(t, err, "generate key config")
+		configs = append(configs, key.KeyConfig)
+	}
+
+	data, err := MarshalKeyConfigs(configs...)
+	test.CheckNoErr(t, err, "marshal key configs")
+	got, err := UnmarshalKeyConfigs(data)
+	test.CheckNoErr(t, err, "unmarshal key configs")
+	if len(got) != len(configs) {
+		test.ReportError(t, len(got), len(configs))
+	}
+	for i := range got {
+		c := &configs[i]
+		if got[i].KeyID != c.KeyID || got[i].KEM != c.KEM ||
+			!got[i].PublicKey.Equal(c.PublicKey) ||
+			len(got[i].Algorithms) != len(c.Algorithms) {
+			test.ReportError(t, got[i], *c, i)
+		}
+	}
+
+	// Configurations with unknown KEMs are skipped.
+	unknown := []byte{0, 11, 42, 0xff, 0xfe, 1, 2, 0, 4, 0, 1, 0, 1}
+	got, err = UnmarshalKeyConfigs(append(unknown, data...))
+	test.CheckNoErr(t, err, "unmarshal key configs")
+	if len(got) != len(configs) {
+		test.ReportError(t, len(got), len(configs))
+	}
+
+	_, err = UnmarshalKeyConfigs(data[:len(data)-1])
+	test.CheckIsErr(t, err, "truncated key configs should fail")
+	var c KeyConfig
+	err = c.UnmarshalBinary(append(data[2:], 0))
+	test.CheckIsErr(t, err, "trailing data should fail")
+}
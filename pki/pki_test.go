@@ -53,3 +53,23 @@ func TestPEM(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemeLookup(t *testing.T) {
+	for _, scheme := range schemes.All() {
+		if cert, ok := scheme.(pki.CertificateScheme); ok {
+			if got := pki.SchemeByOid(cert.Oid()); got != scheme {
+				t.Fatalf("%v: SchemeByOid returned %v", scheme.Name(), got)
+			}
+		}
+		if tls, ok := scheme.(pki.TLSScheme); ok {
+			if got := pki.SchemeByTLSID(tls.TLSIdentifier()); got != scheme {
+				t.Fatalf("%v: SchemeByTLSID returned %v", scheme.Name(), got)
+			}
+		}
+	}
+
+	// ecdsa_secp384r1_sha384
+	if s := pki.SchemeByTLSID(0x0503); s == nil || s.Name() != "ECDSA-P384-SHA384" {
+		t.Fatal("ECDSA-P384-SHA384 not found by its TLS identifier")
+	}
+}
@@ -0,0 +1,207 @@
+package bls
+
+import (
+	cryptoRand "crypto/rand"
+	"io"
+
+	GG "github.com/quantumcoinproject/circl/ecc/bls12381"
+)
+
+// BatchVerifier verifies many signatures at once, each of its own message
+// under its own public key, which is faster than verifying them one at a
+// time. A batch can mix signatures of the three schemes.
+//
+// The zero value is an empty batch ready to use.
+type BatchVerifier[K KeyGroup] struct {
+	entries []batchEntry
+}
+
+// batchEntry has the points of the verification equation of a signature,
+// e(pk, H(m)) = e(g1, sig) for keys in G1, or e(H(m), pk) = e(sig, g2) for
+// keys in G2.
+type batchEntry struct {
+	ok bool
+	g1 GG.G1 // The public key or the hash of the message, for keys in G1 or G2.
+	g2 GG.G2 // The hash of the message or the public key, for keys in G1 or G2.
+	s1 GG.G1 // The signature, for keys in G2.
+	s2 GG.G2 // The signature, for keys in G1.
+}
+
+// NewBatchVerifier returns an empty batch with capacity for n signatures.
+func NewBatchVerifier[K KeyGroup](n int) *BatchVerifier[K] {
+	return &BatchVerifier[K]{entries: make([]batchEntry, 0, n)}
+}
+
+// Add appends a signature of the message under the public key, of the basic
+// scheme, to the batch. Signatures or keys that are not valid are added
+// too, and make the batch invalid.
+func (v *BatchVerifier[K]) Add(pub *PublicKey[K], msg []byte, sig Signature) {
+	v.add(pub, msg, sig, suiteBasic)
+}
+
+// AddAug appends a signature of the message under the public key, of the
+// message augmentation scheme, to the batch.
+func (v *BatchVerifier[K]) AddAug(pub *PublicKey[K], msg []byte, sig Signature) {
+	v.add(pub, augment(pub, msg), sig, suiteAug)
+}
+
+// AddPop appends a signature of the message under the public key, of the
+// proof of possession scheme, to the batch.
+func (v *BatchVerifier[K]) AddPop(pub *PublicKey[K], msg []byte, sig Signature) {
+	v.add(pub, msg, sig, suitePop)
+}
+
+func (v *BatchVerifier[K]) add(pub *PublicKey[K], msg []byte, sig Signature, s suite) {
+	v.entries = append(v.entries, batchEntry{})
+	e := &v.entries[len(v.entries)-1]
+	if !pub.Validate() {
+		return
+	}
+
+	switch any(pub).(type) {
+	case *PublicKey[G1]:
+		k := any(pub.key).(G1)
+		e.g1 = k.g
+		e.g2.Hash(msg, []byte(s.g2))
+		e.ok = e.s2.SetBytes(sig) == nil
+	case *PublicKey[G2]:
+		k := any(pub.key).(G2)
+		e.g2 = k.g
+		e.g1.Hash(msg, []byte(s.g1))
+		e.ok = e.s1.SetBytes(sig) == nil
+	default:
+		panic(ErrInvalid)
+	}
+}
+
+// Len returns the number of signatures in the batch.
+func (v *BatchVerifier[K]) Len() int { return len(v.entries) }
+
+// Verify returns true if all the signatures in the batch are valid, and
+// true for an empty batch. It checks a random linear combination of the
+// verification equations with a single product of pairings, which shares
+// the final exponentiation, taking 128-bit coefficients from rand, or
+// crypto/rand.Reader if rand is nil. It returns false if it fails reading
+// from rand.
+func (v *BatchVerifier[K]) Verify(rand io.Reader) bool {
+	if rand == nil {
+		rand = cryptoRand.Reader
+	}
+
+	idx := make([]int, len(v.entries))
+	for i := range idx {
+		idx[i] = i
+	}
+	return v.verify(rand, idx)
+}
+
+// VerifyEach returns whether each signature in the batch is valid. It first
+// verifies the whole batch as [BatchVerifier.Verify] does, and if that
+// fails, bisects the batch to find the invalid signatures, verifying each
+// half as a batch. So, each invalid signature costs about log2(n) batch
+// verifications of decreasing size.
+func (v *BatchVerifier[K]) VerifyEach(rand io.Reader) []bool {
+	if rand == nil {
+		rand = cryptoRand.Reader
+	}
+
+	valid := make([]bool, len(v.entries))
+	idx := make([]int, 0, len(v.entries))
+	for i := range v.entries {
+		if v.entries[i].ok {
+			idx = append(idx, i)
+		}
+	}
+
+	v.bisect(rand, idx, valid)
+	return valid
+}
+
+// bisect sets valid[i] to true for the valid signatures with indices in
+// idx, which must be decoded.
+func (v *BatchVerifier[K]) bisect(rand io.Reader, idx []int, valid []bool) {
+	if len(idx) == 0 {
+		return
+	}
+	if v.verify(rand, idx) {
+		for _, i := range idx {
+			valid[i] = true
+		}
+		return
+	}
+	if len(idx) == 1 {
+		return
+	}
+
+	half := len(idx) / 2
+	v.bisect(rand, idx[:half], valid)
+	v.bisect(rand, idx[half:], valid)
+}
+
+// verify returns true if the signatures with indices in idx are valid.
+// With random coefficients r_i, it checks for keys in G1 that
+//
+//	prod e([r_i]pk_i, H(m_i)) * e([-1]g1, sum [r_i]sig_i) = 1,
+//
+// and for keys in G2 that
+//
+//	prod e([r_i]H(m_i), pk_i) * e([-1]sum [r_i]sig_i, g2) = 1.
+func (v *BatchVerifier[K]) verify(rand io.Reader, idx []int) bool {
+	if len(idx) == 0 {
+		return true
+	}
+
+	n := len(idx)
+	listG1 := make([]*GG.G1, n+1)
+	listG2 := make([]*GG.G2, n+1)
+	scalars := make([]*GG.Scalar, n+1)
+
+	var r [16]byte
+	for j, i := range idx {
+		e := &v.entries[i]
+		if !e.ok {
+			return false
+		}
+		if _, err := io.ReadFull(rand, r[:]); err != nil {
+			return false
+		}
+		scalars[j] = new(GG.Scalar)
+		scalars[j].SetBytes(r[:])
+		listG1[j], listG2[j] = &e.g1, &e.g2
+	}
+
+	scalars[n] = new(GG.Scalar)
+	scalars[n].SetOne()
+	scalars[n].Neg()
+
+	var identity bool
+	switch any(v).(type) {
+	case *BatchVerifier[G1]:
+		var sum, rs GG.G2
+		sum.SetIdentity()
+		for j, i := range idx {
+			rs.ScalarMult(scalars[j], &v.entries[i].s2)
+			sum.Add(&sum, &rs)
+		}
+		listG1[n], listG2[n] = GG.G1Generator(), &sum
+		identity = sum.IsIdentity()
+	case *BatchVerifier[G2]:
+		var sum, rs GG.G1
+		sum.SetIdentity()
+		for j, i := range idx {
+			rs.ScalarMult(scalars[j], &v.entries[i].s1)
+			sum.Add(&sum, &rs)
+		}
+		listG1[n], listG2[n] = &sum, GG.G2Generator()
+		identity = sum.IsIdentity()
+	default:
+		panic(ErrInvalid)
+	}
+
+	// Pairings with the identity are one, and ProdPair does not support it.
+	if identity {
+		listG1, listG2, scalars = listG1[:n], listG2[:n], scalars[:n]
+	}
+
+	return GG.ProdPair(listG1, listG2, scalars).IsIdentity()
+}
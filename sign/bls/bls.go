@@ -1,9 +1,24 @@
 // Package bls provides BLS signatures using the BLS12-381 pairing curve.
 //
 // This packages implements the IETF/CFRG draft for BLS signatures [1].
-// Currently only the BASIC mode (one of the three modes specified
-// in the draft) is supported. The pairing function is instantiated
-// with the BLS12-381 curve.
+// The pairing function is instantiated with the BLS12-381 curve.
+//
+// # Modes
+//
+// The draft specifies three schemes, which differ in how they prevent
+// rogue key attacks on aggregate signatures. Each one uses its own domain
+// separation tag, so signatures of one scheme are not valid in another.
+//
+//   - Basic: [Sign], [Verify] and [VerifyAggregate]. Aggregate signatures
+//     must be of distinct messages.
+//   - Message augmentation: [SignAug], [VerifyAug] and [VerifyAggregateAug].
+//     Messages are prefixed with the public key of the signer, so aggregate
+//     signatures can be of equal messages.
+//   - Proof of possession: [SignPop], [VerifyPop], [VerifyAggregatePop] and
+//     [FastAggregateVerify]. Every public key comes with a proof, see
+//     [PopProve], which must be checked with [PopVerify] before aggregating
+//     it. Then, signatures of the same message are verified against the
+//     aggregate of their public keys, see [AggregatePublicKeys].
 //
 // # Groups
 //
@@ -47,8 +62,25 @@ var (
 )
 
 const (
-	dstG1 = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
-	dstG2 = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+	dstG1         = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
+	dstG2         = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+	dstAugG1      = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_AUG_"
+	dstAugG2      = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_"
+	dstPopG1      = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	dstPopG2      = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	dstPopProofG1 = "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	dstPopProofG2 = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// suite has the domain separation tags of a ciphersuite used for hashing
+// to G1 and to G2.
+type suite struct{ g1, g2 string }
+
+var (
+	suiteBasic    = suite{dstG1, dstG2}
+	suiteAug      = suite{dstAugG1, dstAugG2}
+	suitePop      = suite{dstPopG1, dstPopG2}
+	suitePopProof = suite{dstPopProofG1, dstPopProofG2}
 )
 
 type Signature = []byte
@@ -67,8 +99,8 @@ type (
 func (f *G1) setBytes(b []byte) error { return f.g.SetBytes(b) }
 func (f *G2) setBytes(b []byte) error { return f.g.SetBytes(b) }
 
-func (f *G1) hash(msg []byte) { f.g.Hash(msg, []byte(dstG1)) }
-func (f *G2) hash(msg []byte) { f.g.Hash(msg, []byte(dstG2)) }
+func (f *G1) hash(msg []byte, s suite) { f.g.Hash(msg, []byte(s.g1)) }
+func (f *G2) hash(msg []byte, s suite) { f.g.Hash(msg, []byte(s.g2)) }
 
 // KeyGroup determines the group used for keys, while the other
 // group is used for signatures.
@@ -259,6 +291,10 @@ func KeyGen[K KeyGroup](ikm, salt, keyInfo []byte) (*PrivateKey[K], error) {
 // Sign computes a signature of a message using a key (defined in
 // G1 or G1).
 func Sign[K KeyGroup](k *PrivateKey[K], msg []byte) Signature {
+	return sign(k, msg, suiteBasic)
+}
+
+func sign[K KeyGroup](k *PrivateKey[K], msg []byte, s suite) Signature {
 	if !k.Validate() {
 		panic(ErrInvalidKey)
 	}
@@ -266,12 +302,12 @@ func Sign[K KeyGroup](k *PrivateKey[K], msg []byte) Signature {
 	switch any(k).(type) {
 	case *PrivateKey[G1]:
 		var Q GG.G2
-		Q.Hash(msg, []byte(dstG2))
+		Q.Hash(msg, []byte(s.g2))
 		Q.ScalarMult(&k.key, &Q)
 		return Q.BytesCompressed()
 	case *PrivateKey[G2]:
 		var Q GG.G1
-		Q.Hash(msg, []byte(dstG1))
+		Q.Hash(msg, []byte(s.g1))
 		Q.ScalarMult(&k.key, &Q)
 		return Q.BytesCompressed()
 	default:
@@ -282,10 +318,14 @@ func Sign[K KeyGroup](k *PrivateKey[K], msg []byte) Signature {
 // Verify returns true if the signature of a message is valid for the
 // corresponding public key.
 func Verify[K KeyGroup](pub *PublicKey[K], msg []byte, sig Signature) bool {
+	return verify(pub, msg, sig, suiteBasic)
+}
+
+func verify[K KeyGroup](pub *PublicKey[K], msg []byte, sig Signature, s suite) bool {
 	var (
 		a, b interface {
 			setBytes([]byte) error
-			hash([]byte)
+			hash([]byte, suite)
 		}
 		listG1 [2]*GG.G1
 		listG2 [2]*GG.G2
@@ -315,7 +355,7 @@ func Verify[K KeyGroup](pub *PublicKey[K], msg []byte, sig Signature) bool {
 	if !pub.Validate() {
 		return false
 	}
-	a.hash(msg)
+	a.hash(msg, s)
 
 	res := GG.ProdPairFrac(listG1[:], listG2[:], []int{1, -1})
 	return res.IsIdentity()
@@ -361,6 +401,12 @@ func Aggregate[K KeyGroup](k K, sigs []Signature) (Signature, error) {
 // the list of messages and public keys provided. The slices must have
 // equal size and have at least one element.
 func VerifyAggregate[K KeyGroup](pubs []*PublicKey[K], msgs [][]byte, aggSig Signature) bool {
+	return verifyAggregate(pubs, msgs, aggSig, suiteBasic)
+}
+
+func verifyAggregate[K KeyGroup](
+	pubs []*PublicKey[K], msgs [][]byte, aggSig Signature, s suite,
+) bool {
 	if len(pubs) != len(msgs) || len(pubs) == 0 {
 		return false
 	}
@@ -384,7 +430,7 @@ func VerifyAggregate[K KeyGroup](pubs []*PublicKey[K], msgs [][]byte, aggSig Sig
 	case []*PublicKey[G1]:
 		for i := range msgs {
 			listG2[i] = new(GG.G2)
-			listG2[i].Hash(msgs[i], []byte(dstG2))
+			listG2[i].Hash(msgs[i], []byte(s.g2))
 
 			xP := any(pubs[i].key).(G1)
 			listG1[i] = &xP.g
@@ -399,7 +445,7 @@ func VerifyAggregate[K KeyGroup](pubs []*PublicKey[K], msgs [][]byte, aggSig Sig
 	case []*PublicKey[G2]:
 		for i := range msgs {
 			listG1[i] = new(GG.G1)
-			listG1[i].Hash(msgs[i], []byte(dstG1))
+			listG1[i].Hash(msgs[i], []byte(s.g1))
 
 			xP := any(pubs[i].key).(G2)
 			listG2[i] = &xP.g
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"testing"
 
+	GG "github.com/quantumcoinproject/circl/ecc/bls12381"
 	"github.com/quantumcoinproject/circl/internal/test"
 	"github.com/quantumcoinproject/circl/sign/bls"
 )
@@ -21,6 +22,12 @@ func TestBls(t *testing.T) {
 	t.Run("G2/Errors", testErrors[bls.G2])
 	t.Run("G1/Aggregation", testAggregation[bls.G1])
 	t.Run("G2/Aggregation", testAggregation[bls.G2])
+	t.Run("G1/ProofOfPossession", testPop[bls.G1])
+	t.Run("G2/ProofOfPossession", testPop[bls.G2])
+	t.Run("G1/Augmentation", testAug[bls.G1])
+	t.Run("G2/Augmentation", testAug[bls.G2])
+	t.Run("G1/Batch", testBatch[bls.G1])
+	t.Run("G2/Batch", testBatch[bls.G2])
 }
 
 func testBls[K bls.KeyGroup](t *testing.T) {
@@ -152,6 +159,167 @@ func testAggregation[K bls.KeyGroup](t *testing.T) {
 	test.CheckOk(ok, "failed to verify aggregated signature", t)
 }
 
+func testPop[K bls.KeyGroup](t *testing.T) {
+	const N = 3
+
+	ikm := [32]byte{}
+	msg := []byte("hello world")
+	sigs := make([]bls.Signature, N)
+	pubKeys := make([]*bls.PublicKey[K], N)
+
+	for i := range sigs {
+		_, _ = rand.Reader.Read(ikm[:])
+		priv, err := bls.KeyGen[K](ikm[:], nil, nil)
+		test.CheckNoErr(t, err, "failed to keygen")
+		pubKeys[i] = priv.PublicKey()
+
+		proof := bls.PopProve(priv)
+		test.CheckOk(bls.PopVerify(pubKeys[i], proof), "failed to verify proof", t)
+		test.CheckOk(!bls.VerifyPop(pubKeys[i], msg, proof), "should fail: proof as signature", t)
+
+		sigs[i] = bls.SignPop(priv, msg)
+		test.CheckOk(bls.VerifyPop(pubKeys[i], msg, sigs[i]), "failed verification", t)
+		test.CheckOk(!bls.Verify(pubKeys[i], msg, sigs[i]), "should fail: basic scheme", t)
+	}
+
+	// A proof is only valid for its public key.
+	_, _ = rand.Reader.Read(ikm[:])
+	other, err := bls.KeyGen[K](ikm[:], nil, nil)
+	test.CheckNoErr(t, err, "failed to keygen")
+	test.CheckOk(!bls.PopVerify(pubKeys[0], bls.PopProve(other)), "should fail: proof of another key", t)
+
+	aggSig, err := bls.Aggregate(*new(K), sigs)
+	test.CheckNoErr(t, err, "failed to aggregate")
+	test.CheckOk(bls.FastAggregateVerify(pubKeys, msg, aggSig), "failed to verify aggregated signature", t)
+	test.CheckOk(!bls.FastAggregateVerify(pubKeys[1:], msg, aggSig), "should fail: missing key", t)
+	test.CheckOk(!bls.FastAggregateVerify(pubKeys, []byte("other"), aggSig), "should fail: wrong message", t)
+	test.CheckOk(!bls.FastAggregateVerify[K](nil, msg, aggSig), "should fail: empty keys", t)
+
+	msgs := [][]byte{msg, msg, msg}
+	test.CheckOk(bls.VerifyAggregatePop(pubKeys, msgs, aggSig), "failed to verify aggregated signature", t)
+
+	aggPub, err := bls.AggregatePublicKeys(pubKeys)
+	test.CheckNoErr(t, err, "failed to aggregate public keys")
+	test.CheckOk(bls.VerifyPop(aggPub, msg, aggSig), "failed to verify with aggregated key", t)
+
+	_, err = bls.AggregatePublicKeys[K](nil)
+	test.CheckIsErr(t, err, "should fail: empty keys")
+}
+
+func testAug[K bls.KeyGroup](t *testing.T) {
+	const N = 3
+
+	ikm := [32]byte{}
+	msg := []byte("hello world")
+	msgs := make([][]byte, N)
+	sigs := make([]bls.Signature, N)
+	pubKeys := make([]*bls.PublicKey[K], N)
+
+	for i := range sigs {
+		_, _ = rand.Reader.Read(ikm[:])
+		priv, err := bls.KeyGen[K](ikm[:], nil, nil)
+		test.CheckNoErr(t, err, "failed to keygen")
+		pubKeys[i] = priv.PublicKey()
+
+		// Messages can be equal in this scheme.
+		msgs[i] = msg
+		sigs[i] = bls.SignAug(priv, msg)
+		test.CheckOk(bls.VerifyAug(pubKeys[i], msg, sigs[i]), "failed verification", t)
+		test.CheckOk(!bls.Verify(pubKeys[i], msg, sigs[i]), "should fail: basic scheme", t)
+		test.CheckOk(!bls.VerifyAug(pubKeys[i], []byte("other"), sigs[i]), "should fail: wrong message", t)
+	}
+
+	aggSig, err := bls.Aggregate(*new(K), sigs)
+	test.CheckNoErr(t, err, "failed to aggregate")
+	test.CheckOk(bls.VerifyAggregateAug(pubKeys, msgs, aggSig), "failed to verify aggregated signature", t)
+	test.CheckOk(!bls.VerifyAggregateAug(pubKeys, msgs[1:], aggSig), "should fail: length mismatch", t)
+
+	msgs[0] = []byte("other")
+	test.CheckOk(!bls.VerifyAggregateAug(pubKeys, msgs, aggSig), "should fail: wrong message", t)
+}
+
+func testBatch[K bls.KeyGroup](t *testing.T) {
+	const N = 9
+
+	ikm := [32]byte{}
+	v := bls.NewBatchVerifier[K](N)
+	msgs := make([][]byte, N)
+	sigs := make([]bls.Signature, N)
+	pubKeys := make([]*bls.PublicKey[K], N)
+
+	for i := range sigs {
+		_, _ = rand.Reader.Read(ikm[:])
+		priv, err := bls.KeyGen[K](ikm[:], nil, nil)
+		test.CheckNoErr(t, err, "failed to keygen")
+		pubKeys[i] = priv.PublicKey()
+		msgs[i] = []byte(fmt.Sprintf("Message number: %v", i))
+
+		// Mixes signatures of the three schemes.
+		switch i % 3 {
+		case 0:
+			sigs[i] = bls.Sign(priv, msgs[i])
+			v.Add(pubKeys[i], msgs[i], sigs[i])
+		case 1:
+			sigs[i] = bls.SignAug(priv, msgs[i])
+			v.AddAug(pubKeys[i], msgs[i], sigs[i])
+		case 2:
+			sigs[i] = bls.SignPop(priv, msgs[i])
+			v.AddPop(pubKeys[i], msgs[i], sigs[i])
+		}
+	}
+
+	test.CheckOk(v.Len() == N, "wrong length of batch", t)
+	test.CheckOk(v.Verify(nil), "failed to verify batch", t)
+	for i, ok := range v.VerifyEach(nil) {
+		test.CheckOk(ok, fmt.Sprintf("failed to verify signature %v", i), t)
+	}
+	test.CheckOk(new(bls.BatchVerifier[K]).Verify(nil), "failed to verify empty batch", t)
+
+	// Invalid signatures are found by bisection.
+	bad := bls.NewBatchVerifier[K](N)
+	invalid := map[int]bool{2: true, 3: true, 7: true}
+	for i := range sigs {
+		switch i {
+		case 2:
+			bad.AddPop(pubKeys[i], msgs[i], sigs[i-1])
+		case 3:
+			bad.Add(pubKeys[i], []byte("other"), sigs[i])
+		case 7:
+			bad.AddAug(pubKeys[i], msgs[i], sigs[i][:1])
+		default:
+			[]func(*bls.PublicKey[K], []byte, bls.Signature){
+				bad.Add, bad.AddAug, bad.AddPop,
+			}[i%3](pubKeys[i], msgs[i], sigs[i])
+		}
+	}
+
+	test.CheckOk(!bad.Verify(nil), "should fail: invalid signatures", t)
+	for i, ok := range bad.VerifyEach(nil) {
+		if ok == invalid[i] {
+			test.ReportError(t, ok, !invalid[i], i)
+		}
+	}
+
+	// Signatures that are the identity cancel out in the random linear
+	// combination, but not in the pairings with the public keys.
+	var identity bls.Signature
+	switch any(pubKeys).(type) {
+	case []*bls.PublicKey[bls.G1]:
+		var P GG.G2
+		P.SetIdentity()
+		identity = P.BytesCompressed()
+	case []*bls.PublicKey[bls.G2]:
+		var P GG.G1
+		P.SetIdentity()
+		identity = P.BytesCompressed()
+	}
+	zero := new(bls.BatchVerifier[K])
+	for i := range pubKeys {
+		zero.Add(pubKeys[i], msgs[i], identity)
+	}
+	test.CheckOk(!zero.Verify(nil), "should fail: identity signatures", t)
+}
+
 func BenchmarkBls(b *testing.B) {
 	b.Run("G1", benchmarkBls[bls.G1])
 	b.Run("G2", benchmarkBls[bls.G2])
@@ -216,4 +384,16 @@ func benchmarkBls[K bls.KeyGroup](b *testing.B) {
 			_ = bls.VerifyAggregate(pubKeys, msgs, aggSig)
 		}
 	})
+
+	b.Run("BatchVerify3", func(b *testing.B) {
+		v := bls.NewBatchVerifier[K](N)
+		for i := range sigs {
+			v.Add(pubKeys[i], msgs[i], sigs[i])
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = v.Verify(nil)
+		}
+	})
 }
@@ -0,0 +1,121 @@
+package bls
+
+// SignAug computes a signature of a message using a key, under the message
+// augmentation scheme. The signed message is prefixed with the public key.
+func SignAug[K KeyGroup](k *PrivateKey[K], msg []byte) Signature {
+	return sign(k, augment(k.PublicKey(), msg), suiteAug)
+}
+
+// VerifyAug returns true if the signature of a message is valid for the
+// public key, under the message augmentation scheme.
+func VerifyAug[K KeyGroup](pub *PublicKey[K], msg []byte, sig Signature) bool {
+	return verify(pub, augment(pub, msg), sig, suiteAug)
+}
+
+// VerifyAggregateAug returns true if the aggregated signature is valid for
+// the list of messages and public keys provided, under the message
+// augmentation scheme. Unlike [VerifyAggregate], the messages need not be
+// distinct. The slices must have equal size and have at least one element.
+func VerifyAggregateAug[K KeyGroup](pubs []*PublicKey[K], msgs [][]byte, aggSig Signature) bool {
+	if len(pubs) != len(msgs) {
+		return false
+	}
+
+	augMsgs := make([][]byte, len(msgs))
+	for i := range msgs {
+		augMsgs[i] = augment(pubs[i], msgs[i])
+	}
+
+	return verifyAggregate(pubs, augMsgs, aggSig, suiteAug)
+}
+
+// augment returns PK || msg, where PK is the serialized public key.
+func augment[K KeyGroup](pub *PublicKey[K], msg []byte) []byte {
+	pk, _ := pub.MarshalBinary()
+	return append(pk, msg...)
+}
+
+// SignPop computes a signature of a message using a key, under the proof of
+// possession scheme.
+func SignPop[K KeyGroup](k *PrivateKey[K], msg []byte) Signature {
+	return sign(k, msg, suitePop)
+}
+
+// VerifyPop returns true if the signature of a message is valid for the
+// public key, under the proof of possession scheme.
+func VerifyPop[K KeyGroup](pub *PublicKey[K], msg []byte, sig Signature) bool {
+	return verify(pub, msg, sig, suitePop)
+}
+
+// VerifyAggregatePop returns true if the aggregated signature is valid for
+// the list of messages and public keys provided, under the proof of
+// possession scheme. The proofs of all public keys must have been checked
+// with [PopVerify]. The slices must have equal size and have at least one
+// element.
+func VerifyAggregatePop[K KeyGroup](pubs []*PublicKey[K], msgs [][]byte, aggSig Signature) bool {
+	return verifyAggregate(pubs, msgs, aggSig, suitePop)
+}
+
+// PopProve computes the proof of possession of the private key, which is a
+// signature of its public key with a domain separation tag of its own.
+func PopProve[K KeyGroup](k *PrivateKey[K]) Signature {
+	pk, _ := k.PublicKey().MarshalBinary()
+	return sign(k, pk, suitePopProof)
+}
+
+// PopVerify returns true if the proof of possession is valid for the
+// public key.
+func PopVerify[K KeyGroup](pub *PublicKey[K], proof Signature) bool {
+	pk, _ := pub.MarshalBinary()
+	return verify(pub, pk, proof, suitePopProof)
+}
+
+// FastAggregateVerify returns true if the aggregated signature is valid for
+// a single message signed with all the public keys provided, under the
+// proof of possession scheme. The proofs of all public keys must have been
+// checked with [PopVerify]. The slice must have at least one element.
+func FastAggregateVerify[K KeyGroup](pubs []*PublicKey[K], msg []byte, aggSig Signature) bool {
+	aggPub, err := AggregatePublicKeys(pubs)
+	if err != nil {
+		return false
+	}
+
+	return verify(aggPub, msg, aggSig, suitePop)
+}
+
+// AggregatePublicKeys produces the public key that verifies the aggregate
+// of signatures of the same message by the public keys given. This is safe
+// against rogue key attacks only if the proofs of all public keys have been
+// checked with [PopVerify]. It returns [ErrInvalidKey] if the aggregate is
+// the identity.
+func AggregatePublicKeys[K KeyGroup](pubs []*PublicKey[K]) (*PublicKey[K], error) {
+	if len(pubs) == 0 {
+		return nil, ErrAggregate
+	}
+
+	agg := new(PublicKey[K])
+	switch any(pubs).(type) {
+	case []*PublicKey[G1]:
+		P := any(&agg.key).(*G1)
+		P.g.SetIdentity()
+		for _, pub := range pubs {
+			Q := any(&pub.key).(*G1)
+			P.g.Add(&P.g, &Q.g)
+		}
+	case []*PublicKey[G2]:
+		P := any(&agg.key).(*G2)
+		P.g.SetIdentity()
+		for _, pub := range pubs {
+			Q := any(&pub.key).(*G2)
+			P.g.Add(&P.g, &Q.g)
+		}
+	default:
+		panic(ErrInvalid)
+	}
+
+	if !agg.Validate() {
+		return nil, ErrInvalidKey
+	}
+
+	return agg, nil
+}
@@ -65,3 +65,31 @@ func testVector[K bls.KeyGroup](t *testing.T, group, name string) {
 		}
 	}
 }
+
+func TestVectorsPop(t *testing.T) {
+	// Test vector taken from the BLS tests of the Ethereum consensus
+	// specifications, which use keys in G1 and the proof of possession
+	// scheme.
+	// Repository: https://github.com/ethereum/consensus-spec-tests
+	// Path: /tests/general/phase0/bls/sign/bls/
+	sk, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	msg := make([]byte, 32)
+	wantPub := "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"
+	wantSig := "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6" +
+		"076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24" +
+		"802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55"
+
+	priv := new(bls.PrivateKey[bls.KeyG1SigG2])
+	test.CheckNoErr(t, priv.UnmarshalBinary(sk), "error decoding sk")
+	pub, err := priv.PublicKey().MarshalBinary()
+	test.CheckNoErr(t, err, "error encoding pk")
+	if got := hex.EncodeToString(pub); got != wantPub {
+		test.ReportError(t, got, wantPub)
+	}
+
+	sig := bls.SignPop(priv, msg)
+	if got := hex.EncodeToString(sig); got != wantSig {
+		test.ReportError(t, got, wantSig, msg)
+	}
+	test.CheckOk(bls.VerifyPop(priv.PublicKey(), msg, sig), "cannot verify", t)
+}
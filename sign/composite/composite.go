@@ -0,0 +1,249 @@
+// Package composite provides composite ML-DSA signatures, which combine
+// ML-DSA with a traditional signature algorithm, as specified in
+// [draft-ietf-lamps-pq-composite-sigs]. A composite signature is valid only
+// if both of its component signatures are.
+//
+// The [ID] represents the following combinations:
+//   - ML-DSA-44: [MLDSA44_Ed25519_SHA512] and [MLDSA44_ECDSA_P256_SHA256].
+//   - ML-DSA-65: [MLDSA65_ECDSA_P256_SHA512], [MLDSA65_ECDSA_P384_SHA512]
+//     and [MLDSA65_Ed25519_SHA512].
+//   - ML-DSA-87: [MLDSA87_ECDSA_P384_SHA512], [MLDSA87_Ed448_SHAKE256] and
+//     [MLDSA87_ECDSA_P521_SHA512].
+//
+// The combinations with RSA and with Brainpool curves are not supported.
+//
+// A message M with context ctx is signed through its representative
+//
+//	M' = Prefix || Label || len(ctx) || ctx || PH(M)
+//
+// where Prefix is the string "CompositeAlgorithmSignatures2025", Label
+// identifies the combination and PH is its pre-hash function. M' is signed
+// both with ML-DSA, using Label as context, and with the traditional
+// algorithm.
+//
+// Public keys, private keys and signatures are the concatenations of their
+// ML-DSA and traditional components, where the ML-DSA private key is its
+// seed. Signatures have a variable length with ECDSA.
+//
+// [draft-ietf-lamps-pq-composite-sigs]: https://datatracker.ietf.org/doc/draft-ietf-lamps-pq-composite-sigs/
+package composite
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/sign"
+	"github.com/quantumcoinproject/circl/sign/mldsa"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa44"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa65"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa87"
+)
+
+// [ID] identifies the supported composite signature algorithms.
+// Note that the zero value is not a valid identifier.
+type ID byte
+
+//nolint:stylecheck
+const (
+	MLDSA44_Ed25519_SHA512    ID = iota + 1 // MLDSA44-Ed25519-SHA512
+	MLDSA44_ECDSA_P256_SHA256               // MLDSA44-ECDSA-P256-SHA256
+	MLDSA65_ECDSA_P256_SHA512               // MLDSA65-ECDSA-P256-SHA512
+	MLDSA65_ECDSA_P384_SHA512               // MLDSA65-ECDSA-P384-SHA512
+	MLDSA65_Ed25519_SHA512                  // MLDSA65-Ed25519-SHA512
+	MLDSA87_ECDSA_P384_SHA512               // MLDSA87-ECDSA-P384-SHA512
+	MLDSA87_Ed448_SHAKE256                  // MLDSA87-Ed448-SHAKE256
+	MLDSA87_ECDSA_P521_SHA512               // MLDSA87-ECDSA-P521-SHA512
+	_MaxParams
+)
+
+// SeedSize is the size of the seeds from which keys are derived.
+const SeedSize = 32
+
+// Size of the ML-DSA seed in private keys.
+const mldsaSeedSize = 32
+
+// Prefix of message representatives, see Section 4.
+const prefix = "CompositeAlgorithmSignatures2025"
+
+// params contains the components of a composite algorithm.
+type params struct {
+	name    string        // Name of the algorithm.
+	oid     int           // Last arc of the object identifier.
+	mldsa   sign.Scheme   // ML-DSA component.
+	trad    traditional   // Traditional component.
+	preHash mldsa.PreHash // Pre-hash function of messages.
+	ID                    // Identifier of the algorithm.
+}
+
+// Stores all the supported (read-only) algorithms.
+var supportedParams = [_MaxParams - 1]params{
+	{ID: MLDSA44_Ed25519_SHA512, oid: 39, mldsa: mldsa44.Scheme(), trad: ed25519Component{}, preHash: mldsa.SHA512, name: "MLDSA44-Ed25519-SHA512"},
+	{ID: MLDSA44_ECDSA_P256_SHA256, oid: 40, mldsa: mldsa44.Scheme(), trad: ecdsaP256, preHash: mldsa.SHA256, name: "MLDSA44-ECDSA-P256-SHA256"},
+	{ID: MLDSA65_ECDSA_P256_SHA512, oid: 45, mldsa: mldsa65.Scheme(), trad: ecdsaP256, preHash: mldsa.SHA512, name: "MLDSA65-ECDSA-P256-SHA512"},
+	{ID: MLDSA65_ECDSA_P384_SHA512, oid: 46, mldsa: mldsa65.Scheme(), trad: ecdsaP384, preHash: mldsa.SHA512, name: "MLDSA65-ECDSA-P384-SHA512"},
+	{ID: MLDSA65_Ed25519_SHA512, oid: 48, mldsa: mldsa65.Scheme(), trad: ed25519Component{}, preHash: mldsa.SHA512, name: "MLDSA65-Ed25519-SHA512"},
+	{ID: MLDSA87_ECDSA_P384_SHA512, oid: 49, mldsa: mldsa87.Scheme(), trad: ecdsaP384, preHash: mldsa.SHA512, name: "MLDSA87-ECDSA-P384-SHA512"},
+	{ID: MLDSA87_Ed448_SHAKE256, oid: 51, mldsa: mldsa87.Scheme(), trad: ed448Component{}, preHash: mldsa.SHAKE256, name: "MLDSA87-Ed448-SHAKE256"},
+	{ID: MLDSA87_ECDSA_P521_SHA512, oid: 54, mldsa: mldsa87.Scheme(), trad: ecdsaP521, preHash: mldsa.SHA512, name: "MLDSA87-ECDSA-P521-SHA512"},
+}
+
+// IsValid returns true if the algorithm is supported.
+func (id ID) IsValid() bool { return 0 < id && id < _MaxParams }
+
+func (id ID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return supportedParams[id-1].name
+}
+
+func (id ID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+	return &supportedParams[id-1]
+}
+
+// label returns the Label of the algorithm, which separates its message
+// representatives from those of other algorithms.
+func (p *params) label() string { return "COMPSIG-" + p.name }
+
+func (p *params) PublicKeySize() int {
+	return p.mldsa.PublicKeySize() + p.trad.publicKeySize()
+}
+
+func (p *params) PrivateKeySize() int {
+	return mldsaSeedSize + p.trad.privateKeySize()
+}
+
+// SignatureSize is the maximum size of a signature.
+func (p *params) SignatureSize() int {
+	return p.mldsa.SignatureSize() + p.trad.signatureSize()
+}
+
+// messageRepresentative returns M' for the message and the context.
+func (p *params) messageRepresentative(msg, context []byte) ([]byte, error) {
+	const MaxContextSize = 255
+	if len(context) > MaxContextSize {
+		return nil, sign.ErrContextTooLong
+	}
+
+	label := p.label()
+	digest := p.preHash.Sum(msg)
+	m := make([]byte, 0, len(prefix)+len(label)+1+len(context)+len(digest))
+	m = append(m, prefix...)
+	m = append(m, label...)
+	m = append(m, byte(len(context)))
+	m = append(m, context...)
+	return append(m, digest...), nil
+}
+
+// [GenerateKey] returns a pair of keys of the algorithm specified.
+// It returns an error if it fails reading from the random source.
+func GenerateKey(random io.Reader, id ID) (*PublicKey, *PrivateKey, error) {
+	p := id.params()
+	if random == nil {
+		random = rand.Reader
+	}
+
+	var seed [mldsaSeedSize]byte
+	if _, err := io.ReadFull(random, seed[:]); err != nil {
+		return nil, nil, err
+	}
+	tradKey, err := p.trad.deriveKey(random)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	priv, err := newPrivateKey(p, seed[:], tradKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv.Public().(*PublicKey), priv, nil
+}
+
+// [NewKeyFromSeed] deterministically derives a pair of keys of the
+// algorithm specified from a seed of [SeedSize] bytes.
+//
+// Panics if seed is not of length [SeedSize].
+func NewKeyFromSeed(id ID, seed []byte) (*PublicKey, *PrivateKey) {
+	p := id.params()
+	if len(seed) != SeedSize {
+		panic(sign.ErrSeedSize)
+	}
+
+	h := sha3.NewShake256()
+	_, _ = h.Write([]byte(p.label()))
+	_, _ = h.Write(seed)
+
+	var mldsaSeed [mldsaSeedSize]byte
+	_, _ = h.Read(mldsaSeed[:])
+	tradKey, err := p.trad.deriveKey(&h)
+	if err != nil {
+		panic(err)
+	}
+
+	priv, err := newPrivateKey(p, mldsaSeed[:], tradKey)
+	if err != nil {
+		panic(err)
+	}
+	return priv.Public().(*PublicKey), priv
+}
+
+// [Sign] returns the composite signature of the message with the specified
+// context. Randomness for the traditional component is read from random,
+// or crypto/rand.Reader if it is nil.
+// It returns an error if the context is larger than 255 bytes, or if the
+// traditional signature fails.
+func Sign(priv *PrivateKey, message, context []byte, random io.Reader) ([]byte, error) {
+	p := priv.ID.params()
+	if random == nil {
+		random = rand.Reader
+	}
+
+	msgPrime, err := p.messageRepresentative(message, context)
+	if err != nil {
+		return nil, err
+	}
+
+	mldsaSig := p.mldsa.Sign(
+		priv.mldsa, msgPrime, &sign.SignatureOpts{Context: p.label()},
+	)
+	tradSig, err := p.trad.sign(priv.trad, msgPrime, random)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(mldsaSig, tradSig...), nil
+}
+
+// [Verify] returns true if the composite signature of the message with the
+// specified context is valid, that is, if both component signatures are.
+func Verify(pub *PublicKey, message, signature, context []byte) bool {
+	p := pub.ID.params()
+	mldsaSigSize := p.mldsa.SignatureSize()
+	if len(signature) < mldsaSigSize || len(signature) > p.SignatureSize() {
+		return false
+	}
+
+	msgPrime, err := p.messageRepresentative(message, context)
+	if err != nil {
+		return false
+	}
+
+	mldsaOk := p.mldsa.Verify(
+		pub.mldsa, msgPrime, signature[:mldsaSigSize],
+		&sign.SignatureOpts{Context: p.label()},
+	)
+	tradOk := p.trad.verify(pub.trad, msgPrime, signature[mldsaSigSize:])
+	return mldsaOk && tradOk
+}
+
+var (
+	ErrParam      = errors.New("sign/composite: invalid composite parameter")
+	ErrPreHash    = errors.New("sign/composite: messages must not be pre-hashed")
+	ErrPrivateKey = errors.New("sign/composite: invalid private key")
+	ErrPublicKey  = errors.New("sign/composite: invalid public key")
+)
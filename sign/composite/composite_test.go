@@ -0,0 +1,163 @@
+package composite
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha512"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign"
+	"github.com/quantumcoinproject/circl/sign/ed25519"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa44"
+)
+
+func TestComposite(t *testing.T) {
+	for id := MLDSA44_Ed25519_SHA512; id < _MaxParams; id++ {
+		t.Run(id.String(), func(t *testing.T) { testComposite(t, id) })
+	}
+}
+
+func testComposite(t *testing.T, id ID) {
+	p := id.params()
+	pub, priv, err := GenerateKey(nil, id)
+	test.CheckNoErr(t, err, "generate key")
+	msg := []byte("message")
+	ctx := []byte("context")
+
+	sig, err := Sign(priv, msg, ctx, nil)
+	test.CheckNoErr(t, err, "sign")
+	if len(sig) > p.SignatureSize() {
+		test.ReportError(t, len(sig), p.SignatureSize())
+	}
+	if !Verify(pub, msg, sig, ctx) {
+		t.Fatal("verification failed")
+	}
+	if Verify(pub, msg, sig, nil) {
+		t.Fatal("verification succeeded with another context")
+	}
+	if Verify(pub, []byte("other"), sig, ctx) {
+		t.Fatal("verification succeeded with another message")
+	}
+
+	// Both components must be valid.
+	n := p.mldsa.SignatureSize()
+	for _, i := range []int{0, len(sig) - 1} {
+		sig[i] ^= 1
+		if Verify(pub, msg, sig, ctx) {
+			t.Fatalf("verification succeeded with a modified byte %v", i >= n)
+		}
+		sig[i] ^= 1
+	}
+
+	// Keys round trip through their encoding, and deriving is deterministic.
+	pk, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal public key")
+	sk, err := priv.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal private key")
+	if len(pk) != p.PublicKeySize() || len(sk) != p.PrivateKeySize() {
+		t.Fatal("wrong size of keys")
+	}
+	pub2 := &PublicKey{ID: id}
+	test.CheckNoErr(t, pub2.UnmarshalBinary(pk), "unmarshal public key")
+	priv2 := &PrivateKey{ID: id}
+	test.CheckNoErr(t, priv2.UnmarshalBinary(sk), "unmarshal private key")
+	if !pub.Equal(pub2) || !priv.Equal(priv2) || !pub.Equal(priv2.Public()) {
+		t.Fatal("keys differ after round trip")
+	}
+
+	seed := make([]byte, SeedSize)
+	pub3, priv3 := NewKeyFromSeed(id, seed)
+	pub4, priv4 := NewKeyFromSeed(id, seed)
+	if !pub3.Equal(pub4) || !priv3.Equal(priv4) || priv.Equal(priv3) {
+		t.Fatal("derived keys are not deterministic")
+	}
+
+	if _, err = Sign(priv, msg, make([]byte, 256), nil); err != sign.ErrContextTooLong {
+		test.ReportError(t, err, sign.ErrContextTooLong)
+	}
+	if _, err = priv.Sign(nil, msg, crypto.SHA256); err != ErrPreHash {
+		test.ReportError(t, err, ErrPreHash)
+	}
+}
+
+// Checks the construction of M' against the components, in lieu of the
+// test vectors of draft-ietf-lamps-pq-composite-sigs, which are not
+// included yet. These should be checked too, at least for
+// MLDSA65-ECDSA-P256-SHA512 and MLDSA65-Ed25519-SHA512, once available.
+func TestMessageRepresentative(t *testing.T) {
+	pub, priv := NewKeyFromSeed(MLDSA44_Ed25519_SHA512, make([]byte, SeedSize))
+	msg := []byte("message")
+	ctx := []byte("context")
+	sig, err := Sign(priv, msg, ctx, nil)
+	test.CheckNoErr(t, err, "sign")
+
+	// M' = Prefix || Label || len(ctx) || ctx || SHA512(M)
+	label := "COMPSIG-MLDSA44-Ed25519-SHA512"
+	digest := sha512.Sum512(msg)
+	msgPrime := []byte("CompositeAlgorithmSignatures2025" + label)
+	msgPrime = append(msgPrime, byte(len(ctx)))
+	msgPrime = append(msgPrime, ctx...)
+	msgPrime = append(msgPrime, digest[:]...)
+
+	// ML-DSA signs M' with the label as context, and Ed25519 signs M'.
+	mldsaPub := pub.mldsa.(*mldsa44.PublicKey)
+	mldsaSig := sig[:mldsa44.SignatureSize]
+	if !mldsa44.Verify(mldsaPub, msgPrime, []byte(label), mldsaSig) {
+		t.Fatal("ML-DSA component does not sign M'")
+	}
+	if !ed25519.Verify(pub.trad, msgPrime, sig[mldsa44.SignatureSize:]) {
+		t.Fatal("Ed25519 component does not sign M'")
+	}
+
+	// ML-DSA is deterministic and Ed25519 is too.
+	sig2, err := Sign(priv, msg, ctx, nil)
+	test.CheckNoErr(t, err, "sign")
+	if !bytes.Equal(sig, sig2) {
+		t.Fatal("signatures are not deterministic")
+	}
+}
+
+// Checks the ECDSA private keys against the ECPrivateKey structure of
+// RFC 5915, which has the version 1, the scalar on a fixed number of
+// bytes, the named curve and the public key.
+func TestECPrivateKey(t *testing.T) {
+	type ecPrivateKey struct {
+		Version    int
+		PrivateKey []byte
+		Curve      asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+		PublicKey  asn1.BitString        `asn1:"optional,explicit,tag:1"`
+	}
+
+	for _, v := range []struct {
+		id    ID
+		curve asn1.ObjectIdentifier
+	}{
+		{MLDSA44_ECDSA_P256_SHA256, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}},
+		{MLDSA65_ECDSA_P256_SHA512, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}},
+		{MLDSA65_ECDSA_P384_SHA512, asn1.ObjectIdentifier{1, 3, 132, 0, 34}},
+		{MLDSA87_ECDSA_P384_SHA512, asn1.ObjectIdentifier{1, 3, 132, 0, 34}},
+		{MLDSA87_ECDSA_P521_SHA512, asn1.ObjectIdentifier{1, 3, 132, 0, 35}},
+	} {
+		c := v.id.params().trad.(*ecdsaComponent)
+		for i := 0; i < 16; i++ {
+			seed := make([]byte, SeedSize)
+			seed[0] = byte(i)
+			pub, priv := NewKeyFromSeed(v.id, seed)
+
+			var k ecPrivateKey
+			rest, err := asn1.Unmarshal(priv.trad, &k)
+			test.CheckNoErr(t, err, "parse ECPrivateKey")
+			if len(rest) != 0 || len(priv.trad) != c.skSize {
+				test.ReportError(t, len(priv.trad), c.skSize, v.id)
+			}
+			if k.Version != 1 || len(k.PrivateKey) != c.scalarSize || !k.Curve.Equal(v.curve) {
+				test.ReportError(t, k, v.curve, v.id)
+			}
+			if !bytes.Equal(k.PublicKey.Bytes, pub.trad) || k.PublicKey.BitLength != 8*len(pub.trad) {
+				test.ReportError(t, k.PublicKey.Bytes, pub.trad, v.id)
+			}
+		}
+	}
+}
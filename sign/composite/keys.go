@@ -0,0 +1,135 @@
+package composite
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"io"
+
+	"github.com/quantumcoinproject/circl/sign"
+)
+
+// [PublicKey] stores a public key of a composite scheme.
+// It implements the [crypto.PublicKey] and [encoding.BinaryMarshaler]
+// interfaces.
+type PublicKey struct {
+	mldsa sign.PublicKey
+	trad  []byte
+	ID
+}
+
+// [PrivateKey] stores a private key of a composite scheme.
+// It implements the [crypto.Signer], [crypto.PrivateKey] and
+// [encoding.BinaryMarshaler] interfaces.
+type PrivateKey struct {
+	seed      [mldsaSeedSize]byte
+	mldsa     sign.PrivateKey
+	trad      []byte
+	publicKey PublicKey
+	ID
+}
+
+// newPrivateKey returns the private key with the ML-DSA seed and the
+// encoded traditional private key given.
+func newPrivateKey(p *params, seed, tradKey []byte) (*PrivateKey, error) {
+	tradPub, err := p.trad.publicKey(tradKey)
+	if err != nil {
+		return nil, err
+	}
+
+	mldsaPub, mldsaPriv := p.mldsa.DeriveKey(seed)
+	k := &PrivateKey{
+		mldsa:     mldsaPriv,
+		trad:      bytes.Clone(tradKey),
+		publicKey: PublicKey{mldsa: mldsaPub, trad: tradPub, ID: p.ID},
+		ID:        p.ID,
+	}
+	copy(k.seed[:], seed)
+	return k, nil
+}
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+// Caller must specify the public key's [ID] in advance.
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	p := k.ID.params()
+	if len(b) < p.mldsa.PublicKeySize() {
+		return ErrPublicKey
+	}
+
+	n := p.mldsa.PublicKeySize()
+	mldsaPub, err := p.mldsa.UnmarshalBinaryPublicKey(b[:n])
+	if err != nil {
+		return ErrPublicKey
+	}
+	if err = p.trad.checkPublicKey(b[n:]); err != nil {
+		return err
+	}
+
+	k.mldsa = mldsaPub
+	k.trad = bytes.Clone(b[n:])
+	return nil
+}
+
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	b, err := k.mldsa.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, k.trad...), nil
+}
+
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && k.ID == other.ID &&
+		k.mldsa.Equal(other.mldsa) &&
+		bytes.Equal(k.trad, other.trad)
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes.
+// Caller must specify the private key's [ID] in advance.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	p := k.ID.params()
+	if len(b) != p.PrivateKeySize() {
+		return ErrPrivateKey
+	}
+
+	k2, err := newPrivateKey(p, b[:mldsaSeedSize], b[mldsaSeedSize:])
+	if err != nil {
+		return err
+	}
+	*k = *k2
+	return nil
+}
+
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	return append(bytes.Clone(k.seed[:]), k.trad...), nil
+}
+
+func (k *PrivateKey) Public() crypto.PublicKey {
+	pub := k.publicKey
+	pub.trad = bytes.Clone(k.publicKey.trad)
+	return &pub
+}
+
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	return ok && k.ID == other.ID &&
+		subtle.ConstantTimeCompare(k.seed[:], other.seed[:]) == 1 &&
+		subtle.ConstantTimeCompare(k.trad, other.trad) == 1
+}
+
+// Sign returns a composite signature of the message with an empty context,
+// reading randomness for the traditional component from rand.
+// It implements the [crypto.Signer] interface. Messages must not be
+// pre-hashed, so opts.HashFunc() must return zero.
+func (k *PrivateKey) Sign(
+	rand io.Reader, msg []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, ErrPreHash
+	}
+	return Sign(k, msg, nil, rand)
+}
+
+func (k *PublicKey) Scheme() sign.Scheme  { return k.ID.Scheme() }
+func (k *PrivateKey) Scheme() sign.Scheme { return k.ID.Scheme() }
@@ -0,0 +1,104 @@
+package composite
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+
+	"github.com/quantumcoinproject/circl/sign"
+)
+
+// Scheme returns the composite scheme of the algorithm.
+func (id ID) Scheme() sign.Scheme { return scheme{id.params()} }
+
+type scheme struct{ *params }
+
+func (s scheme) Name() string          { return s.name }
+func (s scheme) SeedSize() int         { return SeedSize }
+func (s scheme) SupportsContext() bool { return true }
+
+// Oid returns the object identifier of the algorithm, see Section 7 of
+// draft-ietf-lamps-pq-composite-sigs.
+func (s scheme) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 6, s.oid}
+}
+
+// GenerateKey is similar to [GenerateKey] function, except it always reads
+// random bytes from [rand.Reader].
+func (s scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	return GenerateKey(rand.Reader, s.ID)
+}
+
+// Sign returns a composite signature of the message with the context
+// given.
+// If options is nil, an empty context is used.
+// It returns an empty slice if the signature generation fails.
+//
+// Panics if the key is not a [PrivateKey] or when the [ID] mismatches.
+func (s scheme) Sign(
+	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
+) []byte {
+	k, ok := priv.(*PrivateKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	var context []byte
+	if options != nil {
+		context = []byte(options.Context)
+	}
+
+	sig, err := Sign(k, message, context, rand.Reader)
+	if err != nil {
+		return nil
+	}
+
+	return sig
+}
+
+// Verify returns true if the composite signature of the message with the
+// specified context is valid.
+// If options is nil, an empty context is used.
+//
+// Panics if the key is not a [PublicKey] or when the [ID] mismatches.
+func (s scheme) Verify(
+	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
+) bool {
+	k, ok := pub.(*PublicKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	var context []byte
+	if options != nil {
+		context = []byte(options.Context)
+	}
+
+	return Verify(k, message, signature, context)
+}
+
+// DeriveKey deterministically generates a pair of keys from a seed.
+//
+// Panics if seed is not of length [SeedSize].
+func (s scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	return NewKeyFromSeed(s.ID, seed)
+}
+
+func (s scheme) UnmarshalBinaryPublicKey(b []byte) (sign.PublicKey, error) {
+	k := &PublicKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (s scheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
+	k := &PrivateKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,229 @@
+package composite
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"io"
+
+	"github.com/quantumcoinproject/circl/sign/ed25519"
+	"github.com/quantumcoinproject/circl/sign/ed448"
+)
+
+// traditional is the traditional component of a composite scheme. Keys
+// are handled in their encoded form, as they appear in composite keys.
+type traditional interface {
+	publicKeySize() int
+	privateKeySize() int
+
+	// Maximum size of signatures, which can have a variable length.
+	signatureSize() int
+
+	// Derives a private key from the random stream r.
+	deriveKey(r io.Reader) (sk []byte, err error)
+
+	// Checks the private key sk and returns its public key.
+	publicKey(sk []byte) (pk []byte, err error)
+
+	checkPublicKey(pk []byte) error
+	sign(sk, msg []byte, rand io.Reader) ([]byte, error)
+	verify(pk, msg, sig []byte) bool
+}
+
+// Ed25519 keys are encoded as in RFC 8032, and the private key is its seed.
+type ed25519Component struct{}
+
+func (ed25519Component) publicKeySize() int  { return ed25519.PublicKeySize }
+func (ed25519Component) privateKeySize() int { return ed25519.SeedSize }
+func (ed25519Component) signatureSize() int  { return ed25519.SignatureSize }
+
+func (ed25519Component) deriveKey(r io.Reader) ([]byte, error) {
+	sk := make([]byte, ed25519.SeedSize)
+	_, err := io.ReadFull(r, sk)
+	return sk, err
+}
+
+func (ed25519Component) publicKey(sk []byte) ([]byte, error) {
+	if len(sk) != ed25519.SeedSize {
+		return nil, ErrPrivateKey
+	}
+	pk := ed25519.NewKeyFromSeed(sk).Public().(ed25519.PublicKey)
+	return pk, nil
+}
+
+func (ed25519Component) checkPublicKey(pk []byte) error {
+	if len(pk) != ed25519.PublicKeySize {
+		return ErrPublicKey
+	}
+	return nil
+}
+
+func (ed25519Component) sign(sk, msg []byte, _ io.Reader) ([]byte, error) {
+	return ed25519.Sign(ed25519.NewKeyFromSeed(sk), msg), nil
+}
+
+func (ed25519Component) verify(pk, msg, sig []byte) bool {
+	return ed25519.Verify(pk, msg, sig)
+}
+
+// Ed448 keys are encoded as in RFC 8032, and the private key is its seed.
+// Signatures use an empty context.
+type ed448Component struct{}
+
+func (ed448Component) publicKeySize() int  { return ed448.PublicKeySize }
+func (ed448Component) privateKeySize() int { return ed448.SeedSize }
+func (ed448Component) signatureSize() int  { return ed448.SignatureSize }
+
+func (ed448Component) deriveKey(r io.Reader) ([]byte, error) {
+	sk := make([]byte, ed448.SeedSize)
+	_, err := io.ReadFull(r, sk)
+	return sk, err
+}
+
+func (ed448Component) publicKey(sk []byte) ([]byte, error) {
+	if len(sk) != ed448.SeedSize {
+		return nil, ErrPrivateKey
+	}
+	pk := ed448.NewKeyFromSeed(sk).Public().(ed448.PublicKey)
+	return pk, nil
+}
+
+func (ed448Component) checkPublicKey(pk []byte) error {
+	if len(pk) != ed448.PublicKeySize {
+		return ErrPublicKey
+	}
+	return nil
+}
+
+func (ed448Component) sign(sk, msg []byte, _ io.Reader) ([]byte, error) {
+	return ed448.Sign(ed448.NewKeyFromSeed(sk), msg, ""), nil
+}
+
+func (ed448Component) verify(pk, msg, sig []byte) bool {
+	return ed448.Verify(pk, msg, sig, "")
+}
+
+// ECDSA public keys are uncompressed points, private keys are encoded as
+// ECPrivateKey structures of RFC 5915, and signatures as DER encoded
+// ECDSA-Sig-Value structures.
+type ecdsaComponent struct {
+	curve ecdh.Curve
+	hash  crypto.Hash
+
+	// Sizes of a scalar, of an encoded private key and the maximum size
+	// of a signature.
+	scalarSize, skSize, sigSize int
+}
+
+var (
+	ecdsaP256 = &ecdsaComponent{ecdh.P256(), crypto.SHA256, 32, 121, 72}
+	ecdsaP384 = &ecdsaComponent{ecdh.P384(), crypto.SHA384, 48, 167, 104}
+	ecdsaP521 = &ecdsaComponent{ecdh.P521(), crypto.SHA512, 66, 223, 139}
+)
+
+func (c *ecdsaComponent) publicKeySize() int  { return 1 + 2*c.scalarSize }
+func (c *ecdsaComponent) privateKeySize() int { return c.skSize }
+func (c *ecdsaComponent) signatureSize() int  { return c.sigSize }
+
+func (c *ecdsaComponent) deriveKey(r io.Reader) ([]byte, error) {
+	b := make([]byte, c.scalarSize)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		if c.curve == ecdh.P521() {
+			b[0] &= 1
+		}
+
+		// Rejection sampling of scalars in [1, n).
+		k, err := c.curve.NewPrivateKey(b)
+		if err != nil {
+			continue
+		}
+
+		// Converts the key through PKCS #8, as crypto/ecdsa has no
+		// constructor from a scalar.
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		sk, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalECPrivateKey(sk.(*ecdsa.PrivateKey))
+	}
+}
+
+func (c *ecdsaComponent) parsePrivateKey(sk []byte) (*ecdsa.PrivateKey, error) {
+	if len(sk) != c.skSize {
+		return nil, ErrPrivateKey
+	}
+	k, err := x509.ParseECPrivateKey(sk)
+	if err != nil {
+		return nil, ErrPrivateKey
+	}
+	if k2, err := k.ECDH(); err != nil || k2.Curve() != c.curve {
+		return nil, ErrPrivateKey
+	}
+	return k, nil
+}
+
+func (c *ecdsaComponent) publicKey(sk []byte) ([]byte, error) {
+	k, err := c.parsePrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	k2, err := k.PublicKey.ECDH()
+	if err != nil {
+		return nil, ErrPrivateKey
+	}
+	return k2.Bytes(), nil
+}
+
+func (c *ecdsaComponent) parsePublicKey(pk []byte) (*ecdsa.PublicKey, error) {
+	k, err := c.curve.NewPublicKey(pk)
+	if err != nil || len(pk) != c.publicKeySize() {
+		return nil, ErrPublicKey
+	}
+
+	// Converts the key through a SubjectPublicKeyInfo, as crypto/ecdsa
+	// has no constructor from an encoded point.
+	der, err := x509.MarshalPKIXPublicKey(k)
+	if err != nil {
+		return nil, err
+	}
+	k2, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	return k2.(*ecdsa.PublicKey), nil
+}
+
+func (c *ecdsaComponent) checkPublicKey(pk []byte) error {
+	_, err := c.parsePublicKey(pk)
+	return err
+}
+
+func (c *ecdsaComponent) sign(sk, msg []byte, rand io.Reader) ([]byte, error) {
+	k, err := c.parsePrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	h := c.hash.New()
+	_, _ = h.Write(msg)
+	return ecdsa.SignASN1(rand, k, h.Sum(nil))
+}
+
+func (c *ecdsaComponent) verify(pk, msg, sig []byte) bool {
+	k, err := c.parsePublicKey(pk)
+	if err != nil {
+		return false
+	}
+	h := c.hash.New()
+	_, _ = h.Write(msg)
+	return ecdsa.VerifyASN1(k, h.Sum(nil), sig)
+}
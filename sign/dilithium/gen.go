@@ -31,6 +31,7 @@ type Mode struct {
 	TRSize        int
 	CTildeSize    int
 	Oid           asn1.ObjectIdentifier
+	HashOid       asn1.ObjectIdentifier
 }
 
 func (m Mode) Pkg() string {
@@ -57,15 +58,29 @@ func (m Mode) Mode() string {
 	return strings.ReplaceAll(m.Name, "Dilithium", "Mode")
 }
 
+// Name of the pre-hash variant, for ML-DSA.
+func (m Mode) HashName() string {
+	return "Hash" + m.Name
+}
+
 func (m Mode) NIST() bool {
 	return strings.HasPrefix(m.Name, "ML-DSA-")
 }
 
 // https://csrc.nist.gov/Projects/computer-security-objects-register/algorithm-registration
 func (m Mode) OidGo() string {
+	return oidGo(m.Oid)
+}
+
+// OID of HashML-DSA with SHA-512.
+func (m Mode) HashOidGo() string {
+	return oidGo(m.HashOid)
+}
+
+func oidGo(oid asn1.ObjectIdentifier) string {
 	ret := "asn1.ObjectIdentifier{"
 	first := true
-	for _, b := range m.Oid {
+	for _, b := range oid {
 		if first {
 			first = false
 		} else {
@@ -130,6 +145,7 @@ var (
 			TRSize:        64,
 			CTildeSize:    32,
 			Oid:           asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 17},
+			HashOid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 32},
 		},
 		{
 			Name:          "ML-DSA-65",
@@ -144,6 +160,7 @@ var (
 			TRSize:        64,
 			CTildeSize:    48,
 			Oid:           asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 18},
+			HashOid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 33},
 		},
 		{
 			Name:          "ML-DSA-87",
@@ -158,6 +175,7 @@ var (
 			TRSize:        64,
 			CTildeSize:    64,
 			Oid:           asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 19},
+			HashOid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 34},
 		},
 	}
 	TemplateWarning = "// Code generated from"
@@ -83,7 +83,8 @@ func (sig *unpackedSignature) Pack(buf []byte) {
 //
 // Returns whether buf contains a properly packed signature.
 func (sig *unpackedSignature) Unpack(buf []byte) bool {
-	if len(buf) < SignatureSize {
+	// Signatures with trailing data are rejected.
+	if len(buf) != SignatureSize {
 		return false
 	}
 	copy(sig.c[:], buf[:])
@@ -251,8 +252,28 @@ func (sk *PrivateKey) computeT0andT1(t0, t1 *VecK) {
 // For Dilithium this is the top-level verification function.
 // In ML-DSA, this is ML-DSA.Verify_internal.
 func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
-	var sig unpackedSignature
 	var mu [64]byte
+	pk.ComputeMu(msg, &mu)
+	return VerifyMu(pk, &mu, signature)
+}
+
+// ComputeMu computes μ = CRH(tr ‖ msg), the message representative which
+// is signed.
+func (pk *PublicKey) ComputeMu(msg func(io.Writer), mu *[64]byte) {
+	computeMu(pk.tr, msg, mu)
+}
+
+func computeMu(tr *[TRSize]byte, msg func(io.Writer), mu *[64]byte) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(tr[:])
+	msg(&h)
+	_, _ = h.Read(mu[:])
+}
+
+// VerifyMu checks whether the given signature by pk on the message
+// representative μ is valid.
+func VerifyMu(pk *PublicKey, mu *[64]byte, signature []byte) bool {
+	var sig unpackedSignature
 	var zh VecL
 	var Az, Az2dct1, w1 VecK
 	var ch common.Poly
@@ -265,12 +286,6 @@ func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
 		return false
 	}
 
-	// μ = CRH(tr ‖ msg)
-	h := sha3.NewShake256()
-	_, _ = h.Write(pk.tr[:])
-	msg(&h)
-	_, _ = h.Read(mu[:])
-
 	// Compute Az
 	zh = sig.z
 	zh.NTT()
@@ -303,7 +318,7 @@ func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
 	w1.PackW1(w1Packed[:])
 
 	// c' = H(μ, w₁)
-	h.Reset()
+	h := sha3.NewShake256()
 	_, _ = h.Write(mu[:])
 	_, _ = h.Write(w1Packed[:])
 	_, _ = h.Read(cp[:])
@@ -315,10 +330,18 @@ func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
 //
 // For Dilithium this is the top-level signing function. For ML-DSA
 // this is ML-DSA.Sign_internal.
+func SignTo(sk *PrivateKey, msg func(io.Writer), rnd [32]byte, signature []byte) {
+	var mu [64]byte
+	computeMu(&sk.tr, msg, &mu)
+	SignMuTo(sk, &mu, rnd, signature)
+}
+
+// SignMuTo signs the message representative μ = CRH(tr ‖ msg) and writes
+// the signature into signature.
 //
 //nolint:funlen
-func SignTo(sk *PrivateKey, msg func(io.Writer), rnd [32]byte, signature []byte) {
-	var mu, rhop [64]byte
+func SignMuTo(sk *PrivateKey, mu *[64]byte, rnd [32]byte, signature []byte) {
+	var rhop [64]byte
 	var w1Packed [PolyW1Size * K]byte
 	var y, yh VecL
 	var w, w0, w1, w0mcs2, ct0, w0mcs2pct0 VecK
@@ -330,14 +353,8 @@ func SignTo(sk *PrivateKey, msg func(io.Writer), rnd [32]byte, signature []byte)
 		panic("Signature does not fit in that byteslice")
 	}
 
-	//  μ = CRH(tr ‖ msg)
-	h := sha3.NewShake256()
-	_, _ = h.Write(sk.tr[:])
-	msg(&h)
-	_, _ = h.Read(mu[:])
-
 	// ρ' = CRH(key ‖ μ)
-	h.Reset()
+	h := sha3.NewShake256()
 	_, _ = h.Write(sk.key[:])
 	if NIST {
 		_, _ = h.Write(rnd[:])
@@ -0,0 +1,352 @@
+// Package ecdsa provides the Elliptic Curve Digital Signature Algorithm
+// over the NIST curves P-256, P-384 and P-521, as specified in [FIPS 186-5].
+//
+// The [ID] represents the supported combinations of curves and hash
+// functions: [P256_SHA256], [P384_SHA384] and [P521_SHA512]. P-384 uses the
+// constant-time implementation of package ecc/p384.
+//
+// Nonces are derived deterministically from the private key and the message
+// as specified in [RFC 6979]. When a random source is given, signing is
+// hedged: random bytes are added to the derivation, as in Section 3.6 of
+// RFC 6979, so that signatures are randomized but remain secure if the
+// random source fails.
+//
+// Signatures have two encodings: [DER], the ASN.1 structure used in X.509
+// and TLS, and [Raw], the fixed-length concatenation r || s used in IEEE
+// P1363 and JOSE. Public keys are uncompressed points, and private keys are
+// big-endian scalars.
+//
+// [FIPS 186-5]: https://doi.org/10.6028/NIST.FIPS.186-5
+// [RFC 6979]: https://www.rfc-editor.org/rfc/rfc6979
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/quantumcoinproject/circl/ecc/p384"
+	"github.com/quantumcoinproject/circl/sign"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// [ID] identifies the supported curves and hash functions.
+// Note that the zero value is not a valid identifier.
+type ID byte
+
+//nolint:stylecheck
+const (
+	P256_SHA256 ID = iota + 1 // ECDSA-P256-SHA256
+	P384_SHA384               // ECDSA-P384-SHA384
+	P521_SHA512               // ECDSA-P521-SHA512
+	_MaxParams
+)
+
+// Encoding identifies the encoding of signatures.
+type Encoding byte
+
+const (
+	// DER is the ASN.1 DER encoding of the Ecdsa-Sig-Value structure
+	// of RFC 3279, used in X.509 and TLS.
+	DER Encoding = iota
+	// Raw is the fixed-length concatenation r || s of the big-endian
+	// encodings of the integers, as in IEEE P1363 and RFC 7518.
+	Raw
+)
+
+// params contains the curve and hash function of a combination.
+type params struct {
+	name  string         // Name of the combination.
+	curve elliptic.Curve // Elliptic curve.
+	hash  crypto.Hash    // Hash function of messages.
+	tlsID uint           // TLS SignatureScheme code point.
+	oid   int            // Last arc of the ecdsa-with-SHA2 identifier.
+	order *scalarField   // Arithmetic modulo the order of the curve.
+	ID                   // Identifier of the combination.
+}
+
+// Stores all the supported (read-only) combinations.
+var supportedParams = [_MaxParams - 1]params{
+	{ID: P256_SHA256, curve: elliptic.P256(), hash: crypto.SHA256, tlsID: 0x0403, oid: 2, name: "ECDSA-P256-SHA256"},
+	{ID: P384_SHA384, curve: p384.P384(), hash: crypto.SHA384, tlsID: 0x0503, oid: 3, name: "ECDSA-P384-SHA384"},
+	{ID: P521_SHA512, curve: elliptic.P521(), hash: crypto.SHA512, tlsID: 0x0603, oid: 4, name: "ECDSA-P521-SHA512"},
+}
+
+func init() {
+	for i := range supportedParams {
+		p := &supportedParams[i]
+		p.order = newScalarField(p.curve.Params().N)
+	}
+}
+
+// IsValid returns true if the combination is supported.
+func (id ID) IsValid() bool { return 0 < id && id < _MaxParams }
+
+func (id ID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return supportedParams[id-1].name
+}
+
+func (id ID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+	return &supportedParams[id-1]
+}
+
+// scalarSize is the size of scalars and of coordinates in bytes.
+func (p *params) scalarSize() int { return (p.curve.Params().BitSize + 7) / 8 }
+
+func (p *params) PublicKeySize() int  { return 1 + 2*p.scalarSize() }
+func (p *params) PrivateKeySize() int { return p.scalarSize() }
+
+// SeedSize has 64 bits more than scalars, so that reducing seeds gives
+// almost uniform scalars, see Appendix A.2.1 of FIPS 186-5.
+func (p *params) SeedSize() int { return p.scalarSize() + 8 }
+
+// SignatureSize returns the maximum size of signatures with the encoding.
+func (p *params) signatureSize(enc Encoding) int {
+	n := p.scalarSize()
+	if enc == Raw {
+		return 2 * n
+	}
+
+	// SEQUENCE of two INTEGERs, each with a possible leading zero byte.
+	intLen := 2 + 1 + n
+	if n+1 >= 128 {
+		intLen++
+	}
+	seqLen := 2 * intLen
+	if seqLen >= 128 {
+		return 3 + seqLen
+	}
+	return 2 + seqLen
+}
+
+// [GenerateKey] returns a pair of keys of the combination specified,
+// reading random bytes from random, or crypto/rand.Reader if it is nil.
+// It returns an error if it fails reading from the random source.
+func GenerateKey(random io.Reader, id ID) (*PublicKey, *PrivateKey, error) {
+	p := id.params()
+	if random == nil {
+		random = rand.Reader
+	}
+
+	seed := make([]byte, p.SeedSize())
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+
+	priv := newKeyFromSeed(p, seed)
+	return priv.Public().(*PublicKey), priv, nil
+}
+
+// [NewKeyFromSeed] deterministically derives a pair of keys of the
+// combination specified from a seed, as in Appendix A.2.1 of FIPS 186-5.
+//
+// Panics if seed is not of length [sign.Scheme.SeedSize].
+func NewKeyFromSeed(id ID, seed []byte) (*PublicKey, *PrivateKey) {
+	p := id.params()
+	if len(seed) != p.SeedSize() {
+		panic(sign.ErrSeedSize)
+	}
+	priv := newKeyFromSeed(p, seed)
+	return priv.Public().(*PublicKey), priv
+}
+
+func newKeyFromSeed(p *params, seed []byte) *PrivateKey {
+	// d = (c mod (n-1)) + 1
+	n1 := new(big.Int).Sub(p.curve.Params().N, big.NewInt(1))
+	d := new(big.Int).SetBytes(seed)
+	d.Mod(d, n1)
+	d.Add(d, big.NewInt(1))
+	return newPrivateKey(p, d)
+}
+
+// [Sign] returns the signature of the message, hashed with the hash
+// function of the combination, in the encoding given.
+// If random is nil, the nonce is derived deterministically as in RFC 6979;
+// otherwise signing is hedged with random bytes read from random.
+// It returns an error if it fails reading from the random source.
+func Sign(
+	priv *PrivateKey, message []byte, random io.Reader, enc Encoding,
+) ([]byte, error) {
+	p := priv.ID.params()
+	h := p.hash.New()
+	_, _ = h.Write(message)
+	return SignHash(priv, p.hash, h.Sum(nil), random, enc)
+}
+
+// [SignHash] returns the signature of the digest of a message computed
+// with the hash function h, in the encoding given. The hash function is
+// also used to derive the nonce.
+// If random is nil, the nonce is derived deterministically as in RFC 6979;
+// otherwise signing is hedged with random bytes read from random.
+// It returns an error if h is not available, or if it fails reading from
+// the random source.
+func SignHash(
+	priv *PrivateKey, h crypto.Hash, digest []byte, random io.Reader, enc Encoding,
+) ([]byte, error) {
+	p := priv.ID.params()
+	if !h.Available() {
+		return nil, ErrHash
+	}
+
+	var extra []byte
+	if random != nil {
+		extra = make([]byte, h.Size())
+		if _, err := io.ReadFull(random, extra); err != nil {
+			return nil, err
+		}
+	}
+
+	N := p.curve.Params().N
+	f := p.order
+	e := f.fromInt(p.hashToInt(digest))
+	d := f.fromInt(priv.d)
+	nonces := newNonceGenerator(p, h, priv.d, digest, extra)
+	for {
+		k := nonces.next()
+
+		// r = (k*G).x mod N
+		x, _ := p.curve.ScalarBaseMult(p.intToOctets(k))
+		r := x.Mod(x, N)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		// s = k^-1 (e + r*d) mod N, in constant time on k and d.
+		s := f.toInt(f.mul(f.inverse(f.fromInt(k)), f.add(e, f.mul(f.fromInt(r), d))))
+		if s.Sign() == 0 {
+			continue
+		}
+
+		return p.encodeSignature(r, s, enc)
+	}
+}
+
+// [Verify] returns true if the signature, in the encoding given, of the
+// message hashed with the hash function of the combination is valid.
+func Verify(pub *PublicKey, message, signature []byte, enc Encoding) bool {
+	p := pub.ID.params()
+	h := p.hash.New()
+	_, _ = h.Write(message)
+	return VerifyHash(pub, h.Sum(nil), signature, enc)
+}
+
+// [VerifyHash] returns true if the signature, in the encoding given, of the
+// digest of a message is valid.
+func VerifyHash(pub *PublicKey, digest, signature []byte, enc Encoding) bool {
+	p := pub.ID.params()
+	r, s, ok := p.decodeSignature(signature, enc)
+	if !ok {
+		return false
+	}
+
+	N := p.curve.Params().N
+	e := p.hashToInt(digest)
+	w := new(big.Int).ModInverse(s, N)
+	u1 := e.Mul(e, w)
+	u1.Mod(u1, N)
+	u2 := w.Mul(r, w)
+	u2.Mod(u2, N)
+
+	// X = u1*G + u2*Q
+	var x, y *big.Int
+	if c, ok := p.curve.(p384.Curve); ok {
+		x, y = c.CombinedMult(pub.x, pub.y, u1.Bytes(), u2.Bytes())
+	} else {
+		x1, y1 := p.curve.ScalarBaseMult(u1.Bytes())
+		x2, y2 := p.curve.ScalarMult(pub.x, pub.y, u2.Bytes())
+		x, y = p.curve.Add(x1, y1, x2, y2)
+	}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return false
+	}
+
+	return x.Mod(x, N).Cmp(r) == 0
+}
+
+// hashToInt converts a digest to an integer as the bits2int function of
+// RFC 6979, keeping its leftmost bits if it is longer than the order.
+func (p *params) hashToInt(digest []byte) *big.Int {
+	orderBits := p.curve.Params().N.BitLen()
+	orderBytes := (orderBits + 7) / 8
+	if len(digest) > orderBytes {
+		digest = digest[:orderBytes]
+	}
+
+	e := new(big.Int).SetBytes(digest)
+	if excess := len(digest)*8 - orderBits; excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return e
+}
+
+// intToOctets returns the big-endian encoding of x in scalarSize bytes.
+func (p *params) intToOctets(x *big.Int) []byte {
+	return x.FillBytes(make([]byte, p.scalarSize()))
+}
+
+func (p *params) encodeSignature(r, s *big.Int, enc Encoding) ([]byte, error) {
+	switch enc {
+	case Raw:
+		return append(p.intToOctets(r), p.intToOctets(s)...), nil
+	case DER:
+		var b cryptobyte.Builder
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1BigInt(r)
+			b.AddASN1BigInt(s)
+		})
+		return b.Bytes()
+	default:
+		return nil, ErrEncoding
+	}
+}
+
+// decodeSignature returns the integers of the signature, which are checked
+// to be in [1, N).
+func (p *params) decodeSignature(sig []byte, enc Encoding) (r, s *big.Int, ok bool) {
+	r, s = new(big.Int), new(big.Int)
+	switch enc {
+	case Raw:
+		n := p.scalarSize()
+		if len(sig) != 2*n {
+			return nil, nil, false
+		}
+		r.SetBytes(sig[:n])
+		s.SetBytes(sig[n:])
+	case DER:
+		var inner cryptobyte.String
+		input := cryptobyte.String(sig)
+		if !input.ReadASN1(&inner, asn1.SEQUENCE) || !input.Empty() ||
+			!inner.ReadASN1Integer(r) || !inner.ReadASN1Integer(s) ||
+			!inner.Empty() {
+			return nil, nil, false
+		}
+	default:
+		return nil, nil, false
+	}
+
+	N := p.curve.Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(N) >= 0 || s.Cmp(N) >= 0 {
+		return nil, nil, false
+	}
+	return r, s, true
+}
+
+var (
+	ErrEncoding   = errors.New("sign/ecdsa: invalid signature encoding")
+	ErrHash       = errors.New("sign/ecdsa: hash function not available")
+	ErrParam      = errors.New("sign/ecdsa: invalid ECDSA parameter")
+	ErrPrivateKey = errors.New("sign/ecdsa: invalid private key")
+	ErrPublicKey  = errors.New("sign/ecdsa: invalid public key")
+)
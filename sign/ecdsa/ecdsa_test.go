@@ -0,0 +1,149 @@
+package ecdsa_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/ecdsa"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var curves = map[ecdsa.ID]elliptic.Curve{
+	ecdsa.P256_SHA256: elliptic.P256(),
+	ecdsa.P384_SHA384: elliptic.P384(),
+	ecdsa.P521_SHA512: elliptic.P521(),
+}
+
+func TestECDSA(t *testing.T) {
+	for id := range curves {
+		t.Run(id.String(), func(t *testing.T) { testECDSA(t, id) })
+	}
+}
+
+func testECDSA(t *testing.T, id ecdsa.ID) {
+	pub, priv, err := ecdsa.GenerateKey(nil, id)
+	test.CheckNoErr(t, err, "generate key")
+	msg := []byte("message")
+
+	for _, enc := range []ecdsa.Encoding{ecdsa.DER, ecdsa.Raw} {
+		// Deterministic signatures are equal, hedged signatures are not.
+		sig, err := ecdsa.Sign(priv, msg, nil, enc)
+		test.CheckNoErr(t, err, "sign")
+		sig2, err := ecdsa.Sign(priv, msg, nil, enc)
+		test.CheckNoErr(t, err, "sign")
+		if !bytes.Equal(sig, sig2) {
+			t.Fatal("deterministic signatures differ")
+		}
+		sig2, err = ecdsa.Sign(priv, msg, bytes.NewReader(make([]byte, 64)), enc)
+		test.CheckNoErr(t, err, "sign")
+		if bytes.Equal(sig, sig2) {
+			t.Fatal("hedged signature equals the deterministic one")
+		}
+
+		for _, s := range [][]byte{sig, sig2} {
+			if !ecdsa.Verify(pub, msg, s, enc) {
+				t.Fatal("verification failed")
+			}
+			if ecdsa.Verify(pub, []byte("other"), s, enc) {
+				t.Fatal("verification succeeded with another message")
+			}
+			if ecdsa.Verify(pub, msg, s, 1-enc) {
+				t.Fatal("verification succeeded with another encoding")
+			}
+		}
+	}
+
+	// Keys round trip through their encoding.
+	pk, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal public key")
+	sk, err := priv.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal private key")
+	scheme := id.Scheme()
+	if len(pk) != scheme.PublicKeySize() || len(sk) != scheme.PrivateKeySize() {
+		t.Fatal("wrong size of keys")
+	}
+	pub2, err := scheme.UnmarshalBinaryPublicKey(pk)
+	test.CheckNoErr(t, err, "unmarshal public key")
+	priv2, err := scheme.UnmarshalBinaryPrivateKey(sk)
+	test.CheckNoErr(t, err, "unmarshal private key")
+	if !pub.Equal(pub2) || !priv.Equal(priv2) {
+		t.Fatal("keys differ after round trip")
+	}
+
+	// A point off the curve and scalars out of range are rejected.
+	pk[len(pk)-1] ^= 1
+	if _, err = scheme.UnmarshalBinaryPublicKey(pk); err != ecdsa.ErrPublicKey {
+		test.ReportError(t, err, ecdsa.ErrPublicKey)
+	}
+	N := curves[id].Params().N
+	for _, d := range []*big.Int{big.NewInt(0), N} {
+		b := d.FillBytes(make([]byte, scheme.PrivateKeySize()))
+		if _, err = scheme.UnmarshalBinaryPrivateKey(b); err != ecdsa.ErrPrivateKey {
+			test.ReportError(t, err, ecdsa.ErrPrivateKey)
+		}
+	}
+
+	// Signing a digest of another hash function through crypto.Signer.
+	digest := sha256.Sum256(msg)
+	sig, err := priv.Sign(nil, digest[:], crypto.SHA256)
+	test.CheckNoErr(t, err, "sign digest")
+	if !ecdsa.VerifyHash(pub, digest[:], sig, ecdsa.DER) {
+		t.Fatal("verification of crypto.Signer signature failed")
+	}
+}
+
+func TestMalformedSignatures(t *testing.T) {
+	id := ecdsa.P256_SHA256
+	pub, priv := ecdsa.NewKeyFromSeed(id, make([]byte, id.Scheme().SeedSize()))
+	msg := []byte("message")
+	N := curves[id].Params().N
+
+	raw, err := ecdsa.Sign(priv, msg, nil, ecdsa.Raw)
+	test.CheckNoErr(t, err, "sign")
+	r := new(big.Int).SetBytes(raw[:32])
+	s := new(big.Int).SetBytes(raw[32:])
+
+	der := func(r, s *big.Int) []byte {
+		var b cryptobyte.Builder
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1BigInt(r)
+			b.AddASN1BigInt(s)
+		})
+		return b.BytesOrPanic()
+	}
+
+	// (r, N-s) is also a valid signature, as ECDSA is malleable.
+	if !ecdsa.Verify(pub, msg, der(r, new(big.Int).Sub(N, s)), ecdsa.DER) {
+		t.Fatal("verification of (r, N-s) failed")
+	}
+
+	sig := der(r, s)
+	invalid := map[string][]byte{
+		"r = 0":          der(big.NewInt(0), s),
+		"s = 0":          der(r, big.NewInt(0)),
+		"r = N":          der(N, s),
+		"s + N":          der(r, new(big.Int).Add(s, N)),
+		"negative s":     der(r, new(big.Int).Neg(s)),
+		"trailing data":  append(bytes.Clone(sig), 0),
+		"truncated":      sig[:len(sig)-1],
+		"long length":    append([]byte{0x30, 0x81, sig[1]}, sig[2:]...),
+		"empty":          {},
+		"raw as DER":     raw,
+		"leading zeros":  append([]byte{0x30, sig[1] + 1, 0x02, sig[3] + 1, 0x00}, sig[4:]...),
+		"swapped values": der(s, r),
+	}
+	for name, b := range invalid {
+		if ecdsa.Verify(pub, msg, b, ecdsa.DER) {
+			t.Fatalf("verification succeeded with %v", name)
+		}
+	}
+	if ecdsa.Verify(pub, msg, append(raw, 0), ecdsa.Raw) {
+		t.Fatal("verification succeeded with a longer raw signature")
+	}
+}
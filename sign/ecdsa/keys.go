@@ -0,0 +1,112 @@
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"crypto/subtle"
+	"io"
+	"math/big"
+
+	"github.com/quantumcoinproject/circl/sign"
+)
+
+// [PublicKey] stores a public key of ECDSA.
+// It implements the [crypto.PublicKey] and [encoding.BinaryMarshaler]
+// interfaces.
+type PublicKey struct {
+	x, y *big.Int
+	ID
+}
+
+// [PrivateKey] stores a private key of ECDSA.
+// It implements the [crypto.Signer], [crypto.PrivateKey] and
+// [encoding.BinaryMarshaler] interfaces.
+type PrivateKey struct {
+	d         *big.Int
+	publicKey PublicKey
+	ID
+}
+
+func newPrivateKey(p *params, d *big.Int) *PrivateKey {
+	x, y := p.curve.ScalarBaseMult(p.intToOctets(d))
+	return &PrivateKey{d: d, publicKey: PublicKey{x: x, y: y, ID: p.ID}, ID: p.ID}
+}
+
+// UnmarshalBinary recovers a [PublicKey] from an uncompressed point.
+// Caller must specify the public key's [ID] in advance.
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	p := k.ID.params()
+	if len(b) != p.PublicKeySize() {
+		return ErrPublicKey
+	}
+
+	x, y := elliptic.Unmarshal(p.curve, b)
+	if x == nil {
+		return ErrPublicKey
+	}
+
+	k.x, k.y = x, y
+	return nil
+}
+
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	return elliptic.Marshal(k.ID.params().curve, k.x, k.y), nil
+}
+
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && k.ID == other.ID &&
+		k.x.Cmp(other.x) == 0 && k.y.Cmp(other.y) == 0
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a big-endian scalar in
+// [1, N). Caller must specify the private key's [ID] in advance.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	p := k.ID.params()
+	if len(b) != p.PrivateKeySize() {
+		return ErrPrivateKey
+	}
+
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() == 0 || d.Cmp(p.curve.Params().N) >= 0 {
+		return ErrPrivateKey
+	}
+
+	*k = *newPrivateKey(p, d)
+	return nil
+}
+
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	return k.ID.params().intToOctets(k.d), nil
+}
+
+func (k *PrivateKey) Public() crypto.PublicKey {
+	pub := k.publicKey
+	return &pub
+}
+
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	if !ok || k.ID != other.ID {
+		return false
+	}
+	p := k.ID.params()
+	return subtle.ConstantTimeCompare(p.intToOctets(k.d), p.intToOctets(other.d)) == 1
+}
+
+// Sign returns the DER encoded signature of the digest of a message.
+// It implements the [crypto.Signer] interface: the digest must be computed
+// with opts.HashFunc(), which is also used to derive the nonce. Signing is
+// hedged with random bytes read from rand, or deterministic if rand is nil.
+func (k *PrivateKey) Sign(
+	rand io.Reader, digest []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	h := k.ID.params().hash
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		h = opts.HashFunc()
+	}
+	return SignHash(k, h, digest, rand, DER)
+}
+
+func (k *PublicKey) Scheme() sign.Scheme  { return k.ID.Scheme() }
+func (k *PrivateKey) Scheme() sign.Scheme { return k.ID.Scheme() }
@@ -0,0 +1,87 @@
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// nonceGenerator derives the nonces of a signature with the HMAC_DRBG of
+// Section 3.2 of RFC 6979.
+type nonceGenerator struct {
+	p    *params
+	mac  func(key []byte) hash.Hash
+	k, v []byte
+}
+
+// newNonceGenerator returns the generator of nonces for the private key d
+// and the digest, to which extra data is added if it is not empty, as in
+// Section 3.6 of RFC 6979.
+func newNonceGenerator(p *params, h crypto.Hash, d *big.Int, digest, extra []byte) *nonceGenerator {
+	g := &nonceGenerator{
+		p:   p,
+		mac: func(key []byte) hash.Hash { return hmac.New(h.New, key) },
+		k:   make([]byte, h.Size()),
+		v:   make([]byte, h.Size()),
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+
+	x := p.intToOctets(d)
+	h1 := p.bitsToOctets(digest)
+	for _, sep := range []byte{0x00, 0x01} {
+		m := g.mac(g.k)
+		_, _ = m.Write(g.v)
+		_, _ = m.Write([]byte{sep})
+		_, _ = m.Write(x)
+		_, _ = m.Write(h1)
+		_, _ = m.Write(extra)
+		g.k = m.Sum(g.k[:0])
+		g.v = g.hmac(g.v)
+	}
+	return g
+}
+
+func (g *nonceGenerator) hmac(msg ...[]byte) []byte {
+	m := g.mac(g.k)
+	for _, b := range msg {
+		_, _ = m.Write(b)
+	}
+	return m.Sum(nil)
+}
+
+// next returns the next nonce candidate in [1, N).
+func (g *nonceGenerator) next() *big.Int {
+	N := g.p.curve.Params().N
+	for {
+		t := make([]byte, 0, g.p.scalarSize())
+		for len(t) < g.p.scalarSize() {
+			g.v = g.hmac(g.v)
+			t = append(t, g.v...)
+		}
+
+		k := g.p.hashToInt(t)
+		if k.Sign() > 0 && k.Cmp(N) < 0 {
+			// Updates the state for a possible next candidate.
+			g.k = g.hmac(g.v, []byte{0x00})
+			g.v = g.hmac(g.v)
+			return k
+		}
+
+		g.k = g.hmac(g.v, []byte{0x00})
+		g.v = g.hmac(g.v)
+	}
+}
+
+// bitsToOctets is the bits2octets function of RFC 6979: it reduces the
+// digest modulo N and encodes it in scalarSize bytes.
+func (p *params) bitsToOctets(digest []byte) []byte {
+	N := p.curve.Params().N
+	z := p.hashToInt(digest)
+	if z.Cmp(N) >= 0 {
+		z.Sub(z, N)
+	}
+	return p.intToOctets(z)
+}
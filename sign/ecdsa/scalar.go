@@ -0,0 +1,158 @@
+package ecdsa
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// scalarField does arithmetic modulo the order N of a curve in constant
+// time, on integers of a fixed number of 64-bit limbs in the Montgomery
+// domain. It is used on the private key and the nonces, which must not
+// leak through the timing of big.Int operations.
+type scalarField struct {
+	n     []uint64 // N in little-endian limbs.
+	n0inv uint64   // -N⁻¹ mod 2⁶⁴.
+	rr    scalar   // R² mod N, with R = 2^(64*len(n)).
+	nm2   *big.Int // N-2, the exponent of the inversion.
+	size  int      // Size of scalars in bytes.
+}
+
+// scalar is an integer modulo N in Montgomery form, that is x*R mod N.
+type scalar []uint64
+
+func newScalarField(N *big.Int) *scalarField {
+	size := (N.BitLen() + 7) / 8
+	l := (N.BitLen() + 63) / 64
+	f := &scalarField{n: make([]uint64, l), size: size}
+	limbsFromBytes(f.n, N.FillBytes(make([]byte, size)))
+
+	// Newton iteration doubles the correct low bits of the inverse of the
+	// odd N[0] at each step, starting from three.
+	inv := f.n[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - f.n[0]*inv
+	}
+	f.n0inv = -inv
+
+	rr := new(big.Int).Lsh(big.NewInt(1), uint(128*l))
+	rr.Mod(rr, N)
+	f.rr = make(scalar, l)
+	limbsFromBytes(f.rr, rr.FillBytes(make([]byte, 8*l)))
+
+	f.nm2 = new(big.Int).Sub(N, big.NewInt(2))
+	return f
+}
+
+// Sets z to the little-endian limbs of the big-endian integer b.
+func limbsFromBytes(z []uint64, b []byte) {
+	for i := range z {
+		z[i] = 0
+	}
+	for i := range b {
+		z[i/8] |= uint64(b[len(b)-1-i]) << (8 * (i % 8))
+	}
+}
+
+// fromInt returns x in Montgomery form. x must be less than 2N, and its
+// size in bytes is not hidden.
+func (f *scalarField) fromInt(x *big.Int) scalar {
+	t := make([]uint64, len(f.n))
+	limbsFromBytes(t, x.FillBytes(make([]byte, f.size)))
+	z := make(scalar, len(f.n))
+	f.reduce(z, t, 0)
+	return f.mul(z, f.rr)
+}
+
+// toInt returns the integer in [0, N) represented by x.
+func (f *scalarField) toInt(x scalar) *big.Int {
+	one := make(scalar, len(f.n))
+	one[0] = 1
+	z := f.mul(x, one)
+
+	b := make([]byte, 8*len(z))
+	for i := range b {
+		b[len(b)-1-i] = byte(z[i/8] >> (8 * (i % 8)))
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// Sets z to t + carry*R if it is less than N, or to t + carry*R - N
+// otherwise. t + carry*R must be less than 2N.
+func (f *scalarField) reduce(z, t []uint64, carry uint64) {
+	u := make([]uint64, len(f.n))
+	var borrow uint64
+	for i := range u {
+		u[i], borrow = bits.Sub64(t[i], f.n[i], borrow)
+	}
+
+	// Keeps t - N unless the subtraction underflowed without carry.
+	mask := -(carry | (borrow ^ 1))
+	for i := range z {
+		z[i] = t[i] ^ (mask & (t[i] ^ u[i]))
+	}
+}
+
+// add returns x + y mod N.
+func (f *scalarField) add(x, y scalar) scalar {
+	t := make([]uint64, len(f.n))
+	var carry uint64
+	for i := range t {
+		t[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	z := make(scalar, len(f.n))
+	f.reduce(z, t, carry)
+	return z
+}
+
+// mul returns x*y/R mod N, with the coarsely integrated operand scanning
+// method of Montgomery multiplication.
+func (f *scalarField) mul(x, y scalar) scalar {
+	l := len(f.n)
+	t := make([]uint64, l+2)
+	for i := 0; i < l; i++ {
+		// t += x*y[i]
+		var c, cc, hi, lo uint64
+		for j := 0; j < l; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			t[j], cc = bits.Add64(lo, c, 0)
+			c = hi + cc
+		}
+		t[l], cc = bits.Add64(t[l], c, 0)
+		t[l+1] = cc
+
+		// t = (t + m*N)/2⁶⁴, where m makes the low limb vanish.
+		m := t[0] * f.n0inv
+		hi, lo = bits.Mul64(m, f.n[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < l; j++ {
+			hi, lo = bits.Mul64(m, f.n[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			t[j-1], cc = bits.Add64(lo, c, 0)
+			c = hi + cc
+		}
+		t[l-1], cc = bits.Add64(t[l], c, 0)
+		t[l] = t[l+1] + cc
+	}
+
+	z := make(scalar, l)
+	f.reduce(z, t[:l], t[l])
+	return z
+}
+
+// inverse returns x⁻¹ mod N, computed as x^(N-2) with Fermat's little
+// theorem. The exponent is public, so only the sequence of operations
+// depends on N, not on x.
+func (f *scalarField) inverse(x scalar) scalar {
+	z := x
+	for i := f.nm2.BitLen() - 2; i >= 0; i-- {
+		z = f.mul(z, z)
+		if f.nm2.Bit(i) == 1 {
+			z = f.mul(z, x)
+		}
+	}
+	return z
+}
@@ -0,0 +1,55 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+func TestScalarField(t *testing.T) {
+	for id := P256_SHA256; id < _MaxParams; id++ {
+		p := id.params()
+		N := p.curve.Params().N
+		f := p.order
+		nm1 := new(big.Int).Sub(N, big.NewInt(1))
+
+		for i := 0; i < 64; i++ {
+			x, _ := rand.Int(rand.Reader, N)
+			y, _ := rand.Int(rand.Reader, N)
+			if i == 0 {
+				x.Set(nm1)
+				y.Set(nm1)
+			}
+			if x.Sign() == 0 {
+				x.SetInt64(1)
+			}
+			xs, ys := f.fromInt(x), f.fromInt(y)
+
+			want := new(big.Int).Add(x, y)
+			want.Mod(want, N)
+			if got := f.toInt(f.add(xs, ys)); got.Cmp(want) != 0 {
+				test.ReportError(t, got, want, id, x, y)
+			}
+
+			want.Mul(x, y).Mod(want, N)
+			if got := f.toInt(f.mul(xs, ys)); got.Cmp(want) != 0 {
+				test.ReportError(t, got, want, id, x, y)
+			}
+
+			want.ModInverse(x, N)
+			if got := f.toInt(f.inverse(xs)); got.Cmp(want) != 0 {
+				test.ReportError(t, got, want, id, x)
+			}
+		}
+
+		// Integers in [N, 2N) are reduced.
+		x := new(big.Int).Add(N, big.NewInt(5))
+		if x.BitLen() == N.BitLen() {
+			if got := f.toInt(f.fromInt(x)); got.Int64() != 5 {
+				test.ReportError(t, got, 5, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+
+	"github.com/quantumcoinproject/circl/sign"
+)
+
+// Scheme returns the ECDSA scheme of the combination, whose signatures
+// are DER encoded.
+func (id ID) Scheme() sign.Scheme { return scheme{id.params()} }
+
+type scheme struct{ *params }
+
+func (s scheme) Name() string          { return s.name }
+func (s scheme) SupportsContext() bool { return false }
+func (s scheme) TLSIdentifier() uint   { return s.tlsID }
+
+// SignatureSize is the maximum size of DER encoded signatures.
+func (s scheme) SignatureSize() int { return s.signatureSize(DER) }
+
+// Oid returns the ecdsa-with-SHA2 object identifier of the combination, see
+// RFC 5758. Public keys are encoded as for other schemes, under this
+// identifier.
+func (s scheme) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, s.oid}
+}
+
+// GenerateKey is similar to [GenerateKey] function, except it always reads
+// random bytes from [rand.Reader].
+func (s scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	return GenerateKey(rand.Reader, s.ID)
+}
+
+// Sign returns a DER encoded signature of the message, hedged with random
+// bytes from [rand.Reader].
+// It returns an empty slice if the signature generation fails.
+//
+// Panics if the key is not a [PrivateKey], when the [ID] mismatches, or if
+// a context is given.
+func (s scheme) Sign(
+	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
+) []byte {
+	k, ok := priv.(*PrivateKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+	if options != nil && options.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+
+	sig, err := Sign(k, message, rand.Reader, DER)
+	if err != nil {
+		return nil
+	}
+
+	return sig
+}
+
+// Verify returns true if the DER encoded signature of the message is valid.
+//
+// Panics if the key is not a [PublicKey], when the [ID] mismatches, or if
+// a context is given.
+func (s scheme) Verify(
+	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
+) bool {
+	k, ok := pub.(*PublicKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+	if options != nil && options.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+
+	return Verify(k, message, signature, DER)
+}
+
+// DeriveKey deterministically generates a pair of keys from a seed.
+//
+// Panics if seed is not of length [sign.Scheme.SeedSize].
+func (s scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	return NewKeyFromSeed(s.ID, seed)
+}
+
+func (s scheme) UnmarshalBinaryPublicKey(b []byte) (sign.PublicKey, error) {
+	k := &PublicKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (s scheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
+	k := &PrivateKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,80 @@
+package ecdsa_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/ecdsa"
+)
+
+type wycheproofGroup struct {
+	PublicKey struct {
+		Curve        string `json:"curve"`
+		Uncompressed string `json:"uncompressed"`
+	} `json:"publicKey"`
+	Sha   string `json:"sha"`
+	Type  string `json:"type"`
+	Tests []struct {
+		TcID    int      `json:"tcId"`
+		Comment string   `json:"comment"`
+		Msg     string   `json:"msg"`
+		Sig     string   `json:"sig"`
+		Result  string   `json:"result"`
+		Flags   []string `json:"flags"`
+	} `json:"tests"`
+}
+
+type wycheproof struct {
+	Alg    string            `json:"algorithm"`
+	Num    int               `json:"numberOfTests"`
+	Groups []wycheproofGroup `json:"testGroups"`
+}
+
+func TestWycheproof(t *testing.T) {
+	// Test vectors from https://github.com/C2SP/wycheproof, in testvectors_v1.
+	for _, v := range []struct {
+		id   ecdsa.ID
+		file string
+	}{
+		{ecdsa.P256_SHA256, "ecdsa_secp256r1_sha256"},
+		{ecdsa.P384_SHA384, "ecdsa_secp384r1_sha384"},
+		{ecdsa.P521_SHA512, "ecdsa_secp521r1_sha512"},
+	} {
+		t.Run(v.id.String()+"/DER", func(t *testing.T) {
+			testWycheproof(t, v.id, "testdata/"+v.file+"_test.json", ecdsa.DER)
+		})
+		t.Run(v.id.String()+"/Raw", func(t *testing.T) {
+			testWycheproof(t, v.id, "testdata/"+v.file+"_p1363_test.json", ecdsa.Raw)
+		})
+	}
+}
+
+func testWycheproof(t *testing.T, id ecdsa.ID, fileName string, enc ecdsa.Encoding) {
+	input, err := os.ReadFile(fileName)
+	test.CheckNoErr(t, err, "read file")
+
+	var kat wycheproof
+	test.CheckNoErr(t, json.Unmarshal(input, &kat), "parse file")
+
+	scheme := id.Scheme()
+	for _, g := range kat.Groups {
+		pk, _ := hex.DecodeString(g.PublicKey.Uncompressed)
+		pub, err := scheme.UnmarshalBinaryPublicKey(pk)
+		test.CheckNoErr(t, err, "unmarshal public key")
+
+		for _, tc := range g.Tests {
+			msg, _ := hex.DecodeString(tc.Msg)
+			sig, _ := hex.DecodeString(tc.Sig)
+
+			// Acceptable signatures, such as BER encoded ones, are rejected.
+			got := ecdsa.Verify(pub.(*ecdsa.PublicKey), msg, sig, enc)
+			want := tc.Result == "valid"
+			if got != want {
+				test.ReportError(t, got, want, tc.TcID, tc.Comment)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package ed25519
+
+import (
+	cryptoRand "crypto/rand"
+	"io"
+)
+
+// BatchVerifier verifies many Ed25519 signatures at once, which is faster
+// than verifying them one at a time. It supports only the pure Ed25519
+// variant.
+//
+// Signatures are verified with the rules of ZIP-215, so a batch is valid
+// if and only if every signature in it is valid for [VerifyZIP215]. The
+// strict [Verify] can reject some signatures that these rules accept, all
+// of them with non-canonical encodings or points of small order.
+//
+// The zero value is an empty batch ready to use.
+type BatchVerifier struct {
+	entries []zip215Entry
+}
+
+// NewBatchVerifier returns an empty batch with capacity for n signatures.
+func NewBatchVerifier(n int) *BatchVerifier {
+	return &BatchVerifier{entries: make([]zip215Entry, 0, n)}
+}
+
+// Add appends a signature of the message under the public key to the
+// batch. Signatures that cannot be decoded are added too, and make the
+// batch invalid.
+func (v *BatchVerifier) Add(public PublicKey, message, signature []byte) {
+	v.entries = append(v.entries, zip215Entry{})
+	v.entries[len(v.entries)-1].set(public, message, signature)
+}
+
+// Len returns the number of signatures in the batch.
+func (v *BatchVerifier) Len() int { return len(v.entries) }
+
+// Verify returns true if all the signatures in the batch are valid, and
+// true for an empty batch. It checks a random linear combination of the
+// verification equations with a single multi-scalar multiplication, taking
+// 128-bit coefficients from rand, or crypto/rand.Reader if rand is nil.
+// It returns false if it fails reading from rand.
+func (v *BatchVerifier) Verify(rand io.Reader) bool {
+	if rand == nil {
+		rand = cryptoRand.Reader
+	}
+
+	// Checks that [8]([sum z_i s_i]B - sum [z_i]R_i - sum [z_i k_i]A_i)
+	// is the identity.
+	n := len(v.entries)
+	points := make([]pointR1, 0, 2*n)
+	scalars := make([][]byte, 0, 2*n)
+	zero := make([]byte, paramB)
+	sumS := make([]byte, paramB)
+	for i := range v.entries {
+		e := &v.entries[i]
+		if !e.ok {
+			return false
+		}
+
+		z := make([]byte, paramB)
+		if _, err := io.ReadFull(rand, z[:16]); err != nil {
+			return false
+		}
+		zk := make([]byte, paramB)
+		calculateS(zk, zero, z, e.k[:])
+		calculateS(sumS, sumS, z, e.s[:])
+
+		negR, negA := e.R, e.A
+		negR.neg()
+		negA.neg()
+		points = append(points, negR, negA)
+		scalars = append(scalars, z, zk)
+	}
+
+	var P pointR1
+	P.multiMult(sumS, points, scalars)
+	P.clearCofactor()
+	return P.isIdentity()
+}
+
+// VerifyEach returns whether each signature in the batch is valid. It first
+// verifies the whole batch as [BatchVerifier.Verify] does, and only if that
+// fails, verifies the signatures one at a time with the same rules.
+func (v *BatchVerifier) VerifyEach(rand io.Reader) []bool {
+	valid := make([]bool, len(v.entries))
+	if v.Verify(rand) {
+		for i := range valid {
+			valid[i] = true
+		}
+		return valid
+	}
+
+	for i := range v.entries {
+		valid[i] = v.entries[i].verify()
+	}
+	return valid
+}
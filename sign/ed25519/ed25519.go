@@ -20,6 +20,14 @@
 // in this package. While Ed25519Ph accepts an empty context, Ed25519Ctx
 // enforces non-empty context strings.
 //
+// # Verification rules
+//
+// Verification functions follow the strict rules of RFC-8032. The function
+// VerifyZIP215 follows instead the rules of ZIP-215, which are used in
+// consensus systems as they give the same results across implementations.
+// Many signatures can be verified at once, with these rules, using a
+// BatchVerifier.
+//
 // # Compatibility with crypto.ed25519
 //
 // These functions are compatible with the “Ed25519” function defined in
@@ -31,6 +39,7 @@
 // References
 //
 //   - RFC-8032: https://rfc-editor.org/rfc/rfc8032.txt
+//   - ZIP-215: https://zips.z.cash/zip-0215
 //   - Ed25519: https://ed25519.cr.yp.to/
 //   - EdDSA: High-speed high-security signatures. https://doi.org/10.1007/s13389-012-0027-1
 package ed25519
@@ -51,7 +51,12 @@ func (P *pointR1) ToBytes(k []byte) error {
 	return nil
 }
 
-func (P *pointR1) FromBytes(k []byte) bool {
+func (P *pointR1) FromBytes(k []byte) bool { return P.fromBytes(k, false) }
+
+// fromBytes decodes a point. If permissive is true, it also accepts the
+// non-canonical encodings allowed by ZIP-215: y is not reduced modulo p, and
+// x = 0 has its sign bit set.
+func (P *pointR1) fromBytes(k []byte, permissive bool) bool {
 	if len(k) != paramB {
 		panic("wrong size")
 	}
@@ -59,7 +64,9 @@ func (P *pointR1) FromBytes(k []byte) bool {
 	copy(P.y[:], k[:fp.Size])
 	P.y[fp.Size-1] &= 0x7F
 	p := fp.P()
-	if !isLessThan(P.y[:], p[:]) {
+	if permissive {
+		fp.Modp(&P.y)
+	} else if !isLessThan(P.y[:], p[:]) {
 		return false
 	}
 
@@ -74,7 +81,7 @@ func (P *pointR1) FromBytes(k []byte) bool {
 		return false
 	}
 	fp.Modp(&P.x) // x = x mod p
-	if fp.IsZero(&P.x) && signX == 1 {
+	if fp.IsZero(&P.x) && signX == 1 && !permissive {
 		return false
 	}
 	if signX != (P.x[0] & 1) {
@@ -167,6 +174,20 @@ func (P *pointR1) isEqual(Q *pointR1) bool {
 	return b && !fp.IsZero(&P.z) && !fp.IsZero(&Q.z)
 }
 
+// isIdentity returns true if P is the identity point.
+func (P *pointR1) isIdentity() bool {
+	t := &fp.Elt{}
+	fp.Sub(t, &P.y, &P.z)
+	return fp.IsZero(&P.x) && fp.IsZero(t) && !fp.IsZero(&P.z)
+}
+
+// clearCofactor calculates P = 8P.
+func (P *pointR1) clearCofactor() {
+	P.double()
+	P.double()
+	P.double()
+}
+
 func (P *pointR3) neg() {
 	P.addYX, P.subYX = P.subYX, P.addYX
 	fp.Neg(&P.dt2, &P.dt2)
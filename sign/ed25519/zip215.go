@@ -0,0 +1,66 @@
+package ed25519
+
+import "crypto/sha512"
+
+// VerifyZIP215 returns true if the signature is valid according to the
+// rules of ZIP-215, and supports only the pure Ed25519 variant.
+//
+// Unlike [Verify], which follows the strict rules of RFC-8032, it accepts
+// non-canonical encodings of the public key and of R, and it checks the
+// cofactored equation [8][S]B = [8]R + [8][k]A. The scalar S must still be
+// canonical. Verification with these rules gives the same result as with
+// [BatchVerifier], and across implementations following ZIP-215.
+//
+// Reference: https://zips.z.cash/zip-0215
+func VerifyZIP215(public PublicKey, message, signature []byte) bool {
+	var e zip215Entry
+	return e.set(public, message, signature) && e.verify()
+}
+
+// zip215Entry holds a signature decoded with the rules of ZIP-215.
+type zip215Entry struct {
+	A, R pointR1
+	s, k [paramB]byte
+	ok   bool
+}
+
+// set decodes the public key and the signature, and computes the challenge
+// k = SHA512(R || A || M). It returns false if decoding fails.
+func (e *zip215Entry) set(public PublicKey, message, signature []byte) bool {
+	e.ok = len(public) == PublicKeySize &&
+		len(signature) == SignatureSize &&
+		isLessThanOrder(signature[paramB:]) &&
+		e.A.fromBytes(public, true) &&
+		e.R.fromBytes(signature[:paramB], true)
+	if !e.ok {
+		return false
+	}
+
+	H := sha512.New()
+	_, _ = H.Write(signature[:paramB])
+	_, _ = H.Write(public)
+	_, _ = H.Write(message)
+	hRAM := H.Sum(nil)
+	reduceModOrder(hRAM[:], true)
+	copy(e.k[:], hRAM[:paramB])
+	copy(e.s[:], signature[paramB:])
+	return true
+}
+
+// verify returns true if [8]([s]B - [k]A - R) is the identity.
+func (e *zip215Entry) verify() bool {
+	if !e.ok {
+		return false
+	}
+
+	var P pointR1
+	var R pointR2
+	negA := e.A
+	negA.neg()
+	P.doubleMult(&negA, e.s[:], e.k[:])
+	R.fromR1(&e.R)
+	R.neg()
+	P.add(&R)
+	P.clearCofactor()
+	return P.isIdentity()
+}
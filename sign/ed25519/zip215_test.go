@@ -0,0 +1,192 @@
+package ed25519
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// signWithTorsion returns a signature of the message whose R has a component
+// of order 4, which is valid only for the cofactored verification equation.
+func signWithTorsion(t *testing.T, priv PrivateKey, message []byte) []byte {
+	h := sha512.Sum512(priv[:SeedSize])
+	clamp(h[:])
+
+	// The point with y = 0 has order 4.
+	var T pointR1
+	if !T.FromBytes(make([]byte, paramB)) {
+		t.Fatal("decoding of the point of order 4 failed")
+	}
+	var T2 pointR2
+	T2.fromR1(&T)
+
+	r := make([]byte, 2*paramB)
+	_, _ = rand.Read(r)
+	reduceModOrder(r, true)
+	var R pointR1
+	R.fixedMult(r[:paramB])
+	R.add(&T2)
+	sig := make([]byte, SignatureSize)
+	if err := R.ToBytes(sig[:paramB]); err != nil {
+		t.Fatal(err)
+	}
+
+	k := sha512.Sum512(append(append(bytes.Clone(sig[:paramB]), priv[SeedSize:]...), message...))
+	reduceModOrder(k[:], true)
+	calculateS(sig[paramB:], r[:paramB], k[:paramB], h[:paramB])
+	return sig
+}
+
+func TestZIP215(t *testing.T) {
+	_, priv, _ := GenerateKey(nil)
+	pub := priv.Public().(PublicKey)
+	msg := []byte("message")
+
+	sig := Sign(priv, msg)
+	if !Verify(pub, msg, sig) || !VerifyZIP215(pub, msg, sig) {
+		t.Fatal("verification of a canonical signature failed")
+	}
+
+	// Torsion in R is accepted only by the cofactored equation.
+	sig = signWithTorsion(t, priv, msg)
+	if Verify(pub, msg, sig) {
+		t.Fatal("strict verification accepted a signature with torsion")
+	}
+	if !VerifyZIP215(pub, msg, sig) {
+		t.Fatal("ZIP-215 verification rejected a signature with torsion")
+	}
+
+	// Non-canonical encodings of the identity as public key: y = p+1, and
+	// x = 0 with its sign bit set. Then, (R, S) = ([S]B, S) is valid.
+	s := make([]byte, paramB)
+	s[0] = 7
+	var R pointR1
+	R.fixedMult(s)
+	sig = make([]byte, SignatureSize)
+	_ = R.ToBytes(sig[:paramB])
+	copy(sig[paramB:], s)
+
+	yp1 := bytes.Repeat([]byte{0xff}, PublicKeySize)
+	yp1[0], yp1[paramB-1] = 0xee, 0x7f
+	negZero := make([]byte, PublicKeySize)
+	negZero[0], negZero[paramB-1] = 0x01, 0x80
+	for _, pk := range []PublicKey{yp1, negZero} {
+		if Verify(pk, msg, sig) {
+			t.Fatalf("strict verification accepted the public key %x", pk)
+		}
+		if !VerifyZIP215(pk, msg, sig) {
+			t.Fatalf("ZIP-215 verification rejected the public key %x", pk)
+		}
+	}
+
+	// S must be canonical in both modes.
+	sig = Sign(priv, msg)
+	copy(sig[paramB:], order[:])
+	if Verify(pub, msg, sig) || VerifyZIP215(pub, msg, sig) {
+		t.Fatal("verification accepted a non-canonical S")
+	}
+}
+
+// The 196 small-order and non-canonical cases of ZIP-215, from
+// https://github.com/ZcashFoundation/ed25519-zebra, as published in
+// https://github.com/hdevalence/ed25519consensus. All of them are signatures
+// of "Zcash" valid under the rules of ZIP-215.
+func TestZIP215Vectors(t *testing.T) {
+	buf, err := os.ReadFile("testdata/zip215.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []struct {
+		Vk  string `json:"vk"`
+		Sig string `json:"sig"`
+	}
+	if err = json.Unmarshal(buf, &cases); err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 196 {
+		t.Fatalf("expected 196 cases, got %v", len(cases))
+	}
+
+	msg := []byte("Zcash")
+	all := NewBatchVerifier(len(cases))
+	for i, c := range cases {
+		pk, _ := hex.DecodeString(c.Vk)
+		sig, _ := hex.DecodeString(c.Sig)
+		if !VerifyZIP215(pk, msg, sig) {
+			t.Fatalf("case %v: ZIP-215 verification failed", i)
+		}
+
+		var v BatchVerifier
+		v.Add(pk, msg, sig)
+		if !v.Verify(nil) {
+			t.Fatalf("case %v: batch verification failed", i)
+		}
+		all.Add(pk, msg, sig)
+	}
+
+	if !all.Verify(nil) {
+		t.Fatal("batch verification of all the cases failed")
+	}
+	for i, ok := range all.VerifyEach(nil) {
+		if !ok {
+			t.Fatalf("case %v: VerifyEach failed", i)
+		}
+	}
+}
+
+func TestBatchVerifier(t *testing.T) {
+	const n = 16
+	var v BatchVerifier
+	if !v.Verify(nil) {
+		t.Fatal("verification of an empty batch failed")
+	}
+
+	msgs := make([][]byte, n)
+	for i := range msgs {
+		_, priv, _ := GenerateKey(nil)
+		msgs[i] = []byte{byte(i)}
+		sig := Sign(priv, msgs[i])
+		if i == 3 {
+			sig = signWithTorsion(t, priv, msgs[i])
+		}
+		v.Add(priv.Public().(PublicKey), msgs[i], sig)
+	}
+	if v.Len() != n || !v.Verify(nil) {
+		t.Fatal("batch verification failed")
+	}
+
+	// A batch with an invalid signature is rejected, and it is found by
+	// verifying the signatures one at a time.
+	_, priv, _ := GenerateKey(nil)
+	v.Add(priv.Public().(PublicKey), []byte("other"), Sign(priv, msgs[0]))
+	v.Add(priv.Public().(PublicKey), msgs[0], make([]byte, SignatureSize-1))
+	if v.Verify(nil) {
+		t.Fatal("batch verification succeeded with invalid signatures")
+	}
+	for i, ok := range v.VerifyEach(nil) {
+		if ok != (i < n) {
+			t.Fatalf("wrong result for signature %v: %v", i, ok)
+		}
+	}
+}
+
+func BenchmarkBatchVerification(b *testing.B) {
+	for _, n := range []int{1, 8, 64} {
+		v := NewBatchVerifier(n)
+		msg := []byte("message")
+		for i := 0; i < n; i++ {
+			_, priv, _ := GenerateKey(nil)
+			v.Add(priv.Public().(PublicKey), msg, Sign(priv, msg))
+		}
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				v.Verify(nil)
+			}
+		})
+	}
+}
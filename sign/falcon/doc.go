@@ -0,0 +1,26 @@
+//go:generate go run gen.go
+
+// falcon implements the post-quantum signature scheme Falcon, as submitted
+// to round 3 of the NIST PQC competition, which will be standardised by
+// NIST as FN-DSA (FIPS206).
+//
+// Signatures use the padded format, so that they have a fixed size. The
+// Gaussian sampling of signing is implemented with emulated floating-point
+// arithmetic, in constant time and with the same results on all
+// platforms.
+//
+// Key generation solves the NTRU equation as the reference implementation
+// does, with integers whose sizes only depend on the security level, so
+// that it runs in constant time as well. Only the rejection of candidate
+// keys depends on their values.
+//
+// Each of the two security levels of Falcon is implemented by a
+// subpackage. For instance, Falcon-512 can be found in
+//
+//	github.com/quantumcoinproject/circl/sign/falcon/falcon512
+//
+// If your choice for mode is fixed compile-time, use the subpackages.
+// To choose a scheme at runtime, use the generic signatures API under
+//
+//	github.com/quantumcoinproject/circl/sign/schemes
+package falcon
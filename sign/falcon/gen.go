@@ -0,0 +1,82 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates wrappers from templates to prevent too much duplicated code
+// between the code for different modes.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type Mode struct {
+	Name           string
+	Pkg            string
+	LogN           int
+	PublicKeySize  int
+	PrivateKeySize int
+	SignatureSize  int
+	Oid            string
+	TLSIdentifier  string
+}
+
+var (
+	Modes = []Mode{
+		{
+			Name: "Falcon-512", Pkg: "falcon512", LogN: 9,
+			PublicKeySize: 897, PrivateKeySize: 1281, SignatureSize: 666,
+			Oid: "1, 3, 9999, 3, 11", TLSIdentifier: "0xfed7",
+		},
+		{
+			Name: "Falcon-1024", Pkg: "falcon1024", LogN: 10,
+			PublicKeySize: 1793, PrivateKeySize: 2305, SignatureSize: 1280,
+			Oid: "1, 3, 9999, 3, 14", TLSIdentifier: "0xfeda",
+		},
+	}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates modeX/falcon.go from templates/pkg.templ.go
+func generatePackageFiles() {
+	tl, err := template.ParseFiles("templates/pkg.templ.go")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, mode := range Modes {
+		buf := new(bytes.Buffer)
+		err := tl.Execute(buf, mode)
+		if err != nil {
+			panic(err)
+		}
+
+		// Formating output code
+		code, err := format.Source(buf.Bytes())
+		if err != nil {
+			panic(fmt.Sprintf("error formating code: %v", err))
+		}
+
+		res := string(code)
+		offset := strings.Index(res, TemplateWarning)
+		if offset == -1 {
+			panic("Missing template warning in pkg.templ.go")
+		}
+		err = os.MkdirAll(mode.Pkg, 0o755)
+		if err != nil {
+			panic(err)
+		}
+		err = os.WriteFile(mode.Pkg+"/falcon.go", []byte(res[offset:]), 0o644)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package internal implements the Falcon signature scheme for all the
+// supported degrees.
+package internal
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+const (
+	// NonceSize is the size of the random nonce of a signature.
+	NonceSize = 40
+
+	// SeedSize is the size of the seed from which keys are derived.
+	SeedSize = 48
+)
+
+// PublicKeySize returns the size of a packed public key of degree 2^logn.
+func PublicKeySize(logn uint) int { return 1 + (14<<logn)/8 }
+
+// PrivateKeySize returns the size of a packed private key of degree
+// 2^logn.
+func PrivateKeySize(logn uint) int {
+	return 1 + int((2*maxFGBits(logn)+maxCapFGBits)<<logn)/8
+}
+
+// SignatureSize returns the size of a padded signature of degree 2^logn.
+func SignatureSize(logn uint) int {
+	switch logn {
+	case 9:
+		return 666
+	case 10:
+		return 1280
+	}
+	panic("falcon: unsupported degree")
+}
+
+// PublicKey is a Falcon public key h = g/f mod q.
+type PublicKey struct {
+	logn uint
+	h    []uint16
+}
+
+// PrivateKey is a Falcon private key, the short basis
+// [[g, -f], [G, -F]] of the NTRU lattice of h.
+type PrivateKey struct {
+	logn       uint
+	f, g, capF []int8
+	capG       []int8
+	h          []uint16
+}
+
+var (
+	ErrInvalidPublicKey  = errors.New("falcon: invalid public key")
+	ErrInvalidPrivateKey = errors.New("falcon: invalid private key")
+)
+
+// Public returns the public key of sk.
+func (sk *PrivateKey) Public() *PublicKey {
+	return &PublicKey{logn: sk.logn, h: sk.h}
+}
+
+// Equal returns whether the two public keys are equal.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	if pk.logn != other.logn {
+		return false
+	}
+	for i := range pk.h {
+		if pk.h[i] != other.h[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns whether the two private keys are equal, in constant time
+// for private keys of the same degree.
+func (sk *PrivateKey) Equal(other *PrivateKey) bool {
+	if sk.logn != other.logn {
+		return false
+	}
+	a := make([]byte, PrivateKeySize(sk.logn))
+	b := make([]byte, PrivateKeySize(sk.logn))
+	sk.Pack(a)
+	other.Pack(b)
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Pack packs the public key into buf, which must be of length
+// PublicKeySize(logn).
+func (pk *PublicKey) Pack(buf []byte) {
+	buf[0] = byte(pk.logn)
+	var acc uint32
+	accLen := 0
+	j := 1
+	for _, c := range pk.h {
+		acc = acc<<14 | uint32(c)
+		accLen += 14
+		for accLen >= 8 {
+			accLen -= 8
+			buf[j] = byte(acc >> accLen)
+			j++
+		}
+	}
+	if accLen > 0 {
+		buf[j] = byte(acc << (8 - accLen))
+	}
+}
+
+// Unpack unpacks a public key of degree 2^logn from buf, which must be of
+// length PublicKeySize(logn).
+func (pk *PublicKey) Unpack(buf []byte, logn uint) error {
+	if len(buf) != PublicKeySize(logn) || buf[0] != byte(logn) {
+		return ErrInvalidPublicKey
+	}
+	h := make([]uint16, 1<<logn)
+	var acc uint32
+	accLen := 0
+	i := 0
+	for _, b := range buf[1:] {
+		acc = acc<<8 | uint32(b)
+		accLen += 8
+		if accLen >= 14 {
+			accLen -= 14
+			c := (acc >> accLen) & 0x3FFF
+			if c >= Q {
+				return ErrInvalidPublicKey
+			}
+			h[i] = uint16(c)
+			i++
+		}
+	}
+	if acc&(1<<accLen-1) != 0 {
+		return ErrInvalidPublicKey
+	}
+	pk.logn, pk.h = logn, h
+	return nil
+}
+
+// Pack packs the private key into buf, which must be of length
+// PrivateKeySize(logn).
+func (sk *PrivateKey) Pack(buf []byte) {
+	buf[0] = 0x50 + byte(sk.logn)
+	j := 1
+	j += packInts(buf[j:], sk.f, maxFGBits(sk.logn))
+	j += packInts(buf[j:], sk.g, maxFGBits(sk.logn))
+	packInts(buf[j:], sk.capF, maxCapFGBits)
+}
+
+// Unpack unpacks a private key of degree 2^logn from buf, which must be
+// of length PrivateKeySize(logn).
+func (sk *PrivateKey) Unpack(buf []byte, logn uint) error {
+	if len(buf) != PrivateKeySize(logn) || buf[0] != 0x50+byte(logn) {
+		return ErrInvalidPrivateKey
+	}
+	n := 1 << logn
+	f := make([]int8, n)
+	g := make([]int8, n)
+	capF := make([]int8, n)
+	buf = buf[1:]
+	for _, p := range []struct {
+		v    []int8
+		bits uint
+	}{{f, maxFGBits(logn)}, {g, maxFGBits(logn)}, {capF, maxCapFGBits}} {
+		k, ok := unpackInts(p.v, buf, p.bits)
+		if !ok {
+			return ErrInvalidPrivateKey
+		}
+		buf = buf[k:]
+	}
+
+	// G is recomputed from f·G - g·F = q.
+	h, ok := computePublicKey(f, g, logn)
+	if !ok {
+		return ErrInvalidPrivateKey
+	}
+	G := completePrivateKey(f, g, capF, logn)
+	capG := make([]int8, n)
+	const lim = 1<<(maxCapFGBits-1) - 1
+	for i, c := range G {
+		if c < -lim || c > lim {
+			return ErrInvalidPrivateKey
+		}
+		capG[i] = int8(c)
+	}
+	*sk = PrivateKey{logn: logn, f: f, g: g, capF: capF, capG: capG, h: h}
+	return nil
+}
+
+// packInts packs the coefficients of p in two's complement with the given
+// number of bits each, and returns the number of bytes written.
+func packInts(buf []byte, p []int8, bits uint) int {
+	var acc uint32
+	accLen := uint(0)
+	j := 0
+	mask := uint32(1)<<bits - 1
+	for _, c := range p {
+		acc = acc<<bits | (uint32(c) & mask)
+		accLen += bits
+		for accLen >= 8 {
+			accLen -= 8
+			buf[j] = byte(acc >> accLen)
+			j++
+		}
+	}
+	if accLen > 0 {
+		buf[j] = byte(acc << (8 - accLen))
+		j++
+	}
+	return j
+}
+
+// unpackInts is the inverse of packInts. It rejects the value -2^(bits-1),
+// which is never used.
+func unpackInts(p []int8, buf []byte, bits uint) (int, bool) {
+	var acc uint32
+	accLen := uint(0)
+	j, i := 0, 0
+	mask := uint32(1)<<bits - 1
+	sign := uint32(1) << (bits - 1)
+	for i < len(p) {
+		if j == len(buf) {
+			return 0, false
+		}
+		acc = acc<<8 | uint32(buf[j])
+		j++
+		accLen += 8
+		for accLen >= bits && i < len(p) {
+			accLen -= bits
+			w := (acc >> accLen) & mask
+			if w == sign {
+				return 0, false
+			}
+			p[i] = int8(w | -(w & sign)) // sign extension
+			i++
+		}
+	}
+	if acc&(1<<accLen-1) != 0 {
+		return 0, false
+	}
+	return j, true
+}
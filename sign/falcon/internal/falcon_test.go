@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+// randFloat returns a random float64 with a moderate exponent, as
+// overflows and subnormals are not supported.
+func randFloat() float64 {
+	var b [10]byte
+	_, _ = rand.Read(b[:])
+	m := binary.LittleEndian.Uint64(b[:]) & (1<<52 - 1)
+	x := math.Ldexp(math.Float64frombits(m|1023<<52), int(b[8])-128)
+	if b[9]&1 == 1 {
+		x = -x
+	}
+	return x
+}
+
+func TestFpr(t *testing.T) {
+	for i := 0; i < 100000; i++ {
+		a, b := randFloat(), randFloat()
+		x, y := fpr(math.Float64bits(a)), fpr(math.Float64bits(b))
+		for _, tc := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"add", math.Float64frombits(uint64(x.add(y))), a + b},
+			{"sub", math.Float64frombits(uint64(x.sub(y))), a - b},
+			{"mul", math.Float64frombits(uint64(x.mul(y))), a * b},
+			{"div", math.Float64frombits(uint64(x.div(y))), a / b},
+			{"sqrt", math.Float64frombits(uint64(x.neg().sqrt())), math.Sqrt(-a)},
+			{"half", math.Float64frombits(uint64(x.half())), a / 2},
+			{"double", math.Float64frombits(uint64(x.double())), a * 2},
+		} {
+			if tc.name == "sqrt" && a > 0 {
+				continue
+			}
+			if tc.got != tc.want {
+				test.ReportError(t, tc.got, tc.want, tc.name, a, b)
+			}
+		}
+		if a > -1<<61 && a < 1<<61 {
+			if got, want := x.rint(), int64(math.RoundToEven(a)); got != want {
+				test.ReportError(t, got, want, "rint", a)
+			}
+			if got, want := x.floor(), int64(math.Floor(a)); got != want {
+				test.ReportError(t, got, want, "floor", a)
+			}
+			if got, want := x.trunc(), int64(math.Trunc(a)); got != want {
+				test.ReportError(t, got, want, "trunc", a)
+			}
+		}
+		if got, want := x.lt(y) == 1, a < b; got != want {
+			test.ReportError(t, got, want, "lt", a, b)
+		}
+		if k := int64(a * (1 << 20)); fprOf(k) != fpr(math.Float64bits(float64(k))) {
+			test.ReportError(t, fprOf(k), float64(k), "of")
+		}
+	}
+}
+
+func TestRint(t *testing.T) {
+	for _, a := range []float64{0.5, 1.5, 2.5, -0.5, -1.5, -2.5, 0.49999999999999994, 4503599627370495.5} {
+		if got, want := fpr(math.Float64bits(a)).rint(), int64(math.RoundToEven(a)); got != want {
+			test.ReportError(t, got, want, a)
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	for logn := uint(1); logn <= maxLogN; logn++ {
+		n := 1 << logn
+		a, b := make([]int8, n), make([]int8, n)
+		for i := range a {
+			a[i], b[i] = int8(i*37%255-127), int8(i*91%201-100)
+		}
+
+		// Compare the product computed with the FFT to the one modulo q.
+		at, bt := toFFT(a, logn), toFFT(b, logn)
+		polyMul(at, bt)
+		ifft(at, logn)
+		want := make([]int64, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if i+j < n {
+					want[i+j] += int64(a[i]) * int64(b[j])
+				} else {
+					want[i+j-n] -= int64(a[i]) * int64(b[j])
+				}
+			}
+		}
+		for i := range want {
+			if got := at[i].rint(); got != want[i] {
+				test.ReportError(t, got, want[i], logn, i)
+			}
+		}
+
+		// Split and merge.
+		ft := toFFT(a, logn)
+		f0, f1 := make([]fpr, n/2), make([]fpr, n/2)
+		polySplit(f0, f1, ft, logn)
+		ifft(f0, logn-1)
+		ifft(f1, logn-1)
+		for i := 0; i < n/2; i++ {
+			if f0[i].rint() != int64(a[2*i]) || f1[i].rint() != int64(a[2*i+1]) {
+				test.ReportError(t, f0[i].rint(), a[2*i], "split", logn, i)
+			}
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	for _, logn := range []uint{9, 10} {
+		var seed [SeedSize]byte
+		_, _ = rand.Read(seed[:])
+		pk, sk := NewKeyFromSeed(seed[:], logn)
+		sig := make([]byte, SignatureSize(logn))
+		msg := []byte("message")
+		test.CheckNoErr(t, SignTo(sk, msg, rand.Reader, sig), "sign")
+		if !Verify(pk, msg, sig) {
+			t.Fatal("verification failed")
+		}
+		sig[50]++
+		if Verify(pk, msg, sig) {
+			t.Fatal("verification of a modified signature succeeded")
+		}
+	}
+}
+
+func TestBezout(t *testing.T) {
+	const l = 4
+	toBig := func(a []uint64) *big.Int {
+		r := new(big.Int)
+		for i := len(a) - 1; i >= 0; i-- {
+			r.Lsh(r, 64).Or(r, new(big.Int).SetUint64(a[i]))
+		}
+		return r
+	}
+	for i := 0; i < 100; i++ {
+		x, y := make([]uint64, l), make([]uint64, l)
+		var b [16 * l]byte
+		_, _ = rand.Read(b[:])
+		for j := 0; j < l-1; j++ {
+			x[j] = binary.LittleEndian.Uint64(b[8*j:])
+			y[j] = binary.LittleEndian.Uint64(b[8*(l+j):])
+		}
+		x[0] |= 1
+		bx, by := toBig(x), toBig(y)
+		want := new(big.Int).GCD(nil, nil, bx, by).Cmp(big.NewInt(1)) == 0 && y[0]&1 == 1
+
+		u, v := make([]uint64, l), make([]uint64, l)
+		if got := zBezout(u, v, x, y, 64*(l-1)); got != want {
+			test.ReportError(t, got, want, bx, by)
+		}
+		if want {
+			r := new(big.Int).Mul(bx, toBig(u))
+			r.Sub(r, new(big.Int).Mul(by, toBig(v)))
+			if r.Cmp(big.NewInt(1)) != 0 {
+				test.ReportError(t, r, 1, bx, by)
+			}
+		}
+	}
+}
+
+func TestNTRUEquation(t *testing.T) {
+	for _, logn := range []uint{9, 10} {
+		var seed [SeedSize]byte
+		_, _ = rand.Read(seed[:])
+		_, sk := NewKeyFromSeed(seed[:], logn)
+
+		// f·G - g·F = q modulo x^n + 1.
+		n := 1 << logn
+		r := make([]int, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				t := int(sk.f[i])*int(sk.capG[j]) - int(sk.g[i])*int(sk.capF[j])
+				if i+j < n {
+					r[i+j] += t
+				} else {
+					r[i+j-n] -= t
+				}
+			}
+		}
+		r[0] -= Q
+		for i, x := range r {
+			if x != 0 {
+				t.Fatalf("coefficient %v of f·G - g·F - q is %v", i, x)
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// Polynomials of R[x]/(x^n + 1) are represented in FFT form by their
+// evaluations at the n/2 roots of x^n + 1 which are roots of x^(n/2) - i,
+// one of each pair of conjugate roots. The n/2 complex values of a
+// polynomial of degree n are stored with their real parts first, followed
+// by their imaginary parts.
+//
+// The roots are ordered along the tree of the factorisation of
+// x^(n/2) - i: node 1 is x - i, and the children of the node x - w are
+// nodes 2k and 2k+1 for x - √w and x + √w. The evaluation at position p
+// is at the root of node n/2 + p.
+
+// maxLogN is the largest supported degree.
+const maxLogN = 10
+
+var (
+	rootsOnce sync.Once
+
+	// roots[k] is the principal square root of the root w of node k.
+	rootsRe, rootsIm [1 << (maxLogN - 1)]fpr
+)
+
+// initRoots computes the roots with arbitrary precision arithmetic, so
+// that they are the same on all platforms.
+func initRoots() {
+	const prec = 128
+	newFloat := func() *big.Float { return new(big.Float).SetPrec(prec) }
+	one := newFloat().SetInt64(1)
+	half := newFloat().SetFloat64(0.5)
+	wRe := make([]*big.Float, len(rootsRe))
+	wIm := make([]*big.Float, len(rootsIm))
+	wRe[1], wIm[1] = newFloat(), newFloat().SetInt64(1)
+	for k := 1; k < len(wRe); k++ {
+		// √w = √((1 + Re w)/2) + i·√((1 - Re w)/2), with the sign of the
+		// real part chosen to be the one of Im w.
+		sRe := newFloat().Add(one, wRe[k])
+		sRe.Sqrt(sRe.Mul(sRe, half))
+		if wIm[k].Sign() < 0 {
+			sRe.Neg(sRe)
+		}
+		sIm := newFloat().Sub(one, wRe[k])
+		sIm.Sqrt(sIm.Mul(sIm, half))
+
+		re, _ := sRe.Float64()
+		im, _ := sIm.Float64()
+		rootsRe[k], rootsIm[k] = fpr(math.Float64bits(re)), fpr(math.Float64bits(im))
+		if 2*k+1 < len(wRe) {
+			wRe[2*k], wIm[2*k] = sRe, sIm
+			wRe[2*k+1], wIm[2*k+1] = newFloat().Neg(sRe), newFloat().Neg(sIm)
+		}
+	}
+}
+
+// root returns the square root of the root of node k.
+func root(k int) (re, im fpr) {
+	rootsOnce.Do(initRoots)
+	return rootsRe[k], rootsIm[k]
+}
+
+func cmul(aRe, aIm, bRe, bIm fpr) (fpr, fpr) {
+	return aRe.mul(bRe).sub(aIm.mul(bIm)), aRe.mul(bIm).add(aIm.mul(bRe))
+}
+
+// fft converts f of degree 2^logn from coefficients to FFT form in place.
+func fft(f []fpr, logn uint) {
+	hn := len(f) >> 1
+	t := hn
+	for u := uint(1); u < logn; u++ {
+		ht := t >> 1
+		hm := 1 << (u - 1)
+		for i, j1 := 0, 0; i < hm; i, j1 = i+1, j1+t {
+			sRe, sIm := root(hm + i)
+			for j := j1; j < j1+ht; j++ {
+				xRe, xIm := f[j], f[j+hn]
+				yRe, yIm := cmul(f[j+ht], f[j+ht+hn], sRe, sIm)
+				f[j], f[j+hn] = xRe.add(yRe), xIm.add(yIm)
+				f[j+ht], f[j+ht+hn] = xRe.sub(yRe), xIm.sub(yIm)
+			}
+		}
+		t = ht
+	}
+}
+
+// ifft converts f of degree 2^logn from FFT form to coefficients in place.
+func ifft(f []fpr, logn uint) {
+	hn := len(f) >> 1
+	t := 1
+	for u := int(logn) - 1; u >= 1; u-- {
+		dt := t << 1
+		hm := 1 << (u - 1)
+		for i, j1 := 0, 0; i < hm; i, j1 = i+1, j1+dt {
+			sRe, sIm := root(hm + i)
+			for j := j1; j < j1+t; j++ {
+				xRe, xIm := f[j], f[j+hn]
+				yRe, yIm := f[j+t], f[j+t+hn]
+				f[j], f[j+hn] = xRe.add(yRe), xIm.add(yIm)
+				f[j+t], f[j+t+hn] = cmul(xRe.sub(yRe), xIm.sub(yIm), sRe, sIm.neg())
+			}
+		}
+		t = dt
+	}
+	// Each level but the first doubled the values.
+	if logn > 1 {
+		sc := fprScaled(1, 1-int(logn))
+		for i := range f {
+			f[i] = f[i].mul(sc)
+		}
+	}
+}
+
+func polyAdd(a, b []fpr) {
+	for i := range a {
+		a[i] = a[i].add(b[i])
+	}
+}
+
+func polySub(a, b []fpr) {
+	for i := range a {
+		a[i] = a[i].sub(b[i])
+	}
+}
+
+func polyNeg(a []fpr) {
+	for i := range a {
+		a[i] = a[i].neg()
+	}
+}
+
+func polyMulConst(a []fpr, c fpr) {
+	for i := range a {
+		a[i] = a[i].mul(c)
+	}
+}
+
+// polyAdj replaces a, in FFT form, by its adjoint a(1/x).
+func polyAdj(a []fpr) {
+	hn := len(a) >> 1
+	for i := hn; i < len(a); i++ {
+		a[i] = a[i].neg()
+	}
+}
+
+// polyMul multiplies a by b, both in FFT form.
+func polyMul(a, b []fpr) {
+	hn := len(a) >> 1
+	for i := 0; i < hn; i++ {
+		a[i], a[i+hn] = cmul(a[i], a[i+hn], b[i], b[i+hn])
+	}
+}
+
+// polyMulAdj multiplies a by the adjoint of b, both in FFT form.
+func polyMulAdj(a, b []fpr) {
+	hn := len(a) >> 1
+	for i := 0; i < hn; i++ {
+		a[i], a[i+hn] = cmul(a[i], a[i+hn], b[i], b[i+hn].neg())
+	}
+}
+
+// polyMulAutoAdj multiplies a by b, in FFT form, where b is self-adjoint,
+// that is, has only real values.
+func polyMulAutoAdj(a, b []fpr) {
+	hn := len(a) >> 1
+	for i := 0; i < hn; i++ {
+		a[i] = a[i].mul(b[i])
+		a[i+hn] = a[i+hn].mul(b[i])
+	}
+}
+
+// polyDivAutoAdj divides a by b, in FFT form, where b is self-adjoint.
+func polyDivAutoAdj(a, b []fpr) {
+	hn := len(a) >> 1
+	for i := 0; i < hn; i++ {
+		ib := b[i].inv()
+		a[i] = a[i].mul(ib)
+		a[i+hn] = a[i+hn].mul(ib)
+	}
+}
+
+// polyMulSelfAdj replaces a, in FFT form, by a·adj(a). The imaginary parts
+// of the result are zero.
+func polyMulSelfAdj(a []fpr) {
+	hn := len(a) >> 1
+	for i := 0; i < hn; i++ {
+		a[i] = a[i].sqr().add(a[i+hn].sqr())
+		a[i+hn] = fprZero
+	}
+}
+
+// polyAddMulSelfAdj returns a·adj(a) + b·adj(b), in FFT form.
+func polyAddMulSelfAdj(a, b []fpr) []fpr {
+	d := make([]fpr, len(a))
+	hn := len(a) >> 1
+	for i := 0; i < hn; i++ {
+		d[i] = a[i].sqr().add(a[i+hn].sqr()).add(b[i].sqr()).add(b[i+hn].sqr())
+	}
+	return d
+}
+
+// polySplit splits f, of degree 2^logn in FFT form, into f0 and f1 of
+// degree 2^(logn-1) in FFT form such that f = f0(x²) + x·f1(x²).
+func polySplit(f0, f1, f []fpr, logn uint) {
+	if logn == 1 {
+		f0[0], f1[0] = f[0], f[1]
+		return
+	}
+	hn := len(f) >> 1
+	qn := hn >> 1
+	for q := 0; q < qn; q++ {
+		aRe, aIm := f[2*q], f[2*q+hn]
+		bRe, bIm := f[2*q+1], f[2*q+1+hn]
+		f0[q], f0[q+qn] = aRe.add(bRe).half(), aIm.add(bIm).half()
+		// The root of position 2q is the square root of the root of
+		// node qn + q.
+		sRe, sIm := root(qn + q)
+		f1[q], f1[q+qn] = cmul(aRe.sub(bRe).half(), aIm.sub(bIm).half(), sRe, sIm.neg())
+	}
+}
+
+// polyMerge is the inverse of polySplit.
+func polyMerge(f, f0, f1 []fpr, logn uint) {
+	if logn == 1 {
+		f[0], f[1] = f0[0], f1[0]
+		return
+	}
+	hn := len(f) >> 1
+	qn := hn >> 1
+	for q := 0; q < qn; q++ {
+		sRe, sIm := root(qn + q)
+		yRe, yIm := cmul(f1[q], f1[q+qn], sRe, sIm)
+		xRe, xIm := f0[q], f0[q+qn]
+		f[2*q], f[2*q+hn] = xRe.add(yRe), xIm.add(yIm)
+		f[2*q+1], f[2*q+1+hn] = xRe.sub(yRe), xIm.sub(yIm)
+	}
+}
@@ -0,0 +1,284 @@
+package internal
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fpr is an IEEE-754 binary64 value, stored as its bit pattern. All
+// operations on it are emulated with integer arithmetic, so that they are
+// constant-time and give the same results on all platforms. Subnormals are
+// flushed to zero, and infinities and NaNs are not supported.
+type fpr uint64
+
+const (
+	fprZero    fpr = 0
+	fprOne     fpr = 0x3FF0000000000000
+	fprTwo     fpr = 0x4000000000000000
+	fprOneHalf fpr = 0x3FE0000000000000
+	fprPTwo63  fpr = 0x43E0000000000000
+)
+
+var (
+	fprQ          = fprOf(Q)
+	fprInvQ       = fpr(math.Float64bits(1.0 / Q))
+	fprLog2       = fpr(math.Float64bits(math.Ln2))
+	fprInvLog2    = fpr(math.Float64bits(1 / math.Ln2))
+	fprBNormMax   = fpr(math.Float64bits(16822.4121))
+	fprInvSigma0  = fpr(math.Float64bits(0.150865048875372721532312163019)) // 1/(2·1.8205²)
+	fprSigmaMin9  = fpr(math.Float64bits(1.2778336969128337))
+	fprSigmaMin10 = fpr(math.Float64bits(1.2982803343442918))
+	fprSigma9     = fpr(math.Float64bits(165.7366171829776))
+	fprSigma10    = fpr(math.Float64bits(168.38857144654395))
+)
+
+// makeFpr returns (-1)^s·2^e·m rounded to nearest even, where m is either
+// zero or in [2^54, 2^55). The two lowest bits of m are the rounding bits,
+// the lowest one being sticky.
+func makeFpr(s uint64, e int, m uint64) fpr {
+	e += 1076
+	// Values too small to be normal are flushed to zero.
+	m &= uint64(int64(e)>>63) ^ ^uint64(0)
+	// A zero mantissa gets a zero exponent, but keeps its sign.
+	e &= -int(m >> 54)
+	x := (s << 63) | (m >> 2)
+	// The top bit of m, equal to one, increments the exponent field.
+	x += uint64(e) << 52
+	x += (0xC8 >> (m & 7)) & 1
+	return fpr(x)
+}
+
+// shrSticky returns x >> k, with the bits shifted out ORed into the lowest
+// bit of the result. k must be in [0, 63].
+func shrSticky(x uint64, k uint) uint64 {
+	mask := (uint64(1) << k) - 1
+	x |= (x & mask) + mask
+	return x >> k
+}
+
+// normalize returns (-1)^s·2^e·m for any m < 2^64.
+func normalize(s uint64, e int, m uint64) fpr {
+	lz := bits.LeadingZeros64(m)
+	m <<= uint(lz) & 63
+	// Compress to 55 bits, the lowest one being sticky.
+	m = shrSticky(m, 9)
+	return makeFpr(s, e-lz+9, m)
+}
+
+// fprScaled returns i·2^sc.
+func fprScaled(i int64, sc int) fpr {
+	s := uint64(i) >> 63
+	m := (uint64(i) ^ -s) + s
+	return normalize(s, sc, m)
+}
+
+func fprOf(i int64) fpr { return fprScaled(i, 0) }
+
+func (x fpr) neg() fpr { return x ^ (1 << 63) }
+
+func (x fpr) sign() uint64 { return uint64(x) >> 63 }
+
+// exp returns the biased exponent of x.
+func (x fpr) exp() int { return int(x>>52) & 0x7FF }
+
+// mant returns the mantissa of x with its implicit top bit, or zero if x is
+// zero.
+func (x fpr) mant() uint64 {
+	nz := uint64(x.exp()+0x7FF) >> 11
+	return (uint64(x) & (1<<52 - 1)) | nz<<52
+}
+
+// half returns x/2.
+func (x fpr) half() fpr {
+	// Decrementing the exponent of a zero or of the smallest normal
+	// values would underflow, flush those to zero instead.
+	e := uint64(x.exp())
+	x -= 1 << 52
+	return x & fpr(-(uint64(int64(1-e)>>63) & 1))
+}
+
+// double returns 2x.
+func (x fpr) double() fpr {
+	nz := uint64(x.exp()+0x7FF) >> 11
+	return x + fpr(nz<<52)
+}
+
+func (x fpr) add(y fpr) fpr {
+	// Make x the operand with the largest magnitude. When magnitudes are
+	// equal, make x the positive one so that x + (-x) = +0.
+	const mask = 1<<63 - 1
+	xm, ym := uint64(x)&mask, uint64(y)&mask
+	_, b := bits.Sub64(xm, ym, 0)
+	eq := ((xm ^ ym) - 1) >> 63
+	cs := b | (eq & x.sign())
+	sw := (uint64(x) ^ uint64(y)) & -cs
+	x ^= fpr(sw)
+	y ^= fpr(sw)
+
+	// Mantissas are given 3 extra bits of precision.
+	ex, ey := x.exp(), y.exp()
+	xu, yu := x.mant()<<3, y.mant()<<3
+	sx, sy := x.sign(), y.sign()
+
+	cc := ex - ey
+	yu &= -(uint64(cc-60) >> 63)
+	yu = shrSticky(yu, uint(cc)&63)
+
+	// Add or subtract.
+	d := sx ^ sy
+	yu = (yu ^ -d) + d
+	xu += yu
+	return normalize(sx, ex-1078, xu)
+}
+
+func (x fpr) sub(y fpr) fpr { return x.add(y.neg()) }
+
+func (x fpr) mul(y fpr) fpr {
+	xu, yu := x.mant(), y.mant()
+	hi, lo := bits.Mul64(xu, yu)
+	// The product is in [2^104, 2^106); keep 55 or 56 bits, the lowest
+	// being sticky.
+	const m50 = 1<<50 - 1
+	z := hi<<14 | lo>>50 | ((lo&m50)+m50)>>50
+	t := z >> 55
+	z = (z >> t) | (z & t)
+	e := x.exp() + y.exp() - 2100 + int(t)
+
+	// The product of zeros is zero.
+	nz := (uint64(x.exp()+0x7FF) >> 11) & (uint64(y.exp()+0x7FF) >> 11)
+	z &= -nz
+	return makeFpr(x.sign()^y.sign(), e, z)
+}
+
+func (x fpr) sqr() fpr { return x.mul(x) }
+
+// div returns x/y. y must not be zero.
+func (x fpr) div(y fpr) fpr {
+	xu, yu := x.mant(), y.mant()
+	// Long division, yielding a 55 bits quotient.
+	var q uint64
+	for i := 0; i < 55; i++ {
+		b := ((xu - yu) >> 63) - 1
+		xu -= b & yu
+		q |= b & 1
+		xu <<= 1
+		q <<= 1
+	}
+	q |= (xu | -xu) >> 63
+	// q is in [2^54, 2^56).
+	es := q >> 55
+	q = (q >> es) | (q & 1)
+	e := x.exp() - y.exp() - 55 + int(es)
+
+	nz := uint64(x.exp()+0x7FF) >> 11
+	q &= -nz
+	return makeFpr(x.sign()^y.sign(), e, q)
+}
+
+func (x fpr) inv() fpr { return fprOne.div(x) }
+
+// sqrt returns the square root of x, which must be nonnegative.
+func (x fpr) sqrt() fpr {
+	xu := x.mant()
+	ex := x.exp()
+	e := ex - 1023
+	// Make the exponent even.
+	xu += xu & -uint64(e&1)
+	e >>= 1
+	xu <<= 1
+
+	// Bit by bit square root: the value 1 is 2^53 in xu.
+	var q, s uint64
+	r := uint64(1) << 53
+	for i := 0; i < 54; i++ {
+		t := s + r
+		b := ((xu - t) >> 63) - 1
+		s += (r << 1) & b
+		xu -= t & b
+		q += r & b
+		xu <<= 1
+		r >>= 1
+	}
+	q <<= 1
+	q |= (xu | -xu) >> 63
+
+	nz := uint64(ex+0x7FF) >> 11
+	q &= -nz
+	return makeFpr(0, e-54, q)
+}
+
+// fixed returns the mantissa of x, shifted so that its top bit is bit 62,
+// along with the right shift k that converts it back to an integer, with
+// k ≥ 64 meaning that |x| < 1/2.
+func (x fpr) fixed() (m uint64, k int) {
+	return (uint64(x)<<10 | 1<<62) & (1<<63 - 1), 1085 - x.exp()
+}
+
+// rint returns x rounded to the nearest integer, ties to even. |x| must
+// be less than 2^62.
+func (x fpr) rint() int64 {
+	m, k := x.fixed()
+	m &= -(uint64(k-64) >> 63)
+	sh := uint(k) & 63
+	q := m >> sh
+	rem := m << (64 - sh)
+	sticky := ((rem << 1) | -(rem << 1)) >> 63
+	q += (rem >> 63) & ((q & 1) | sticky)
+	s := x.sign()
+	return int64((q ^ -s) + s)
+}
+
+// floor returns the largest integer not greater than x. |x| must be less
+// than 2^62.
+func (x fpr) floor() int64 {
+	m, k := x.fixed()
+	s := x.sign()
+	xi := int64((m ^ -s) + s)
+	// Arithmetic shifts round toward minus infinity. Shifts of 64 bits or
+	// more yield 0 or -1.
+	return xi >> uint(min(k, 63))
+}
+
+// trunc returns x rounded toward zero. |x| must be less than 2^62.
+func (x fpr) trunc() int64 {
+	m, k := x.fixed()
+	m &= -(uint64(k-64) >> 63)
+	q := m >> (uint(k) & 63)
+	s := x.sign()
+	return int64((q ^ -s) + s)
+}
+
+// lt returns 1 if x < y, and 0 otherwise.
+func (x fpr) lt(y fpr) uint64 {
+	// Map values to unsigned integers with the same order.
+	key := func(v fpr) uint64 {
+		s := v.sign()
+		return uint64(v) ^ (-s | 1<<63)
+	}
+	_, b := bits.Sub64(key(x), key(y), 0)
+	return b
+}
+
+// expmP63 returns 2^63·ccs·exp(-x), for x in [0, ln 2] and ccs in [0, 1).
+func expmP63(x, ccs fpr) uint64 {
+	z := uint64(x.mul(fprPTwo63).trunc())
+	// Horner evaluation of the Taylor series of exp(-z), whose
+	// coefficients are in 2^63 fixed point.
+	y := expmCoeffs[0]
+	for _, c := range expmCoeffs[1:] {
+		hi, lo := bits.Mul64(z, y)
+		y = c - (hi<<1 | lo>>63)
+	}
+	hi, lo := bits.Mul64(uint64(ccs.mul(fprPTwo63).trunc()), y)
+	return hi<<1 | lo>>63
+}
+
+// expmCoeffs are floor(2^63/k!) for k = 15 down to 0.
+var expmCoeffs = [16]uint64{
+	0x00000000006b9fcf, 0x00000000064e5d2a, 0x000000005849184e,
+	0x000000047bb63bfe, 0x00000035cc8acfea, 0x0000024fc9f6ef13,
+	0x0000171de3a556c7, 0x0000d00d00d00d00, 0x0006806806806806,
+	0x002d82d82d82d82d, 0x0111111111111111, 0x0555555555555555,
+	0x1555555555555555, 0x4000000000000000, 0x8000000000000000,
+	0x8000000000000000,
+}
@@ -0,0 +1,319 @@
+package internal
+
+// maxFGBits is the number of bits of the coefficients of f and g in the
+// encoding of a private key.
+func maxFGBits(logn uint) uint {
+	return [maxLogN + 1]uint{0, 8, 8, 8, 8, 8, 7, 7, 6, 6, 5}[logn]
+}
+
+// maxCapFGBits is the number of bits of the coefficients of F and G.
+const maxCapFGBits = 8
+
+// NewKeyFromSeed derives a key pair of degree 2^logn from seed.
+func NewKeyFromSeed(seed []byte, logn uint) (*PublicKey, *PrivateKey) {
+	rng := newPrng(seed)
+	n := 1 << logn
+	lim := int8(1<<(maxFGBits(logn)-1) - 1)
+	for {
+		f := rng.sampleFG(logn)
+		g := rng.sampleFG(logn)
+
+		// The coefficients of f and g must fit in the encoding of the
+		// private key.
+		var norm int
+		inRange := true
+		for i := 0; i < n; i++ {
+			if f[i] < -lim || f[i] > lim || g[i] < -lim || g[i] > lim {
+				inRange = false
+			}
+			norm += int(f[i])*int(f[i]) + int(g[i])*int(g[i])
+		}
+		if !inRange || norm >= 16823 {
+			continue
+		}
+
+		// The Gram-Schmidt norm of the basis must be small, that is,
+		// (q·adj(f)/(f·adj(f) + g·adj(g)), q·adj(g)/(f·adj(f) + g·adj(g)))
+		// must also have a norm at most 1.17·√q.
+		if !orthogonalizedNormOK(f, g, logn) {
+			continue
+		}
+
+		h, ok := computePublicKey(f, g, logn)
+		if !ok {
+			continue
+		}
+
+		F, G, ok := ntruSolve(f, g, logn)
+		if !ok {
+			continue
+		}
+
+		pk := &PublicKey{logn: logn, h: h}
+		sk := &PrivateKey{logn: logn, f: f, g: g, capF: F, capG: G, h: h}
+		return pk, sk
+	}
+}
+
+func orthogonalizedNormOK(f, g []int8, logn uint) bool {
+	n := len(f)
+	ft, gt := toFFT(f, logn), toFFT(g, logn)
+	d := polyAddMulSelfAdj(ft, gt)
+	// By Parseval, the squared norm of a polynomial is 2/n times the sum
+	// of its squared values in FFT form, and here the squared values are
+	// q²/(f·adj(f) + g·adj(g)).
+	sum := fprZero
+	for i := 0; i < n/2; i++ {
+		sum = sum.add(d[i].inv())
+	}
+	sum = sum.mul(fprQ.sqr()).mul(fprScaled(2, -int(logn)))
+	return sum.lt(fprBNormMax) == 1
+}
+
+func toFFT(f []int8, logn uint) []fpr {
+	t := make([]fpr, len(f))
+	for i, c := range f {
+		t[i] = fprOf(int64(c))
+	}
+	fft(t, logn)
+	return t
+}
+
+// fgBitLength gives the average and the standard deviation of the bit
+// length of the largest coefficient of f and g after d field norms,
+// measured on Falcon-512 and Falcon-1024 key pairs. The NTRU solver sizes
+// its integers from them, so that the sizes are public. Keys whose
+// intermediate values do not fit are rejected.
+var fgBitLength = [maxLogN + 1]struct{ avg, std int }{
+	{4, 1}, {11, 1}, {25, 1}, {51, 1}, {102, 2}, {202, 2},
+	{401, 4}, {795, 6}, {1578, 9}, {3143, 17}, {6277, 25},
+}
+
+// ntruBounds are the public bounds on the sizes of the integers at one
+// depth of the NTRU solver.
+type ntruBounds struct {
+	logn   uint // Degree of the polynomials.
+	fgMax  int  // Bits of the coefficients of f and g.
+	fgMin  int  // Lower bound on the bit length of f and g.
+	capMax int  // Bits of the coefficients of F and G once reduced.
+}
+
+func newNtruBounds(logn uint, depth int) ntruBounds {
+	b := fgBitLength[depth]
+	r := ntruBounds{
+		logn:  logn,
+		fgMax: b.avg + 6*b.std + 8,
+		fgMin: max(1, b.avg-6*b.std-8),
+	}
+	if logn == 0 {
+		// F and G are q times Bézout coefficients bounded by f and g.
+		r.capMax = r.fgMax + 15
+	} else {
+		r.capMax = r.fgMax + int(logn) + 8
+	}
+	return r
+}
+
+// limbs returns the number of limbs of integers of the given bits, along
+// with their sign.
+func limbs(bits int) int { return bits/64 + 1 }
+
+// ntruSolve returns F and G such that f·G - g·F = q modulo x^n + 1 with
+// small coefficients, and false if there are none or if they do not fit
+// in the encoding of the private key.
+//
+// The solver follows the one of the reference implementation: it
+// recursively takes field norms of f and g down to integers, solves the
+// equation there with the extended binary GCD, and lifts the solution
+// back, reducing it at each depth with Babai's round-off algorithm. All
+// the integers have sizes fixed by the degree and the depth, so that the
+// solver runs in constant time. Only the rejection of f and g depends on
+// their values.
+func ntruSolve(f, g []int8, logn uint) (capF, capG []int8, ok bool) {
+	n := len(f)
+	bounds := make([]ntruBounds, logn+1)
+	fs := make([]zpoly, logn+1)
+	gs := make([]zpoly, logn+1)
+	for d := range bounds {
+		bounds[d] = newNtruBounds(logn-uint(d), d)
+	}
+
+	l := limbs(bounds[0].fgMax)
+	fs[0], gs[0] = newZpoly(n, l), newZpoly(n, l)
+	for i := range f {
+		zSet(fs[0].coeff(i), []uint64{uint64(int64(f[i]))})
+		zSet(gs[0].coeff(i), []uint64{uint64(int64(g[i]))})
+	}
+	for d := 1; d <= int(logn); d++ {
+		l := limbs(bounds[d].fgMax)
+		fs[d] = fieldNorm(fs[d-1], l)
+		gs[d] = fieldNorm(gs[d-1], l)
+	}
+
+	Fd, Gd, ok := solveDeepest(fs[logn], gs[logn], bounds[logn])
+	if !ok {
+		return nil, nil, false
+	}
+	for d := int(logn) - 1; d >= 0; d-- {
+		b := bounds[d]
+		l := limbs(bounds[d+1].capMax + b.fgMax + int(b.logn) + 1)
+		Fd = lift(Fd, gs[d], l)
+		Gd = lift(Gd, fs[d], l)
+		Fd, Gd, ok = reduce(fs[d], gs[d], Fd, Gd, b, bounds[d+1].capMax)
+		if !ok {
+			return nil, nil, false
+		}
+	}
+
+	const lim = 1<<(maxCapFGBits-1) - 1
+	capF = make([]int8, n)
+	capG = make([]int8, n)
+	F := make([]int32, n)
+	G := make([]int32, n)
+	inRange := uint64(0)
+	for i := 0; i < n; i++ {
+		x := int64(Fd.coeff(i)[0])
+		y := int64(Gd.coeff(i)[0])
+		for _, z := range []int64{x, y} {
+			inRange |= uint64(z+lim) | uint64(lim-z)
+		}
+		F[i], G[i] = int32(x), int32(y)
+		capF[i], capG[i] = int8(x), int8(y)
+	}
+	if inRange>>63 != 0 || !checkNtru(f, g, F, G) {
+		return nil, nil, false
+	}
+	return capF, capG, true
+}
+
+// checkNtru returns whether f·G - g·F = q modulo x^n + 1.
+func checkNtru(f, g []int8, F, G []int32) bool {
+	n := len(f)
+	r := make([]int32, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			t := int32(f[i])*G[j] - int32(g[i])*F[j]
+			if i+j < n {
+				r[i+j] += t
+			} else {
+				r[i+j-n] -= t
+			}
+		}
+	}
+	r[0] -= Q
+	var acc int32
+	for _, x := range r {
+		acc |= x
+	}
+	return acc == 0
+}
+
+// fieldNorm returns N(f), with coefficients of l limbs, such that
+// N(f)(x²) = f(x)·f(-x).
+func fieldNorm(f zpoly, l int) zpoly {
+	n := f.n()
+	m := f.mag()
+	r := newZpoly(n/2, l)
+	polyMulAdd(r, n, m, 1, m.galoisConj(), 2)
+	return r
+}
+
+// lift returns F(x²)·g(-x), with coefficients of l limbs.
+func lift(F, g zpoly, l int) zpoly {
+	n := g.n()
+	r := newZpoly(n, l)
+	polyMulAdd(r, n, F.mag(), 2, g.mag().galoisConj(), 1)
+	return r
+}
+
+// solveDeepest solves f·G - g·F = q for integers f and g.
+func solveDeepest(f, g zpoly, b ntruBounds) (capF, capG zpoly, ok bool) {
+	l := limbs(b.capMax)
+	x := f.truncate(l).mag()
+	y := g.truncate(l).mag()
+	u := newZpoly(1, l)
+	v := newZpoly(1, l)
+	ok = zBezout(u.c, v.c, x.c, y.c, b.fgMax)
+
+	// With |f|·u - |g|·v = 1, G = ±q·u and F = ±q·v.
+	q := []uint64{Q}
+	capF, capG = newZpoly(1, l), newZpoly(1, l)
+	zMul(capF.c, v.c, q)
+	zMul(capG.c, u.c, q)
+	zCondNeg(capF.c, y.sign[0])
+	zCondNeg(capG.c, x.sign[0])
+	return capF, capG, ok
+}
+
+// Parameters of the reduction: approximations of the integers are scaled
+// to have about approxBits bits, and each round reduces the scale of k by
+// reduceStep bits.
+const (
+	approxBits = 256
+	reduceStep = 25
+)
+
+// reduce reduces F and G with Babai's round-off algorithm: (F, G) is
+// reduced by k·(f, g), with k = ⌊(F·adj(f) + G·adj(g))/(f·adj(f) + g·adj(g))⌉,
+// computed on floating-point approximations. F and G are lifted from
+// coefficients of capBits bits and have far more bits than the precision
+// of the approximations, so k is computed in rounds, each one multiplied
+// by a decreasing power of two. The sizes and the number of rounds are
+// public, and reduce returns false if k does not fit in the precision.
+func reduce(f, g, F, G zpoly, b ntruBounds, capBits int) (capF, capG zpoly, ok bool) {
+	n := f.n()
+	logn := b.logn
+	capBits += b.fgMax + int(logn) + 1
+
+	toFFT := func(p zpoly, sc int) []fpr {
+		t := make([]fpr, n)
+		for i := range t {
+			t[i] = zToFpr(p.coeff(i), sc)
+		}
+		fft(t, logn)
+		return t
+	}
+	sf := b.fgMax - approxBits
+	ft, gt := toFFT(f, sf), toFFT(g, sf)
+	den := polyAddMulSelfAdj(ft, gt)
+	for i := 0; i < n/2; i++ {
+		den[i] = den[i].inv()
+	}
+	fm, gm := f.mag(), g.mag()
+
+	lim := fprScaled(1, 52)
+	var fail uint64
+	k := make([]int64, n)
+	sk := max(0, capBits-b.fgMin)
+	for last := 0; last < 2; {
+		sF := capBits - approxBits
+		Ft, Gt := toFFT(F, sF), toFFT(G, sF)
+		polyMulAdj(Ft, ft)
+		polyMulAdj(Gt, gt)
+		polyAdd(Ft, Gt)
+		polyMulAutoAdj(Ft, den)
+		ifft(Ft, logn)
+
+		sc := fprScaled(1, sF-sf-sk)
+		for i := range k {
+			x := Ft[i].mul(sc)
+			fail |= lim.neg().lt(x) ^ 1
+			fail |= x.lt(lim) ^ 1
+			k[i] = x.rint()
+		}
+		polySubScaledMul(F, k, fm, sk)
+		polySubScaledMul(G, k, gm, sk)
+
+		if c := sk + b.fgMax + int(logn) + 8; c < capBits {
+			capBits = c
+			if l := limbs(capBits); l < F.l {
+				F, G = F.truncate(l), G.truncate(l)
+			}
+		}
+		if sk == 0 {
+			last++
+		}
+		sk = max(0, sk-reduceStep)
+	}
+	return F, G, fail == 0
+}
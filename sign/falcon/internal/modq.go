@@ -0,0 +1,146 @@
+package internal
+
+// Q is the modulus of the public key.
+const Q = 12289
+
+// psi is a primitive 2048th root of unity modulo Q.
+const psi = 1945
+
+// zetas[logn][k] is the brv(k)th power of a primitive 2^(logn+1)th root of
+// unity, where brv reverses logn bits.
+var zetas [maxLogN + 1][]uint32
+
+func init() {
+	for logn := uint(1); logn <= maxLogN; logn++ {
+		n := 1 << logn
+		z := uint32(psi)
+		for i := logn; i < maxLogN; i++ {
+			z = z * z % Q
+		}
+		pows := make([]uint32, n)
+		pows[0] = 1
+		for i := 1; i < n; i++ {
+			pows[i] = pows[i-1] * z % Q
+		}
+		zetas[logn] = make([]uint32, n)
+		for k := 0; k < n; k++ {
+			r := 0
+			for i := uint(0); i < logn; i++ {
+				r |= (k >> i & 1) << (logn - 1 - i)
+			}
+			zetas[logn][k] = pows[r]
+		}
+	}
+}
+
+// ntt computes the NTT of a, with coefficients in [0, Q), in place. The
+// result is in bit-reversed order.
+func ntt(a []uint32, logn uint) {
+	n := len(a)
+	z := zetas[logn]
+	k := 0
+	for l := n >> 1; l >= 1; l >>= 1 {
+		for start := 0; start < n; start += 2 * l {
+			k++
+			zeta := z[k]
+			for j := start; j < start+l; j++ {
+				t := zeta * a[j+l] % Q
+				a[j+l] = (a[j] + Q - t) % Q
+				a[j] = (a[j] + t) % Q
+			}
+		}
+	}
+}
+
+// invNtt is the inverse of ntt.
+func invNtt(a []uint32, logn uint) {
+	n := len(a)
+	z := zetas[logn]
+	k := n
+	for l := 1; l < n; l <<= 1 {
+		for start := 0; start < n; start += 2 * l {
+			k--
+			zeta := Q - z[k]
+			for j := start; j < start+l; j++ {
+				t := a[j]
+				a[j] = (t + a[j+l]) % Q
+				a[j+l] = zeta * ((t + Q - a[j+l]) % Q) % Q
+			}
+		}
+	}
+	ninv := modExp(uint32(n), Q-2)
+	for i := range a {
+		a[i] = a[i] * ninv % Q
+	}
+}
+
+func modExp(x, e uint32) uint32 {
+	r := uint32(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = r * x % Q
+		}
+		x = x * x % Q
+	}
+	return r
+}
+
+// modQ returns x mod Q in [0, Q).
+func modQ(x int32) uint32 {
+	return uint32(x%Q+Q) % Q
+}
+
+// computePublicKey returns h = g/f mod Q, and false if f is not invertible
+// modulo Q.
+func computePublicKey(f, g []int8, logn uint) ([]uint16, bool) {
+	n := len(f)
+	ft := make([]uint32, n)
+	gt := make([]uint32, n)
+	for i := range f {
+		ft[i] = modQ(int32(f[i]))
+		gt[i] = modQ(int32(g[i]))
+	}
+	ntt(ft, logn)
+	ntt(gt, logn)
+	ok := true
+	for i := range ft {
+		if ft[i] == 0 {
+			ok = false
+			ft[i] = 1
+		}
+		gt[i] = gt[i] * modExp(ft[i], Q-2) % Q
+	}
+	invNtt(gt, logn)
+	h := make([]uint16, n)
+	for i := range h {
+		h[i] = uint16(gt[i])
+	}
+	return h, ok
+}
+
+// completePrivateKey returns G = g·F/f mod Q with coefficients in
+// [-Q/2, Q/2], where f must be invertible modulo Q.
+func completePrivateKey(f, g, capF []int8, logn uint) []int32 {
+	n := len(f)
+	ft := make([]uint32, n)
+	gt := make([]uint32, n)
+	Ft := make([]uint32, n)
+	for i := range f {
+		ft[i] = modQ(int32(f[i]))
+		gt[i] = modQ(int32(g[i]))
+		Ft[i] = modQ(int32(capF[i]))
+	}
+	ntt(ft, logn)
+	ntt(gt, logn)
+	ntt(Ft, logn)
+	for i := range gt {
+		gt[i] = gt[i] * Ft[i] % Q * modExp(ft[i], Q-2) % Q
+	}
+	invNtt(gt, logn)
+	G := make([]int32, n)
+	for i := range G {
+		x := int32(gt[i])
+		G[i] = x - Q&((Q/2-x)>>31)
+	}
+	return G
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// prng is a source of random bytes, expanded from a seed with SHAKE256.
+type prng struct {
+	h   sha3.State
+	buf [8 * 136]byte
+	off int
+}
+
+func newPrng(seed ...[]byte) *prng {
+	p := &prng{h: sha3.NewShake256()}
+	for _, s := range seed {
+		_, _ = p.h.Write(s)
+	}
+	p.off = len(p.buf)
+	return p
+}
+
+func (p *prng) refill() {
+	_, _ = p.h.Read(p.buf[:])
+	p.off = 0
+}
+
+func (p *prng) byte() uint8 {
+	if p.off == len(p.buf) {
+		p.refill()
+	}
+	b := p.buf[p.off]
+	p.off++
+	return b
+}
+
+func (p *prng) uint64() uint64 {
+	if p.off+8 > len(p.buf) {
+		p.refill()
+	}
+	x := binary.LittleEndian.Uint64(p.buf[p.off:])
+	p.off += 8
+	return x
+}
+
+// rcdt is the reverse cumulative distribution table of the half Gaussian
+// of standard deviation σ₀ = 1.8205, with 72 bits of precision: rcdt[i] is
+// 2^72 times the probability of a sample greater than i.
+var rcdt = [18][2]uint64{ // high 8 bits, low 64 bits
+	{0xa3, 0xf7f42ed3ac39180a},
+	{0x54, 0xd32b181f3f7ddb8a},
+	{0x22, 0x7dcdd0934829c206},
+	{0x0a, 0xd1754377c7994aea},
+	{0x02, 0x95846caef33f1f75},
+	{0x00, 0x774ac754ed74bd64},
+	{0x00, 0x1024dd542b776ae9},
+	{0x00, 0x01a1ffdc65ad63df},
+	{0x00, 0x001f80d88a7b642c},
+	{0x00, 0x0001c3fdb2040c6d},
+	{0x00, 0x000012cf24d031fe},
+	{0x00, 0x000000949f8b0922},
+	{0x00, 0x00000003665da99a},
+	{0x00, 0x000000000ebf6ebc},
+	{0x00, 0x00000000002f5d7f},
+	{0x00, 0x0000000000007099},
+	{0x00, 0x00000000000000c6},
+	{0x00, 0x0000000000000001},
+}
+
+// baseSampler returns a sample of the half Gaussian of standard deviation
+// σ₀, in constant time.
+func (p *prng) baseSampler() int {
+	lo := p.uint64()
+	hi := uint64(p.byte())
+	z := 0
+	for _, r := range rcdt {
+		// Add one if (hi, lo) < r.
+		_, b := bits.Sub64(lo, r[1], 0)
+		_, b = bits.Sub64(hi, r[0], b)
+		z += int(b)
+	}
+	return z
+}
+
+// berExp returns true with probability ccs·exp(-x), for x ≥ 0, in
+// constant time.
+func (p *prng) berExp(x, ccs fpr) bool {
+	// Rounding errors could make x slightly negative.
+	x &= fpr(x.sign() - 1)
+
+	// x = s·ln 2 + r, with s an integer and r in [0, ln 2).
+	s := x.mul(fprInvLog2).trunc()
+	r := x.sub(fprOf(s).mul(fprLog2))
+	r &= fpr(r.sign() - 1)
+	// exp(-x) = 2^-s·exp(-r); s is capped to 63, which introduces a
+	// negligible bias.
+	sw := uint64(s)
+	sw ^= (sw ^ 63) & -((63 - sw) >> 63)
+	z := ((expmP63(r, ccs) << 1) - 1) >> sw
+
+	// Lazily compare z to a random 64 bits value, byte by byte from the
+	// top. The timing only depends on the random bytes.
+	var w int
+	for i := 64; i > 0; {
+		i -= 8
+		w = int(p.byte()) - int(z>>uint(i)&0xFF)
+		if w != 0 {
+			break
+		}
+	}
+	return w < 0
+}
+
+// samplerZ returns a sample of the discrete Gaussian of center mu and
+// standard deviation 1/isigma, with sigmaMin the minimal standard
+// deviation of the parameter set.
+func (p *prng) samplerZ(mu, isigma, sigmaMin fpr) int64 {
+	s := mu.floor()
+	r := mu.sub(fprOf(s))
+	dss := isigma.sqr().half()
+	ccs := isigma.mul(sigmaMin)
+	for {
+		// Sample z from a bimodal Gaussian of centers 0 and 1, and
+		// accept it with probability depending on its distance to r.
+		z0 := p.baseSampler()
+		b := int(p.byte() & 1)
+		z := b + ((b<<1)-1)*z0
+
+		x := fprOf(int64(z)).sub(r).sqr().mul(dss)
+		x = x.sub(fprOf(int64(z0 * z0)).mul(fprInvSigma0))
+		if p.berExp(x, ccs) {
+			return s + int64(z)
+		}
+	}
+}
+
+// gaussTable is the reverse cumulative distribution table of the absolute
+// value of the discrete Gaussian of standard deviation 1.17·√(Q/2048),
+// with 63 bits of precision.
+var gaussTable = [26]uint64{
+	0x6e2ec827d20d54a8, 0x4ca71379d0d44e74, 0x30b8137bd96bccf6,
+	0x1c1d82b0c61fb6dc, 0x0ea8f1a2e8a80d59, 0x06e14e41d01bf047,
+	0x02e5be00e3fc0fb8, 0x0117a1a6bd470418, 0x005e30bf367966c1,
+	0x001c4dea0bd5921b, 0x000794225cd14a1f, 0x0001ce69617df12b,
+	0x00006205f2debafa, 0x00001278063f6c50, 0x00000317547af83c,
+	0x00000075990f1ddb, 0x0000000f82c483bf, 0x00000001d0af3998,
+	0x00000000303c51dc, 0x000000000470aea1, 0x00000000005cc4d1,
+	0x000000000006b625, 0x0000000000006e2c, 0x0000000000000643,
+	0x0000000000000051, 0x0000000000000004,
+}
+
+// sampleFG returns a polynomial of degree 2^logn with coefficients from
+// the discrete Gaussian of standard deviation 1.17·√(Q/2^(logn+1)), as
+// sums of samples of the one for degree 1024.
+func (p *prng) sampleFG(logn uint) []int8 {
+	f := make([]int8, 1<<logn)
+	k := 1 << (maxLogN - logn)
+	var mod2 int
+	for i := 0; i < len(f); {
+		var v int64
+		for j := 0; j < k; j++ {
+			u := p.uint64()
+			s := u >> 63
+			u &= 1<<63 - 1
+			var z uint64
+			for _, t := range gaussTable {
+				z += (u - t) >> 63
+			}
+			v += int64((z ^ -s) + s)
+		}
+		// Coefficients are in [-127, 127] with overwhelming
+		// probability; others are rejected later.
+		v = max(-128, min(127, v))
+
+		// The resultant of f and x^n + 1 is odd if the sum of the
+		// coefficients of f is, which the NTRU solver requires, so the
+		// last coefficient is drawn again until it is.
+		if i == len(f)-1 && (mod2^int(v))&1 == 0 {
+			continue
+		}
+		mod2 ^= int(v)
+		f[i] = int8(v)
+		i++
+	}
+	return f
+}
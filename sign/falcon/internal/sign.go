@@ -0,0 +1,342 @@
+package internal
+
+import (
+	"io"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// sigBound returns ⌊β²⌋, the bound on the squared norm of signatures.
+func sigBound(logn uint) int64 {
+	switch logn {
+	case 9:
+		return 34034726
+	case 10:
+		return 70265242
+	}
+	panic("falcon: unsupported degree")
+}
+
+// sigmas returns σ and σ_min for the degree 2^logn.
+func sigmas(logn uint) (sigma, sigmaMin fpr) {
+	switch logn {
+	case 9:
+		return fprSigma9, fprSigmaMin9
+	case 10:
+		return fprSigma10, fprSigmaMin10
+	}
+	panic("falcon: unsupported degree")
+}
+
+// hashToPoint hashes the nonce and the message to a polynomial of degree
+// 2^logn with coefficients modulo q.
+func hashToPoint(nonce, msg []byte, logn uint) []uint16 {
+	h := sha3.NewShake256()
+	_, _ = h.Write(nonce)
+	_, _ = h.Write(msg)
+	c := make([]uint16, 1<<logn)
+	var buf [2]byte
+	for i := 0; i < len(c); {
+		_, _ = h.Read(buf[:])
+		// Rejection sampling of values below 5q.
+		t := uint32(buf[0])<<8 | uint32(buf[1])
+		if t < 5*Q {
+			c[i] = uint16(t % Q)
+			i++
+		}
+	}
+	return c
+}
+
+// ffLDL computes the LDL* tree of the Gram matrix [[g00, g01], [adj(g01),
+// g11]] of degree 2^logn, in FFT form, into tree. Leaves are normalized to
+// √leaf/σ, the inverse of the standard deviation to sample with. The
+// inputs are clobbered.
+//
+// The tree of degree 2^logn is L10, followed by the trees of the two
+// diagonal elements of D split in degree 2^(logn-1); a tree of degree 1
+// is a leaf. Its size is (logn + 1)·2^logn.
+func ffLDL(tree, g00, g01, g11 []fpr, logn uint, sigma fpr) {
+	n := len(g00)
+	hn := n >> 1
+
+	// L10 = adj(g01)/g00 and D11 = g11 - L10·g01.
+	l10 := tree[:n]
+	copy(l10, g01)
+	polyAdj(l10)
+	polyDivAutoAdj(l10, g00)
+	t := make([]fpr, n)
+	copy(t, l10)
+	polyMul(t, g01)
+	polySub(g11, t)
+	for i := hn; i < n; i++ {
+		g11[i] = fprZero
+	}
+
+	if logn == 1 {
+		tree[n] = g00[0].sqrt().div(sigma)
+		tree[n+1] = g11[0].sqrt().div(sigma)
+		return
+	}
+
+	sub := int(logn) << (logn - 1)
+	for k, d := range [][]fpr{g00, g11} {
+		d0, d1 := make([]fpr, hn), make([]fpr, hn)
+		polySplit(d0, d1, d, logn)
+		e := make([]fpr, hn)
+		copy(e, d0)
+		ffLDL(tree[n+k*sub:n+(k+1)*sub], d0, d1, e, logn-1, sigma)
+	}
+}
+
+// ffSampling samples z close to (t0, t1), in FFT form of degree 2^logn,
+// with the tree of the Gram matrix of the basis, and stores the result in
+// t0 and t1.
+func ffSampling(rng *prng, t0, t1, tree []fpr, logn uint, sigmaMin fpr) {
+	if logn == 0 {
+		t0[0] = fprOf(rng.samplerZ(t0[0], tree[0], sigmaMin))
+		t1[0] = fprOf(rng.samplerZ(t1[0], tree[0], sigmaMin))
+		return
+	}
+
+	n := len(t0)
+	hn := n >> 1
+	l10 := tree[:n]
+	sub := int(logn) << (logn - 1)
+	tree0, tree1 := tree[n:n+sub], tree[n+sub:n+2*sub]
+
+	// z1 is sampled close to t1.
+	a, b := make([]fpr, hn), make([]fpr, hn)
+	polySplit(a, b, t1, logn)
+	ffSampling(rng, a, b, tree1, logn-1, sigmaMin)
+	z1 := make([]fpr, n)
+	polyMerge(z1, a, b, logn)
+
+	// z0 is sampled close to t0 + (t1 - z1)·L10.
+	polySub(t1, z1)
+	polyMul(t1, l10)
+	polyAdd(t0, t1)
+	polySplit(a, b, t0, logn)
+	ffSampling(rng, a, b, tree0, logn-1, sigmaMin)
+	polyMerge(t0, a, b, logn)
+	copy(t1, z1)
+}
+
+// SignTo signs msg with sk, using entropy from rand, and writes the padded
+// signature into sig, which must be of length SignatureSize(sk.logn).
+func SignTo(sk *PrivateKey, msg []byte, rand io.Reader, sig []byte) error {
+	logn := sk.logn
+	n := 1 << logn
+	var nonce [NonceSize]byte
+	var seed [SeedSize]byte
+	if _, err := io.ReadFull(rand, nonce[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(rand, seed[:]); err != nil {
+		return err
+	}
+	rng := newPrng(seed[:])
+	c := hashToPoint(nonce[:], msg, logn)
+	sigma, sigmaMin := sigmas(logn)
+
+	// The basis B = [[g, -f], [G, -F]] and its Gram matrix B·B*.
+	b00, b01 := toFFT(sk.g, logn), toFFT(sk.f, logn)
+	b10, b11 := toFFT(sk.capG, logn), toFFT(sk.capF, logn)
+	polyNeg(b01)
+	polyNeg(b11)
+	g00 := polyAddMulSelfAdj(b00, b01)
+	g11 := polyAddMulSelfAdj(b10, b11)
+	g01 := make([]fpr, n)
+	t := make([]fpr, n)
+	copy(g01, b00)
+	polyMulAdj(g01, b10)
+	copy(t, b01)
+	polyMulAdj(t, b11)
+	polyAdd(g01, t)
+	tree := make([]fpr, (int(logn)+1)<<logn)
+	ffLDL(tree, g00, g01, g11, logn, sigma)
+
+	ct := make([]fpr, n)
+	for i := range c {
+		ct[i] = fprOf(int64(c[i]))
+	}
+	fft(ct, logn)
+
+	s1 := make([]int16, n)
+	s2 := make([]int16, n)
+	t0, t1 := make([]fpr, n), make([]fpr, n)
+	body := sig[1+NonceSize:]
+	for {
+		// (t0, t1) = (c, 0)·B⁻¹ = (-c·F/q, c·f/q).
+		copy(t0, ct)
+		polyMul(t0, b11)
+		polyMulConst(t0, fprInvQ)
+		copy(t1, ct)
+		polyMul(t1, b01)
+		polyMulConst(t1, fprInvQ.neg())
+
+		ffSampling(rng, t0, t1, tree, logn, sigmaMin)
+
+		// s = (c, 0) - z·B.
+		v0, v1 := make([]fpr, n), make([]fpr, n)
+		copy(v0, t0)
+		polyMul(v0, b00)
+		copy(t, t1)
+		polyMul(t, b10)
+		polyAdd(v0, t)
+		copy(v1, t0)
+		polyMul(v1, b01)
+		copy(t, t1)
+		polyMul(t, b11)
+		polyAdd(v1, t)
+		ifft(v0, logn)
+		ifft(v1, logn)
+
+		var norm int64
+		for i := 0; i < n; i++ {
+			s1[i] = int16(int64(c[i]) - v0[i].rint())
+			s2[i] = int16(-v1[i].rint())
+			norm += int64(s1[i])*int64(s1[i]) + int64(s2[i])*int64(s2[i])
+		}
+		if norm > sigBound(logn) {
+			continue
+		}
+		if compress(body, s2) {
+			break
+		}
+	}
+
+	sig[0] = 0x30 + byte(logn)
+	copy(sig[1:], nonce[:])
+	return nil
+}
+
+// Verify checks whether sig is a valid padded signature of msg by pk.
+func Verify(pk *PublicKey, msg, sig []byte) bool {
+	logn := pk.logn
+	n := 1 << logn
+	if len(sig) != SignatureSize(logn) || sig[0] != 0x30+byte(logn) {
+		return false
+	}
+	s2 := make([]int16, n)
+	if !decompress(s2, sig[1+NonceSize:]) {
+		return false
+	}
+	c := hashToPoint(sig[1:1+NonceSize], msg, logn)
+
+	// s1 = c - s2·h mod q.
+	a := make([]uint32, n)
+	h := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		a[i] = modQ(int32(s2[i]))
+		h[i] = uint32(pk.h[i])
+	}
+	ntt(a, logn)
+	ntt(h, logn)
+	for i := range a {
+		a[i] = a[i] * h[i] % Q
+	}
+	invNtt(a, logn)
+
+	var norm int64
+	for i := 0; i < n; i++ {
+		s1 := (int64(c[i]) - int64(a[i]) + Q) % Q
+		if s1 > Q/2 {
+			s1 -= Q
+		}
+		norm += s1*s1 + int64(s2[i])*int64(s2[i])
+	}
+	return norm <= sigBound(logn)
+}
+
+// compress encodes s into buf, padding it with zeros, and returns false
+// if it does not fit.
+func compress(buf []byte, s []int16) bool {
+	var acc uint32
+	accLen := uint(0)
+	j := 0
+	for _, x := range s {
+		if x < -2047 || x > 2047 {
+			return false
+		}
+		// Sign, then the 7 lowest bits of |x|, then the other bits of
+		// |x| in unary.
+		w := uint32(x)
+		sign := w >> 31
+		w = (w ^ -sign) + sign
+		acc = acc<<8 | sign<<7 | w&0x7F
+		acc <<= w>>7 + 1
+		acc |= 1
+		accLen += 8 + uint(w>>7) + 1
+		for accLen >= 8 {
+			if j == len(buf) {
+				return false
+			}
+			accLen -= 8
+			buf[j] = byte(acc >> accLen)
+			j++
+		}
+	}
+	if accLen > 0 {
+		if j == len(buf) {
+			return false
+		}
+		buf[j] = byte(acc << (8 - accLen))
+		j++
+	}
+	clear(buf[j:])
+	return true
+}
+
+// decompress is the inverse of compress. It rejects non-canonical
+// encodings, including non-zero padding.
+func decompress(s []int16, buf []byte) bool {
+	var acc uint32
+	accLen := uint(0)
+	j := 0
+	for i := range s {
+		if j == len(buf) {
+			return false
+		}
+		acc = acc<<8 | uint32(buf[j])
+		j++
+		b := acc >> accLen
+		sign := b & 0x80
+		m := b & 0x7F
+		for {
+			if accLen == 0 {
+				if j == len(buf) {
+					return false
+				}
+				acc = acc<<8 | uint32(buf[j])
+				j++
+				accLen = 8
+			}
+			accLen--
+			if (acc>>accLen)&1 != 0 {
+				break
+			}
+			m += 128
+			if m > 2047 {
+				return false
+			}
+		}
+		// "-0" is not canonical.
+		if sign != 0 && m == 0 {
+			return false
+		}
+		s[i] = int16(m)
+		if sign != 0 {
+			s[i] = -s[i]
+		}
+	}
+	if acc&(1<<accLen-1) != 0 {
+		return false
+	}
+	for _, b := range buf[j:] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,315 @@
+package internal
+
+import "math/bits"
+
+// Arithmetic on the large integers of the NTRU solver.
+//
+// A zint is a signed integer stored in a fixed number of 64-bit limbs, in
+// two's complement and little-endian order. The number of limbs only
+// depends on public values, the degree and the depth in the recursion of
+// the solver, and the operations take a time that only depends on the
+// number of limbs, not on the values. Results that do not fit are reduced
+// modulo 2^(64·limbs), which the solver detects with its final check.
+
+// zpoly is a polynomial of zints, each of l limbs.
+type zpoly struct {
+	c []uint64
+	l int
+}
+
+func newZpoly(n, l int) zpoly { return zpoly{make([]uint64, n*l), l} }
+
+func (p zpoly) n() int               { return len(p.c) / p.l }
+func (p zpoly) coeff(i int) []uint64 { return p.c[i*p.l : (i+1)*p.l] }
+
+// truncate returns the polynomial with coefficients of l limbs, which
+// must be enough to hold them.
+func (p zpoly) truncate(l int) zpoly {
+	r := newZpoly(p.n(), l)
+	for i := 0; i < p.n(); i++ {
+		zSet(r.coeff(i), p.coeff(i))
+	}
+	return r
+}
+
+// zmag is a polynomial of zints in sign and magnitude form: the sign of
+// each coefficient is a mask, all ones if it is negative.
+type zmag struct {
+	zpoly
+	sign []uint64
+}
+
+func (p zpoly) mag() zmag {
+	m := zmag{newZpoly(p.n(), p.l), make([]uint64, p.n())}
+	copy(m.c, p.c)
+	for i := range m.sign {
+		a := m.coeff(i)
+		m.sign[i] = zSign(a)
+		zCondNeg(a, m.sign[i])
+	}
+	return m
+}
+
+// galoisConj returns p(-x).
+func (p zmag) galoisConj() zmag {
+	r := zmag{p.zpoly, make([]uint64, len(p.sign))}
+	for i, s := range p.sign {
+		r.sign[i] = s ^ -uint64(i&1)
+	}
+	return r
+}
+
+// zSign returns a mask of all ones if a is negative.
+func zSign(a []uint64) uint64 { return -(a[len(a)-1] >> 63) }
+
+// zSet sets a to b, sign extended or truncated to the length of a.
+func zSet(a, b []uint64) {
+	s := zSign(b)
+	for i := range a {
+		if i < len(b) {
+			a[i] = b[i]
+		} else {
+			a[i] = s
+		}
+	}
+}
+
+// zIsZero returns a mask of all ones if a is zero.
+func zIsZero(a []uint64) uint64 {
+	var t uint64
+	for _, x := range a {
+		t |= x
+	}
+	return ((t | -t) >> 63) - 1
+}
+
+// zCondNeg negates a if m is all ones, and leaves it unchanged if m is
+// zero.
+func zCondNeg(a []uint64, m uint64) {
+	c := m & 1
+	for i := range a {
+		a[i], c = bits.Add64(a[i]^m, 0, c)
+	}
+}
+
+// zCondAdd adds b to a if m is all ones.
+func zCondAdd(a, b []uint64, m uint64) {
+	var c uint64
+	for i := range a {
+		a[i], c = bits.Add64(a[i], b[i]&m, c)
+	}
+}
+
+// zCondSub subtracts b from a if m is all ones.
+func zCondSub(a, b []uint64, m uint64) {
+	var c uint64
+	for i := range a {
+		a[i], c = bits.Sub64(a[i], b[i]&m, c)
+	}
+}
+
+// zCondShr1 shifts the nonnegative a right by one bit if m is all ones.
+func zCondShr1(a []uint64, m uint64) {
+	for i := range a {
+		var next uint64
+		if i+1 < len(a) {
+			next = a[i+1]
+		}
+		a[i] ^= (a[i] ^ (a[i]>>1 | next<<63)) & m
+	}
+}
+
+// zMul sets t to a·b, for nonnegative a and b, modulo 2^(64·len(t)).
+func zMul(t, a, b []uint64) {
+	clear(t)
+	for i, x := range a {
+		if i >= len(t) {
+			break
+		}
+		var c uint64
+		j := 0
+		for ; j < len(b) && i+j < len(t); j++ {
+			hi, lo := bits.Mul64(x, b[j])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[i+j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			t[i+j], c = lo, hi+cc
+		}
+		if i+j < len(t) {
+			t[i+j] = c
+		}
+	}
+}
+
+// zAddSigned adds to a the nonnegative t, negated if m is all ones.
+func zAddSigned(a, t []uint64, m uint64) {
+	c := m & 1
+	for i := range a {
+		var x uint64
+		if i < len(t) {
+			x = t[i]
+		}
+		a[i], c = bits.Add64(a[i], x^m, c)
+	}
+}
+
+// zSubShifted subtracts t·2^sh from a.
+func zSubShifted(a, t []uint64, sh int) {
+	s := zSign(t)
+	limb := func(i int) uint64 {
+		switch {
+		case i < 0:
+			return 0
+		case i >= len(t):
+			return s
+		}
+		return t[i]
+	}
+	ws, bs := sh/64, uint(sh%64)
+	var c uint64
+	for i := range a {
+		x := limb(i - ws)
+		if bs != 0 {
+			x = x<<bs | limb(i-ws-1)>>(64-bs)
+		}
+		a[i], c = bits.Sub64(a[i], x, c)
+	}
+}
+
+// zToFpr returns an approximation of a·2^-sc. Limbs worth less than
+// 2^(sc-128) are ignored.
+func zToFpr(a []uint64, sc int) fpr {
+	t := append([]uint64(nil), a...)
+	s := zSign(t)
+	zCondNeg(t, s)
+	x := fprZero
+	for i := len(t) - 1; i >= 0 && 64*i+64 >= sc-128; i-- {
+		x = x.add(normalize(0, 64*i-sc, t[i]))
+	}
+	return x ^ fpr(s<<63)
+}
+
+// polyMulAdd adds to d the product of a and b modulo x^n + 1, where the
+// coefficient i of a is at the position astep·i, and keeps the positions
+// of the product that are multiples of dstep, the position dstep·k being
+// added to the coefficient k of d.
+func polyMulAdd(d zpoly, n int, a zmag, astep int, b zmag, dstep int) {
+	t := make([]uint64, min(a.l+b.l, d.l))
+	for i := 0; i < a.n(); i++ {
+		ai := a.coeff(i)
+		for j := 0; j < b.n(); j++ {
+			p := astep*i + j
+			if p%dstep != 0 {
+				continue
+			}
+			var neg uint64
+			if p >= n {
+				p -= n
+				neg = ^uint64(0)
+			}
+			zMul(t, ai, b.coeff(j))
+			zAddSigned(d.coeff(p/dstep), t, a.sign[i]^b.sign[j]^neg)
+		}
+	}
+}
+
+// polySubScaledMul subtracts (k·f)·2^sh from p modulo x^n + 1, where the
+// coefficients of k are less than 2^62 in absolute value.
+func polySubScaledMul(p zpoly, k []int64, f zmag, sh int) {
+	n := len(k)
+	km := make([]uint64, n)
+	ks := make([]uint64, n)
+	for i, x := range k {
+		ks[i] = uint64(x >> 63)
+		km[i] = (uint64(x) ^ ks[i]) - ks[i]
+	}
+
+	// The coefficients of k·f have less than 62 + 10 bits more than the
+	// ones of f.
+	kf := newZpoly(n, f.l+2)
+	t := make([]uint64, f.l+1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			q := i + j
+			var neg uint64
+			if q >= n {
+				q -= n
+				neg = ^uint64(0)
+			}
+			zMul(t, km[i:i+1], f.coeff(j))
+			zAddSigned(kf.coeff(q), t, ks[i]^f.sign[j]^neg)
+		}
+	}
+	for i := 0; i < n; i++ {
+		zSubShifted(p.coeff(i), kf.coeff(i), sh)
+	}
+}
+
+// zBezout sets u and v to integers such that x·u - y·v = 1, where x and
+// y are positive, and returns false if x or y is even or if they are not
+// coprime. All the integers have the same number of limbs, and x and y
+// must be less than 2^bound.
+func zBezout(u, v, x, y []uint64, bound int) bool {
+	l := len(x)
+	a := append([]uint64(nil), x...)
+	b := append([]uint64(nil), y...)
+	// a = x·u0 - y·v0 and b = x·u1 - y·v1, with 0 < u0, u1 ≤ y and
+	// 0 ≤ v0, v1 ≤ x.
+	u0, v0 := make([]uint64, l), make([]uint64, l)
+	u1, v1 := append([]uint64(nil), y...), append([]uint64(nil), x...)
+	u0[0] = 1
+	zCondSub(v1, u0, ^uint64(0))
+
+	// Each iteration makes a or b odd by subtracting the smallest from
+	// the largest if both are odd, and halves the even one, until b is
+	// zero and a is their gcd. The total length of a and b decreases by
+	// at least one bit at each iteration.
+	t := make([]uint64, l)
+	for iter := 0; iter < 2*bound; iter++ {
+		both := -(a[0] & b[0] & 1)
+		copy(t, b)
+		zCondSub(t, a, ^uint64(0))
+		gt := both & zSign(t)
+		lt := both &^ gt
+		zCondSub(a, b, gt)
+		zCondSub(b, a, lt)
+		zBezoutSub(u0, v0, u1, v1, x, y, gt)
+		zBezoutSub(u1, v1, u0, v0, x, y, lt)
+
+		ea := -(^a[0] & 1)
+		eb := -(^b[0] & 1) &^ ea
+		zBezoutHalf(a, u0, v0, x, y, ea)
+		zBezoutHalf(b, u1, v1, x, y, eb)
+	}
+
+	copy(u, u0)
+	copy(v, v0)
+	one := make([]uint64, l)
+	one[0] = 1
+	zCondSub(a, one, ^uint64(0))
+	ok := zIsZero(a) & zIsZero(b) & -(x[0] & y[0] & 1)
+	return ok == ^uint64(0)
+}
+
+// zBezoutSub subtracts (u1, v1) from (u0, v0) if m is all ones, and adds
+// (y, x) to the result if u0 is not positive anymore.
+func zBezoutSub(u0, v0, u1, v1, x, y []uint64, m uint64) {
+	zCondSub(u0, u1, m)
+	zCondSub(v0, v1, m)
+	fix := m & (zSign(u0) | zIsZero(u0))
+	zCondAdd(u0, y, fix)
+	zCondAdd(v0, x, fix)
+}
+
+// zBezoutHalf halves a if m is all ones, along with (u, v) such that
+// a = x·u - y·v.
+func zBezoutHalf(a, u, v, x, y []uint64, m uint64) {
+	zCondShr1(a, m)
+	// u and v have the same parity, as x and y are odd.
+	odd := m & -(u[0] & 1)
+	zCondAdd(u, y, odd)
+	zCondAdd(v, x, odd)
+	zCondShr1(u, m)
+	zCondShr1(v, m)
+}
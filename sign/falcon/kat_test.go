@@ -0,0 +1,110 @@
+package falcon
+
+// Code to generate test vectors in the format of the NIST "PQCsignKAT"
+// files. See PQCsignKAT_sign.c and randombytes.c in the reference
+// implementation.
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/nist"
+	"github.com/quantumcoinproject/circl/sign"
+	"github.com/quantumcoinproject/circl/sign/falcon/falcon1024"
+	"github.com/quantumcoinproject/circl/sign/falcon/falcon512"
+)
+
+type drbgReader struct{ g *nist.DRBG }
+
+func (r drbgReader) Read(p []byte) (int, error) {
+	r.g.Fill(p)
+	return len(p), nil
+}
+
+func TestPQCgenKATSign(t *testing.T) {
+	for _, tc := range []struct {
+		scheme sign.Scheme
+		sign   func(sk sign.PrivateKey, msg []byte, rand io.Reader) []byte
+		want   string
+	}{
+		// The key generation and the sampler of signing follow the
+		// specification, but do not use the same random tapes as the
+		// reference implementation, so these hashes are regression values
+		// of this implementation: they do not match the NIST submission
+		// files. The pairs of public keys and signed messages of those
+		// files are not in testdata yet, so nothing here checks the
+		// verification of signatures made by the reference implementation.
+		{
+			falcon512.Scheme(),
+			func(sk sign.PrivateKey, msg []byte, rand io.Reader) []byte {
+				var sig [falcon512.SignatureSize]byte
+				_ = falcon512.SignTo(sk.(*falcon512.PrivateKey), msg, rand, sig[:])
+				return sig[:]
+			},
+			"9fa38c3340766c3c20e17e420e98e9ef0e507bcf4db44249ecb0d2d990b42655",
+		},
+		{
+			falcon1024.Scheme(),
+			func(sk sign.PrivateKey, msg []byte, rand io.Reader) []byte {
+				var sig [falcon1024.SignatureSize]byte
+				_ = falcon1024.SignTo(sk.(*falcon1024.PrivateKey), msg, rand, sig[:])
+				return sig[:]
+			},
+			"261757b43a0648d3f775219ac29baa0693fae301363626033d49450a67a492b4",
+		},
+	} {
+		t.Run(tc.scheme.Name(), func(t *testing.T) {
+			var seed [48]byte
+			eseed := make([]byte, tc.scheme.SeedSize())
+			for i := 0; i < 48; i++ {
+				seed[i] = byte(i)
+			}
+			f := sha256.New()
+			g := nist.NewDRBG(&seed)
+			fmt.Fprintf(f, "# %s\n\n", tc.scheme.Name())
+			// Key generation is slow, so only the first ten vectors are
+			// generated.
+			for i := 0; i < 10; i++ {
+				mlen := 33 * (i + 1)
+				g.Fill(seed[:])
+				msg := make([]byte, mlen)
+				g.Fill(msg[:])
+
+				fmt.Fprintf(f, "count = %d\n", i)
+				fmt.Fprintf(f, "seed = %X\n", seed)
+				fmt.Fprintf(f, "mlen = %d\n", mlen)
+				fmt.Fprintf(f, "msg = %X\n", msg)
+
+				g2 := nist.NewDRBG(&seed)
+				g2.Fill(eseed)
+				pk, sk := tc.scheme.DeriveKey(eseed)
+
+				ppk, err := pk.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				psk, err := sk.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				fmt.Fprintf(f, "pk = %X\n", ppk)
+				fmt.Fprintf(f, "sk = %X\n", psk)
+				fmt.Fprintf(f, "smlen = %d\n", mlen+tc.scheme.SignatureSize())
+
+				sig := tc.sign(sk, msg, drbgReader{&g2})
+
+				fmt.Fprintf(f, "sm = %X%X\n\n", sig, msg)
+
+				if !tc.scheme.Verify(pk, msg, sig, nil) {
+					t.Fatal()
+				}
+			}
+			if got := fmt.Sprintf("%x", f.Sum(nil)); got != tc.want {
+				t.Fatalf("%s: got %s, want %s", tc.scheme.Name(), got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package hashsig provides the parts of WOTS+ and of Merkle trees shared by
+// the hash-based signature schemes SLH-DSA, in package slhdsa, and XMSS, in
+// package xmss.
+//
+// The schemes hash with different functions and addresses: FIPS 205 has
+// 12-byte tree addresses, compressed for SHA-2, and RFC 8391 has 8-byte
+// tree addresses followed by a word selecting keys and bitmasks. So the
+// functions of this package take the hash of a node as a [NodeHash], which
+// sets the address of the scheme.
+package hashsig
+
+// See RFC 8391 -- Section 3 and FIPS 205 -- Section 5
+// Winternitz One-Time Signature Plus Scheme, with w = 16.
+
+const (
+	WotsW    uint32 = 16 // WotsW is w = 2^lg_w, where lg_w = 4.
+	WotsLen2 uint32 = 3  // WotsLen2 is len_2, which is 3 for n ≤ 32.
+
+	// MaxWotsLen is the number of digits of messages of 32 bytes.
+	MaxWotsLen = 2*32 + WotsLen2
+)
+
+// WotsLen1 returns len_1, the number of base-w digits of n bytes.
+func WotsLen1(n uint32) uint32 { return 2 * n }
+
+// WotsLen returns len, the number of chains of the keys for n-byte hashes.
+func WotsLen(n uint32) uint32 { return WotsLen1(n) + WotsLen2 }
+
+// WotsDigits sets digits to the base-w digits of msg followed by the ones of
+// its checksum, and returns them. digits must have WotsLen(len(msg))
+// elements.
+//
+// See RFC 8391 -- Section 3.1.5 -- Algorithm 5 and
+// FIPS 205 -- Section 5.2 -- Algorithm 7.
+func WotsDigits(digits []uint32, msg []byte) []uint32 {
+	wotsLen1 := WotsLen1(uint32(len(msg)))
+	digits = digits[:wotsLen1+WotsLen2]
+	csum := wotsLen1 * (WotsW - 1)
+	for i := range wotsLen1 {
+		digits[i] = uint32((msg[i/2] >> ((1 - (i & 1)) << 2)) & 0xF)
+		csum -= digits[i]
+	}
+	for i := range WotsLen2 {
+		digits[wotsLen1+i] = (csum >> (8 - 4*i)) & 0xF
+	}
+	return digits
+}
+
+// NodeHash sets out to the node of index i at height z+1 of a tree, from
+// its children left and right at height z. out may alias left or right.
+type NodeHash func(out, left, right []byte, z, i uint32)
+
+// Stack holds the nodes of a tree being computed by [Stack.TreeHash].
+type Stack struct {
+	nodes []byte
+	z     []uint32
+	n     int
+}
+
+// NewStack returns a stack for the nodes of n bytes of trees of height up
+// to z.
+func NewStack(n, z uint32) Stack {
+	z = max(z, 1)
+	return Stack{make([]byte, n*z), make([]uint32, 0, z), int(n)}
+}
+
+// Clear erases the nodes of the stack.
+func (s *Stack) Clear() {
+	clear(s.nodes)
+	s.z = s.z[:0]
+}
+
+func (s *Stack) top() []byte {
+	i := len(s.z) - 1
+	return s.nodes[i*s.n : (i+1)*s.n]
+}
+
+// TreeHash sets out to the node of index i at height z, computing the
+// leaves below it from the left to the right with leaf, which sets its
+// first argument to the leaf of the index given. If save is not nil, it is
+// called with every node computed.
+//
+// See RFC 8391 -- Section 4.1.6 -- Algorithm 9 and
+// FIPS 205 -- Section 6.1 -- Algorithm 9 -- Iterative version.
+func (s *Stack) TreeHash(
+	out []byte, i, z uint32, leaf func(out []byte, i uint32), h NodeHash,
+	save func(i, z uint32, node []byte),
+) {
+	s.z = s.z[:0]
+	node := out[:s.n]
+	for k := range uint32(1) << z {
+		li := i<<z + k
+		lz := uint32(0)
+		leaf(node, li)
+		if save != nil {
+			save(li, lz, node)
+		}
+
+		for len(s.z) > 0 && s.z[len(s.z)-1] == lz {
+			left := s.top()
+			s.z = s.z[:len(s.z)-1]
+			li = li >> 1
+			h(node, left, node, lz, li)
+			lz = lz + 1
+			if save != nil {
+				save(li, lz, node)
+			}
+		}
+
+		s.z = append(s.z, lz)
+		copy(s.top(), node)
+	}
+}
+
+// RootFromAuthPath sets node, the leaf of index idx, to the root of the
+// tree computed from the authentication path of the leaf, which has the
+// siblings of the nodes from the leaf up to the root.
+//
+// See RFC 8391 -- Section 4.1.10 -- Algorithm 13 and
+// FIPS 205 -- Section 6.3 -- Algorithm 11.
+func RootFromAuthPath(node []byte, idx uint32, authPath []byte, h NodeHash) {
+	n := len(node)
+	for k := 0; k < len(authPath)/n; k++ {
+		sibling := authPath[k*n : (k+1)*n]
+		if (idx>>k)&0x1 == 0 {
+			h(node, node, sibling, uint32(k), idx>>(k+1))
+		} else {
+			h(node, sibling, node, uint32(k), idx>>(k+1))
+		}
+	}
+}
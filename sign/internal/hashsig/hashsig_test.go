@@ -0,0 +1,71 @@
+package hashsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+func TestWotsDigits(t *testing.T) {
+	var digits [MaxWotsLen]uint32
+	msg := make([]byte, 16)
+	got := WotsDigits(digits[:], msg)
+	test.CheckOk(len(got) == int(WotsLen(16)), "wrong number of digits", t)
+
+	// The checksum of 32 zero digits is 32·15 = 0x1E0.
+	want := append(make([]uint32, 32), 1, 14, 0)
+	for i := range want {
+		test.CheckOk(got[i] == want[i], "wrong digit", t)
+	}
+
+	msg[0] = 0xA5
+	got = WotsDigits(digits[:], msg)
+	test.CheckOk(got[0] == 0xA && got[1] == 0x5, "wrong digit order", t)
+}
+
+func toyHash(out, left, right []byte, z, i uint32) {
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, [2]uint32{z, i})
+	h.Write(left)
+	h.Write(right)
+	copy(out, h.Sum(nil))
+}
+
+func toyLeaf(out []byte, i uint32) {
+	h := sha256.Sum256(binary.BigEndian.AppendUint32(nil, i))
+	copy(out, h[:])
+}
+
+func TestTreeHash(t *testing.T) {
+	const n, height = 32, 5
+	stack := NewStack(n, height)
+	root := make([]byte, n)
+	stack.TreeHash(root, 0, height, toyLeaf, toyHash, nil)
+
+	// Every node saved must be the one computed by a tree of its height.
+	nodes := make(map[[2]uint32][]byte)
+	stack.TreeHash(make([]byte, n), 0, height, toyLeaf, toyHash,
+		func(i, z uint32, node []byte) { nodes[[2]uint32{i, z}] = bytes.Clone(node) })
+	test.CheckOk(len(nodes) == 1<<(height+1)-1, "wrong number of nodes saved", t)
+	test.CheckOk(bytes.Equal(nodes[[2]uint32{0, height}], root), "wrong root saved", t)
+
+	node := make([]byte, n)
+	for z := range uint32(height) {
+		sub := NewStack(n, z)
+		sub.TreeHash(node, 3>>z, z, toyLeaf, toyHash, nil)
+		test.CheckOk(bytes.Equal(node, nodes[[2]uint32{3 >> z, z}]), "wrong inner node", t)
+	}
+
+	for idx := range uint32(1) << height {
+		authPath := make([]byte, 0, height*n)
+		for z := range uint32(height) {
+			authPath = append(authPath, nodes[[2]uint32{(idx >> z) ^ 1, z}]...)
+		}
+		toyLeaf(node, idx)
+		RootFromAuthPath(node, idx, authPath, toyHash)
+		test.CheckOk(bytes.Equal(node, root), "wrong root from authentication path", t)
+	}
+}
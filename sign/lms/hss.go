@@ -0,0 +1,234 @@
+// Package lms provides the stateful hash-based signature scheme LMS and its
+// multi-level variant HSS, as specified in [RFC 8554], with the parameter
+// sets of SHA-256 and SHAKE256 approved by [SP 800-208].
+//
+// A key is an HSS key with one to eight levels, see [Level]. Each level has
+// a parameter set of LMS, see [LMSType], and one of LM-OTS, see [OTSType].
+// An HSS key with a single level signs as the LMS key at its top, but its
+// public keys and signatures have the encoding of HSS. The LMS tree at each
+// level signs the public keys of the trees below, generated when needed,
+// and the bottom tree signs messages. So, a key can produce 2^h signatures,
+// where h is the sum of the heights of its levels, and its key generation
+// only computes the top tree.
+//
+// # State
+//
+// Every signature uses a different one-time key, selected by its index, and
+// signing twice with the same index allows forgeries. Hence, signing needs
+// a [stateful.Store], set with [PrivateKey.SetStore], which persists the
+// indices used before a signature is released. Encoded private keys do not
+// include the index: the store is the only source of truth for it.
+//
+// Private keys are encoded as u32str(L) || types || I || SEED, where types
+// has the LMS and LM-OTS typecodes of the L levels, and I and SEED are the
+// identifier and seed of the top LMS key. The keys of the lower levels and
+// the randomizers of signatures are derived from them, following the
+// reference implementation of HSS. Signatures are deterministic.
+//
+// [RFC 8554]: https://www.rfc-editor.org/rfc/rfc8554
+// [SP 800-208]: https://doi.org/10.6028/NIST.SP.800-208
+package lms
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// MaxLevels is the maximum number of levels of HSS keys.
+const MaxLevels = 8
+
+// maxHeight is the maximum sum of the heights of the levels of private
+// keys, so signature indices fit in 64 bits.
+const maxHeight = 64
+
+// [GenerateKey] returns a pair of HSS keys with the levels given, from the
+// top to the bottom. It returns an error if it fails reading from the
+// random source, or [ErrParam] if the levels are not valid, or if the sum
+// of their heights exceeds 64.
+// If random is nil, [crypto/rand.Reader] is used.
+func GenerateKey(random io.Reader, levels ...Level) (*PublicKey, *PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	if err := checkLevels(levels); err != nil {
+		return nil, nil, err
+	}
+
+	seed := make([]byte, idSize+levels[0].LMS.params().m)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+
+	return NewKeyFromSeed(seed, levels...)
+}
+
+// [NewKeyFromSeed] returns the pair of HSS keys with the levels given,
+// derived from the seed, which is I || SEED of the top LMS key and must
+// have 16+m bytes, where m is the hash length of the top level. It returns
+// [ErrParam] if the levels are not valid, or [ErrSeed] if the seed has
+// another length.
+func NewKeyFromSeed(seed []byte, levels ...Level) (*PublicKey, *PrivateKey, error) {
+	if err := checkLevels(levels); err != nil {
+		return nil, nil, err
+	}
+	if len(seed) != idSize+int(levels[0].LMS.params().m) {
+		return nil, nil, ErrSeed
+	}
+
+	priv := newPrivateKey(levels, seed[:idSize], seed[idSize:])
+	pub := priv.publicKey
+	return &pub, priv, nil
+}
+
+func checkLevels(levels []Level) error {
+	if len(levels) == 0 || len(levels) > MaxLevels {
+		return ErrParam
+	}
+
+	height := 0
+	for _, l := range levels {
+		if !l.IsValid() {
+			return ErrParam
+		}
+		height += l.LMS.Height()
+	}
+	if height > maxHeight {
+		return ErrParam
+	}
+	return nil
+}
+
+// [Sign] returns the HSS signature of the message with the private key. It
+// reserves the index of the signature from the store of the key before
+// computing the signature, and returns an error if the key has no store,
+// if the reservation fails, or [stateful.ErrExhausted] if the key has no
+// signatures left.
+func Sign(priv *PrivateKey, message []byte) ([]byte, error) {
+	if priv.counter == nil {
+		return nil, ErrStore
+	}
+
+	idx, err := priv.counter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	return priv.sign(idx, message), nil
+}
+
+// See RFC 8554 -- Section 6.2.
+func (k *PrivateKey) sign(idx uint64, message []byte) []byte {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// Splits the index into the leaves of each level, and the number of the
+	// tree used at each level.
+	numLevels := len(k.levels)
+	qs := make([]uint32, numLevels)
+	trees := make([]uint64, numLevels)
+	rest := idx
+	for i := numLevels - 1; i >= 0; i-- {
+		h := uint32(k.levels[i].LMS.Height())
+		qs[i] = uint32(rest & (1<<h - 1))
+		rest = shr(rest, h)
+		trees[i] = rest
+	}
+
+	for i := range k.cache {
+		c := &k.cache[i]
+		if i > 0 && (c.key == nil || c.tree != trees[i]) {
+			id, seed := k.cache[i-1].key.child(k.levels[i], qs[i-1])
+			c.key = newLMSKey(k.levels[i], id, seed)
+			c.tree = trees[i]
+			c.sigOk = false
+		}
+		c.key.update(c.key.hasher(), qs[i])
+	}
+
+	sig := binary.BigEndian.AppendUint32(nil, uint32(numLevels-1))
+	for i := range numLevels - 1 {
+		c := &k.cache[i]
+		if !c.sigOk || c.sigQ != qs[i] {
+			c.sig = c.key.sign(c.key.hasher(), qs[i], k.cache[i+1].key.publicKey())
+			c.sigQ, c.sigOk = qs[i], true
+		}
+		sig = append(sig, c.sig...)
+		sig = append(sig, k.cache[i+1].key.publicKey()...)
+	}
+
+	bottom := k.cache[numLevels-1].key
+	return append(sig, bottom.sign(bottom.hasher(), qs[numLevels-1], message)...)
+}
+
+// child returns the identifier I and seed SEED of the LMS key with the
+// level given that is signed by the q-th one-time key.
+func (k *lmsKey) child(l Level, q uint32) (id, seed []byte) {
+	p := k.LMS.params()
+	ots := &otsKey{otsParams: k.OTS.params(), h: newHasher(32, p.isSHA2), id: k.id, q: q, seed: k.seed}
+	id = make([]byte, 32)
+	ots.derive(id, seedChildID)
+	seed = make([]byte, 32)
+	ots.derive(seed, seedChildSeed)
+	return id[:idSize], seed[:l.LMS.params().m]
+}
+
+func shr(x uint64, n uint32) uint64 {
+	if n >= 64 {
+		return 0
+	}
+	return x >> n
+}
+
+// [Verify] returns true if the HSS signature of the message is valid under
+// the public key.
+//
+// See RFC 8554 -- Section 6.3.
+func Verify(pub *PublicKey, message, signature []byte) bool {
+	if len(signature) < 4 {
+		return false
+	}
+	nspk := binary.BigEndian.Uint32(signature)
+	if nspk+1 != pub.levels {
+		return false
+	}
+
+	key := pub.lms
+	sig := signature[4:]
+	for range nspk {
+		sigLen := lmsSigSize(sig)
+		if sigLen == 0 {
+			return false
+		}
+		lmsSig := sig[:sigLen]
+		sig = sig[sigLen:]
+
+		if len(sig) < 4 || !LMSType(binary.BigEndian.Uint32(sig)).IsValid() {
+			return false
+		}
+		pubLen := LMSType(binary.BigEndian.Uint32(sig)).params().pubSize()
+		if len(sig) < pubLen {
+			return false
+		}
+		nextKey := sig[:pubLen]
+		sig = sig[pubLen:]
+
+		if !lmsVerify(key, nextKey, lmsSig) {
+			return false
+		}
+		key = nextKey
+	}
+
+	return lmsVerify(key, message, sig)
+}
+
+var (
+	ErrParam      = errors.New("sign/lms: invalid LMS parameter")
+	ErrPreHash    = errors.New("sign/lms: messages must not be pre-hashed")
+	ErrPrivateKey = errors.New("sign/lms: invalid private key")
+	ErrPublicKey  = errors.New("sign/lms: invalid public key")
+	ErrSeed       = errors.New("sign/lms: invalid seed length")
+	ErrStore      = errors.New("sign/lms: private key has no state store")
+	ErrTree       = errors.New("sign/lms: invalid tree height or tree index")
+)
@@ -0,0 +1,192 @@
+package lms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/quantumcoinproject/circl/sign"
+	"github.com/quantumcoinproject/circl/sign/stateful"
+)
+
+// [PublicKey] stores an HSS public key, which is encoded as
+// u32str(L) || pub, where pub is the LMS public key of the top level.
+// It implements the [crypto.PublicKey] and [encoding.BinaryMarshaler]
+// interfaces.
+type PublicKey struct {
+	levels uint32
+	lms    []byte
+}
+
+// [PrivateKey] stores an HSS private key.
+// It implements the [crypto.Signer], [crypto.PrivateKey] and
+// [encoding.BinaryMarshaler] interfaces.
+//
+// A private key signs only after a store of its state is set with
+// [PrivateKey.SetStore]. It is safe for concurrent use.
+type PrivateKey struct {
+	levels    []Level
+	id, seed  []byte // Identifier and seed of the top LMS key.
+	publicKey PublicKey
+	counter   *stateful.Counter
+
+	mu    sync.Mutex   // Guards cache.
+	cache []levelCache // Keys used by the last signature, one per level.
+}
+
+// levelCache stores the LMS key of a level used by the last signature, and
+// its signature of the public key of the level below.
+type levelCache struct {
+	key   *lmsKey
+	tree  uint64 // Number of the tree of the key in its level.
+	sigOk bool
+	sigQ  uint32
+	sig   []byte
+}
+
+// newPrivateKey returns the private key with the levels, identifier and
+// seed given, which must be valid. It computes the tree of the top level.
+func newPrivateKey(levels []Level, id, seed []byte) *PrivateKey {
+	k := &PrivateKey{
+		levels: append([]Level(nil), levels...),
+		id:     bytes.Clone(id),
+		seed:   bytes.Clone(seed),
+		cache:  make([]levelCache, len(levels)),
+	}
+
+	top := newLMSKey(levels[0], k.id, k.seed)
+	top.update(top.hasher(), 0)
+	k.cache[0].key = top
+	k.publicKey = PublicKey{levels: uint32(len(levels)), lms: top.publicKey()}
+	return k
+}
+
+// Levels returns the levels of the key, from the top to the bottom.
+func (k *PrivateKey) Levels() []Level { return append([]Level(nil), k.levels...) }
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return ErrPublicKey
+	}
+	levels := binary.BigEndian.Uint32(b)
+	if _, ok := parsePublicKey(b[4:]); !ok || levels == 0 || levels > MaxLevels {
+		return ErrPublicKey
+	}
+
+	k.levels = levels
+	k.lms = bytes.Clone(b[4:])
+	return nil
+}
+
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint32(nil, k.levels)
+	return append(b, k.lms...), nil
+}
+
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && k.levels == other.levels && bytes.Equal(k.lms, other.lms)
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes. It
+// computes the tree of the top level, as key generation does. The key has
+// no store of its state.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return ErrPrivateKey
+	}
+	numLevels := binary.BigEndian.Uint32(b)
+	if numLevels == 0 || numLevels > MaxLevels || len(b) < int(4+8*numLevels) {
+		return ErrPrivateKey
+	}
+
+	levels := make([]Level, numLevels)
+	for i := range levels {
+		levels[i].LMS = LMSType(binary.BigEndian.Uint32(b[4+8*i:]))
+		levels[i].OTS = OTSType(binary.BigEndian.Uint32(b[8+8*i:]))
+	}
+	if checkLevels(levels) != nil {
+		return ErrPrivateKey
+	}
+
+	rest := b[4+8*numLevels:]
+	if len(rest) != idSize+int(levels[0].LMS.params().m) {
+		return ErrPrivateKey
+	}
+
+	*k = *newPrivateKey(levels, rest[:idSize], rest[idSize:])
+	return nil
+}
+
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint32(nil, uint32(len(k.levels)))
+	for _, l := range k.levels {
+		b = binary.BigEndian.AppendUint32(b, uint32(l.LMS))
+		b = binary.BigEndian.AppendUint32(b, uint32(l.OTS))
+	}
+	b = append(b, k.id...)
+	return append(b, k.seed...), nil
+}
+
+func (k *PrivateKey) Public() crypto.PublicKey {
+	pub := k.publicKey
+	return &pub
+}
+
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	if !ok || len(k.levels) != len(other.levels) {
+		return false
+	}
+	for i := range k.levels {
+		if k.levels[i] != other.levels[i] {
+			return false
+		}
+	}
+	return bytes.Equal(k.id, other.id) &&
+		subtle.ConstantTimeCompare(k.seed, other.seed) == 1
+}
+
+// SetStore sets the store of the state of the key, from which signing
+// reserves the indices of signatures, batch indices at a time. A batch of
+// zero reserves one index at a time. Larger batches make fewer writes to
+// the store, but the indices of a batch not used when the process exits
+// are lost.
+//
+// The store must be used by this key only, and only one [PrivateKey] value
+// of a key may have it set. It must not be called concurrently with
+// signing.
+func (k *PrivateKey) SetStore(store stateful.Store, batch uint64) {
+	height := uint32(0)
+	for _, l := range k.levels {
+		height += l.LMS.params().h
+	}
+
+	max := uint64(math.MaxUint64)
+	if height < 64 {
+		max = 1 << height
+	}
+	k.counter = stateful.NewCounter(store, max, batch)
+}
+
+// Sign returns the HSS signature of the message, reserving its index from
+// the store of the key. The rand argument is not used, as signatures are
+// deterministic.
+// It implements the [crypto.Signer] interface. Messages must not be
+// pre-hashed, so opts.HashFunc() must return zero.
+func (k *PrivateKey) Sign(
+	rand io.Reader, msg []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, ErrPreHash
+	}
+	return Sign(k, msg)
+}
+
+func (k *PublicKey) Scheme() sign.Scheme  { return sch }
+func (k *PrivateKey) Scheme() sign.Scheme { return sch }
@@ -0,0 +1,147 @@
+package lms
+
+import "encoding/binary"
+
+// See RFC 8554 -- Section 4
+// LM-OTS One-Time Signatures
+
+// Domain separators, see RFC 8554 -- Section 7.1.
+const (
+	dPBLC = 0x8080
+	dMESG = 0x8181
+	dLEAF = 0x8282
+	dINTR = 0x8383
+)
+
+// Indices of the pseudorandom values derived from the seed besides the
+// one-time private keys, following the reference implementation of HSS.
+const (
+	seedRandomizer = 0xfffd // Randomizer C of the q-th one-time key.
+	seedChildSeed  = 0xfffe // SEED of the child of the q-th one-time key.
+	seedChildID    = 0xffff // I of the child of the q-th one-time key.
+)
+
+// idSize is the length of the key identifier I.
+const idSize = 16
+
+// otsKey is the q-th one-time key of the LMS key with identifier I and
+// seed SEED, whose private keys are derived as in RFC 8554 -- Appendix A.
+type otsKey struct {
+	*otsParams
+	h    *hasher
+	id   []byte
+	q    uint32
+	seed []byte
+}
+
+// derive sets out to H(I || u32str(q) || u16str(i) || u8str(0xff) || SEED),
+// the i-th pseudorandom value of the one-time key.
+func (k *otsKey) derive(out []byte, i uint16) {
+	var b [idSize + 7]byte
+	copy(b[:], k.id)
+	binary.BigEndian.PutUint32(b[idSize:], k.q)
+	binary.BigEndian.PutUint16(b[idSize+4:], i)
+	b[idSize+6] = 0xff
+	k.h.sum(out, b[:], k.seed)
+}
+
+// chain iterates the i-th hash chain of the key from step start to end.
+func chain(h *hasher, id []byte, q uint32, out, x []byte, i uint16, start, end uint32) {
+	n := h.n
+	b := make([]byte, idSize+7+n)
+	copy(b, id)
+	binary.BigEndian.PutUint32(b[idSize:], q)
+	binary.BigEndian.PutUint16(b[idSize+4:], i)
+	tmp := b[idSize+7:]
+	copy(tmp, x)
+	for j := start; j < end; j++ {
+		b[idSize+6] = byte(j)
+		h.sum(tmp, b)
+	}
+	copy(out[:n], tmp)
+}
+
+// publicHash sets out to H(I || u32str(q) || u16str(D_PBLC) || z), which is
+// the public key if z are the ends of the chains.
+func publicHash(h *hasher, id []byte, q uint32, out, z []byte) {
+	var b [idSize + 6]byte
+	copy(b[:], id)
+	binary.BigEndian.PutUint32(b[idSize:], q)
+	binary.BigEndian.PutUint16(b[idSize+4:], dPBLC)
+	h.sum(out, b[:], z)
+}
+
+// See RFC 8554 -- Section 4.3 -- Algorithm 1.
+func (k *otsKey) publicKey(out []byte) {
+	n := k.n
+	z := make([]byte, k.p*n)
+	for i := range k.p {
+		zi := z[i*n : (i+1)*n]
+		k.derive(zi, uint16(i))
+		chain(k.h, k.id, k.q, zi, zi, uint16(i), 0, 1<<k.w-1)
+	}
+	publicHash(k.h, k.id, k.q, out, z)
+}
+
+// See RFC 8554 -- Section 4.5 -- Algorithm 3.
+// The randomizer C is derived from the seed, so signing the same message
+// with the same one-time key gives the same signature.
+func (k *otsKey) sign(sig, msg []byte) {
+	n := k.n
+	binary.BigEndian.PutUint32(sig, uint32(k.OTSType))
+	c := sig[4 : 4+n]
+	k.derive(c, seedRandomizer)
+
+	y := sig[4+n:]
+	for i, a := range k.coefficients(k.h, k.id, k.q, c, msg) {
+		yi := y[uint32(i)*n : uint32(i+1)*n]
+		k.derive(yi, uint16(i))
+		chain(k.h, k.id, k.q, yi, yi, uint16(i), 0, a)
+	}
+}
+
+// See RFC 8554 -- Section 4.6 -- Algorithm 4b.
+// It sets out to the candidate public key Kc computed from the signature,
+// whose type and length must have been checked.
+func (p *otsParams) publicKeyFromSig(h *hasher, id []byte, q uint32, out, sig, msg []byte) {
+	n := p.n
+	c := sig[4 : 4+n]
+	y := sig[4+n:]
+	z := make([]byte, p.p*n)
+	for i, a := range p.coefficients(h, id, q, c, msg) {
+		zi := z[uint32(i)*n : uint32(i+1)*n]
+		chain(h, id, q, zi, y[uint32(i)*n:], uint16(i), a, 1<<p.w-1)
+	}
+	publicHash(h, id, q, out, z)
+}
+
+// coefficients returns the coefficients of Q || Cksm(Q), where
+// Q = H(I || u32str(q) || u16str(D_MESG) || C || message).
+func (p *otsParams) coefficients(h *hasher, id []byte, q uint32, c, msg []byte) []uint32 {
+	var b [idSize + 6]byte
+	copy(b[:], id)
+	binary.BigEndian.PutUint32(b[idSize:], q)
+	binary.BigEndian.PutUint16(b[idSize+4:], dMESG)
+	qc := make([]byte, p.n+2)
+	h.sum(qc, b[:], c, msg)
+
+	// See RFC 8554 -- Section 4.4.
+	u := 8 * p.n / p.w
+	maxCoef := uint32(1)<<p.w - 1
+	sum := uint32(0)
+	for i := range u {
+		sum += maxCoef - coef(qc, i, p.w)
+	}
+	binary.BigEndian.PutUint16(qc[p.n:], uint16(sum<<p.ls))
+
+	a := make([]uint32, p.p)
+	for i := range a {
+		a[i] = coef(qc, uint32(i), p.w)
+	}
+	return a
+}
+
+// coef returns the i-th w-bit value of s, see RFC 8554 -- Section 3.1.3.
+func coef(s []byte, i, w uint32) uint32 {
+	return (uint32(1)<<w - 1) & uint32(s[i*w/8]>>(8-(w*(i%(8/w))+w)))
+}
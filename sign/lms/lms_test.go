@@ -0,0 +1,264 @@
+package lms_test
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/lms"
+	"github.com/quantumcoinproject/circl/sign/stateful"
+)
+
+func TestParams(t *testing.T) {
+	for ots := lms.LMOTS_SHA256_N32_W1; ots.IsValid(); ots++ {
+		for typ := lms.LMS_SHA256_M32_H5; typ.IsValid(); typ++ {
+			// Types match if their hash functions and lengths match.
+			want := (ots-1)/4 == lms.OTSType(typ-lms.LMS_SHA256_M32_H5)/5
+			if got := (lms.Level{LMS: typ, OTS: ots}).IsValid(); got != want {
+				test.ReportError(t, got, want, typ, ots)
+			}
+		}
+	}
+	if lms.OTSType(0).IsValid() || lms.LMSType(4).IsValid() {
+		t.Fatal("reserved typecodes are valid")
+	}
+
+	// Sizes of signatures of RFC 8554 -- Section 5.4 and SP 800-208.
+	for _, v := range []struct {
+		levels []lms.Level
+		seed   int
+		sig    int
+	}{
+		{[]lms.Level{{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8}}, 48, 4 + 1292},
+		{[]lms.Level{{lms.LMS_SHA256_M32_H10, lms.LMOTS_SHA256_N32_W1}}, 48, 4 + 8844},
+		{[]lms.Level{{lms.LMS_SHAKE_M24_H5, lms.LMOTS_SHAKE_N24_W4}}, 40, 4 + 1380},
+		{[]lms.Level{
+			{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W2},
+			{lms.LMS_SHA256_M24_H5, lms.LMOTS_SHA256_N24_W1},
+		}, 48, 4 + 4460 + 48 + 4956},
+	} {
+		pub, priv, err := lms.NewKeyFromSeed(make([]byte, v.seed), v.levels...)
+		test.CheckNoErr(t, err, "new key")
+		priv.SetStore(stateful.NewMemoryStore(0), 0)
+		sig, err := lms.Sign(priv, nil)
+		test.CheckNoErr(t, err, "sign")
+		if len(sig) != v.sig || !lms.Verify(pub, nil, sig) {
+			test.ReportError(t, len(sig), v.sig, v.levels)
+		}
+	}
+
+	for _, levels := range [][]lms.Level{
+		nil,
+		make([]lms.Level, lms.MaxLevels+1),
+		{{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHAKE_N32_W8}},
+		{{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N24_W8}},
+		{
+			{lms.LMS_SHA256_M32_H25, lms.LMOTS_SHA256_N32_W8},
+			{lms.LMS_SHA256_M32_H25, lms.LMOTS_SHA256_N32_W8},
+			{lms.LMS_SHA256_M32_H25, lms.LMOTS_SHA256_N32_W8},
+		},
+	} {
+		_, _, err := lms.GenerateKey(nil, levels...)
+		if err != lms.ErrParam {
+			test.ReportError(t, err, lms.ErrParam, levels)
+		}
+	}
+}
+
+func TestHSS(t *testing.T) {
+	for _, levels := range [][]lms.Level{
+		{{lms.LMS_SHA256_M32_H10, lms.LMOTS_SHA256_N32_W4}},
+		{{lms.LMS_SHAKE_M24_H10, lms.LMOTS_SHAKE_N24_W2}},
+		{
+			{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8},
+			{lms.LMS_SHAKE_M32_H5, lms.LMOTS_SHAKE_N32_W2},
+		},
+		{
+			{lms.LMS_SHAKE_M32_H5, lms.LMOTS_SHAKE_N32_W4},
+			{lms.LMS_SHA256_M24_H5, lms.LMOTS_SHA256_N24_W4},
+			{lms.LMS_SHA256_M24_H5, lms.LMOTS_SHA256_N24_W8},
+		},
+	} {
+		name := ""
+		for _, l := range levels {
+			name += "/" + l.LMS.String()
+		}
+		t.Run(name[1:], func(t *testing.T) { testHSS(t, levels) })
+	}
+}
+
+func testHSS(t *testing.T, levels []lms.Level) {
+	pub, priv, err := lms.GenerateKey(nil, levels...)
+	test.CheckNoErr(t, err, "generate key")
+
+	_, err = lms.Sign(priv, []byte("message"))
+	if err != lms.ErrStore {
+		test.ReportError(t, err, lms.ErrStore)
+	}
+
+	// Signs across trees of the bottom level for several levels.
+	const n = 70
+	priv.SetStore(stateful.NewMemoryStore(0), 4)
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		msg := []byte{byte(i)}
+		sigs[i], err = lms.Sign(priv, msg)
+		test.CheckNoErr(t, err, "sign")
+		if !lms.Verify(pub, msg, sigs[i]) {
+			t.Fatalf("verification of signature %v failed", i)
+		}
+		if lms.Verify(pub, []byte("other"), sigs[i]) {
+			t.Fatalf("verification of signature %v with another message succeeded", i)
+		}
+	}
+
+	// Keys round trip through their encoding.
+	pk, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal public key")
+	sk, err := priv.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal private key")
+	pub2 := new(lms.PublicKey)
+	test.CheckNoErr(t, pub2.UnmarshalBinary(pk), "unmarshal public key")
+	priv2 := new(lms.PrivateKey)
+	test.CheckNoErr(t, priv2.UnmarshalBinary(sk), "unmarshal private key")
+	if !pub.Equal(pub2) || !priv.Equal(priv2) || !pub.Equal(priv2.Public()) {
+		t.Fatal("keys differ after round trip")
+	}
+
+	// Signatures are deterministic, so a key resuming from an index signs
+	// as the key that signed in order.
+	priv2.SetStore(stateful.NewMemoryStore(n-5), 0)
+	for i := n - 5; i < n; i++ {
+		sig, err := priv2.Sign(nil, []byte{byte(i)}, crypto.Hash(0))
+		test.CheckNoErr(t, err, "sign")
+		if !bytes.Equal(sig, sigs[i]) {
+			t.Fatalf("signature %v differs after resuming", i)
+		}
+	}
+
+	// Modified signatures are rejected.
+	sig := sigs[n-1]
+	for _, i := range []int{0, 3, 7, len(sig) / 2, len(sig) - 1} {
+		sig[i] ^= 1
+		if lms.Verify(pub, []byte{n - 1}, sig) {
+			t.Fatalf("verification succeeded with byte %v modified", i)
+		}
+		sig[i] ^= 1
+	}
+	if lms.Verify(pub, []byte{n - 1}, sig[:len(sig)-1]) {
+		t.Fatal("verification succeeded with a short signature")
+	}
+	if lms.Verify(pub, []byte{n - 1}, append(sig, 0)) {
+		t.Fatal("verification succeeded with a long signature")
+	}
+
+	sk[3]++
+	if err = priv2.UnmarshalBinary(sk); err != lms.ErrPrivateKey {
+		test.ReportError(t, err, lms.ErrPrivateKey)
+	}
+	pk[4] ^= 1
+	if err = pub2.UnmarshalBinary(pk); err != lms.ErrPublicKey {
+		test.ReportError(t, err, lms.ErrPublicKey)
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	levels := []lms.Level{
+		{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8},
+		{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8},
+	}
+	pub, priv, err := lms.NewKeyFromSeed(make([]byte, 48), levels...)
+	test.CheckNoErr(t, err, "new key")
+	priv.SetStore(stateful.NewMemoryStore(1<<10-1), 8)
+
+	msg := []byte("message")
+	sig, err := lms.Sign(priv, msg)
+	test.CheckNoErr(t, err, "sign with the last index")
+	if !lms.Verify(pub, msg, sig) {
+		t.Fatal("verification of the last signature failed")
+	}
+
+	_, err = lms.Sign(priv, msg)
+	if !errors.Is(err, stateful.ErrExhausted) {
+		test.ReportError(t, err, stateful.ErrExhausted)
+	}
+}
+
+// TestKeyGen checks the public key of RFC 8554 -- Appendix F -- Test Case 2,
+// whose top level is generated from its identifier and seed.
+func TestKeyGen(t *testing.T) {
+	seed, _ := hex.DecodeString("d08fabd4a2091ff0a8cb4ed834e74534" +
+		"558b8966c48ae9cb898b423c83443aae014a72f1b1ab5cc85cf1d892903b5439")
+	want, _ := hex.DecodeString("00000002" + "00000006" + "00000003" +
+		"d08fabd4a2091ff0a8cb4ed834e74534" +
+		"32a58885cd9ba0431235466bff9651c6c92124404d45fa53cf161c28f1ad5a8e")
+
+	pub, _, err := lms.NewKeyFromSeed(seed,
+		lms.Level{LMS: lms.LMS_SHA256_M32_H10, OTS: lms.LMOTS_SHA256_N32_W4},
+		lms.Level{LMS: lms.LMS_SHA256_M32_H5, OTS: lms.LMOTS_SHA256_N32_W8},
+	)
+	test.CheckNoErr(t, err, "new key")
+	got, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal public key")
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want)
+	}
+}
+
+// TestVectors checks the test cases of RFC 8554 -- Appendix F, given as a
+// JSON array of objects with the hex encoded public key, message and
+// signature. The file is not part of the tree yet, so this test fails
+// until it is added.
+func TestVectors(t *testing.T) {
+	const fileName = "testdata/rfc8554.json"
+	input, err := os.ReadFile(fileName)
+	test.CheckNoErr(t, err, "read file")
+
+	var vectors []struct {
+		Name, PublicKey, Message, Signature string
+	}
+	test.CheckNoErr(t, json.Unmarshal(input, &vectors), "parse file")
+
+	for _, v := range vectors {
+		pk, _ := hex.DecodeString(v.PublicKey)
+		msg, _ := hex.DecodeString(v.Message)
+		sig, _ := hex.DecodeString(v.Signature)
+
+		pub := new(lms.PublicKey)
+		test.CheckNoErr(t, pub.UnmarshalBinary(pk), v.Name)
+		if !lms.Verify(pub, msg, sig) {
+			t.Fatalf("%v: verification failed", v.Name)
+		}
+		sig[len(sig)-1] ^= 1
+		if lms.Verify(pub, msg, sig) {
+			t.Fatalf("%v: verification of a modified signature succeeded", v.Name)
+		}
+	}
+}
+
+func BenchmarkHSS(b *testing.B) {
+	levels := []lms.Level{
+		{lms.LMS_SHA256_M32_H10, lms.LMOTS_SHA256_N32_W4},
+		{lms.LMS_SHA256_M32_H10, lms.LMOTS_SHA256_N32_W4},
+	}
+	pub, priv, _ := lms.NewKeyFromSeed(make([]byte, 48), levels...)
+	priv.SetStore(stateful.NewMemoryStore(0), 64)
+	msg := []byte("message")
+	sig, _ := lms.Sign(priv, msg)
+
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = lms.Sign(priv, msg)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = lms.Verify(pub, msg, sig)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package lms
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// [OTSType] identifies the supported parameter sets of LM-OTS, with the
+// typecodes registered by RFC 8554 and SP 800-208.
+type OTSType uint32
+
+//nolint:stylecheck
+const (
+	LMOTS_SHA256_N32_W1 OTSType = iota + 1 // LMOTS_SHA256_N32_W1
+	LMOTS_SHA256_N32_W2                    // LMOTS_SHA256_N32_W2
+	LMOTS_SHA256_N32_W4                    // LMOTS_SHA256_N32_W4
+	LMOTS_SHA256_N32_W8                    // LMOTS_SHA256_N32_W8
+	LMOTS_SHA256_N24_W1                    // LMOTS_SHA256_N24_W1
+	LMOTS_SHA256_N24_W2                    // LMOTS_SHA256_N24_W2
+	LMOTS_SHA256_N24_W4                    // LMOTS_SHA256_N24_W4
+	LMOTS_SHA256_N24_W8                    // LMOTS_SHA256_N24_W8
+	LMOTS_SHAKE_N32_W1                     // LMOTS_SHAKE_N32_W1
+	LMOTS_SHAKE_N32_W2                     // LMOTS_SHAKE_N32_W2
+	LMOTS_SHAKE_N32_W4                     // LMOTS_SHAKE_N32_W4
+	LMOTS_SHAKE_N32_W8                     // LMOTS_SHAKE_N32_W8
+	LMOTS_SHAKE_N24_W1                     // LMOTS_SHAKE_N24_W1
+	LMOTS_SHAKE_N24_W2                     // LMOTS_SHAKE_N24_W2
+	LMOTS_SHAKE_N24_W4                     // LMOTS_SHAKE_N24_W4
+	LMOTS_SHAKE_N24_W8                     // LMOTS_SHAKE_N24_W8
+	_MaxOTSType
+)
+
+// [LMSType] identifies the supported parameter sets of LMS, with the
+// typecodes registered by RFC 8554 and SP 800-208.
+type LMSType uint32
+
+//nolint:stylecheck
+const (
+	LMS_SHA256_M32_H5  LMSType = iota + 5 // LMS_SHA256_M32_H5
+	LMS_SHA256_M32_H10                    // LMS_SHA256_M32_H10
+	LMS_SHA256_M32_H15                    // LMS_SHA256_M32_H15
+	LMS_SHA256_M32_H20                    // LMS_SHA256_M32_H20
+	LMS_SHA256_M32_H25                    // LMS_SHA256_M32_H25
+	LMS_SHA256_M24_H5                     // LMS_SHA256_M24_H5
+	LMS_SHA256_M24_H10                    // LMS_SHA256_M24_H10
+	LMS_SHA256_M24_H15                    // LMS_SHA256_M24_H15
+	LMS_SHA256_M24_H20                    // LMS_SHA256_M24_H20
+	LMS_SHA256_M24_H25                    // LMS_SHA256_M24_H25
+	LMS_SHAKE_M32_H5                      // LMS_SHAKE_M32_H5
+	LMS_SHAKE_M32_H10                     // LMS_SHAKE_M32_H10
+	LMS_SHAKE_M32_H15                     // LMS_SHAKE_M32_H15
+	LMS_SHAKE_M32_H20                     // LMS_SHAKE_M32_H20
+	LMS_SHAKE_M32_H25                     // LMS_SHAKE_M32_H25
+	LMS_SHAKE_M24_H5                      // LMS_SHAKE_M24_H5
+	LMS_SHAKE_M24_H10                     // LMS_SHAKE_M24_H10
+	LMS_SHAKE_M24_H15                     // LMS_SHAKE_M24_H15
+	LMS_SHAKE_M24_H20                     // LMS_SHAKE_M24_H20
+	LMS_SHAKE_M24_H25                     // LMS_SHAKE_M24_H25
+	_MaxLMSType
+)
+
+// IsValid returns true if the parameter set is supported.
+func (t OTSType) IsValid() bool { return 0 < t && t < _MaxOTSType }
+
+func (t OTSType) String() string {
+	if !t.IsValid() {
+		return ErrParam.Error()
+	}
+	return otsNames[t-1]
+}
+
+// IsValid returns true if the parameter set is supported.
+func (t LMSType) IsValid() bool { return LMS_SHA256_M32_H5 <= t && t < _MaxLMSType }
+
+func (t LMSType) String() string {
+	if !t.IsValid() {
+		return ErrParam.Error()
+	}
+	return lmsNames[t-LMS_SHA256_M32_H5]
+}
+
+// Height returns the height of the trees, so an LMS key can produce
+// 2^Height signatures.
+func (t LMSType) Height() int { return int(t.params().h) }
+
+var otsNames = [_MaxOTSType - 1]string{
+	"LMOTS_SHA256_N32_W1", "LMOTS_SHA256_N32_W2", "LMOTS_SHA256_N32_W4", "LMOTS_SHA256_N32_W8",
+	"LMOTS_SHA256_N24_W1", "LMOTS_SHA256_N24_W2", "LMOTS_SHA256_N24_W4", "LMOTS_SHA256_N24_W8",
+	"LMOTS_SHAKE_N32_W1", "LMOTS_SHAKE_N32_W2", "LMOTS_SHAKE_N32_W4", "LMOTS_SHAKE_N32_W8",
+	"LMOTS_SHAKE_N24_W1", "LMOTS_SHAKE_N24_W2", "LMOTS_SHAKE_N24_W4", "LMOTS_SHAKE_N24_W8",
+}
+
+var lmsNames = [_MaxLMSType - LMS_SHA256_M32_H5]string{
+	"LMS_SHA256_M32_H5", "LMS_SHA256_M32_H10", "LMS_SHA256_M32_H15", "LMS_SHA256_M32_H20", "LMS_SHA256_M32_H25",
+	"LMS_SHA256_M24_H5", "LMS_SHA256_M24_H10", "LMS_SHA256_M24_H15", "LMS_SHA256_M24_H20", "LMS_SHA256_M24_H25",
+	"LMS_SHAKE_M32_H5", "LMS_SHAKE_M32_H10", "LMS_SHAKE_M32_H15", "LMS_SHAKE_M32_H20", "LMS_SHAKE_M32_H25",
+	"LMS_SHAKE_M24_H5", "LMS_SHAKE_M24_H10", "LMS_SHAKE_M24_H15", "LMS_SHAKE_M24_H20", "LMS_SHAKE_M24_H25",
+}
+
+// Level is the pair of parameter sets of one level of an HSS key, that is,
+// of its LMS trees and of their one-time keys. Both must use the same hash
+// function and output length.
+type Level struct {
+	LMS LMSType
+	OTS OTSType
+}
+
+// IsValid returns true if both parameter sets are supported and use the
+// same hash function and output length.
+func (l Level) IsValid() bool {
+	if !l.LMS.IsValid() || !l.OTS.IsValid() {
+		return false
+	}
+	lp, op := l.LMS.params(), l.OTS.params()
+	return lp.m == op.n && lp.isSHA2 == op.isSHA2
+}
+
+// otsParams contains the constants of an LM-OTS parameter set, see
+// RFC 8554 -- Section 4.1.
+type otsParams struct {
+	n      uint32 // Length of hash outputs.
+	w      uint32 // Width in bits of the Winternitz coefficients.
+	p      uint32 // Number of hash chains.
+	ls     uint32 // Left shift of the checksum.
+	isSHA2 bool   // True, if the hash function is SHA-256, otherwise is SHAKE256.
+	OTSType
+}
+
+// lmsParams contains the constants of an LMS parameter set, see
+// RFC 8554 -- Section 5.1.
+type lmsParams struct {
+	m      uint32 // Length of hash outputs.
+	h      uint32 // Height of the tree.
+	isSHA2 bool   // True, if the hash function is SHA-256, otherwise is SHAKE256.
+	LMSType
+}
+
+func (t OTSType) params() *otsParams {
+	if !t.IsValid() {
+		panic(ErrParam)
+	}
+
+	i := uint32(t - 1)
+	p := &otsParams{
+		n:       32 - 8*(i/4%2),
+		w:       1 << (i % 4),
+		isSHA2:  i < 8,
+		OTSType: t,
+	}
+
+	// See RFC 8554 -- Appendix B.
+	u := (8*p.n + p.w - 1) / p.w
+	bits := uint32(0)
+	for x := ((uint32(1) << p.w) - 1) * u; x > 0; x >>= 1 {
+		bits++
+	}
+	v := (bits + p.w - 1) / p.w
+	p.p = u + v
+	p.ls = 16 - v*p.w
+	return p
+}
+
+func (t LMSType) params() *lmsParams {
+	if !t.IsValid() {
+		panic(ErrParam)
+	}
+
+	i := uint32(t - LMS_SHA256_M32_H5)
+	return &lmsParams{
+		m:       32 - 8*(i/5%2),
+		h:       5 * (i%5 + 1),
+		isSHA2:  i < 10,
+		LMSType: t,
+	}
+}
+
+// otsSigSize is the length of LM-OTS signatures, see RFC 8554 -- Section 4.5.
+func (p *otsParams) sigSize() int { return int(4 + p.n*(p.p+1)) }
+
+// lmsPubSize is the length of LMS public keys, see RFC 8554 -- Section 5.3.
+func (p *lmsParams) pubSize() int { return int(8 + idSize + p.m) }
+
+// sigSize is the length of LMS signatures with one-time signatures of the
+// parameter set given, see RFC 8554 -- Section 5.4.
+func (p *lmsParams) sigSize(ots *otsParams) int {
+	return int(8+p.h*p.m) + ots.sigSize()
+}
+
+// hasher computes the hash function of a parameter set, which is SHA-256
+// or SHAKE256, truncated to n bytes. It reuses its buffers, so it is not
+// safe for concurrent use.
+type hasher struct {
+	n      uint32
+	isSHA2 bool
+	sha    hash.Hash
+	shake  sha3.State
+	digest [sha256.Size]byte
+}
+
+func newHasher(n uint32, isSHA2 bool) *hasher {
+	h := &hasher{n: n, isSHA2: isSHA2}
+	if isSHA2 {
+		h.sha = sha256.New()
+	} else {
+		h.shake = sha3.NewShake256()
+	}
+	return h
+}
+
+// sum sets out to the hash of the concatenation of the inputs.
+func (h *hasher) sum(out []byte, in ...[]byte) {
+	if h.isSHA2 {
+		h.sha.Reset()
+		for _, x := range in {
+			_, _ = h.sha.Write(x)
+		}
+		h.sha.Sum(h.digest[:0])
+		copy(out[:h.n], h.digest[:])
+	} else {
+		h.shake.Reset()
+		for _, x := range in {
+			_, _ = h.shake.Write(x)
+		}
+		_, _ = h.shake.Read(out[:h.n])
+	}
+}
@@ -0,0 +1,154 @@
+package lms
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+
+	"github.com/quantumcoinproject/circl/sign"
+)
+
+// defaultLevels are the levels of the keys generated by [Scheme], two
+// levels of LMS_SHA256_M32_H10 with LMOTS_SHA256_N32_W4, so keys can
+// produce 2^20 signatures.
+var defaultLevels = []Level{
+	{LMS_SHA256_M32_H10, LMOTS_SHA256_N32_W4},
+	{LMS_SHA256_M32_H10, LMOTS_SHA256_N32_W4},
+}
+
+// Scheme returns the HSS signature scheme. It verifies signatures and
+// unmarshals keys of any levels, and generates keys with the default levels.
+//
+// Keys generated, derived or unmarshaled by the scheme have no store of
+// their state, and sign only after one that persists it is set with
+// [PrivateKey.SetStore]. Until then, signing with the scheme returns an
+// empty slice.
+func Scheme() sign.Scheme { return sch }
+
+var sch sign.Scheme = &scheme{}
+
+type scheme struct{}
+
+func (*scheme) Name() string          { return "HSS-LMS" }
+func (*scheme) SupportsContext() bool { return false }
+
+// PublicKeySize is the size of public keys whose top level has 32-byte
+// hashes, which is the maximum.
+func (*scheme) PublicKeySize() int {
+	return 4 + defaultLevels[0].LMS.params().pubSize()
+}
+
+// PrivateKeySize is the size of the private keys with the default levels.
+func (*scheme) PrivateKeySize() int {
+	return 4 + 8*len(defaultLevels) + idSize + int(defaultLevels[0].LMS.params().m)
+}
+
+// SignatureSize is the maximum size of signatures of keys with any levels.
+// It is reached with eight levels of LMOTS_SHA256_N32_W1, whose heights
+// sum to 64.
+func (*scheme) SignatureSize() int {
+	ots := LMOTS_SHA256_N32_W1.params()
+	n := int(ots.n)
+	return 4 + MaxLevels*(8+ots.sigSize()) + maxHeight*n +
+		(MaxLevels-1)*LMS_SHA256_M32_H5.params().pubSize()
+}
+
+// SeedSize is the size of the seeds of keys with the default levels.
+func (*scheme) SeedSize() int {
+	return idSize + int(defaultLevels[0].LMS.params().m)
+}
+
+// Oid returns the id-alg-hss-lms-hashsig object identifier, see RFC 8708.
+func (*scheme) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 3, 17}
+}
+
+// GenerateKey is similar to [GenerateKey] function, with the default levels,
+// except it always reads random bytes from [rand.Reader]. The private key
+// has no store of its state.
+func (*scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	pub, priv, err := GenerateKey(rand.Reader, defaultLevels...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pub, priv, nil
+}
+
+// Sign returns the signature of the message.
+// It returns an empty slice if the signature generation fails, which
+// happens if the key has no store, if the reservation of an index fails,
+// or if the key has no signatures left.
+//
+// Panics if the key is not a [PrivateKey], or if a context is given.
+func (*scheme) Sign(
+	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
+) []byte {
+	k, ok := priv.(*PrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if options != nil && options.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+
+	sig, err := Sign(k, message)
+	if err != nil {
+		return nil
+	}
+
+	return sig
+}
+
+// Verify returns true if the signature of the message is valid.
+//
+// Panics if the key is not a [PublicKey], or if a context is given.
+func (*scheme) Verify(
+	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
+) bool {
+	k, ok := pub.(*PublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if options != nil && options.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+
+	return Verify(k, message, signature)
+}
+
+// DeriveKey deterministically generates a pair of keys with
+// the default levels from a seed. The private key has no store of its state.
+//
+// Panics if seed is not of length [sign.Scheme.SeedSize].
+func (s *scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != s.SeedSize() {
+		panic(sign.ErrSeedSize)
+	}
+
+	pub, priv, err := NewKeyFromSeed(seed, defaultLevels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return pub, priv
+}
+
+func (*scheme) UnmarshalBinaryPublicKey(b []byte) (sign.PublicKey, error) {
+	k := new(PublicKey)
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (*scheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
+	k := new(PrivateKey)
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,271 @@
+package lms
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// See RFC 8554 -- Section 5
+// Leighton-Micali Signatures
+
+// lmsKey is an LMS private key, given by its identifier I and seed SEED.
+type lmsKey struct {
+	Level
+	id, seed []byte
+	cache    treeCache
+}
+
+func newLMSKey(l Level, id, seed []byte) *lmsKey {
+	return &lmsKey{Level: l, id: id, seed: seed}
+}
+
+func (k *lmsKey) hasher() *hasher {
+	p := k.LMS.params()
+	return newHasher(p.m, p.isSHA2)
+}
+
+func (k *lmsKey) otsKey(h *hasher, q uint32) *otsKey {
+	return &otsKey{otsParams: k.OTS.params(), h: h, id: k.id, q: q, seed: k.seed}
+}
+
+// leafHash sets out to H(I || u32str(r) || u16str(D_LEAF) || K).
+func leafHash(h *hasher, id []byte, r uint32, out, pk []byte) {
+	var b [idSize + 6]byte
+	copy(b[:], id)
+	binary.BigEndian.PutUint32(b[idSize:], r)
+	binary.BigEndian.PutUint16(b[idSize+4:], dLEAF)
+	h.sum(out, b[:], pk)
+}
+
+// nodeHash sets out to H(I || u32str(r) || u16str(D_INTR) || left || right).
+// Note that out can alias left or right.
+func nodeHash(h *hasher, id []byte, r uint32, out, left, right []byte) {
+	var b [idSize + 6]byte
+	copy(b[:], id)
+	binary.BigEndian.PutUint32(b[idSize:], r)
+	binary.BigEndian.PutUint16(b[idSize+4:], dINTR)
+	h.sum(out, b[:], left, right)
+}
+
+type item struct {
+	node []byte
+	z    uint32
+}
+
+// treeHash sets out to the node at height z and index i from the left,
+// computing the leaves from the left to the right with a stack. Its node
+// number is r = 2^(h-z) + i, see RFC 8554 -- Section 5.3. If save is not
+// nil, it is called with every node computed.
+func (k *lmsKey) treeHash(
+	h *hasher, out []byte, i, z uint32, save func(i, z uint32, node []byte),
+) {
+	p := k.LMS.params()
+	if !(z <= p.h && i < (1<<(p.h-z))) {
+		panic(ErrTree)
+	}
+
+	stack := make([]item, 0, z+1)
+	pk := make([]byte, p.m)
+	for j := range uint32(1) << z {
+		li := i<<z + j
+		lz := uint32(0)
+		node := make([]byte, p.m)
+		k.otsKey(h, li).publicKey(pk)
+		leafHash(h, k.id, 1<<p.h+li, node, pk)
+		if save != nil {
+			save(li, lz, node)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].z == lz {
+			left := stack[len(stack)-1].node
+			stack = stack[:len(stack)-1]
+
+			li = li >> 1
+			lz = lz + 1
+			nodeHash(h, k.id, 1<<(p.h-lz)+li, node, left, node)
+			if save != nil {
+				save(li, lz, node)
+			}
+		}
+
+		stack = append(stack, item{node, lz})
+	}
+
+	copy(out, stack[0].node)
+}
+
+// publicKey returns the encoding of the public key, which is
+// u32str(type) || u32str(otstype) || I || T[1].
+func (k *lmsKey) publicKey() []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(k.LMS))
+	b = binary.BigEndian.AppendUint32(b, uint32(k.OTS))
+	b = append(b, k.id...)
+	return append(b, k.cache.root()...)
+}
+
+// See RFC 8554 -- Section 5.4.1.
+// It returns u32str(q) || lmots_signature || u32str(type) || path, where
+// the cache must have been updated for the q-th leaf.
+func (k *lmsKey) sign(h *hasher, q uint32, msg []byte) []byte {
+	p, op := k.LMS.params(), k.OTS.params()
+	sig := make([]byte, p.sigSize(op))
+	binary.BigEndian.PutUint32(sig, q)
+	otsSig := sig[4 : 4+op.sigSize()]
+	k.otsKey(h, q).sign(otsSig, msg)
+
+	rest := sig[4+op.sigSize():]
+	binary.BigEndian.PutUint32(rest, uint32(k.LMS))
+	k.cache.authPath(rest[4:], p.m, q)
+	return sig
+}
+
+// lmsVerify returns true if sig is a valid LMS signature of msg under the
+// encoded public key pub, see RFC 8554 -- Section 5.4.2 -- Algorithm 6a.
+func lmsVerify(pub, msg, sig []byte) bool {
+	l, ok := parsePublicKey(pub)
+	if !ok {
+		return false
+	}
+	p, op := l.LMS.params(), l.OTS.params()
+	if len(sig) != p.sigSize(op) {
+		return false
+	}
+
+	q := binary.BigEndian.Uint32(sig)
+	otsSig := sig[4 : 4+op.sigSize()]
+	rest := sig[4+op.sigSize():]
+	if OTSType(binary.BigEndian.Uint32(otsSig)) != l.OTS ||
+		LMSType(binary.BigEndian.Uint32(rest)) != l.LMS ||
+		q>>p.h != 0 {
+		return false
+	}
+
+	// See RFC 8554 -- Section 5.4.2 -- Algorithm 6b.
+	id := pub[8 : 8+idSize]
+	h := newHasher(p.m, p.isSHA2)
+	node := make([]byte, p.m)
+	op.publicKeyFromSig(h, id, q, node, otsSig, msg)
+
+	r := 1<<p.h + q
+	leafHash(h, id, r, node, node)
+	path := rest[4:]
+	for i := uint32(0); r > 1; i, r = i+1, r>>1 {
+		sibling := path[i*p.m : (i+1)*p.m]
+		if r&1 == 1 {
+			nodeHash(h, id, r>>1, node, sibling, node)
+		} else {
+			nodeHash(h, id, r>>1, node, node, sibling)
+		}
+	}
+
+	return bytes.Equal(node, pub[8+idSize:])
+}
+
+// lmsSigSize returns the length of the LMS signature at the start of sig,
+// given by its typecodes, or zero if they are not supported or sig is
+// shorter.
+func lmsSigSize(sig []byte) int {
+	if len(sig) < 8 || !OTSType(binary.BigEndian.Uint32(sig[4:])).IsValid() {
+		return 0
+	}
+	op := OTSType(binary.BigEndian.Uint32(sig[4:])).params()
+
+	off := 4 + op.sigSize()
+	if len(sig) < off+4 || !LMSType(binary.BigEndian.Uint32(sig[off:])).IsValid() {
+		return 0
+	}
+	size := LMSType(binary.BigEndian.Uint32(sig[off:])).params().sigSize(op)
+	if len(sig) < size {
+		return 0
+	}
+	return size
+}
+
+// parsePublicKey returns the parameter sets of an encoded LMS public key,
+// and whether its length matches them.
+func parsePublicKey(pub []byte) (l Level, ok bool) {
+	if len(pub) < 8 {
+		return l, false
+	}
+	l.LMS = LMSType(binary.BigEndian.Uint32(pub))
+	l.OTS = OTSType(binary.BigEndian.Uint32(pub[4:]))
+	return l, l.IsValid() && len(pub) == l.LMS.params().pubSize()
+}
+
+// treeCache stores the nodes of an LMS tree needed to compute the
+// authentication paths of its leaves. With s = h/2, it keeps the nodes at
+// heights s and above, and the nodes of the subtree of height s containing
+// the current leaf. So, signing with the leaves in order computes each leaf
+// twice, taking 2^(s+1) + 2^(h-s+1) nodes of memory.
+type treeCache struct {
+	ok     bool
+	top    [][]byte // top[z-s] has the nodes at height z >= s.
+	low    uint32   // Index of the subtree of height s in bottom.
+	bottom [][]byte // bottom[z] has the nodes at height z < s of the subtree.
+}
+
+// update prepares the cache of the key to compute the authentication path
+// of the q-th leaf.
+func (k *lmsKey) update(h *hasher, q uint32) {
+	c := &k.cache
+	p := k.LMS.params()
+	s := p.h / 2
+	n := p.m
+	low := q >> s
+
+	saveBottom := func(i, z uint32, node []byte) {
+		if z < s {
+			copy(c.bottom[z][(i-low<<(s-z))*n:], node[:n])
+		}
+	}
+
+	if !c.ok {
+		c.top = make([][]byte, p.h-s+1)
+		for z := range c.top {
+			c.top[z] = make([]byte, n<<(p.h-s-uint32(z)))
+		}
+		c.bottom = make([][]byte, s)
+		for z := range c.bottom {
+			c.bottom[z] = make([]byte, n<<(s-uint32(z)))
+		}
+
+		for j := range uint32(1) << (p.h - s) {
+			var save func(i, z uint32, node []byte)
+			if j == low {
+				save = saveBottom
+			}
+			k.treeHash(h, c.top[0][j*n:(j+1)*n], j, s, save)
+		}
+
+		for z := uint32(1); z < uint32(len(c.top)); z++ {
+			for i := range uint32(1) << (p.h - s - z) {
+				children := c.top[z-1][2*i*n:]
+				r := uint32(1)<<(p.h-s-z) + i
+				nodeHash(h, k.id, r, c.top[z][i*n:(i+1)*n], children[:n], children[n:2*n])
+			}
+		}
+
+		c.low = low
+		c.ok = true
+	} else if c.low != low {
+		k.treeHash(h, make([]byte, n), low, s, saveBottom)
+		c.low = low
+	}
+}
+
+// root returns the root of the cached tree.
+func (c *treeCache) root() []byte { return c.top[len(c.top)-1] }
+
+// authPath sets out to the authentication path of the leaf, which must be
+// in the subtree prepared by update.
+func (c *treeCache) authPath(out []byte, n, leaf uint32) {
+	s := uint32(len(c.bottom))
+	for z := range s {
+		sibling := ((leaf >> z) ^ 1) - c.low<<(s-z)
+		copy(out[z*n:], c.bottom[z][sibling*n:(sibling+1)*n])
+	}
+	for z := range uint32(len(c.top) - 1) {
+		sibling := (leaf >> (s + z)) ^ 1
+		copy(out[(s+z)*n:], c.top[z][sibling*n:(sibling+1)*n])
+	}
+}
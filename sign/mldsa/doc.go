@@ -5,6 +5,17 @@
 //
 //	github.com/quantumcoinproject/circl/sign/mldsa/mldsa44
 //
+// The pre-hash variant HashML-DSA is available in each subpackage through
+// SignPreHashTo and VerifyPreHash, with the hash functions of [PreHash],
+// and as a separate scheme with SHA-512 through HashScheme, the only
+// variant with a registered object identifier. The HashPrivateKey of that
+// scheme implements crypto.Signer and signs digests of any [PreHash]; the
+// private keys of pure ML-DSA refuse to sign digests.
+//
+// The message representative μ can be computed apart from the signer, for
+// instance by a front-end which does not hold the private key, with
+// ComputeMu or ComputePreHashMu, and then signed with SignMuTo.
+//
 // If your choice for mode is fixed compile-time, use the subpackages.
 // To choose a scheme at runtime, use the generic signatures API under
 //
@@ -10,6 +10,9 @@ import (
 	"io"
 	"os"
 	"testing"
+
+	"github.com/quantumcoinproject/circl/sign/mldsa"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa44/internal"
 )
 
 // []byte but is encoded in hex for JSON
@@ -45,6 +48,22 @@ func readGzip(path string) ([]byte, error) {
 	return gunzip(buf)
 }
 
+// Hash functions of the ACVP test groups of HashML-DSA.
+var acvpPreHash = map[string]mldsa.PreHash{
+	"SHA2-224":     mldsa.SHA224,
+	"SHA2-256":     mldsa.SHA256,
+	"SHA2-384":     mldsa.SHA384,
+	"SHA2-512":     mldsa.SHA512,
+	"SHA2-512/224": mldsa.SHA512_224,
+	"SHA2-512/256": mldsa.SHA512_256,
+	"SHA3-224":     mldsa.SHA3_224,
+	"SHA3-256":     mldsa.SHA3_256,
+	"SHA3-384":     mldsa.SHA3_384,
+	"SHA3-512":     mldsa.SHA3_512,
+	"SHAKE-128":    mldsa.SHAKE128,
+	"SHAKE-256":    mldsa.SHAKE256,
+}
+
 func TestACVP(t *testing.T) {
 	for _, sub := range []string{
 		"keyGen",
@@ -168,14 +187,20 @@ func testACVP(t *testing.T, sub string) {
 			}
 		case abstractGroup.TestType == "AFT" && sub == "sigGen":
 			var group struct {
-				TgID          int    `json:"tgId"`
-				ParameterSet  string `json:"parameterSet"`
-				Deterministic bool   `json:"deterministic"`
-				Tests         []struct {
+				TgID               int    `json:"tgId"`
+				ParameterSet       string `json:"parameterSet"`
+				Deterministic      bool   `json:"deterministic"`
+				SignatureInterface string `json:"signatureInterface"`
+				PreHash            string `json:"preHash"`
+				ExternalMu         bool   `json:"externalMu"`
+				Tests              []struct {
 					TcID    int      `json:"tcId"`
 					Sk      HexBytes `json:"sk"`
 					Message HexBytes `json:"message"`
+					Mu      HexBytes `json:"mu"`
 					Rnd     HexBytes `json:"rnd"`
+					Context HexBytes `json:"context"`
+					HashAlg string   `json:"hashAlg"`
 				}
 			}
 			if err := json.Unmarshal(rawGroup, &group); err != nil {
@@ -208,7 +233,43 @@ func testACVP(t *testing.T, sub string) {
 					copy(rnd[:], test.Rnd)
 				}
 
-				sig2 := sk.(*PrivateKey).unsafeSignInternal(test.Message, rnd)
+				priv := sk.(*PrivateKey)
+				pub := priv.Public().(*PublicKey)
+
+				var sig2 []byte
+				switch {
+				case group.ExternalMu:
+					var mu [MuSize]byte
+					copy(mu[:], test.Mu)
+					sig2 = make([]byte, SignatureSize)
+					internal.SignMuTo((*internal.PrivateKey)(priv), &mu, rnd, sig2)
+				case group.SignatureInterface != "external":
+					sig2 = priv.unsafeSignInternal(test.Message, rnd)
+
+					// Signing μ computed apart gives the same signature.
+					mu := unsafeComputeMuInternal(pub, test.Message)
+					sig3 := make([]byte, SignatureSize)
+					internal.SignMuTo((*internal.PrivateKey)(priv), &mu, rnd, sig3)
+					if !bytes.Equal(sig3, result.Signature) {
+						t.Fatalf("signature of mu doesn't match: %x ≠ %x",
+							sig3, result.Signature)
+					}
+				case group.PreHash == "preHash":
+					ph, ok := acvpPreHash[test.HashAlg]
+					if !ok {
+						t.Fatalf("unknown hash function %s", test.HashAlg)
+					}
+					sig2 = make([]byte, SignatureSize)
+					err = signPreHash(priv, ph,
+						ph.Sum(test.Message), test.Context, rnd, sig2)
+					if err != nil {
+						t.Fatal(err)
+					}
+				default:
+					msg := append([]byte{0, byte(len(test.Context))}, test.Context...)
+					msg = append(msg, test.Message...)
+					sig2 = priv.unsafeSignInternal(msg, rnd)
+				}
 
 				if !bytes.Equal(sig2, result.Signature) {
 					t.Fatalf("signature doesn't match: %x ≠ %x",
@@ -217,13 +278,20 @@ func testACVP(t *testing.T, sub string) {
 			}
 		case abstractGroup.TestType == "AFT" && sub == "sigVer":
 			var group struct {
-				TgID         int      `json:"tgId"`
-				ParameterSet string   `json:"parameterSet"`
-				Pk           HexBytes `json:"pk"`
-				Tests        []struct {
+				TgID               int      `json:"tgId"`
+				ParameterSet       string   `json:"parameterSet"`
+				Pk                 HexBytes `json:"pk"`
+				SignatureInterface string   `json:"signatureInterface"`
+				PreHash            string   `json:"preHash"`
+				ExternalMu         bool     `json:"externalMu"`
+				Tests              []struct {
 					TcID      int      `json:"tcId"`
+					Pk        HexBytes `json:"pk"`
 					Message   HexBytes `json:"message"`
+					Mu        HexBytes `json:"mu"`
 					Signature HexBytes `json:"signature"`
+					Context   HexBytes `json:"context"`
+					HashAlg   string   `json:"hashAlg"`
 				}
 			}
 			if err := json.Unmarshal(rawGroup, &group); err != nil {
@@ -234,11 +302,6 @@ func testACVP(t *testing.T, sub string) {
 				continue
 			}
 
-			pk, err := scheme.UnmarshalBinaryPublicKey(group.Pk)
-			if err != nil {
-				t.Fatal(err)
-			}
-
 			for _, test := range group.Tests {
 				var result struct {
 					TestPassed bool `json:"testPassed"`
@@ -251,7 +314,40 @@ func testACVP(t *testing.T, sub string) {
 					t.Fatal(err)
 				}
 
-				passed2 := unsafeVerifyInternal(pk.(*PublicKey), test.Message, test.Signature)
+				// Newer test vectors have a public key per test.
+				rawPk := group.Pk
+				if test.Pk != nil {
+					rawPk = test.Pk
+				}
+				pk, err := scheme.UnmarshalBinaryPublicKey(rawPk)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pub := pk.(*PublicKey)
+
+				var passed2 bool
+				switch {
+				case group.ExternalMu:
+					var mu [MuSize]byte
+					copy(mu[:], test.Mu)
+					passed2 = VerifyMu(pub, &mu, test.Signature)
+				case group.SignatureInterface != "external":
+					passed2 = unsafeVerifyInternal(pub, test.Message, test.Signature)
+
+					mu := unsafeComputeMuInternal(pub, test.Message)
+					if VerifyMu(pub, &mu, test.Signature) != passed2 {
+						t.Fatalf("verification of mu ≠ %v", passed2)
+					}
+				case group.PreHash == "preHash":
+					ph, ok := acvpPreHash[test.HashAlg]
+					if !ok {
+						t.Fatalf("unknown hash function %s", test.HashAlg)
+					}
+					passed2 = VerifyPreHash(pub, ph, ph.Sum(test.Message),
+						test.Context, test.Signature)
+				default:
+					passed2 = Verify(pub, test.Message, test.Context, test.Signature)
+				}
 				if passed2 != result.TestPassed {
 					t.Fatalf("verification %v ≠ %v", passed2, result.TestPassed)
 				}
@@ -261,3 +357,139 @@ func testACVP(t *testing.T, sub string) {
 		}
 	}
 }
+
+// Test vectors from https://github.com/C2SP/wycheproof, in testvectors_v1.
+// They cover the external interface, with contexts, and signatures of μ
+// computed apart, both deterministic and randomized.
+func TestWycheproof(t *testing.T) {
+	t.Run("sign", testWycheproofSign)
+	t.Run("verify", testWycheproofVerify)
+}
+
+func testWycheproofSign(t *testing.T) {
+	buf, err := readGzip("../testdata/wycheproof/mldsa_44_sign_seed_test.json.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kat struct {
+		TestGroups []struct {
+			PrivateSeed HexBytes `json:"privateSeed"`
+			PublicKey   HexBytes `json:"publicKey"`
+			Tests       []struct {
+				TcID   int       `json:"tcId"`
+				Msg    *HexBytes `json:"msg"`
+				Mu     HexBytes  `json:"mu"`
+				Ctx    HexBytes  `json:"ctx"`
+				Rnd    HexBytes  `json:"rnd"`
+				Sig    HexBytes  `json:"sig"`
+				Result string    `json:"result"`
+			} `json:"tests"`
+		} `json:"testGroups"`
+	}
+	if err = json.Unmarshal(buf, &kat); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range kat.TestGroups {
+		// Seeds of the wrong size cannot be passed to NewKeyFromSeed.
+		if len(group.PrivateSeed) != SeedSize {
+			continue
+		}
+
+		var seed [SeedSize]byte
+		copy(seed[:], group.PrivateSeed)
+		pk, sk := NewKeyFromSeed(&seed)
+		if !bytes.Equal(pk.Bytes(), group.PublicKey) {
+			t.Fatal("public key does not match")
+		}
+
+		for _, test := range group.Tests {
+			var rnd [32]byte
+			copy(rnd[:], test.Rnd)
+			valid := test.Result == "valid"
+
+			mu, err := [MuSize]byte{}, error(nil)
+			if test.Msg != nil {
+				mu, err = ComputeMu(pk, *test.Msg, test.Ctx)
+				if err != nil {
+					if valid {
+						t.Fatalf("tc=%d: %v", test.TcID, err)
+					}
+					continue
+				}
+				if test.Mu != nil && !bytes.Equal(mu[:], test.Mu) {
+					t.Fatalf("tc=%d: mu does not match", test.TcID)
+				}
+			} else {
+				copy(mu[:], test.Mu)
+			}
+			if !valid {
+				t.Fatalf("tc=%d: invalid test signed", test.TcID)
+			}
+
+			sig := make([]byte, SignatureSize)
+			internal.SignMuTo((*internal.PrivateKey)(sk), &mu, rnd, sig)
+			if !bytes.Equal(sig, test.Sig) {
+				t.Fatalf("tc=%d: signature doesn't match: %x ≠ %x",
+					test.TcID, sig, test.Sig)
+			}
+
+			if test.Rnd == nil {
+				// The same deterministic signature through the public API.
+				if test.Msg != nil {
+					err = SignTo(sk, *test.Msg, test.Ctx, false, sig)
+				} else {
+					err = SignMuTo(sk, &mu, false, sig)
+				}
+				if err != nil || !bytes.Equal(sig, test.Sig) {
+					t.Fatalf("tc=%d: deterministic signature doesn't match", test.TcID)
+				}
+			}
+			if !VerifyMu(pk, &mu, test.Sig) {
+				t.Fatalf("tc=%d: verification of mu failed", test.TcID)
+			}
+		}
+	}
+}
+
+func testWycheproofVerify(t *testing.T) {
+	buf, err := readGzip("../testdata/wycheproof/mldsa_44_verify_test.json.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kat struct {
+		TestGroups []struct {
+			PublicKey HexBytes `json:"publicKey"`
+			Tests     []struct {
+				TcID   int      `json:"tcId"`
+				Msg    HexBytes `json:"msg"`
+				Ctx    HexBytes `json:"ctx"`
+				Sig    HexBytes `json:"sig"`
+				Result string   `json:"result"`
+			} `json:"tests"`
+		} `json:"testGroups"`
+	}
+	if err = json.Unmarshal(buf, &kat); err != nil {
+		t.Fatal(err)
+	}
+
+	scheme := Scheme()
+	for _, group := range kat.TestGroups {
+		pk, err := scheme.UnmarshalBinaryPublicKey(group.PublicKey)
+
+		for _, test := range group.Tests {
+			valid := test.Result == "valid"
+			if err != nil {
+				if valid {
+					t.Fatalf("tc=%d: %v", test.TcID, err)
+				}
+				continue
+			}
+			if got := Verify(pk.(*PublicKey), test.Msg, test.Ctx, test.Sig); got != valid {
+				t.Fatalf("tc=%d: verification %v ≠ %v", test.TcID, got, valid)
+			}
+		}
+	}
+}
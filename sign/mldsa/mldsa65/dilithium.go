@@ -12,6 +12,7 @@ import (
 
 	"github.com/quantumcoinproject/circl/sign"
 	common "github.com/quantumcoinproject/circl/sign/internal/dilithium"
+	"github.com/quantumcoinproject/circl/sign/mldsa"
 	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa65/internal"
 )
 
@@ -154,6 +155,164 @@ func Verify(pk *PublicKey, msg, ctx, sig []byte) bool {
 	)
 }
 
+// SignPreHashTo signs digest, the hash of a message with ph, using
+// HashML-DSA and writes the signature into sig.
+// It will panic if sig is not of length at least SignatureSize.
+//
+// ctx is the optional context string. Errors if ctx is larger than 255 bytes,
+// if ph is not supported or if digest is not of length ph.Size().
+func SignPreHashTo(
+	sk *PrivateKey,
+	ph mldsa.PreHash,
+	digest, ctx []byte,
+	randomized bool,
+	sig []byte,
+) error {
+	var rnd [32]byte
+	if randomized {
+		_, err := cryptoRand.Read(rnd[:])
+		if err != nil {
+			return err
+		}
+	}
+	return signPreHash(sk, ph, digest, ctx, rnd, sig)
+}
+
+func signPreHash(
+	sk *PrivateKey,
+	ph mldsa.PreHash,
+	digest, ctx []byte,
+	rnd [32]byte,
+	sig []byte,
+) error {
+	if len(ctx) > 255 {
+		return sign.ErrContextTooLong
+	}
+	if !ph.Valid() {
+		return mldsa.ErrPreHash
+	}
+	if len(digest) != ph.Size() {
+		return mldsa.ErrDigestSize
+	}
+
+	internal.SignTo(
+		(*internal.PrivateKey)(sk),
+		preHashMessage(ph, digest, ctx),
+		rnd,
+		sig,
+	)
+	return nil
+}
+
+// VerifyPreHash checks whether the given HashML-DSA signature by pk on
+// digest, the hash of a message with ph, is valid.
+//
+// ctx is the optional context string. Fails if ctx is larger than 255 bytes.
+// A nil context string is equivalent to an empty context string.
+func VerifyPreHash(pk *PublicKey, ph mldsa.PreHash, digest, ctx, sig []byte) bool {
+	if len(ctx) > 255 || !ph.Valid() || len(digest) != ph.Size() {
+		return false
+	}
+	return internal.Verify(
+		(*internal.PublicKey)(pk),
+		preHashMessage(ph, digest, ctx),
+		sig,
+	)
+}
+
+// Writes the message M' signed by HashML-DSA, see FIPS 204 -- Algorithm 4.
+func preHashMessage(ph mldsa.PreHash, digest, ctx []byte) func(io.Writer) {
+	return func(w io.Writer) {
+		_, _ = w.Write([]byte{1})
+		_, _ = w.Write([]byte{byte(len(ctx))})
+		_, _ = w.Write(ctx)
+		_, _ = w.Write(ph.OID())
+		_, _ = w.Write(digest)
+	}
+}
+
+// MuSize is the size of μ, the representative of a message and its context
+// which is signed by ML-DSA.
+const MuSize = 64
+
+// ComputeMu computes μ, the representative of msg with context ctx, to be
+// signed with SignMuTo by the private key of pk. This allows hashing the
+// message apart from the signer, see FIPS 204 -- Section 6.2.
+//
+// ctx is the optional context string. Errors if ctx is larger than 255 bytes.
+// A nil context string is equivalent to an empty context string.
+func ComputeMu(pk *PublicKey, msg, ctx []byte) (mu [MuSize]byte, err error) {
+	if len(ctx) > 255 {
+		return mu, sign.ErrContextTooLong
+	}
+	(*internal.PublicKey)(pk).ComputeMu(
+		func(w io.Writer) {
+			_, _ = w.Write([]byte{0})
+			_, _ = w.Write([]byte{byte(len(ctx))})
+			_, _ = w.Write(ctx)
+			_, _ = w.Write(msg)
+		},
+		&mu,
+	)
+	return mu, nil
+}
+
+// ComputePreHashMu is as ComputeMu for HashML-DSA, where digest is the
+// hash of the message with ph.
+//
+// Errors if ctx is larger than 255 bytes, if ph is not supported or if
+// digest is not of length ph.Size().
+func ComputePreHashMu(
+	pk *PublicKey,
+	ph mldsa.PreHash,
+	digest, ctx []byte,
+) (mu [MuSize]byte, err error) {
+	if len(ctx) > 255 {
+		return mu, sign.ErrContextTooLong
+	}
+	if !ph.Valid() {
+		return mu, mldsa.ErrPreHash
+	}
+	if len(digest) != ph.Size() {
+		return mu, mldsa.ErrDigestSize
+	}
+	(*internal.PublicKey)(pk).ComputeMu(preHashMessage(ph, digest, ctx), &mu)
+	return mu, nil
+}
+
+// SignMuTo signs μ, as computed by ComputeMu or ComputePreHashMu for the
+// public key of sk, and writes the signature into sig.
+// It will panic if sig is not of length at least SignatureSize.
+func SignMuTo(sk *PrivateKey, mu *[MuSize]byte, randomized bool, sig []byte) error {
+	var rnd [32]byte
+	if randomized {
+		_, err := cryptoRand.Read(rnd[:])
+		if err != nil {
+			return err
+		}
+	}
+	internal.SignMuTo((*internal.PrivateKey)(sk), mu, rnd, sig)
+	return nil
+}
+
+// VerifyMu checks whether the given signature by pk on μ, as computed by
+// ComputeMu or ComputePreHashMu, is valid.
+func VerifyMu(pk *PublicKey, mu *[MuSize]byte, sig []byte) bool {
+	return internal.VerifyMu((*internal.PublicKey)(pk), mu, sig)
+}
+
+// Do not use. Computes μ of the message of ML-DSA.Sign_internal used for
+// compatibility tests.
+func unsafeComputeMuInternal(pk *PublicKey, msg []byte) (mu [MuSize]byte) {
+	(*internal.PublicKey)(pk).ComputeMu(
+		func(w io.Writer) {
+			_, _ = w.Write(msg)
+		},
+		&mu,
+	)
+	return mu
+}
+
 // Sets pk to the public key encoded in buf.
 func (pk *PublicKey) Unpack(buf *[PublicKeySize]byte) {
 	(*internal.PublicKey)(pk).Unpack(buf)
@@ -226,6 +385,9 @@ func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
 // crypto.Hash(0) for opts.  rand is ignored.  Will only return an error
 // if opts.HashFunc() is non-zero.
 //
+// Digests of messages are signed with HashML-DSA by HashPrivateKey, or
+// with SignPreHashTo.
+//
 // This function is used to make PrivateKey implement the crypto.Signer
 // interface.  The package-level SignTo function might be more convenient
 // to use.
@@ -233,7 +395,7 @@ func (sk *PrivateKey) Sign(rand io.Reader, msg []byte, opts crypto.SignerOpts) (
 	sig []byte, err error) {
 	var ret [SignatureSize]byte
 
-	if opts.HashFunc() != crypto.Hash(0) {
+	if _, ok := opts.(mldsa.PreHash); ok || opts.HashFunc() != crypto.Hash(0) {
 		return nil, errors.New("dilithium: cannot sign hashed message")
 	}
 	if err = SignTo(sk, msg, nil, false, ret[:]); err != nil {
@@ -382,3 +544,172 @@ func (sk *PrivateKey) Scheme() sign.Scheme {
 func (sk *PublicKey) Scheme() sign.Scheme {
 	return sch
 }
+
+// HashPublicKey is the type of HashML-DSA-65 public key: a ML-DSA-65
+// public key for signatures of messages pre-hashed with SHA-512.
+type HashPublicKey struct{ PublicKey }
+
+// HashPrivateKey is the type of HashML-DSA-65 private key: a ML-DSA-65
+// private key for signatures of messages pre-hashed with SHA-512.
+type HashPrivateKey struct{ PrivateKey }
+
+// Sign signs the given message with HashML-DSA.
+//
+// If opts.HashFunc() is zero, msg is hashed with SHA-512, as in
+// HashML-DSA-65.  Otherwise, msg must be the digest of the message with
+// the pre-hash function given by opts: either a crypto.Hash supported by
+// mldsa.PreHashFor, such as crypto.SHA256 or crypto.SHA512, or an
+// mldsa.PreHash, such as mldsa.SHAKE128, which has no crypto.Hash.
+// rand is ignored.
+//
+// Only the SHA-512 variant has a registered object identifier and can be
+// verified with HashScheme.  Signatures with other pre-hash functions
+// must be verified with VerifyPreHash.
+//
+// This function is used to make HashPrivateKey implement the crypto.Signer
+// interface.  The package-level SignPreHashTo function might be more
+// convenient to use.
+func (sk *HashPrivateKey) Sign(rand io.Reader, msg []byte, opts crypto.SignerOpts) (
+	sig []byte, err error) {
+	var ret [SignatureSize]byte
+
+	ph, digest := mldsa.SHA512, msg
+	if o, ok := opts.(mldsa.PreHash); ok {
+		ph = o
+	} else if h := opts.HashFunc(); h == crypto.Hash(0) {
+		digest = mldsa.SHA512.Sum(msg)
+	} else if ph, err = mldsa.PreHashFor(h); err != nil {
+		return nil, err
+	}
+	err = SignPreHashTo(&sk.PrivateKey, ph, digest, nil, false, ret[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[:], nil
+}
+
+// Computes the public key corresponding to this private key.
+//
+// Returns a *HashPublicKey.  The type crypto.PublicKey is used to make
+// HashPrivateKey implement the crypto.Signer interface.
+func (sk *HashPrivateKey) Public() crypto.PublicKey {
+	return &HashPublicKey{*sk.PrivateKey.Public().(*PublicKey)}
+}
+
+// Equal returns whether the two private keys equal.
+func (sk *HashPrivateKey) Equal(other crypto.PrivateKey) bool {
+	castOther, ok := other.(*HashPrivateKey)
+	if !ok {
+		return false
+	}
+	return sk.PrivateKey.Equal(&castOther.PrivateKey)
+}
+
+// Equal returns whether the two public keys equal.
+func (pk *HashPublicKey) Equal(other crypto.PublicKey) bool {
+	castOther, ok := other.(*HashPublicKey)
+	if !ok {
+		return false
+	}
+	return pk.PublicKey.Equal(&castOther.PublicKey)
+}
+
+type hashScheme struct{}
+
+var hashSch sign.Scheme = &hashScheme{}
+
+// HashScheme returns a generic signature interface for HashML-DSA-65, the
+// pre-hash variant of ML-DSA-65 with SHA-512.
+func HashScheme() sign.Scheme { return hashSch }
+
+func (*hashScheme) Name() string          { return "HashML-DSA-65" }
+func (*hashScheme) PublicKeySize() int    { return PublicKeySize }
+func (*hashScheme) PrivateKeySize() int   { return PrivateKeySize }
+func (*hashScheme) SignatureSize() int    { return SignatureSize }
+func (*hashScheme) SeedSize() int         { return SeedSize }
+func (*hashScheme) SupportsContext() bool { return true }
+
+func (*hashScheme) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 33}
+}
+
+func (*hashScheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	pk, sk, err := GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &HashPublicKey{*pk}, &HashPrivateKey{*sk}, nil
+}
+
+func (*hashScheme) Sign(
+	sk sign.PrivateKey,
+	msg []byte,
+	opts *sign.SignatureOpts,
+) []byte {
+	var ctx []byte
+	sig := make([]byte, SignatureSize)
+
+	priv, ok := sk.(*HashPrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		ctx = []byte(opts.Context)
+	}
+	err := SignPreHashTo(
+		&priv.PrivateKey, mldsa.SHA512, mldsa.SHA512.Sum(msg), ctx, false, sig,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return sig
+}
+
+func (*hashScheme) Verify(
+	pk sign.PublicKey,
+	msg, sig []byte,
+	opts *sign.SignatureOpts,
+) bool {
+	var ctx []byte
+	pub, ok := pk.(*HashPublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		ctx = []byte(opts.Context)
+	}
+	return VerifyPreHash(
+		&pub.PublicKey, mldsa.SHA512, mldsa.SHA512.Sum(msg), ctx, sig,
+	)
+}
+
+func (*hashScheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	pk, sk := sch.DeriveKey(seed)
+	return &HashPublicKey{*pk.(*PublicKey)}, &HashPrivateKey{*sk.(*PrivateKey)}
+}
+
+func (*hashScheme) UnmarshalBinaryPublicKey(buf []byte) (sign.PublicKey, error) {
+	pk, err := sch.UnmarshalBinaryPublicKey(buf)
+	if err != nil {
+		return nil, err
+	}
+	return &HashPublicKey{*pk.(*PublicKey)}, nil
+}
+
+func (*hashScheme) UnmarshalBinaryPrivateKey(buf []byte) (sign.PrivateKey, error) {
+	sk, err := sch.UnmarshalBinaryPrivateKey(buf)
+	if err != nil {
+		return nil, err
+	}
+	return &HashPrivateKey{*sk.(*PrivateKey)}, nil
+}
+
+func (sk *HashPrivateKey) Scheme() sign.Scheme {
+	return hashSch
+}
+
+func (sk *HashPublicKey) Scheme() sign.Scheme {
+	return hashSch
+}
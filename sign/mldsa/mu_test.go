@@ -0,0 +1,56 @@
+package mldsa_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/mldsa"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa65"
+)
+
+func TestExternalMu(t *testing.T) {
+	var seed [mldsa65.SeedSize]byte
+	pk, sk := mldsa65.NewKeyFromSeed(&seed)
+	msg := []byte("message")
+	ctx := []byte("context")
+
+	// Signing μ computed apart gives the same signature as signing the
+	// message, and both are verified either way.
+	mu, err := mldsa65.ComputeMu(pk, msg, ctx)
+	test.CheckNoErr(t, err, "compute mu")
+	sig := make([]byte, mldsa65.SignatureSize)
+	test.CheckNoErr(t, mldsa65.SignMuTo(sk, &mu, false, sig), "sign mu")
+	want := make([]byte, mldsa65.SignatureSize)
+	test.CheckNoErr(t, mldsa65.SignTo(sk, msg, ctx, false, want), "sign")
+	if !bytes.Equal(sig, want) {
+		t.Fatal("signature of mu differs from the signature of the message")
+	}
+	if !mldsa65.VerifyMu(pk, &mu, sig) || !mldsa65.Verify(pk, msg, ctx, sig) {
+		t.Fatal("verification failed")
+	}
+
+	test.CheckNoErr(t, mldsa65.SignMuTo(sk, &mu, true, sig), "sign mu")
+	if !mldsa65.Verify(pk, msg, ctx, sig) {
+		t.Fatal("verification of randomized signature failed")
+	}
+	mu[0]++
+	if mldsa65.VerifyMu(pk, &mu, sig) {
+		t.Fatal("verification succeeded with a modified mu")
+	}
+
+	// Same for HashML-DSA.
+	digest := mldsa.SHA256.Sum(msg)
+	mu, err = mldsa65.ComputePreHashMu(pk, mldsa.SHA256, digest, ctx)
+	test.CheckNoErr(t, err, "compute mu")
+	test.CheckNoErr(t, mldsa65.SignMuTo(sk, &mu, false, sig), "sign mu")
+	err = mldsa65.SignPreHashTo(sk, mldsa.SHA256, digest, ctx, false, want)
+	test.CheckNoErr(t, err, "sign")
+	if !bytes.Equal(sig, want) {
+		t.Fatal("signature of mu differs from the signature of the digest")
+	}
+
+	if _, err = mldsa65.ComputeMu(pk, msg, make([]byte, 256)); err == nil {
+		t.Fatal("context larger than 255 bytes accepted")
+	}
+}
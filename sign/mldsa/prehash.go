@@ -0,0 +1,129 @@
+package mldsa
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"errors"
+
+	"github.com/quantumcoinproject/circl/xof"
+	_ "golang.org/x/crypto/sha3"
+)
+
+// PreHash identifies the hash function used by HashML-DSA, the pre-hash
+// variant of ML-DSA, to hash messages before signing them.
+//
+// See FIPS 204 -- Section 5.4.
+type PreHash byte
+
+// Supported pre-hash functions. Their values are the last arc of their
+// object identifiers.
+const (
+	SHA256     PreHash = 1
+	SHA384     PreHash = 2
+	SHA512     PreHash = 3
+	SHA224     PreHash = 4
+	SHA512_224 PreHash = 5
+	SHA512_256 PreHash = 6
+	SHA3_224   PreHash = 7
+	SHA3_256   PreHash = 8
+	SHA3_384   PreHash = 9
+	SHA3_512   PreHash = 10
+	SHAKE128   PreHash = 11
+	SHAKE256   PreHash = 12
+)
+
+var (
+	ErrPreHash    = errors.New("sign/mldsa: invalid prehash function")
+	ErrDigestSize = errors.New("sign/mldsa: wrong size for digest")
+)
+
+var preHash2Hash = [...]crypto.Hash{
+	SHA256:     crypto.SHA256,
+	SHA384:     crypto.SHA384,
+	SHA512:     crypto.SHA512,
+	SHA224:     crypto.SHA224,
+	SHA512_224: crypto.SHA512_224,
+	SHA512_256: crypto.SHA512_256,
+	SHA3_224:   crypto.SHA3_224,
+	SHA3_256:   crypto.SHA3_256,
+	SHA3_384:   crypto.SHA3_384,
+	SHA3_512:   crypto.SHA3_512,
+}
+
+// PreHashFor returns the pre-hash function corresponding to h, as passed
+// in crypto.SignerOpts. Returns [ErrPreHash] if h is not supported.
+func PreHashFor(h crypto.Hash) (PreHash, error) {
+	for ph, h2 := range preHash2Hash {
+		if h2 != 0 && h2 == h {
+			return PreHash(ph), nil
+		}
+	}
+	return 0, ErrPreHash
+}
+
+// HashFunc returns the hash function of ph, or zero for SHAKE128 and
+// SHAKE256, which are not a crypto.Hash. It makes PreHash implement
+// crypto.SignerOpts, so that it can select the pre-hash function of the
+// HashPrivateKey of the subpackages.
+func (ph PreHash) HashFunc() crypto.Hash {
+	if int(ph) >= len(preHash2Hash) {
+		return 0
+	}
+	return preHash2Hash[ph]
+}
+
+// Valid returns whether ph is a supported pre-hash function.
+func (ph PreHash) Valid() bool { return ph >= SHA256 && ph <= SHAKE256 }
+
+// Size returns the size of the digests of ph. SHAKE128 and SHAKE256 are
+// used with 256 and 512 bits of output respectively.
+func (ph PreHash) Size() int {
+	switch ph {
+	case SHAKE128:
+		return 32
+	case SHAKE256:
+		return 64
+	}
+	if !ph.Valid() {
+		return 0
+	}
+	return preHash2Hash[ph].Size()
+}
+
+// Sum returns the digest of msg with ph.
+//
+// Panics if ph is not supported.
+func (ph PreHash) Sum(msg []byte) []byte {
+	if !ph.Valid() {
+		panic(ErrPreHash)
+	}
+	var x xof.ID
+	switch ph {
+	case SHAKE128:
+		x = xof.SHAKE128
+	case SHAKE256:
+		x = xof.SHAKE256
+	default:
+		h := preHash2Hash[ph].New()
+		_, _ = h.Write(msg)
+		return h.Sum(nil)
+	}
+	ret := make([]byte, ph.Size())
+	h := x.New()
+	_, _ = h.Write(msg)
+	_, _ = h.Read(ret)
+	return ret
+}
+
+// OID returns the DER encoding of the object identifier of ph, as
+// included in HashML-DSA signatures, or nil if ph is not supported.
+func (ph PreHash) OID() []byte {
+	// Source https://csrc.nist.gov/Projects/computer-security-objects-register/algorithm-registration
+	if !ph.Valid() {
+		return nil
+	}
+	return []byte{
+		0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, byte(ph),
+	}
+}
@@ -0,0 +1,137 @@
+package mldsa_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/mldsa"
+	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa44"
+)
+
+func TestPreHash(t *testing.T) {
+	for ph := mldsa.SHA256; ph <= mldsa.SHAKE256; ph++ {
+		if got := len(ph.Sum([]byte("message"))); got != ph.Size() {
+			test.ReportError(t, got, ph.Size(), ph)
+		}
+	}
+
+	// id-sha512 is 2.16.840.1.101.3.4.2.3.
+	want := []byte{0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03}
+	if got := mldsa.SHA512.OID(); !bytes.Equal(got, want) {
+		test.ReportError(t, got, want)
+	}
+
+	if ph, err := mldsa.PreHashFor(crypto.SHA3_256); err != nil || ph != mldsa.SHA3_256 {
+		test.ReportError(t, ph, mldsa.SHA3_256)
+	}
+	if _, err := mldsa.PreHashFor(crypto.MD5); err != mldsa.ErrPreHash {
+		test.ReportError(t, err, mldsa.ErrPreHash)
+	}
+	if mldsa.PreHash(0).Valid() || mldsa.PreHash(13).Valid() {
+		t.Fatal("invalid pre-hash function accepted")
+	}
+	if mldsa.SHA384.HashFunc() != crypto.SHA384 || mldsa.SHAKE256.HashFunc() != 0 {
+		t.Fatal("wrong hash function of pre-hash function")
+	}
+}
+
+func TestSignPreHash(t *testing.T) {
+	var seed [mldsa44.SeedSize]byte
+	pk, sk := mldsa44.NewKeyFromSeed(&seed)
+	msg := []byte("message")
+	ctx := []byte("context")
+	digest := mldsa.SHAKE128.Sum(msg)
+
+	sig := make([]byte, mldsa44.SignatureSize)
+	err := mldsa44.SignPreHashTo(sk, mldsa.SHAKE128, digest, ctx, false, sig)
+	test.CheckNoErr(t, err, "sign")
+
+	// HashML-DSA signs M' = 1 || |ctx| || ctx || OID || PH(M) with the
+	// internal signing function.
+	msgPrime := append([]byte{1, byte(len(ctx))}, ctx...)
+	msgPrime = append(msgPrime, mldsa.SHAKE128.OID()...)
+	msgPrime = append(msgPrime, digest...)
+	if want := sk.SignNoContext(msgPrime, [32]byte{}); !bytes.Equal(sig, want) {
+		t.Fatal("signature does not match the internal signature of M'")
+	}
+
+	if !mldsa44.VerifyPreHash(pk, mldsa.SHAKE128, digest, ctx, sig) {
+		t.Fatal("verification failed")
+	}
+	if mldsa44.VerifyPreHash(pk, mldsa.SHAKE256, mldsa.SHAKE256.Sum(msg), ctx, sig) {
+		t.Fatal("verification succeeded with another hash function")
+	}
+	if mldsa44.Verify(pk, msg, ctx, sig) {
+		t.Fatal("HashML-DSA signature verified as a pure signature")
+	}
+
+	err = mldsa44.SignPreHashTo(sk, mldsa.SHA256, digest[:31], nil, false, sig)
+	if err != mldsa.ErrDigestSize {
+		test.ReportError(t, err, mldsa.ErrDigestSize)
+	}
+
+	// Private keys of pure ML-DSA do not sign digests.
+	h := sha256.Sum256(msg)
+	if _, err = sk.Sign(nil, h[:], crypto.SHA256); err == nil {
+		t.Fatal("pure private key signed a digest")
+	}
+	if _, err = sk.Sign(nil, digest, mldsa.SHAKE128); err == nil {
+		t.Fatal("pure private key signed a digest")
+	}
+}
+
+func TestHashScheme(t *testing.T) {
+	scheme := mldsa44.HashScheme()
+	pk, sk, err := scheme.GenerateKey()
+	test.CheckNoErr(t, err, "generate key")
+	msg := []byte("message")
+
+	// The signature of the message by the scheme and of its digest by the
+	// private key are the same.
+	sig := scheme.Sign(sk, msg, nil)
+	h := mldsa.SHA512.Sum(msg)
+	sig2, err := sk.Sign(nil, h, crypto.SHA512)
+	test.CheckNoErr(t, err, "sign digest")
+	if !bytes.Equal(sig, sig2) {
+		t.Fatal("signatures of the message and the digest differ")
+	}
+	if !scheme.Verify(pk, msg, sig, nil) {
+		t.Fatal("verification failed")
+	}
+
+	// Digests of other pre-hash functions are signed through crypto.Signer,
+	// and verified with VerifyPreHash only.
+	hsk := sk.(*mldsa44.HashPrivateKey)
+	hpk := &pk.(*mldsa44.HashPublicKey).PublicKey
+	for _, tc := range []struct {
+		ph   mldsa.PreHash
+		opts crypto.SignerOpts
+	}{
+		{mldsa.SHA256, crypto.SHA256},
+		{mldsa.SHA512, crypto.SHA512},
+		{mldsa.SHAKE128, mldsa.SHAKE128},
+		{mldsa.SHA256, mldsa.SHA256},
+	} {
+		digest := tc.ph.Sum(msg)
+		sig, err := hsk.Sign(nil, digest, tc.opts)
+		test.CheckNoErr(t, err, "sign digest")
+		if !mldsa44.VerifyPreHash(hpk, tc.ph, digest, nil, sig) {
+			test.ReportError(t, false, true, tc.ph)
+		}
+		if tc.ph != mldsa.SHA512 && scheme.Verify(pk, msg, sig, nil) {
+			test.ReportError(t, true, false, tc.ph)
+		}
+	}
+	if _, err = sk.Sign(nil, h, crypto.MD5); err != mldsa.ErrPreHash {
+		test.ReportError(t, err, mldsa.ErrPreHash)
+	}
+	if _, err = sk.Sign(nil, h[:32], crypto.SHA512); err != mldsa.ErrDigestSize {
+		test.ReportError(t, err, mldsa.ErrDigestSize)
+	}
+	if pure := pk.(*mldsa44.HashPublicKey).PublicKey; mldsa44.Verify(&pure, msg, nil, sig) {
+		t.Fatal("HashML-DSA signature verified as a pure signature")
+	}
+}
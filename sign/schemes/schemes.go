@@ -4,24 +4,35 @@
 //
 //	Ed25519
 //	Ed448
+//	ECDSA
 //	Ed25519-Dilithium2
 //	Ed448-Dilithium3
 //	Dilithium
 //	ML-DSA
+//	HashML-DSA
 //	SLH-DSA
+//	HashSLH-DSA
+//	Falcon
+//	Composite ML-DSA
+//	HSS-LMS
 package schemes
 
 import (
 	"strings"
 
 	"github.com/quantumcoinproject/circl/sign"
+	"github.com/quantumcoinproject/circl/sign/composite"
 	dilithium2 "github.com/quantumcoinproject/circl/sign/dilithium/mode2"
 	dilithium3 "github.com/quantumcoinproject/circl/sign/dilithium/mode3"
 	dilithium5 "github.com/quantumcoinproject/circl/sign/dilithium/mode5"
+	"github.com/quantumcoinproject/circl/sign/ecdsa"
 	"github.com/quantumcoinproject/circl/sign/ed25519"
 	"github.com/quantumcoinproject/circl/sign/ed448"
 	"github.com/quantumcoinproject/circl/sign/eddilithium2"
 	"github.com/quantumcoinproject/circl/sign/eddilithium3"
+	"github.com/quantumcoinproject/circl/sign/falcon/falcon1024"
+	"github.com/quantumcoinproject/circl/sign/falcon/falcon512"
+	"github.com/quantumcoinproject/circl/sign/lms"
 	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa44"
 	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa65"
 	"github.com/quantumcoinproject/circl/sign/mldsa/mldsa87"
@@ -31,6 +42,9 @@ import (
 var allSchemes = [...]sign.Scheme{
 	ed25519.Scheme(),
 	ed448.Scheme(),
+	ecdsa.P256_SHA256.Scheme(),
+	ecdsa.P384_SHA384.Scheme(),
+	ecdsa.P521_SHA512.Scheme(),
 	eddilithium2.Scheme(),
 	eddilithium3.Scheme(),
 	dilithium2.Scheme(),
@@ -39,6 +53,9 @@ var allSchemes = [...]sign.Scheme{
 	mldsa44.Scheme(),
 	mldsa65.Scheme(),
 	mldsa87.Scheme(),
+	mldsa44.HashScheme(),
+	mldsa65.HashScheme(),
+	mldsa87.HashScheme(),
 	slhdsa.SHA2_128s.Scheme(),
 	slhdsa.SHAKE_128s.Scheme(),
 	slhdsa.SHA2_128f.Scheme(),
@@ -51,6 +68,29 @@ var allSchemes = [...]sign.Scheme{
 	slhdsa.SHAKE_256s.Scheme(),
 	slhdsa.SHA2_256f.Scheme(),
 	slhdsa.SHAKE_256f.Scheme(),
+	slhdsa.SHA2_128s.PreHashScheme(),
+	slhdsa.SHAKE_128s.PreHashScheme(),
+	slhdsa.SHA2_128f.PreHashScheme(),
+	slhdsa.SHAKE_128f.PreHashScheme(),
+	slhdsa.SHA2_192s.PreHashScheme(),
+	slhdsa.SHAKE_192s.PreHashScheme(),
+	slhdsa.SHA2_192f.PreHashScheme(),
+	slhdsa.SHAKE_192f.PreHashScheme(),
+	slhdsa.SHA2_256s.PreHashScheme(),
+	slhdsa.SHAKE_256s.PreHashScheme(),
+	slhdsa.SHA2_256f.PreHashScheme(),
+	slhdsa.SHAKE_256f.PreHashScheme(),
+	falcon512.Scheme(),
+	falcon1024.Scheme(),
+	composite.MLDSA44_Ed25519_SHA512.Scheme(),
+	composite.MLDSA44_ECDSA_P256_SHA256.Scheme(),
+	composite.MLDSA65_ECDSA_P256_SHA512.Scheme(),
+	composite.MLDSA65_ECDSA_P384_SHA512.Scheme(),
+	composite.MLDSA65_Ed25519_SHA512.Scheme(),
+	composite.MLDSA87_ECDSA_P384_SHA512.Scheme(),
+	composite.MLDSA87_Ed448_SHAKE256.Scheme(),
+	composite.MLDSA87_ECDSA_P521_SHA512.Scheme(),
+	lms.Scheme(),
 }
 
 var allSchemeNames map[string]sign.Scheme
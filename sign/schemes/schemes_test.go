@@ -2,10 +2,13 @@ package schemes_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/quantumcoinproject/circl/sign"
+	"github.com/quantumcoinproject/circl/sign/lms"
 	"github.com/quantumcoinproject/circl/sign/schemes"
+	"github.com/quantumcoinproject/circl/sign/stateful"
 )
 
 func TestCaseSensitivity(t *testing.T) {
@@ -68,9 +71,25 @@ func TestApi(t *testing.T) {
 			if scheme.SupportsContext() {
 				opts.Context = "A context"
 			}
+
+			// HSS keys sign only once a store of their state is set.
+			if _, ok := sk.(*lms.PrivateKey); ok {
+				if scheme.Sign(sk, msg, opts) != nil {
+					t.Fatal()
+				}
+				setStore(sk)
+			}
+
 			sig := scheme.Sign(sk, msg, opts)
 
-			if scheme.SignatureSize() != len(sig) {
+			// Only DER encoded ECDSA signatures, on their own or within a
+			// composite signature, and HSS signatures, whose size depends
+			// on the parameters of the key, have a variable length.
+			if strings.Contains(scheme.Name(), "ECDSA") || scheme.Name() == "HSS-LMS" {
+				if len(sig) > scheme.SignatureSize() {
+					t.Fatal()
+				}
+			} else if scheme.SignatureSize() != len(sig) {
 				t.Fatal()
 			}
 
@@ -114,6 +133,9 @@ func Example() {
 	// Output:
 	// Ed25519
 	// Ed448
+	// ECDSA-P256-SHA256
+	// ECDSA-P384-SHA384
+	// ECDSA-P521-SHA512
 	// Ed25519-Dilithium2
 	// Ed448-Dilithium3
 	// Dilithium2
@@ -122,6 +144,9 @@ func Example() {
 	// ML-DSA-44
 	// ML-DSA-65
 	// ML-DSA-87
+	// HashML-DSA-44
+	// HashML-DSA-65
+	// HashML-DSA-87
 	// SLH-DSA-SHA2-128s
 	// SLH-DSA-SHAKE-128s
 	// SLH-DSA-SHA2-128f
@@ -134,6 +159,36 @@ func Example() {
 	// SLH-DSA-SHAKE-256s
 	// SLH-DSA-SHA2-256f
 	// SLH-DSA-SHAKE-256f
+	// SLH-DSA-SHA2-128s-with-SHA256
+	// SLH-DSA-SHAKE-128s-with-SHAKE128
+	// SLH-DSA-SHA2-128f-with-SHA256
+	// SLH-DSA-SHAKE-128f-with-SHAKE128
+	// SLH-DSA-SHA2-192s-with-SHA512
+	// SLH-DSA-SHAKE-192s-with-SHAKE256
+	// SLH-DSA-SHA2-192f-with-SHA512
+	// SLH-DSA-SHAKE-192f-with-SHAKE256
+	// SLH-DSA-SHA2-256s-with-SHA512
+	// SLH-DSA-SHAKE-256s-with-SHAKE256
+	// SLH-DSA-SHA2-256f-with-SHA512
+	// SLH-DSA-SHAKE-256f-with-SHAKE256
+	// Falcon-512
+	// Falcon-1024
+	// MLDSA44-Ed25519-SHA512
+	// MLDSA44-ECDSA-P256-SHA256
+	// MLDSA65-ECDSA-P256-SHA512
+	// MLDSA65-ECDSA-P384-SHA512
+	// MLDSA65-Ed25519-SHA512
+	// MLDSA87-ECDSA-P384-SHA512
+	// MLDSA87-Ed448-SHAKE256
+	// MLDSA87-ECDSA-P521-SHA512
+	// HSS-LMS
+}
+
+// setStore sets an in-memory store of the state of HSS keys.
+func setStore(sk sign.PrivateKey) {
+	if k, ok := sk.(*lms.PrivateKey); ok {
+		k.SetStore(stateful.NewMemoryStore(0), 0)
+	}
 }
 
 func BenchmarkGenerateKeyPair(b *testing.B) {
@@ -153,6 +208,7 @@ func BenchmarkSign(b *testing.B) {
 	for _, scheme := range allSchemes {
 		msg := []byte(fmt.Sprintf("Signing with %s", scheme.Name()))
 		_, sk, _ := scheme.GenerateKey()
+		setStore(sk)
 		b.Run(scheme.Name(), func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				_ = scheme.Sign(sk, msg, opts)
@@ -167,6 +223,7 @@ func BenchmarkVerify(b *testing.B) {
 	for _, scheme := range allSchemes {
 		msg := []byte(fmt.Sprintf("Signing with %s", scheme.Name()))
 		pk, sk, _ := scheme.GenerateKey()
+		setStore(sk)
 		sig := scheme.Sign(sk, msg, opts)
 		b.Run(scheme.Name(), func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
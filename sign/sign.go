@@ -77,7 +77,8 @@ type Scheme interface {
 	// Size of binary marshalled public keys.
 	PrivateKeySize() int
 
-	// Size of signatures.
+	// Size of signatures, or their maximum size if they have a variable
+	// length.
 	SignatureSize() int
 
 	// Size of seeds.
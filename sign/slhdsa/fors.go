@@ -1,5 +1,7 @@
 package slhdsa
 
+import "github.com/quantumcoinproject/circl/sign/internal/hashsig"
+
 // See FIPS 205 -- Section 8
 // Forest of Random Subsets (FORS) is a few-time signature scheme that is
 // used to sign the digests of the actual messages.
@@ -10,7 +12,7 @@ type (
 	forsSignature  []forsPair // k*forsPairSize() bytes
 	forsPair       struct {
 		sk   forsPrivateKey // forsSkSize() bytes
-		auth [][]byte       // a*n bytes
+		auth []byte         // a*n bytes
 	} // forsSkSize() + a*n bytes
 )
 
@@ -29,10 +31,7 @@ func (fs *forsSignature) fromBytes(p *params, c *cursor) {
 
 func (fp *forsPair) fromBytes(p *params, c *cursor) {
 	fp.sk = c.Next(p.forsSkSize())
-	fp.auth = make([][]byte, p.a)
-	for i := range fp.auth {
-		fp.auth[i] = c.Next(p.n)
-	}
+	fp.auth = c.Next(p.a * p.n)
 }
 
 // See FIPS 205 -- Section 8.1 -- Algorithm 14.
@@ -62,32 +61,13 @@ func (s *statePriv) forsNodeIter(
 
 	s.H.address.Set(addr)
 
-	twoZ := uint32(1) << z
-	iTwoZ := i << z
-	for k := range twoZ {
-		li := iTwoZ + k
-		lz := uint32(0)
-
+	leaf := func(out []byte, li uint32) {
 		sk := s.forsSkGen(addr, li)
 		s.F.address.SetTreeIndex(li)
 		s.F.SetMessage(sk)
-		node := s.F.Final()
-
-		for !stack.isEmpty() && stack.top().z == lz {
-			left := stack.pop()
-			li = (li - 1) >> 1
-			lz = lz + 1
-
-			s.H.address.SetTreeHeight(lz)
-			s.H.address.SetTreeIndex(li)
-			s.H.SetMsgs(left.node, node)
-			node = s.H.Final()
-		}
-
-		stack.push(item{node, lz})
+		copy(out, s.F.Final())
 	}
-
-	copy(root, stack.pop().node)
+	stack.TreeHash(root, i, z, leaf, s.nodeHash, nil)
 }
 
 // See FIPS 205 -- Section 8.3 -- Algorithm 16.
@@ -111,9 +91,10 @@ func (s *statePriv) forsSign(sig forsSignature, digest []byte, addr address) {
 		forsSk := s.forsSkGen(addr, treeIdx)
 		copy(sig[i].sk, forsSk)
 
+		auth := cursor(sig[i].auth)
 		for j := range s.a {
 			shift := (indicesI >> j) ^ 1
-			s.forsNodeIter(stack, sig[i].auth[j], (i<<(s.a-j))+shift, j, addr)
+			s.forsNodeIter(stack, auth.Next(s.n), (i<<(s.a-j))+shift, j, addr)
 		}
 	}
 }
@@ -136,6 +117,7 @@ func (s *state) forsPkFromSig(
 
 	in, bits, total := 0, uint32(0), uint32(0)
 	maskA := (uint32(1) << s.a) - 1
+	node := make([]byte, s.n)
 
 	for i := range s.k {
 		for bits < s.a {
@@ -149,21 +131,8 @@ func (s *state) forsPkFromSig(
 		treeIdx := (i << s.a) + indicesI
 		s.F.address.SetTreeIndex(treeIdx)
 		s.F.SetMessage(sig[i].sk)
-		node := s.F.Final()
-
-		for j := range s.a {
-			if (indicesI>>j)&0x1 == 0 {
-				treeIdx = treeIdx >> 1
-				s.H.SetMsgs(node, sig[i].auth[j])
-			} else {
-				treeIdx = (treeIdx - 1) >> 1
-				s.H.SetMsgs(sig[i].auth[j], node)
-			}
-
-			s.H.address.SetTreeHeight(j + 1)
-			s.H.address.SetTreeIndex(treeIdx)
-			node = s.H.Final()
-		}
+		copy(node, s.F.Final())
+		hashsig.RootFromAuthPath(node, treeIdx, sig[i].auth, s.nodeHash)
 
 		s.T.WriteMessage(node)
 	}
@@ -135,3 +135,24 @@ func (k PublicKey) Equal(x crypto.PublicKey) bool {
 		bytes.Equal(k.seed, other.seed) &&
 		bytes.Equal(k.root, other.root)
 }
+
+// [PreHashPrivateKey] stores a private key of the HashSLH-DSA scheme of its
+// [ID], see [ID.PreHashScheme].
+// It implements the [crypto.Signer] and [crypto.PrivateKey] interfaces.
+type PreHashPrivateKey struct{ PrivateKey }
+
+func (k PreHashPrivateKey) Public() crypto.PublicKey { return PreHashPublicKey{k.PublicKey()} }
+func (k PreHashPrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(PreHashPrivateKey)
+	return ok && k.PrivateKey.Equal(other.PrivateKey)
+}
+
+// [PreHashPublicKey] stores a public key of the HashSLH-DSA scheme of its
+// [ID], see [ID.PreHashScheme].
+// It implements the [crypto.PublicKey] interface.
+type PreHashPublicKey struct{ PublicKey }
+
+func (k PreHashPublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(PreHashPublicKey)
+	return ok && k.PublicKey.Equal(other.PublicKey)
+}
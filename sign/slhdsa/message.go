@@ -65,14 +65,18 @@ func NewPreHashWithXof(x xof.ID) (*PreHash, error) {
 func (ph *PreHash) Reset()                      { ph.writer.Reset() }
 func (ph *PreHash) Write(b []byte) (int, error) { return ph.writer.Write(b) }
 
-// BuildMessage returns a [Message] for signing, and resets the writer.
-func (ph *PreHash) BuildMessage() (*Message, error) {
-	// Source https://csrc.nist.gov/Projects/computer-security-objects-register/algorithm-registration
-	const oidLen = 11
-	oid := [oidLen]byte{
+// Source https://csrc.nist.gov/Projects/computer-security-objects-register/algorithm-registration
+const oidLen = 11
+
+func (ph *PreHash) oidBytes() [oidLen]byte {
+	return [oidLen]byte{
 		0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, ph.oid,
 	}
+}
 
+// BuildMessage returns a [Message] for signing, and resets the writer.
+func (ph *PreHash) BuildMessage() (*Message, error) {
+	oid := ph.oidBytes()
 	msg := make([]byte, oidLen+ph.size)
 	copy(msg, oid[:])
 	switch f := ph.writer.(type) {
@@ -91,6 +95,18 @@ func (ph *PreHash) BuildMessage() (*Message, error) {
 	return &Message{msg, 1}, nil
 }
 
+// digestMessage returns a [Message] for signing digest, which was computed
+// with the hash function of ph.
+// Returns [ErrMsgLen] if digest does not have the size of this function.
+func (ph *PreHash) digestMessage(digest []byte) (*Message, error) {
+	if len(digest) != ph.size {
+		return nil, ErrMsgLen
+	}
+
+	oid := ph.oidBytes()
+	return &Message{append(oid[:], digest...), 1}, nil
+}
+
 // [Message] wraps a message for signing.
 type Message struct {
 	msg       []byte
@@ -113,3 +129,53 @@ func (m *Message) getMsgPrime(context []byte) ([]byte, error) {
 		[]byte{m.isPreHash, byte(len(context))}, context...), m.msg...,
 	), nil
 }
+
+// preHashFunc returns the hash function used by the HashSLH-DSA scheme of
+// the parameter set, or zero if it is an XOF.
+func (p *params) preHashFunc() crypto.Hash {
+	switch {
+	case p.isSHA2 && p.n == 16:
+		return crypto.SHA256
+	case p.isSHA2:
+		return crypto.SHA512
+	default:
+		return 0
+	}
+}
+
+func (p *params) preHashName() string {
+	switch {
+	case p.isSHA2 && p.n == 16:
+		return "SHA256"
+	case p.isSHA2:
+		return "SHA512"
+	case p.n == 16:
+		return "SHAKE128"
+	default:
+		return "SHAKE256"
+	}
+}
+
+// newPreHash returns the pre-hash function of the HashSLH-DSA scheme of
+// the parameter set.
+func (p *params) newPreHash() (ph *PreHash) {
+	switch {
+	case p.isSHA2:
+		ph, _ = NewPreHashWithHash(p.preHashFunc())
+	case p.n == 16:
+		ph, _ = NewPreHashWithXof(xof.SHAKE128)
+	default:
+		ph, _ = NewPreHashWithXof(xof.SHAKE256)
+	}
+	return
+}
+
+// preHashMessage returns the [Message] signed by the HashSLH-DSA scheme of
+// the parameter set for msg.
+func (p *params) preHashMessage(msg []byte) (*Message, error) {
+	ph := p.newPreHash()
+	if _, err := ph.Write(msg); err != nil {
+		return nil, err
+	}
+	return ph.BuildMessage()
+}
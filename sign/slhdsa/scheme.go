@@ -2,6 +2,7 @@ package slhdsa
 
 import (
 	"crypto/rand"
+	"encoding/asn1"
 
 	"github.com/quantumcoinproject/circl/internal/sha3"
 	"github.com/quantumcoinproject/circl/sign"
@@ -112,3 +113,131 @@ func (s scheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
 
 	return k, nil
 }
+
+// PreHashScheme returns the HashSLH-DSA scheme of the parameter set, which
+// signs messages pre-hashed with the function of its object identifier:
+// SHA-256 or SHA-512 for the SHA2 parameter sets, and SHAKE128 or
+// SHAKE256 for the SHAKE ones.
+func (id ID) PreHashScheme() sign.Scheme { return preHashScheme{scheme{id.params()}} }
+
+func (k PreHashPrivateKey) Scheme() sign.Scheme { return k.ID.PreHashScheme() }
+func (k PreHashPublicKey) Scheme() sign.Scheme  { return k.ID.PreHashScheme() }
+
+type preHashScheme struct{ scheme }
+
+func (s preHashScheme) Name() string { return s.name + "-with-" + s.preHashName() }
+
+// Oid returns the object identifier of the parameter set, see
+// https://csrc.nist.gov/Projects/computer-security-objects-register/algorithm-registration
+func (s preHashScheme) Oid() asn1.ObjectIdentifier {
+	oids := [_MaxParams - 1]int{
+		SHA2_128s - 1:  35,
+		SHA2_128f - 1:  36,
+		SHA2_192s - 1:  37,
+		SHA2_192f - 1:  38,
+		SHA2_256s - 1:  39,
+		SHA2_256f - 1:  40,
+		SHAKE_128s - 1: 41,
+		SHAKE_128f - 1: 42,
+		SHAKE_192s - 1: 43,
+		SHAKE_192f - 1: 44,
+		SHAKE_256s - 1: 45,
+		SHAKE_256f - 1: 46,
+	}
+	return asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, oids[s.ID-1]}
+}
+
+func (s preHashScheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	pub, priv, err := GenerateKey(rand.Reader, s.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return PreHashPublicKey{pub}, PreHashPrivateKey{priv}, nil
+}
+
+// Sign returns a randomized HashSLH-DSA signature of the message with the
+// context given.
+// If options is nil, an empty context is used.
+// It returns an empty slice if the signature generation fails.
+//
+// Panics if the key is not a [PreHashPrivateKey] or when the [ID]
+// mismatches.
+func (s preHashScheme) Sign(
+	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
+) []byte {
+	k, ok := priv.(PreHashPrivateKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	var context []byte
+	if options != nil {
+		context = []byte(options.Context)
+	}
+
+	msg, err := s.preHashMessage(message)
+	if err != nil {
+		return nil
+	}
+
+	sig, err := SignRandomized(&k.PrivateKey, rand.Reader, msg, context)
+	if err != nil {
+		return nil
+	}
+
+	return sig
+}
+
+// Verify returns true if the HashSLH-DSA signature of the message with the
+// specified context is valid.
+// If options is nil, an empty context is used.
+//
+// Panics if the key is not a [PreHashPublicKey] or when the [ID]
+// mismatches.
+func (s preHashScheme) Verify(
+	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
+) bool {
+	k, ok := pub.(PreHashPublicKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	var context []byte
+	if options != nil {
+		context = []byte(options.Context)
+	}
+
+	msg, err := s.preHashMessage(message)
+	if err != nil {
+		return false
+	}
+
+	return Verify(&k.PublicKey, msg, signature, context)
+}
+
+// DeriveKey deterministically generates a pair of keys from a seed.
+//
+// Panics if seed is not of length [sign.Scheme.SeedSize].
+func (s preHashScheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	pub, priv := s.scheme.DeriveKey(seed)
+	return PreHashPublicKey{pub.(PublicKey)}, PreHashPrivateKey{priv.(PrivateKey)}
+}
+
+func (s preHashScheme) UnmarshalBinaryPublicKey(b []byte) (sign.PublicKey, error) {
+	k, err := s.scheme.UnmarshalBinaryPublicKey(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return PreHashPublicKey{k.(PublicKey)}, nil
+}
+
+func (s preHashScheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
+	k, err := s.scheme.UnmarshalBinaryPrivateKey(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return PreHashPrivateKey{k.(PrivateKey)}, nil
+}
@@ -15,6 +15,10 @@
 //   - Based on SHA2: [SHA2_256s] and [SHA2_256f].
 //   - Based on SHAKE: [SHAKE_256s] and [SHAKE_256f].
 //
+// Each parameter set has a generic [sign.Scheme] for pure signatures,
+// [ID.Scheme], and one for pre-hash signatures with the hash function of
+// its HashSLH-DSA object identifier, [ID.PreHashScheme].
+//
 // [FIPS 205]: https://doi.org/10.6028/NIST.FIPS.205
 package slhdsa
 
@@ -103,6 +107,37 @@ func (k PrivateKey) Sign(
 	return SignRandomized(&k, random, NewMessage(message), nil)
 }
 
+// [PreHashPrivateKey.Sign] returns a randomized HashSLH-DSA signature of the
+// message with an empty context.
+// If opts is nil or opts.HashFunc() is zero, the message is hashed with the pre-hash
+// function of the key's [ID]. Otherwise, the message must be a digest
+// computed with opts.HashFunc(), which must be that pre-hash function.
+// It returns an error if it fails reading from the random source.
+func (k PreHashPrivateKey) Sign(
+	random io.Reader, message []byte, opts crypto.SignerOpts,
+) (signature []byte, err error) {
+	params := k.ID.params()
+	var h crypto.Hash
+	if opts != nil {
+		h = opts.HashFunc()
+	}
+
+	var msg *Message
+	switch {
+	case h == 0:
+		msg, err = params.preHashMessage(message)
+	case h == params.preHashFunc():
+		msg, err = params.newPreHash().digestMessage(message)
+	default:
+		err = ErrPreHash
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return SignRandomized(&k.PrivateKey, random, msg, nil)
+}
+
 func (k *PrivateKey) doSign(
 	message *Message, context, addRand []byte,
 ) ([]byte, error) {
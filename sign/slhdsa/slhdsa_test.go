@@ -8,6 +8,7 @@ import (
 
 	"github.com/quantumcoinproject/circl/internal/sha3"
 	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign"
 	"github.com/quantumcoinproject/circl/sign/slhdsa"
 	"github.com/quantumcoinproject/circl/xof"
 )
@@ -37,6 +38,7 @@ func testSlhdsa(t *testing.T, sigIDs []slhdsa.ID) {
 		t.Run(id.String(), func(t *testing.T) {
 			t.Run("Keys", func(t *testing.T) { testKeys(t, id) })
 			t.Run("Sign", func(t *testing.T) { testSign(t, id) })
+			t.Run("PreHash", func(t *testing.T) { testPreHashScheme(t, id) })
 		})
 	}
 }
@@ -67,6 +69,48 @@ func testKeys(t *testing.T, id slhdsa.ID) {
 	test.CheckOk(pub2.Equal(pub3), "public key not equal", t)
 }
 
+func testPreHashScheme(t *testing.T, id slhdsa.ID) {
+	scheme := id.PreHashScheme()
+	pub, priv, err := scheme.GenerateKey()
+	test.CheckNoErr(t, err, "GenerateKey failed")
+
+	msg := []byte("Alice and Bob")
+	opts := &sign.SignatureOpts{Context: "context"}
+	sig := scheme.Sign(priv, msg, opts)
+	test.CheckOk(scheme.Verify(pub, msg, sig, opts), "Verify failed", t)
+	test.CheckOk(
+		!id.Scheme().Verify(pub.(slhdsa.PreHashPublicKey).PublicKey, msg, sig, opts),
+		"HashSLH-DSA signature verified as a pure signature", t)
+
+	// Signing as a crypto.Signer, either the message or its digest when
+	// the pre-hash function is a crypto.Hash.
+	signer := priv.(crypto.Signer)
+	sig, err = signer.Sign(rand.Reader, msg, nil)
+	test.CheckNoErr(t, err, "Sign message failed")
+	test.CheckOk(scheme.Verify(pub, msg, sig, nil), "Verify failed", t)
+
+	var h crypto.Hash
+	switch scheme.Name() {
+	case id.String() + "-with-SHA256":
+		h = crypto.SHA256
+	case id.String() + "-with-SHA512":
+		h = crypto.SHA512
+	default:
+		_, err = signer.Sign(rand.Reader, msg, crypto.SHA256)
+		test.CheckIsErr(t, err, "Sign digest should fail")
+		return
+	}
+
+	hh := h.New()
+	_, _ = hh.Write(msg)
+	sig, err = signer.Sign(rand.Reader, hh.Sum(nil), h)
+	test.CheckNoErr(t, err, "Sign digest failed")
+	test.CheckOk(scheme.Verify(pub, msg, sig, nil), "Verify failed", t)
+
+	_, err = signer.Sign(rand.Reader, hh.Sum(nil)[1:], h)
+	test.CheckIsErr(t, err, "Sign digest of wrong size should fail")
+}
+
 func testSign(t *testing.T, id slhdsa.ID) {
 	pub, priv, err := slhdsa.GenerateKey(rand.Reader, id)
 	test.CheckNoErr(t, err, "GenerateKey failed")
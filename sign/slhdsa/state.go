@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/sign/internal/hashsig"
 )
 
 // statePriv encapsulates common data for performing a private operation.
@@ -299,48 +300,17 @@ type sha3rw struct{ sha3.State }
 func (s *sha3rw) Final(out []byte)         { _, _ = s.Read(out) }
 func (s *sha3rw) SumIdempotent(out []byte) { _, _ = s.Clone().Read(out) }
 
-type (
-	item struct {
-		node []byte
-		z    uint32
-	}
-	stackNode []item
-)
-
-func (p *params) NewStack(z uint32) stackNode {
-	s := make([]item, z)
-	c := cursor(make([]byte, z*p.n))
-	for i := range s {
-		s[i].node = c.Next(p.n)
-	}
-
-	return s[:0]
-}
+type stackNode = hashsig.Stack
 
-func (s stackNode) isEmpty() bool { return len(s) == 0 }
-func (s stackNode) top() item     { return s[len(s)-1] }
-func (s *stackNode) push(v item) {
-	next := len(*s)
-	*s = (*s)[:next+1]
-	(*s)[next].z = v.z
-	copy((*s)[next].node, v.node)
-}
-
-func (s *stackNode) pop() (v item) {
-	last := len(*s) - 1
-	if last >= 0 {
-		v = (*s)[last]
-		*s = (*s)[:last]
-	}
-	return
-}
+func (p *params) NewStack(z uint32) stackNode { return hashsig.NewStack(p.n, z) }
 
-func (s *stackNode) Clear() {
-	*s = (*s)[:cap(*s)]
-	for i := range *s {
-		clearSlice(&(*s)[i].node)
-	}
-	clear((*s)[:])
+// nodeHash is the [hashsig.NodeHash] of the XMSS and FORS trees, whose
+// addresses are set in H by the caller.
+func (s *state) nodeHash(out, left, right []byte, z, i uint32) {
+	s.H.address.SetTreeHeight(z + 1)
+	s.H.address.SetTreeIndex(i)
+	s.H.SetMsgs(left, right)
+	copy(out, s.H.Final())
 }
 
 type cursor []byte
@@ -1,12 +1,11 @@
 package slhdsa
 
+import "github.com/quantumcoinproject/circl/sign/internal/hashsig"
+
 // See FIPS 205 -- Section 5
 // Winternitz One-Time Signature Plus Scheme
 
-const (
-	wotsW    uint32 = 16 // wotsW is w = 2^lg_w, where lg_w = 4.
-	wotsLen2 uint32 = 3  // wotsLen2 is len_2 fixed to 3.
-)
+const wotsW = hashsig.WotsW
 
 type (
 	wotsPublicKey []byte // n bytes
@@ -14,8 +13,8 @@ type (
 )
 
 func (p *params) wotsSigSize() uint32 { return p.wotsLen() * p.n }
-func (p *params) wotsLen() uint32     { return p.wotsLen1() + wotsLen2 }
-func (p *params) wotsLen1() uint32    { return 2 * p.n }
+func (p *params) wotsLen() uint32     { return hashsig.WotsLen(p.n) }
+func (p *params) wotsLen1() uint32    { return hashsig.WotsLen1(p.n) }
 
 func (ws *wotsSignature) fromBytes(p *params, c *cursor) {
 	*ws = c.Next(p.wotsSigSize())
@@ -66,34 +65,19 @@ func (s *statePriv) wotsSign(sig wotsSignature, msg []byte, addr address) {
 		panic(ErrMsgLen)
 	}
 
-	curSig := cursor(sig)
-	wotsLen1 := s.wotsLen1()
-	csum := wotsLen1 * (wotsW - 1)
-
 	s.PRF.address.Set(addr)
 	s.PRF.address.SetTypeAndClear(addressWotsPrf)
 	s.PRF.address.SetKeyPairAddress(addr.GetKeyPairAddress())
 
-	// Signs every nibble of the message and computes the checksum.
-	for i := range wotsLen1 {
-		s.PRF.address.SetChainAddress(i)
-		sk := s.PRF.Final()
-
-		addr.SetChainAddress(i)
-		msgi := uint32((msg[i/2] >> ((1 - (i & 1)) << 2)) & 0xF)
-		sigi := s.chain(sk, 0, msgi, addr)
-		copy(curSig.Next(s.n), sigi)
-		csum -= msgi
-	}
-
-	// Lastly, every nibble of the checksum is also signed.
-	for i := range wotsLen2 {
-		s.PRF.address.SetChainAddress(wotsLen1 + i)
+	// Signs every nibble of the message and of its checksum.
+	var digits [hashsig.MaxWotsLen]uint32
+	curSig := cursor(sig)
+	for i, digit := range hashsig.WotsDigits(digits[:], msg) {
+		s.PRF.address.SetChainAddress(uint32(i))
 		sk := s.PRF.Final()
 
-		addr.SetChainAddress(wotsLen1 + i)
-		csumi := (csum >> (8 - 4*i)) & 0xF
-		sigi := s.chain(sk, 0, csumi, addr)
+		addr.SetChainAddress(uint32(i))
+		sigi := s.chain(sk, 0, digit, addr)
 		copy(curSig.Next(s.n), sigi)
 	}
 }
@@ -106,9 +90,6 @@ func (s *state) wotsPkFromSig(
 		panic(ErrMsgLen)
 	}
 
-	wotsLen1 := s.wotsLen1()
-	csum := wotsLen1 * (wotsW - 1)
-
 	s.T.address.Set(addr)
 	s.T.address.SetTypeAndClear(addressWotsPk)
 	s.T.address.SetKeyPairAddress(addr.GetKeyPairAddress())
@@ -116,23 +97,12 @@ func (s *state) wotsPkFromSig(
 	s.T.Reset()
 	curSig := cursor(sig)
 
-	// Signs every nibble of the message, computes the checksum, and
-	// feeds each signature to the T function.
-	for i := range wotsLen1 {
-		addr.SetChainAddress(i)
-		msgi := uint32((msg[i/2] >> ((1 - (i & 1)) << 2)) & 0xF)
-		sigi := s.chain(curSig.Next(s.n), msgi, wotsW-1-msgi, addr)
-
-		s.T.WriteMessage(sigi)
-		csum -= msgi
-	}
-
-	// Every nibble of the checksum is also signed feeding the signature
-	// to the T function.
-	for i := range wotsLen2 {
-		addr.SetChainAddress(wotsLen1 + i)
-		csumi := (csum >> (8 - 4*i)) & 0xF
-		sigi := s.chain(curSig.Next(s.n), csumi, wotsW-1-csumi, addr)
+	// Completes the chain of every nibble of the message and of its
+	// checksum, and feeds their ends to the T function.
+	var digits [hashsig.MaxWotsLen]uint32
+	for i, digit := range hashsig.WotsDigits(digits[:], msg) {
+		addr.SetChainAddress(uint32(i))
+		sigi := s.chain(curSig.Next(s.n), digit, wotsW-1-digit, addr)
 
 		s.T.WriteMessage(sigi)
 	}
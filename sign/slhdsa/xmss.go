@@ -1,5 +1,7 @@
 package slhdsa
 
+import "github.com/quantumcoinproject/circl/sign/internal/hashsig"
+
 // See FIPS 205 -- Section 6
 // eXtended Merkle Signature Scheme (XMSS) extends the WOTS+ signature
 // scheme into one that can sign multiple messages.
@@ -38,31 +40,12 @@ func (s *statePriv) xmssNodeIter(
 	s.H.address.Set(addr)
 	s.H.address.SetTypeAndClear(addressTree)
 
-	twoZ := uint32(1) << z
-	iTwoZ := i << z
-	for k := range twoZ {
-		li := iTwoZ + k
-		lz := uint32(0)
-
+	leaf := func(out []byte, li uint32) {
 		addr.SetTypeAndClear(addressWotsHash)
 		addr.SetKeyPairAddress(li)
-		node := s.wotsPkGen(addr)
-
-		for !stack.isEmpty() && stack.top().z == lz {
-			left := stack.pop()
-			li = (li - 1) >> 1
-			lz = lz + 1
-
-			s.H.address.SetTreeHeight(lz)
-			s.H.address.SetTreeIndex(li)
-			s.H.SetMsgs(left.node, node)
-			node = s.H.Final()
-		}
-
-		stack.push(item{node, lz})
+		copy(out, s.wotsPkGen(addr))
 	}
-
-	copy(root, stack.pop().node)
+	stack.TreeHash(root, i, z, leaf, s.nodeHash, nil)
 }
 
 // See FIPS 205 -- Section 6.2 -- Algorithm 10.
@@ -86,26 +69,9 @@ func (s *state) xmssPkFromSig(
 ) {
 	addr.SetTypeAndClear(addressWotsHash)
 	addr.SetKeyPairAddress(idx)
-	pk := xmssPublicKey(s.wotsPkFromSig(sig.wotsSig, msg, addr))
+	copy(out, s.wotsPkFromSig(sig.wotsSig, msg, addr))
 
-	treeIdx := idx
 	s.H.address.Set(addr)
 	s.H.address.SetTypeAndClear(addressTree)
-
-	authPath := cursor(sig.authPath)
-	for k := range s.hPrime {
-		if (idx>>k)&0x1 == 0 {
-			treeIdx = treeIdx >> 1
-			s.H.SetMsgs(pk, authPath.Next(s.n))
-		} else {
-			treeIdx = (treeIdx - 1) >> 1
-			s.H.SetMsgs(authPath.Next(s.n), pk)
-		}
-
-		s.H.address.SetTreeHeight(k + 1)
-		s.H.address.SetTreeIndex(treeIdx)
-		pk = s.H.Final()
-	}
-
-	copy(out, pk)
+	hashsig.RootFromAuthPath(out[:s.n], idx, sig.authPath, s.nodeHash)
 }
@@ -0,0 +1,129 @@
+package stateful
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// FileStore is a [Store] that keeps the next unused index in a file. Every
+// reservation writes a temporary file, syncs it to disk and renames it over
+// the state file, so the file is never left half written. It is safe for
+// concurrent use within a process, but a file must not be shared by
+// several stores.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// CreateFileStore creates the file of a store for a new key, whose next
+// unused index is zero. It fails if the file exists, which prevents the
+// state of a key from being reset.
+func CreateFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = f.Write(make([]byte, 8))
+	if err == nil {
+		err = f.Sync()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = syncDir(filepath.Dir(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{path: path}, nil
+}
+
+// OpenFileStore returns the store kept in the file, which must exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path}
+	if _, err := s.read(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reserve implements the [Store] interface.
+func (s *FileStore) Reserve(n uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+	if first+n < first {
+		return 0, ErrExhausted
+	}
+
+	if err := s.write(first + n); err != nil {
+		return 0, err
+	}
+	return first, nil
+}
+
+func (s *FileStore) read() (uint64, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, ErrState
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (s *FileStore) write(next uint64) error {
+	dir := filepath.Dir(s.path)
+	f, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	_, err = f.Write(binary.BigEndian.AppendUint64(nil, next))
+	if err == nil {
+		err = f.Sync()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir persists the entries of a directory, such as a renamed file.
+// Windows does not support syncing directories, and persists renames with
+// the metadata of the file instead.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if errClose := d.Close(); err == nil {
+		err = errClose
+	}
+	return err
+}
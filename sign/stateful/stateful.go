@@ -0,0 +1,111 @@
+// Package stateful provides the state persistence of stateful hash-based
+// signature schemes, such as XMSS and LMS.
+//
+// A private key of these schemes is a large set of one-time keys, and the
+// signature index selects which of them signs a message. Signing twice
+// with the same one-time key allows forgeries, so the index of every
+// signature must be unique over the lifetime of the key, including across
+// crashes and restarts of the signer.
+//
+// A [Store] keeps the next unused index of a key. Before computing a
+// signature, the key reserves its index from the store, and the store
+// persists the reservation before returning it. Hence, a crash after a
+// reservation can only lose indices, never release two signatures with the
+// same one. Keys may reserve indices in batches, see [Counter], trading
+// fewer writes to the store for more indices lost on a crash.
+//
+// A store must hold the state of a single key, and the state must never be
+// restored from a backup, as that rolls back the index.
+package stateful
+
+import (
+	"errors"
+	"sync"
+)
+
+// Store persists the signature indices reserved by a private key.
+type Store interface {
+	// Reserve marks as used the n indices that follow the ones reserved
+	// before, and returns the first of them. It must not return before
+	// the reservation is persisted, and must not return an index twice.
+	Reserve(n uint64) (first uint64, err error)
+}
+
+// Counter hands out the signature indices of a private key, reserving them
+// from a [Store] in batches. It is safe for concurrent use.
+type Counter struct {
+	mu        sync.Mutex
+	store     Store
+	next, end uint64 // Indices reserved and not used yet.
+	max       uint64 // Number of indices of the key.
+	batch     uint64
+}
+
+// NewCounter returns a counter of the indices in [0, max) reserved from the
+// store, batch indices at a time, or one at a time if batch is zero.
+func NewCounter(store Store, max, batch uint64) *Counter {
+	if batch == 0 {
+		batch = 1
+	}
+	return &Counter{store: store, max: max, batch: batch}
+}
+
+// Next returns an index that was not returned before, reserving a new batch
+// from the store if needed. It returns [ErrExhausted] if the key has no
+// indices left, or the error of the store if the reservation fails.
+func (c *Counter) Next() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.next == c.end {
+		first, err := c.store.Reserve(c.batch)
+		if err != nil {
+			return 0, err
+		}
+		if first >= c.max {
+			return 0, ErrExhausted
+		}
+
+		c.next = first
+		c.end = c.max
+		if c.batch < c.max-first {
+			c.end = first + c.batch
+		}
+	}
+
+	i := c.next
+	c.next++
+	return i, nil
+}
+
+// MemoryStore is a [Store] that keeps the state in memory. It does not
+// persist the state, so it is only suitable for keys that do not outlive
+// the process, and for testing. It is safe for concurrent use.
+type MemoryStore struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewMemoryStore returns a store whose next unused index is next.
+func NewMemoryStore(next uint64) *MemoryStore { return &MemoryStore{next: next} }
+
+// Reserve implements the [Store] interface.
+func (s *MemoryStore) Reserve(n uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next+n < s.next {
+		return 0, ErrExhausted
+	}
+	first := s.next
+	s.next += n
+	return first, nil
+}
+
+var (
+	// ErrExhausted is returned when a key has no signature indices left.
+	ErrExhausted = errors.New("sign/stateful: no signature indices left")
+
+	// ErrState is returned when the persisted state cannot be parsed.
+	ErrState = errors.New("sign/stateful: invalid state")
+)
@@ -0,0 +1,71 @@
+package stateful_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/stateful"
+)
+
+func TestCounter(t *testing.T) {
+	store := stateful.NewMemoryStore(0)
+	c := stateful.NewCounter(store, 10, 4)
+	for want := uint64(0); want < 10; want++ {
+		got, err := c.Next()
+		test.CheckNoErr(t, err, "next")
+		if got != want {
+			test.ReportError(t, got, want)
+		}
+	}
+	if _, err := c.Next(); !errors.Is(err, stateful.ErrExhausted) {
+		test.ReportError(t, err, stateful.ErrExhausted)
+	}
+
+	// The indices of a batch not used are lost, never reused.
+	c = stateful.NewCounter(store, 100, 4)
+	got, err := c.Next()
+	test.CheckNoErr(t, err, "next")
+	if want := uint64(16); got != want {
+		test.ReportError(t, got, want)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	s, err := stateful.CreateFileStore(path)
+	test.CheckNoErr(t, err, "create")
+	if _, err = stateful.CreateFileStore(path); err == nil {
+		t.Fatal("creating an existing store succeeded")
+	}
+
+	c := stateful.NewCounter(s, 1000, 10)
+	for range 3 {
+		_, err = c.Next()
+		test.CheckNoErr(t, err, "next")
+	}
+
+	// Simulates a crash: the reopened store continues after the batch.
+	s, err = stateful.OpenFileStore(path)
+	test.CheckNoErr(t, err, "open")
+	got, err := s.Reserve(5)
+	test.CheckNoErr(t, err, "reserve")
+	if want := uint64(10); got != want {
+		test.ReportError(t, got, want)
+	}
+	got, err = s.Reserve(1)
+	test.CheckNoErr(t, err, "reserve")
+	if want := uint64(15); got != want {
+		test.ReportError(t, got, want)
+	}
+
+	test.CheckNoErr(t, os.WriteFile(path, []byte{1}, 0o600), "write")
+	if _, err = s.Reserve(1); err != stateful.ErrState {
+		test.ReportError(t, err, stateful.ErrState)
+	}
+	if _, err = stateful.OpenFileStore(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("opening a missing store succeeded")
+	}
+}
@@ -0,0 +1,50 @@
+package xmss
+
+import "encoding/binary"
+
+// See RFC 8391 -- Section 2.5
+// Hash Function Address Scheme
+
+const (
+	addressOTS = iota
+	addressLTree
+	addressHashTree
+)
+
+const addressSize = 32
+
+// address is an address of RFC 8391. The meaning of the words after the
+// type depends on it:
+//   - OTS: OTS address, chain address, hash address.
+//   - L-tree: L-tree address, tree height, tree index.
+//   - hash tree: padding, tree height, tree index.
+type address struct {
+	layer      uint32
+	tree       uint64
+	typ        uint32
+	word       [3]uint32
+	keyAndMask uint32
+}
+
+// newAddress returns an address of the type given in the tree at the
+// layer, with the remaining words cleared.
+func newAddress(layer uint32, tree uint64, typ uint32) address {
+	return address{layer: layer, tree: tree, typ: typ}
+}
+
+func (a *address) setOTSAddress(i uint32)   { a.word[0] = i }
+func (a *address) setLTreeAddress(i uint32) { a.word[0] = i }
+func (a *address) setChainAddress(i uint32) { a.word[1] = i }
+func (a *address) setTreeHeight(i uint32)   { a.word[1] = i }
+func (a *address) setHashAddress(i uint32)  { a.word[2] = i }
+func (a *address) setTreeIndex(i uint32)    { a.word[2] = i }
+
+func (a *address) toBytes(b []byte) {
+	binary.BigEndian.PutUint32(b[0:], a.layer)
+	binary.BigEndian.PutUint64(b[4:], a.tree)
+	binary.BigEndian.PutUint32(b[12:], a.typ)
+	binary.BigEndian.PutUint32(b[16:], a.word[0])
+	binary.BigEndian.PutUint32(b[20:], a.word[1])
+	binary.BigEndian.PutUint32(b[24:], a.word[2])
+	binary.BigEndian.PutUint32(b[28:], a.keyAndMask)
+}
@@ -0,0 +1,140 @@
+package xmss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/quantumcoinproject/circl/sign/stateful"
+)
+
+// [PublicKey] stores a public key of XMSS or XMSS^MT, which is encoded as
+// OID || root || SEED.
+// It implements the [crypto.PublicKey] and [encoding.BinaryMarshaler]
+// interfaces.
+type PublicKey struct {
+	root, seed []byte
+	ID
+}
+
+// [PrivateKey] stores a private key of XMSS or XMSS^MT, which is encoded
+// as SK_SEED || SK_PRF || PK, where PK is the encoding of its public key.
+// It implements the [crypto.Signer], [crypto.PrivateKey] and
+// [encoding.BinaryMarshaler] interfaces.
+//
+// A private key signs only after a store of its state is set with
+// [PrivateKey.SetStore]. It is safe for concurrent use.
+type PrivateKey struct {
+	skSeed, skPrf []byte
+	publicKey     PublicKey
+	counter       *stateful.Counter
+
+	mu     sync.Mutex  // Guards caches.
+	caches []treeCache // Trees used by the last signature, one per layer.
+	ID
+}
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+// Caller must specify the public key's [ID] in advance, which must match
+// the OID of the encoding.
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	p := k.ID.params()
+	if len(b) != p.PublicKeySize() || binary.BigEndian.Uint32(b) != p.oid {
+		return ErrPublicKey
+	}
+
+	c := cursor(bytes.Clone(b[4:]))
+	k.root = c.Next(p.n)
+	k.seed = c.Next(p.n)
+	return nil
+}
+
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint32(nil, k.params().oid)
+	b = append(b, k.root...)
+	return append(b, k.seed...), nil
+}
+
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && k.ID == other.ID &&
+		bytes.Equal(k.root, other.root) &&
+		bytes.Equal(k.seed, other.seed)
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes.
+// Caller must specify the private key's [ID] in advance. The key has no
+// store of its state.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	p := k.ID.params()
+	if len(b) != p.PrivateKeySize() {
+		return ErrPrivateKey
+	}
+
+	pub := PublicKey{ID: k.ID}
+	if err := pub.UnmarshalBinary(b[2*p.n:]); err != nil {
+		return ErrPrivateKey
+	}
+
+	c := cursor(bytes.Clone(b[:2*p.n]))
+	*k = PrivateKey{
+		skSeed:    c.Next(p.n),
+		skPrf:     c.Next(p.n),
+		publicKey: pub,
+		ID:        k.ID,
+	}
+	return nil
+}
+
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	pk, err := k.publicKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	b := append(bytes.Clone(k.skSeed), k.skPrf...)
+	return append(b, pk...), nil
+}
+
+func (k *PrivateKey) Public() crypto.PublicKey {
+	pub := k.publicKey
+	return &pub
+}
+
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	return ok && k.ID == other.ID &&
+		subtle.ConstantTimeCompare(k.skSeed, other.skSeed) == 1 &&
+		subtle.ConstantTimeCompare(k.skPrf, other.skPrf) == 1 &&
+		k.publicKey.Equal(&other.publicKey)
+}
+
+// SetStore sets the store of the state of the key, from which signing
+// reserves the indices of signatures, batch indices at a time. A batch of
+// zero reserves one index at a time. Larger batches make fewer writes to
+// the store, but the indices of a batch not used when the process exits
+// are lost.
+//
+// The store must be used by this key only, and only one [PrivateKey] value
+// of a key may have it set. It must not be called concurrently with
+// signing.
+func (k *PrivateKey) SetStore(store stateful.Store, batch uint64) {
+	k.counter = stateful.NewCounter(store, 1<<k.params().h, batch)
+}
+
+// Sign returns the signature of the message, reserving its index from the
+// store of the key. The rand argument is not used, as signatures are
+// deterministic.
+// It implements the [crypto.Signer] interface. Messages must not be
+// pre-hashed, so opts.HashFunc() must return zero.
+func (k *PrivateKey) Sign(
+	rand io.Reader, msg []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, ErrPreHash
+	}
+	return Sign(k, msg)
+}
@@ -0,0 +1,359 @@
+package xmss
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"strings"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+)
+
+// [ID] identifies the supported parameter sets of XMSS and XMSS^MT.
+// Note that the zero value is not a valid identifier.
+type ID byte
+
+//nolint:stylecheck
+const (
+	XMSS_SHA2_10_256          ID = iota + 1 // XMSS-SHA2_10_256
+	XMSS_SHA2_16_256                        // XMSS-SHA2_16_256
+	XMSS_SHA2_20_256                        // XMSS-SHA2_20_256
+	XMSS_SHA2_10_192                        // XMSS-SHA2_10_192
+	XMSS_SHA2_16_192                        // XMSS-SHA2_16_192
+	XMSS_SHA2_20_192                        // XMSS-SHA2_20_192
+	XMSS_SHAKE256_10_256                    // XMSS-SHAKE256_10_256
+	XMSS_SHAKE256_16_256                    // XMSS-SHAKE256_16_256
+	XMSS_SHAKE256_20_256                    // XMSS-SHAKE256_20_256
+	XMSS_SHAKE256_10_192                    // XMSS-SHAKE256_10_192
+	XMSS_SHAKE256_16_192                    // XMSS-SHAKE256_16_192
+	XMSS_SHAKE256_20_192                    // XMSS-SHAKE256_20_192
+	XMSSMT_SHA2_20_2_256                    // XMSSMT-SHA2_20/2_256
+	XMSSMT_SHA2_20_4_256                    // XMSSMT-SHA2_20/4_256
+	XMSSMT_SHA2_40_2_256                    // XMSSMT-SHA2_40/2_256
+	XMSSMT_SHA2_40_4_256                    // XMSSMT-SHA2_40/4_256
+	XMSSMT_SHA2_40_8_256                    // XMSSMT-SHA2_40/8_256
+	XMSSMT_SHA2_60_3_256                    // XMSSMT-SHA2_60/3_256
+	XMSSMT_SHA2_60_6_256                    // XMSSMT-SHA2_60/6_256
+	XMSSMT_SHA2_60_12_256                   // XMSSMT-SHA2_60/12_256
+	XMSSMT_SHA2_20_2_192                    // XMSSMT-SHA2_20/2_192
+	XMSSMT_SHA2_20_4_192                    // XMSSMT-SHA2_20/4_192
+	XMSSMT_SHA2_40_2_192                    // XMSSMT-SHA2_40/2_192
+	XMSSMT_SHA2_40_4_192                    // XMSSMT-SHA2_40/4_192
+	XMSSMT_SHA2_40_8_192                    // XMSSMT-SHA2_40/8_192
+	XMSSMT_SHA2_60_3_192                    // XMSSMT-SHA2_60/3_192
+	XMSSMT_SHA2_60_6_192                    // XMSSMT-SHA2_60/6_192
+	XMSSMT_SHA2_60_12_192                   // XMSSMT-SHA2_60/12_192
+	XMSSMT_SHAKE256_20_2_256                // XMSSMT-SHAKE256_20/2_256
+	XMSSMT_SHAKE256_20_4_256                // XMSSMT-SHAKE256_20/4_256
+	XMSSMT_SHAKE256_40_2_256                // XMSSMT-SHAKE256_40/2_256
+	XMSSMT_SHAKE256_40_4_256                // XMSSMT-SHAKE256_40/4_256
+	XMSSMT_SHAKE256_40_8_256                // XMSSMT-SHAKE256_40/8_256
+	XMSSMT_SHAKE256_60_3_256                // XMSSMT-SHAKE256_60/3_256
+	XMSSMT_SHAKE256_60_6_256                // XMSSMT-SHAKE256_60/6_256
+	XMSSMT_SHAKE256_60_12_256               // XMSSMT-SHAKE256_60/12_256
+	XMSSMT_SHAKE256_20_2_192                // XMSSMT-SHAKE256_20/2_192
+	XMSSMT_SHAKE256_20_4_192                // XMSSMT-SHAKE256_20/4_192
+	XMSSMT_SHAKE256_40_2_192                // XMSSMT-SHAKE256_40/2_192
+	XMSSMT_SHAKE256_40_4_192                // XMSSMT-SHAKE256_40/4_192
+	XMSSMT_SHAKE256_40_8_192                // XMSSMT-SHAKE256_40/8_192
+	XMSSMT_SHAKE256_60_3_192                // XMSSMT-SHAKE256_60/3_192
+	XMSSMT_SHAKE256_60_6_192                // XMSSMT-SHAKE256_60/6_192
+	XMSSMT_SHAKE256_60_12_192               // XMSSMT-SHAKE256_60/12_192
+	_MaxParams
+)
+
+// [IDByName] returns the [ID] that corresponds to the given name,
+// or an error if no parameter set was found.
+// Names are case insensitive.
+//
+// Example:
+//
+//	IDByName("XMSSMT-SHA2_20/4_256") // returns (XMSSMT_SHA2_20_4_256, nil)
+func IDByName(name string) (ID, error) {
+	v := strings.ToLower(name)
+	for i := range supportedParams {
+		if strings.ToLower(supportedParams[i].name) == v {
+			return supportedParams[i].ID, nil
+		}
+	}
+
+	return ID(0), ErrParam
+}
+
+// [IDByOID] returns the [ID] of the XMSS parameter set, or of the XMSS^MT
+// one if mt is true, with the 32-bit identifier given, as registered by
+// RFC 8391 and SP 800-208.
+func IDByOID(oid uint32, mt bool) (ID, error) {
+	for i := range supportedParams {
+		if supportedParams[i].oid == oid && (supportedParams[i].d > 1) == mt {
+			return supportedParams[i].ID, nil
+		}
+	}
+
+	return ID(0), ErrParam
+}
+
+// IsValid returns true if the parameter set is supported.
+func (id ID) IsValid() bool { return 0 < id && id < _MaxParams }
+
+func (id ID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return supportedParams[id-1].name
+}
+
+// OID returns the 32-bit identifier of the parameter set, which prefixes
+// the encoding of public keys. XMSS and XMSS^MT have separate identifiers.
+func (id ID) OID() uint32 { return id.params().oid }
+
+// IsMultiTree returns true if the parameter set is of XMSS^MT.
+func (id ID) IsMultiTree() bool { return id.params().d > 1 }
+
+// Height returns the total height of the trees, so keys can produce
+// 2^Height signatures.
+func (id ID) Height() int { return int(id.params().h) }
+
+// Layers returns the number of layers of trees, which is one for XMSS.
+func (id ID) Layers() int { return int(id.params().d) }
+
+// SeedSize returns the size of the seeds used by [NewKeyFromSeed].
+func (id ID) SeedSize() int { return int(3 * id.params().n) }
+
+// PublicKeySize returns the size of encoded public keys.
+func (id ID) PublicKeySize() int { return id.params().PublicKeySize() }
+
+// PrivateKeySize returns the size of encoded private keys.
+func (id ID) PrivateKeySize() int { return id.params().PrivateKeySize() }
+
+// SignatureSize returns the size of signatures.
+func (id ID) SignatureSize() int { return id.params().SignatureSize() }
+
+func (id ID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+	return &supportedParams[id-1]
+}
+
+// params contains all the relevant constants of a parameter set.
+type params struct {
+	name   string // Name of the parameter set.
+	oid    uint32 // Identifier of RFC 8391 and SP 800-208.
+	n      uint32 // Length of hash outputs and WOTS+ messages.
+	h      uint32 // Total height of the trees.
+	d      uint32 // Number of layers of trees, one for XMSS.
+	isSHA2 bool   // True, if the hash function is SHA2, otherwise is SHAKE256.
+	ID            // Identifier of the parameter set.
+}
+
+// Stores all the supported (read-only) parameter sets.
+var supportedParams = [_MaxParams - 1]params{
+	{ID: XMSS_SHA2_10_256, oid: 0x01, n: 32, h: 10, d: 1, isSHA2: true, name: "XMSS-SHA2_10_256"},
+	{ID: XMSS_SHA2_16_256, oid: 0x02, n: 32, h: 16, d: 1, isSHA2: true, name: "XMSS-SHA2_16_256"},
+	{ID: XMSS_SHA2_20_256, oid: 0x03, n: 32, h: 20, d: 1, isSHA2: true, name: "XMSS-SHA2_20_256"},
+	{ID: XMSS_SHA2_10_192, oid: 0x0d, n: 24, h: 10, d: 1, isSHA2: true, name: "XMSS-SHA2_10_192"},
+	{ID: XMSS_SHA2_16_192, oid: 0x0e, n: 24, h: 16, d: 1, isSHA2: true, name: "XMSS-SHA2_16_192"},
+	{ID: XMSS_SHA2_20_192, oid: 0x0f, n: 24, h: 20, d: 1, isSHA2: true, name: "XMSS-SHA2_20_192"},
+	{ID: XMSS_SHAKE256_10_256, oid: 0x10, n: 32, h: 10, d: 1, name: "XMSS-SHAKE256_10_256"},
+	{ID: XMSS_SHAKE256_16_256, oid: 0x11, n: 32, h: 16, d: 1, name: "XMSS-SHAKE256_16_256"},
+	{ID: XMSS_SHAKE256_20_256, oid: 0x12, n: 32, h: 20, d: 1, name: "XMSS-SHAKE256_20_256"},
+	{ID: XMSS_SHAKE256_10_192, oid: 0x13, n: 24, h: 10, d: 1, name: "XMSS-SHAKE256_10_192"},
+	{ID: XMSS_SHAKE256_16_192, oid: 0x14, n: 24, h: 16, d: 1, name: "XMSS-SHAKE256_16_192"},
+	{ID: XMSS_SHAKE256_20_192, oid: 0x15, n: 24, h: 20, d: 1, name: "XMSS-SHAKE256_20_192"},
+	{ID: XMSSMT_SHA2_20_2_256, oid: 0x01, n: 32, h: 20, d: 2, isSHA2: true, name: "XMSSMT-SHA2_20/2_256"},
+	{ID: XMSSMT_SHA2_20_4_256, oid: 0x02, n: 32, h: 20, d: 4, isSHA2: true, name: "XMSSMT-SHA2_20/4_256"},
+	{ID: XMSSMT_SHA2_40_2_256, oid: 0x03, n: 32, h: 40, d: 2, isSHA2: true, name: "XMSSMT-SHA2_40/2_256"},
+	{ID: XMSSMT_SHA2_40_4_256, oid: 0x04, n: 32, h: 40, d: 4, isSHA2: true, name: "XMSSMT-SHA2_40/4_256"},
+	{ID: XMSSMT_SHA2_40_8_256, oid: 0x05, n: 32, h: 40, d: 8, isSHA2: true, name: "XMSSMT-SHA2_40/8_256"},
+	{ID: XMSSMT_SHA2_60_3_256, oid: 0x06, n: 32, h: 60, d: 3, isSHA2: true, name: "XMSSMT-SHA2_60/3_256"},
+	{ID: XMSSMT_SHA2_60_6_256, oid: 0x07, n: 32, h: 60, d: 6, isSHA2: true, name: "XMSSMT-SHA2_60/6_256"},
+	{ID: XMSSMT_SHA2_60_12_256, oid: 0x08, n: 32, h: 60, d: 12, isSHA2: true, name: "XMSSMT-SHA2_60/12_256"},
+	{ID: XMSSMT_SHA2_20_2_192, oid: 0x21, n: 24, h: 20, d: 2, isSHA2: true, name: "XMSSMT-SHA2_20/2_192"},
+	{ID: XMSSMT_SHA2_20_4_192, oid: 0x22, n: 24, h: 20, d: 4, isSHA2: true, name: "XMSSMT-SHA2_20/4_192"},
+	{ID: XMSSMT_SHA2_40_2_192, oid: 0x23, n: 24, h: 40, d: 2, isSHA2: true, name: "XMSSMT-SHA2_40/2_192"},
+	{ID: XMSSMT_SHA2_40_4_192, oid: 0x24, n: 24, h: 40, d: 4, isSHA2: true, name: "XMSSMT-SHA2_40/4_192"},
+	{ID: XMSSMT_SHA2_40_8_192, oid: 0x25, n: 24, h: 40, d: 8, isSHA2: true, name: "XMSSMT-SHA2_40/8_192"},
+	{ID: XMSSMT_SHA2_60_3_192, oid: 0x26, n: 24, h: 60, d: 3, isSHA2: true, name: "XMSSMT-SHA2_60/3_192"},
+	{ID: XMSSMT_SHA2_60_6_192, oid: 0x27, n: 24, h: 60, d: 6, isSHA2: true, name: "XMSSMT-SHA2_60/6_192"},
+	{ID: XMSSMT_SHA2_60_12_192, oid: 0x28, n: 24, h: 60, d: 12, isSHA2: true, name: "XMSSMT-SHA2_60/12_192"},
+	{ID: XMSSMT_SHAKE256_20_2_256, oid: 0x29, n: 32, h: 20, d: 2, name: "XMSSMT-SHAKE256_20/2_256"},
+	{ID: XMSSMT_SHAKE256_20_4_256, oid: 0x2a, n: 32, h: 20, d: 4, name: "XMSSMT-SHAKE256_20/4_256"},
+	{ID: XMSSMT_SHAKE256_40_2_256, oid: 0x2b, n: 32, h: 40, d: 2, name: "XMSSMT-SHAKE256_40/2_256"},
+	{ID: XMSSMT_SHAKE256_40_4_256, oid: 0x2c, n: 32, h: 40, d: 4, name: "XMSSMT-SHAKE256_40/4_256"},
+	{ID: XMSSMT_SHAKE256_40_8_256, oid: 0x2d, n: 32, h: 40, d: 8, name: "XMSSMT-SHAKE256_40/8_256"},
+	{ID: XMSSMT_SHAKE256_60_3_256, oid: 0x2e, n: 32, h: 60, d: 3, name: "XMSSMT-SHAKE256_60/3_256"},
+	{ID: XMSSMT_SHAKE256_60_6_256, oid: 0x2f, n: 32, h: 60, d: 6, name: "XMSSMT-SHAKE256_60/6_256"},
+	{ID: XMSSMT_SHAKE256_60_12_256, oid: 0x30, n: 32, h: 60, d: 12, name: "XMSSMT-SHAKE256_60/12_256"},
+	{ID: XMSSMT_SHAKE256_20_2_192, oid: 0x31, n: 24, h: 20, d: 2, name: "XMSSMT-SHAKE256_20/2_192"},
+	{ID: XMSSMT_SHAKE256_20_4_192, oid: 0x32, n: 24, h: 20, d: 4, name: "XMSSMT-SHAKE256_20/4_192"},
+	{ID: XMSSMT_SHAKE256_40_2_192, oid: 0x33, n: 24, h: 40, d: 2, name: "XMSSMT-SHAKE256_40/2_192"},
+	{ID: XMSSMT_SHAKE256_40_4_192, oid: 0x34, n: 24, h: 40, d: 4, name: "XMSSMT-SHAKE256_40/4_192"},
+	{ID: XMSSMT_SHAKE256_40_8_192, oid: 0x35, n: 24, h: 40, d: 8, name: "XMSSMT-SHAKE256_40/8_192"},
+	{ID: XMSSMT_SHAKE256_60_3_192, oid: 0x36, n: 24, h: 60, d: 3, name: "XMSSMT-SHAKE256_60/3_192"},
+	{ID: XMSSMT_SHAKE256_60_6_192, oid: 0x37, n: 24, h: 60, d: 6, name: "XMSSMT-SHAKE256_60/6_192"},
+	{ID: XMSSMT_SHAKE256_60_12_192, oid: 0x38, n: 24, h: 60, d: 12, name: "XMSSMT-SHAKE256_60/12_192"},
+}
+
+// hPrime is the height of each tree.
+func (p *params) hPrime() uint32 { return p.h / p.d }
+
+// idxSize is the length of the signature index, which is four bytes for
+// XMSS and the bytes needed to encode h bits for XMSS^MT.
+func (p *params) idxSize() uint32 {
+	if p.d == 1 {
+		return 4
+	}
+	return (p.h + 7) / 8
+}
+
+// padSize is the length of the domain separator prefixed to every hash
+// input, which is n bytes, except for the 192-bit parameter sets of
+// SP 800-208 that use four bytes.
+func (p *params) padSize() uint32 {
+	if p.n == 24 {
+		return 4
+	}
+	return p.n
+}
+
+func (p *params) PublicKeySize() int  { return int(4 + 2*p.n) }
+func (p *params) PrivateKeySize() int { return int(2*p.n) + p.PublicKeySize() }
+func (p *params) SignatureSize() int {
+	return int(p.idxSize() + p.n + p.d*p.wotsSigSize() + p.h*p.n)
+}
+
+// Domain separators of the hash functions, see RFC 8391 -- Section 5.1 and
+// SP 800-208 -- Section 7.2.1.
+const (
+	domainF         = 0
+	domainH         = 1
+	domainHashMsg   = 2
+	domainPRF       = 3
+	domainPRFKeygen = 4
+)
+
+// hasher computes the keyed hash functions of a parameter set, which are
+// toByte(domain, padSize) || KEY || M hashed with SHA-256 or SHAKE256 and
+// truncated to n bytes. It reuses its buffers, so it is not safe for
+// concurrent use.
+type hasher struct {
+	*params
+	sha    hash.Hash
+	shake  sha3.State
+	pad    [32]byte
+	digest [sha256.Size]byte
+	buf    [2 * 32]byte // Input of F and H.
+	key    [32]byte     // Key of F and H.
+	mask   [2 * 32]byte // Bitmasks.
+	addr   [addressSize]byte
+}
+
+func newHasher(p *params) *hasher {
+	h := &hasher{params: p}
+	if p.isSHA2 {
+		h.sha = sha256.New()
+	} else {
+		h.shake = sha3.NewShake256()
+	}
+	return h
+}
+
+// sum sets out to the hash of the domain separator and the inputs.
+func (h *hasher) sum(out []byte, domain uint32, in ...[]byte) {
+	pad := h.pad[:h.padSize()]
+	binary.BigEndian.PutUint32(pad[len(pad)-4:], domain)
+
+	if h.isSHA2 {
+		h.sha.Reset()
+		_, _ = h.sha.Write(pad)
+		for _, x := range in {
+			_, _ = h.sha.Write(x)
+		}
+		h.sha.Sum(h.digest[:0])
+		copy(out[:h.n], h.digest[:])
+	} else {
+		h.shake.Reset()
+		_, _ = h.shake.Write(pad)
+		for _, x := range in {
+			_, _ = h.shake.Write(x)
+		}
+		_, _ = h.shake.Read(out[:h.n])
+	}
+}
+
+// prf sets out to PRF(seed, ADRS).
+func (h *hasher) prf(out, seed []byte, a *address) {
+	a.toBytes(h.addr[:])
+	h.sum(out, domainPRF, seed, h.addr[:])
+}
+
+// prfKeygen sets out to the WOTS+ private key PRF_keygen(skSeed, seed || ADRS).
+func (h *hasher) prfKeygen(out, skSeed, seed []byte, a *address) {
+	a.toBytes(h.addr[:])
+	h.sum(out, domainPRFKeygen, skSeed, seed, h.addr[:])
+}
+
+// prfMsg sets out to the randomness PRF(skPrf, toByte(idx, 32)) of the
+// signature with index idx.
+func (h *hasher) prfMsg(out, skPrf []byte, idx uint64) {
+	var b [32]byte
+	binary.BigEndian.PutUint64(b[24:], idx)
+	h.sum(out, domainPRF, skPrf, b[:])
+}
+
+// hashMsg sets out to H_msg(r || root || toByte(idx, n), msg).
+func (h *hasher) hashMsg(out, r, root []byte, idx uint64, msg []byte) {
+	b := make([]byte, h.n)
+	binary.BigEndian.PutUint64(b[h.n-8:], idx)
+	h.sum(out, domainHashMsg, r, root, b, msg)
+}
+
+// f sets out to F(KEY, x XOR BM), where the key and the bitmask are taken
+// from the PRF with the address. Note that out can alias x.
+func (h *hasher) f(out, x, seed []byte, a *address) {
+	n := h.n
+	key, mask := h.key[:n], h.mask[:n]
+	a.keyAndMask = 0
+	h.prf(key, seed, a)
+	a.keyAndMask = 1
+	h.prf(mask, seed, a)
+
+	buf := h.buf[:n]
+	xor(buf, x, mask)
+	h.sum(out, domainF, key, buf)
+}
+
+// randHash sets out to RAND_HASH(left, right, SEED, ADRS) as described in
+// RFC 8391 -- Section 4.1.4. Note that out can alias left or right.
+func (h *hasher) randHash(out, left, right, seed []byte, a *address) {
+	n := h.n
+	key, mask := h.key[:n], h.mask[:2*n]
+	a.keyAndMask = 0
+	h.prf(key, seed, a)
+	a.keyAndMask = 1
+	h.prf(mask[:n], seed, a)
+	a.keyAndMask = 2
+	h.prf(mask[n:], seed, a)
+
+	buf := h.buf[:2*n]
+	xor(buf[:n], left, mask[:n])
+	xor(buf[n:], right, mask[n:])
+	h.sum(out, domainH, key, buf)
+}
+
+func xor(out, x, y []byte) {
+	for i := range out {
+		out[i] = x[i] ^ y[i]
+	}
+}
+
+type cursor []byte
+
+func (c *cursor) Next(n uint32) (out []byte) {
+	if len(*c) >= int(n) {
+		out = (*c)[:n]
+		*c = (*c)[n:]
+	}
+	return
+}
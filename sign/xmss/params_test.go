@@ -0,0 +1,112 @@
+package xmss
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/sha3"
+	"github.com/quantumcoinproject/circl/internal/test"
+)
+
+// Checks the hash functions against their definitions in RFC 8391 --
+// Section 5.1 and SP 800-208 -- Section 5, written out byte by byte: the
+// domain separator takes n bytes, or four bytes for n = 24, and the
+// outputs of SHA-256 are truncated to n bytes.
+func TestHasher(t *testing.T) {
+	for _, id := range []ID{
+		XMSS_SHA2_10_256,
+		XMSS_SHA2_10_192,
+		XMSS_SHAKE256_10_256,
+		XMSS_SHAKE256_10_192,
+	} {
+		t.Run(id.String(), func(t *testing.T) { testHasher(t, id) })
+	}
+}
+
+func testHasher(t *testing.T, id ID) {
+	p := id.params()
+	n := int(p.n)
+	h := newHasher(p)
+
+	hash := func(domain byte, in ...[]byte) []byte {
+		padLen := n
+		if n == 24 {
+			padLen = 4
+		}
+		b := make([]byte, padLen)
+		b[padLen-1] = domain
+		for _, x := range in {
+			b = append(b, x...)
+		}
+		if p.isSHA2 {
+			d := sha256.Sum256(b)
+			return d[:n]
+		}
+		d := make([]byte, n)
+		sha3.ShakeSum256(d, b)
+		return d
+	}
+
+	seed := bytes.Repeat([]byte{0x11}, n)
+	skSeed := bytes.Repeat([]byte{0x22}, n)
+	x := bytes.Repeat([]byte{0x33}, n)
+	y := bytes.Repeat([]byte{0x44}, n)
+
+	a := newAddress(3, 0x0102030405060708, addressOTS)
+	a.setOTSAddress(5)
+	a.setChainAddress(6)
+	a.setHashAddress(7)
+	adrs := func(keyAndMask byte) []byte {
+		b := make([]byte, 32)
+		b[3] = 3
+		binary.BigEndian.PutUint64(b[4:], 0x0102030405060708)
+		b[15] = addressOTS
+		b[19], b[23], b[27] = 5, 6, 7
+		b[31] = keyAndMask
+		return b
+	}
+
+	got := make([]byte, n)
+	h.prfKeygen(got, skSeed, seed, &a)
+	want := hash(domainPRFKeygen, skSeed, seed, adrs(0))
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want, "PRF_keygen")
+	}
+
+	h.f(got, x, seed, &a)
+	key := hash(domainPRF, seed, adrs(0))
+	bm := hash(domainPRF, seed, adrs(1))
+	xor(bm, bm, x)
+	want = hash(domainF, key, bm)
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want, "F")
+	}
+
+	h.randHash(got, x, y, seed, &a)
+	key = hash(domainPRF, seed, adrs(0))
+	bmL := hash(domainPRF, seed, adrs(1))
+	bmR := hash(domainPRF, seed, adrs(2))
+	xor(bmL, bmL, x)
+	xor(bmR, bmR, y)
+	want = hash(domainH, key, bmL, bmR)
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want, "RAND_HASH")
+	}
+
+	idx := make([]byte, 32)
+	idx[31] = 9
+	h.prfMsg(got, skSeed, 9)
+	want = hash(domainPRF, skSeed, idx)
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want, "PRF")
+	}
+
+	msg := []byte("message")
+	h.hashMsg(got, x, y, 9, msg)
+	want = hash(domainHashMsg, x, y, idx[32-n:], msg)
+	if !bytes.Equal(got, want) {
+		test.ReportError(t, got, want, "H_msg")
+	}
+}
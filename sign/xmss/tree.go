@@ -0,0 +1,172 @@
+package xmss
+
+import "github.com/quantumcoinproject/circl/sign/internal/hashsig"
+
+// See RFC 8391 -- Section 4.1
+// XMSS trees, with leaves that compress WOTS+ public keys with L-trees.
+
+// See RFC 8391 -- Section 4.1.5 -- Algorithm 8.
+// It compresses the WOTS+ public key in place and sets out to its root. The
+// address must be an L-tree address.
+func (h *hasher) lTree(out, pk, seed []byte, a address) {
+	n := h.n
+	l := h.wotsLen()
+	a.setTreeHeight(0)
+	for l > 1 {
+		for i := range l / 2 {
+			a.setTreeIndex(i)
+			h.randHash(pk[i*n:(i+1)*n], pk[2*i*n:(2*i+1)*n], pk[(2*i+1)*n:(2*i+2)*n], seed, &a)
+		}
+		if l%2 == 1 {
+			copy(pk[(l/2)*n:], pk[(l-1)*n:l*n])
+		}
+		l = (l + 1) / 2
+		a.setTreeHeight(a.word[1] + 1)
+	}
+	copy(out, pk[:n])
+}
+
+// leaf sets out to the i-th leaf of the tree at the layer.
+func (h *hasher) leaf(out []byte, i, layer uint32, tree uint64, skSeed, seed []byte) {
+	pk := make([]byte, h.wotsSigSize())
+	ots := newAddress(layer, tree, addressOTS)
+	ots.setOTSAddress(i)
+	h.wotsPkGen(pk, skSeed, seed, ots)
+
+	lt := newAddress(layer, tree, addressLTree)
+	lt.setLTreeAddress(i)
+	h.lTree(out, pk, seed, lt)
+}
+
+// See RFC 8391 -- Section 4.1.6 -- Algorithm 9.
+// It sets out to the node at height z and index i of the tree at the layer.
+// If save is not nil, it is called with every node computed.
+func (h *hasher) treeHash(
+	out []byte, i, z, layer uint32, tree uint64, skSeed, seed []byte,
+	save func(i, z uint32, node []byte),
+) {
+	if !(z <= h.hPrime() && i < (1<<(h.hPrime()-z))) {
+		panic(ErrTree)
+	}
+
+	stack := hashsig.NewStack(h.n, z)
+	leaf := func(out []byte, i uint32) { h.leaf(out, i, layer, tree, skSeed, seed) }
+	stack.TreeHash(out, i, z, leaf, h.nodeHash(layer, tree, seed), save)
+}
+
+// nodeHash returns the hash of the nodes of the tree at the layer.
+func (h *hasher) nodeHash(layer uint32, tree uint64, seed []byte) hashsig.NodeHash {
+	a := newAddress(layer, tree, addressHashTree)
+	return func(out, left, right []byte, z, i uint32) {
+		a.setTreeHeight(z)
+		a.setTreeIndex(i)
+		h.randHash(out, left, right, seed, &a)
+	}
+}
+
+// See RFC 8391 -- Section 4.1.10 -- Algorithm 13.
+// It sets out to the root of the tree at the layer computed from the
+// signature of msg with the idx-th one-time key and its authentication path.
+func (h *hasher) rootFromSig(
+	out []byte, idx uint32, wotsSig, authPath, msg []byte,
+	layer uint32, tree uint64, seed []byte,
+) {
+	pk := make([]byte, h.wotsSigSize())
+	ots := newAddress(layer, tree, addressOTS)
+	ots.setOTSAddress(idx)
+	h.wotsPkFromSig(pk, wotsSig, msg, seed, ots)
+
+	lt := newAddress(layer, tree, addressLTree)
+	lt.setLTreeAddress(idx)
+	h.lTree(out, pk, seed, lt)
+
+	hashsig.RootFromAuthPath(out[:h.n], idx, authPath, h.nodeHash(layer, tree, seed))
+}
+
+// treeCache stores the nodes of a tree of the private key needed to compute
+// the authentication paths of its leaves. With s = hPrime/2, it keeps the
+// nodes at heights s and above, and the nodes of the subtree of height s
+// containing the current leaf. So, signing with the leaves in order
+// computes each leaf twice, taking 2^(s+1) + 2^(hPrime-s+1) nodes of memory.
+//
+// For the layers above the bottom one, it also keeps the WOTS+ signature of
+// the root of the tree below, which only changes with the leaf.
+type treeCache struct {
+	ok     bool
+	tree   uint64
+	top    [][]byte // top[z-s] has the nodes at height z >= s.
+	low    uint32   // Index of the subtree of height s in bottom.
+	bottom [][]byte // bottom[z] has the nodes at height z < s of the subtree.
+
+	sigOk   bool
+	sigLeaf uint32
+	sig     []byte
+}
+
+// update prepares the cache to compute the authentication path of the leaf
+// of the tree at the layer.
+func (c *treeCache) update(h *hasher, layer uint32, tree uint64, leaf uint32, skSeed, seed []byte) {
+	hp := h.hPrime()
+	s := hp / 2
+	n := h.n
+	low := leaf >> s
+
+	saveBottom := func(i, z uint32, node []byte) {
+		if z < s {
+			copy(c.bottom[z][(i-low<<(s-z))*n:], node[:n])
+		}
+	}
+
+	if !c.ok || c.tree != tree {
+		c.ok, c.sigOk = false, false
+		c.tree = tree
+		c.top = make([][]byte, hp-s+1)
+		for z := range c.top {
+			c.top[z] = make([]byte, n<<(hp-s-uint32(z)))
+		}
+		c.bottom = make([][]byte, s)
+		for z := range c.bottom {
+			c.bottom[z] = make([]byte, n<<(s-uint32(z)))
+		}
+
+		for j := range uint32(1) << (hp - s) {
+			var save func(i, z uint32, node []byte)
+			if j == low {
+				save = saveBottom
+			}
+			h.treeHash(c.top[0][j*n:(j+1)*n], j, s, layer, tree, skSeed, seed, save)
+		}
+
+		hash := h.nodeHash(layer, tree, seed)
+		for z := uint32(1); z < uint32(len(c.top)); z++ {
+			for i := range uint32(1) << (hp - s - z) {
+				children := c.top[z-1][2*i*n:]
+				hash(c.top[z][i*n:(i+1)*n], children[:n], children[n:2*n], s+z-1, i)
+			}
+		}
+
+		c.low = low
+		c.ok = true
+	} else if c.low != low {
+		h.treeHash(make([]byte, n), low, s, layer, tree, skSeed, seed, saveBottom)
+		c.low = low
+	}
+}
+
+// root returns the root of the cached tree.
+func (c *treeCache) root() []byte { return c.top[len(c.top)-1] }
+
+// authPath sets out to the authentication path of the leaf, which must be
+// in the subtree prepared by update.
+func (c *treeCache) authPath(out []byte, n, leaf uint32) {
+	s := uint32(len(c.bottom))
+	o := cursor(out)
+	for z := range s {
+		sibling := ((leaf >> z) ^ 1) - c.low<<(s-z)
+		copy(o.Next(n), c.bottom[z][sibling*n:])
+	}
+	for z := range uint32(len(c.top) - 1) {
+		sibling := (leaf >> (s + z)) ^ 1
+		copy(o.Next(n), c.top[z][sibling*n:])
+	}
+}
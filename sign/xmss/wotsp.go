@@ -0,0 +1,64 @@
+package xmss
+
+import "github.com/quantumcoinproject/circl/sign/internal/hashsig"
+
+// See RFC 8391 -- Section 3
+// Winternitz One-Time Signature Plus Scheme, with w = 16.
+
+const wotsW = hashsig.WotsW
+
+func (p *params) wotsSigSize() uint32 { return p.wotsLen() * p.n }
+func (p *params) wotsLen() uint32     { return hashsig.WotsLen(p.n) }
+
+// See RFC 8391 -- Section 3.1.2 -- Algorithm 2.
+// It sets out to the chain of x from start for steps iterations.
+func (h *hasher) chain(out, x []byte, start, steps uint32, seed []byte, a *address) {
+	copy(out[:h.n], x)
+	for j := start; j < start+steps; j++ {
+		a.setHashAddress(j)
+		h.f(out, out, seed, a)
+	}
+}
+
+// wotsSk sets out to the i-th private key of the one-time key at the
+// address, see SP 800-208 -- Section 7.2.1.
+func (h *hasher) wotsSk(out []byte, i uint32, skSeed, seed []byte, a *address) {
+	a.setChainAddress(i)
+	a.setHashAddress(0)
+	a.keyAndMask = 0
+	h.prfKeygen(out, skSeed, seed, a)
+}
+
+// See RFC 8391 -- Section 3.1.4 -- Algorithm 4.
+// The address must be an OTS address.
+func (h *hasher) wotsPkGen(pk, skSeed, seed []byte, a address) {
+	c := cursor(pk)
+	for i := range h.wotsLen() {
+		pki := c.Next(h.n)
+		h.wotsSk(pki, i, skSeed, seed, &a)
+		h.chain(pki, pki, 0, wotsW-1, seed, &a)
+	}
+}
+
+// See RFC 8391 -- Section 3.1.5 -- Algorithm 5.
+// The message must have n bytes, and the address must be an OTS address.
+func (h *hasher) wotsSign(sig, msg, skSeed, seed []byte, a address) {
+	var digits [hashsig.MaxWotsLen]uint32
+	c := cursor(sig)
+	for i, digit := range hashsig.WotsDigits(digits[:], msg[:h.n]) {
+		sigi := c.Next(h.n)
+		h.wotsSk(sigi, uint32(i), skSeed, seed, &a)
+		h.chain(sigi, sigi, 0, digit, seed, &a)
+	}
+}
+
+// See RFC 8391 -- Section 3.1.6 -- Algorithm 6.
+// The message must have n bytes, and the address must be an OTS address.
+func (h *hasher) wotsPkFromSig(pk, sig, msg, seed []byte, a address) {
+	var digits [hashsig.MaxWotsLen]uint32
+	pkc, sigc := cursor(pk), cursor(sig)
+	for i, digit := range hashsig.WotsDigits(digits[:], msg[:h.n]) {
+		a.setChainAddress(uint32(i))
+		h.chain(pkc.Next(h.n), sigc.Next(h.n), digit, wotsW-1-digit, seed, &a)
+	}
+}
@@ -0,0 +1,212 @@
+// Package xmss provides the stateful hash-based signature schemes XMSS and
+// XMSS^MT, as specified in [RFC 8391] with the parameter sets approved by
+// [SP 800-208].
+//
+// The [ID] represents the following parameter sets, where h is the total
+// height of the trees and d is the number of layers of XMSS^MT:
+//   - XMSS with SHA-256: [XMSS_SHA2_10_256] to [XMSS_SHA2_20_192].
+//   - XMSS with SHAKE256: [XMSS_SHAKE256_10_256] to [XMSS_SHAKE256_20_192].
+//   - XMSS^MT with SHA-256: [XMSSMT_SHA2_20_2_256] to [XMSSMT_SHA2_60_12_192].
+//   - XMSS^MT with SHAKE256: [XMSSMT_SHAKE256_20_2_256] to
+//     [XMSSMT_SHAKE256_60_12_192].
+//
+// The parameter sets of RFC 8391 with SHA-512 and SHAKE128 are not
+// supported, as SP 800-208 does not approve them.
+//
+// The construction mirrors the one of SLH-DSA in package slhdsa, with
+// WOTS+ one-time keys as leaves of Merkle trees and XMSS^MT stacking trees
+// in layers, but the hash functions differ: RFC 8391 masks the inputs with
+// bitmasks, compresses the WOTS+ public keys with L-trees and derives the
+// one-time keys as SP 800-208 requires. The WOTS+ digits and the tree
+// traversals are shared with package slhdsa, while the hash functions and
+// the addresses are specific to each package.
+//
+// # State
+//
+// Every signature uses a different one-time key, selected by its index, and
+// signing twice with the same index allows forgeries. Hence, signing needs
+// a [stateful.Store], set with [PrivateKey.SetStore], which persists the
+// indices used before a signature is released. Encoded private keys do not
+// include the index: the store is the only source of truth for it.
+//
+// A key can produce 2^h signatures. Key generation computes all the leaves
+// of the top tree, which takes seconds for trees of height 10 and can take
+// minutes for trees of height 20.
+//
+// [RFC 8391]: https://www.rfc-editor.org/rfc/rfc8391
+// [SP 800-208]: https://doi.org/10.6028/NIST.SP.800-208
+package xmss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// [GenerateKey] returns a pair of keys using the parameter set specified.
+// It returns an error if it fails reading from the random source.
+// If random is nil, [crypto/rand.Reader] is used.
+func GenerateKey(random io.Reader, id ID) (*PublicKey, *PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+
+	seed := make([]byte, id.SeedSize())
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+
+	pub, priv := NewKeyFromSeed(id, seed)
+	return pub, priv, nil
+}
+
+// [NewKeyFromSeed] returns the pair of keys derived from the seed, which is
+// SK_SEED || SK_PRF || SEED and must have [ID.SeedSize] bytes.
+// It panics if the seed has another length.
+func NewKeyFromSeed(id ID, seed []byte) (*PublicKey, *PrivateKey) {
+	p := id.params()
+	if len(seed) != id.SeedSize() {
+		panic(ErrSeed)
+	}
+
+	c := cursor(bytes.Clone(seed))
+	priv := &PrivateKey{
+		skSeed: c.Next(p.n),
+		skPrf:  c.Next(p.n),
+		ID:     id,
+	}
+	priv.publicKey = PublicKey{seed: c.Next(p.n), ID: id}
+
+	// The root is the one of the tree at the top layer, which is the
+	// first tree to sign with at that layer.
+	priv.caches = make([]treeCache, p.d)
+	top := &priv.caches[p.d-1]
+	top.update(newHasher(p), p.d-1, 0, 0, priv.skSeed, priv.publicKey.seed)
+	priv.publicKey.root = bytes.Clone(top.root())
+
+	pub := priv.publicKey
+	return &pub, priv
+}
+
+// [Sign] returns the signature of the message with the private key. It
+// reserves the index of the signature from the store of the key before
+// computing the signature, and returns an error if the key has no store,
+// if the reservation fails, or [stateful.ErrExhausted] if the key has no
+// signatures left.
+func Sign(priv *PrivateKey, message []byte) ([]byte, error) {
+	if priv.counter == nil {
+		return nil, ErrStore
+	}
+
+	idx, err := priv.counter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	return priv.sign(idx, message), nil
+}
+
+// See RFC 8391 -- Section 4.1.9 -- Algorithm 12 and
+// Section 4.2.4 -- Algorithm 16.
+func (k *PrivateKey) sign(idx uint64, message []byte) []byte {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	p := k.params()
+	n, hp := p.n, p.hPrime()
+	h := newHasher(p)
+	if k.caches == nil {
+		k.caches = make([]treeCache, p.d)
+	}
+
+	sig := make([]byte, p.SignatureSize())
+	c := cursor(sig)
+	putIdx(c.Next(p.idxSize()), idx)
+	r := c.Next(n)
+	h.prfMsg(r, k.skPrf, idx)
+	node := make([]byte, n)
+	h.hashMsg(node, r, k.publicKey.root, idx, message)
+
+	seed := k.publicKey.seed
+	tree, leaf := idx>>hp, uint32(idx&(1<<hp-1))
+	for j := range p.d {
+		tc := &k.caches[j]
+		tc.update(h, j, tree, leaf, k.skSeed, seed)
+
+		wotsSig := c.Next(p.wotsSigSize())
+		if j > 0 && tc.sigOk && tc.sigLeaf == leaf {
+			copy(wotsSig, tc.sig)
+		} else {
+			ots := newAddress(j, tree, addressOTS)
+			ots.setOTSAddress(leaf)
+			h.wotsSign(wotsSig, node, k.skSeed, seed, ots)
+			if j > 0 {
+				tc.sig = bytes.Clone(wotsSig)
+				tc.sigLeaf, tc.sigOk = leaf, true
+			}
+		}
+
+		tc.authPath(c.Next(hp*n), n, leaf)
+		node = tc.root()
+		tree, leaf = tree>>hp, uint32(tree&(1<<hp-1))
+	}
+
+	return sig
+}
+
+// [Verify] returns true if the signature of the message is valid under the
+// public key.
+//
+// See RFC 8391 -- Section 4.1.10 -- Algorithm 14 and
+// Section 4.2.5 -- Algorithm 17.
+func Verify(pub *PublicKey, message, signature []byte) bool {
+	p := pub.params()
+	if len(signature) != p.SignatureSize() {
+		return false
+	}
+
+	n, hp := p.n, p.hPrime()
+	c := cursor(signature)
+	idx := getIdx(c.Next(p.idxSize()))
+	if idx>>p.h != 0 {
+		return false
+	}
+
+	h := newHasher(p)
+	node := make([]byte, n)
+	h.hashMsg(node, c.Next(n), pub.root, idx, message)
+
+	tree, leaf := idx>>hp, uint32(idx&(1<<hp-1))
+	for j := range p.d {
+		wotsSig := c.Next(p.wotsSigSize())
+		authPath := c.Next(hp * n)
+		h.rootFromSig(node, leaf, wotsSig, authPath, node, j, tree, pub.seed)
+		tree, leaf = tree>>hp, uint32(tree&(1<<hp-1))
+	}
+
+	return bytes.Equal(node, pub.root)
+}
+
+func putIdx(b []byte, idx uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], idx)
+	copy(b, buf[8-len(b):])
+}
+
+func getIdx(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[8-len(b):], b)
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+var (
+	ErrParam      = errors.New("sign/xmss: invalid XMSS parameter")
+	ErrPreHash    = errors.New("sign/xmss: messages must not be pre-hashed")
+	ErrPrivateKey = errors.New("sign/xmss: invalid private key")
+	ErrPublicKey  = errors.New("sign/xmss: invalid public key")
+	ErrSeed       = errors.New("sign/xmss: invalid seed length")
+	ErrStore      = errors.New("sign/xmss: private key has no state store")
+	ErrTree       = errors.New("sign/xmss: invalid tree height or tree index")
+)
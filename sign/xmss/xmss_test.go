@@ -0,0 +1,168 @@
+package xmss_test
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/stateful"
+	"github.com/quantumcoinproject/circl/sign/xmss"
+)
+
+func TestParams(t *testing.T) {
+	for id := xmss.XMSS_SHA2_10_256; id.IsValid(); id++ {
+		id2, err := xmss.IDByName(id.String())
+		test.CheckNoErr(t, err, "IDByName")
+		id3, err := xmss.IDByOID(id.OID(), id.IsMultiTree())
+		test.CheckNoErr(t, err, "IDByOID")
+		if id2 != id || id3 != id {
+			test.ReportError(t, id2, id, id3)
+		}
+		if id.Height()%id.Layers() != 0 {
+			t.Fatalf("%v: height not divisible by layers", id)
+		}
+	}
+
+	// Some sizes of RFC 8391 -- Section 5.3 and SP 800-208.
+	for _, v := range []struct {
+		id  xmss.ID
+		sig int
+	}{
+		{xmss.XMSS_SHA2_10_256, 2500},
+		{xmss.XMSS_SHAKE256_20_192, 1732},
+		{xmss.XMSSMT_SHA2_20_2_256, 4963},
+		{xmss.XMSSMT_SHA2_60_12_256, 27688},
+	} {
+		if got := v.id.SignatureSize(); got != v.sig {
+			test.ReportError(t, got, v.sig, v.id)
+		}
+	}
+}
+
+func TestXMSS(t *testing.T) {
+	for _, id := range []xmss.ID{
+		xmss.XMSS_SHA2_10_256,
+		xmss.XMSS_SHAKE256_10_192,
+		xmss.XMSSMT_SHA2_20_4_192,
+		xmss.XMSSMT_SHAKE256_40_8_256,
+	} {
+		t.Run(id.String(), func(t *testing.T) { testXMSS(t, id) })
+	}
+}
+
+func testXMSS(t *testing.T, id xmss.ID) {
+	seed := bytes.Repeat([]byte{0x5a}, id.SeedSize())
+	pub, priv := xmss.NewKeyFromSeed(id, seed)
+
+	_, err := xmss.Sign(priv, []byte("message"))
+	if err != xmss.ErrStore {
+		test.ReportError(t, err, xmss.ErrStore)
+	}
+
+	// Signs across trees of the bottom layer for XMSS^MT.
+	const n = 70
+	priv.SetStore(stateful.NewMemoryStore(0), 4)
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		msg := []byte{byte(i)}
+		sigs[i], err = xmss.Sign(priv, msg)
+		test.CheckNoErr(t, err, "sign")
+		if !xmss.Verify(pub, msg, sigs[i]) {
+			t.Fatalf("verification of signature %v failed", i)
+		}
+		if xmss.Verify(pub, []byte("other"), sigs[i]) {
+			t.Fatalf("verification of signature %v with another message succeeded", i)
+		}
+	}
+
+	// Keys round trip through their encoding.
+	pk, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal public key")
+	sk, err := priv.MarshalBinary()
+	test.CheckNoErr(t, err, "marshal private key")
+	if len(pk) != id.PublicKeySize() || len(sk) != id.PrivateKeySize() {
+		t.Fatal("wrong size of keys")
+	}
+	pub2 := &xmss.PublicKey{ID: id}
+	test.CheckNoErr(t, pub2.UnmarshalBinary(pk), "unmarshal public key")
+	priv2 := &xmss.PrivateKey{ID: id}
+	test.CheckNoErr(t, priv2.UnmarshalBinary(sk), "unmarshal private key")
+	if !pub.Equal(pub2) || !priv.Equal(priv2) || !pub.Equal(priv2.Public()) {
+		t.Fatal("keys differ after round trip")
+	}
+
+	// Signatures are deterministic, so a key resuming from an index signs
+	// as the key that signed in order.
+	priv2.SetStore(stateful.NewMemoryStore(n-5), 0)
+	for i := n - 5; i < n; i++ {
+		sig, err := priv2.Sign(nil, []byte{byte(i)}, crypto.Hash(0))
+		test.CheckNoErr(t, err, "sign")
+		if !bytes.Equal(sig, sigs[i]) {
+			t.Fatalf("signature %v differs after resuming", i)
+		}
+	}
+
+	// Modified signatures are rejected.
+	sig := sigs[n-1]
+	for _, i := range []int{0, len(sig) / 2, len(sig) - 1} {
+		sig[i] ^= 1
+		if xmss.Verify(pub, []byte{n - 1}, sig) {
+			t.Fatalf("verification succeeded with byte %v modified", i)
+		}
+		sig[i] ^= 1
+	}
+	if xmss.Verify(pub, []byte{n - 1}, sig[:len(sig)-1]) {
+		t.Fatal("verification succeeded with a short signature")
+	}
+
+	pk[0] ^= 1
+	if err = pub2.UnmarshalBinary(pk); err != xmss.ErrPublicKey {
+		test.ReportError(t, err, xmss.ErrPublicKey)
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	id := xmss.XMSSMT_SHA2_20_4_256
+	pub, priv := xmss.NewKeyFromSeed(id, make([]byte, id.SeedSize()))
+	last := uint64(1)<<id.Height() - 1
+	priv.SetStore(stateful.NewMemoryStore(last), 8)
+
+	msg := []byte("message")
+	sig, err := xmss.Sign(priv, msg)
+	test.CheckNoErr(t, err, "sign with the last index")
+	if !xmss.Verify(pub, msg, sig) {
+		t.Fatal("verification of the last signature failed")
+	}
+
+	_, err = xmss.Sign(priv, msg)
+	if !errors.Is(err, stateful.ErrExhausted) {
+		test.ReportError(t, err, stateful.ErrExhausted)
+	}
+
+	// The index cannot exceed the height of the trees.
+	sig[0] ^= 0x10
+	if xmss.Verify(pub, msg, sig) {
+		t.Fatal("verification succeeded with an index out of range")
+	}
+}
+
+func BenchmarkXMSS(b *testing.B) {
+	id := xmss.XMSSMT_SHA2_20_4_256
+	pub, priv := xmss.NewKeyFromSeed(id, make([]byte, id.SeedSize()))
+	priv.SetStore(stateful.NewMemoryStore(0), 64)
+	msg := []byte("message")
+	sig, _ := xmss.Sign(priv, msg)
+
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = xmss.Sign(priv, msg)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = xmss.Verify(pub, msg, sig)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+// Package bls provides threshold BLS signatures.
+//
+// A dealer splits a [bls.PrivateKey] into n key shares with Shamir's secret
+// sharing, see [secretsharing], so that any t+1 of the participants can
+// produce a signature, and t or fewer cannot. Each participant signs with
+// its key share, and the signature shares, which can be checked against the
+// public key share of each participant, are combined with Lagrange
+// interpolation into a standard BLS signature. The combined signature is
+// the same one that the dealt key would produce, so it verifies with
+// [bls.Verify] under the public key of the dealt key.
+//
+// Signatures follow the basic scheme of [bls.Sign].
+package bls
+
+import (
+	"errors"
+	"io"
+
+	"github.com/quantumcoinproject/circl/group"
+	"github.com/quantumcoinproject/circl/math/polynomial"
+	"github.com/quantumcoinproject/circl/secretsharing"
+	"github.com/quantumcoinproject/circl/sign/bls"
+)
+
+var (
+	ErrParams    = errors.New("tss/bls: invalid threshold parameters")
+	ErrKeyShare  = errors.New("tss/bls: invalid key share")
+	ErrNumShares = errors.New("tss/bls: not enough signature shares")
+	ErrSigShare  = errors.New("tss/bls: invalid signature share")
+)
+
+// KeyShare is the share of a private key of a participant.
+type KeyShare[K bls.KeyGroup] struct {
+	ID  uint // Identifier of the participant, from 1 to n.
+	Key *bls.PrivateKey[K]
+}
+
+// PublicKeyShare is the public key of the share of a participant, which
+// verifies its signature shares.
+type PublicKeyShare[K bls.KeyGroup] struct {
+	ID  uint // Identifier of the participant, from 1 to n.
+	Key *bls.PublicKey[K]
+}
+
+// SignatureShare is the signature of a message by a participant.
+type SignatureShare struct {
+	ID        uint // Identifier of the participant, from 1 to n.
+	Signature bls.Signature
+}
+
+// Deal splits the private key into n key shares, such that t+1 of them are
+// needed to produce a signature. It requires 0 <= t < n. Random bytes are
+// read from rnd.
+func Deal[K bls.KeyGroup](rnd io.Reader, key *bls.PrivateKey[K], t, n uint) ([]KeyShare[K], error) {
+	if t >= n {
+		return nil, ErrParams
+	}
+
+	g := keyGroup[K]()
+	b, err := key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	secret := g.NewScalar()
+	if err = secret.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	shares := secretsharing.New(rnd, t, secret).Share(n)
+	keyShares := make([]KeyShare[K], n)
+	for i := range shares {
+		b, err = shares[i].Value.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		k := new(bls.PrivateKey[K])
+		// A share is zero with negligible probability only.
+		if k.UnmarshalBinary(b) != nil {
+			return nil, ErrKeyShare
+		}
+		keyShares[i] = KeyShare[K]{ID: uint(i + 1), Key: k}
+	}
+
+	return keyShares, nil
+}
+
+// Public returns the public key share of the participant.
+func (s KeyShare[K]) Public() PublicKeyShare[K] {
+	return PublicKeyShare[K]{ID: s.ID, Key: s.Key.PublicKey()}
+}
+
+// Sign returns the signature share of the message by the participant.
+func (s KeyShare[K]) Sign(msg []byte) SignatureShare {
+	return SignatureShare{ID: s.ID, Signature: bls.Sign(s.Key, msg)}
+}
+
+// Verify returns true if the signature share of the message is valid for
+// the public key share, and both belong to the same participant.
+func (s PublicKeyShare[K]) Verify(msg []byte, sig SignatureShare) bool {
+	return s.ID == sig.ID && bls.Verify(s.Key, msg, sig.Signature)
+}
+
+// Combine returns the signature produced by interpolating the signature
+// shares, which must be of the same message by at least t+1 distinct
+// participants. Only the first t+1 shares are used. Combine does not check
+// the signature shares; if any of them is not valid, the signature is not
+// either, so invalid shares should be discarded with [PublicKeyShare.Verify]
+// first.
+func Combine[K bls.KeyGroup](t uint, shares []SignatureShare) (bls.Signature, error) {
+	if uint(len(shares)) <= t {
+		return nil, ErrNumShares
+	}
+	shares = shares[:t+1]
+
+	g := signatureGroup[K]()
+	ids := make([]group.Scalar, len(shares))
+	sigs := make([]group.Element, len(shares))
+	for i := range shares {
+		if shares[i].ID == 0 {
+			return nil, ErrSigShare
+		}
+		for j := range shares[:i] {
+			if shares[i].ID == shares[j].ID {
+				return nil, ErrSigShare
+			}
+		}
+		ids[i] = g.NewScalar().SetUint64(uint64(shares[i].ID))
+		sigs[i] = g.NewElement()
+		if sigs[i].UnmarshalBinary(shares[i].Signature) != nil || sigs[i].IsIdentity() {
+			return nil, ErrSigShare
+		}
+	}
+
+	zero := g.NewScalar()
+	sig := g.Identity()
+	for i := range sigs {
+		l := polynomial.LagrangeBase(uint(i), ids, zero)
+		sig.Add(sig, sigs[i].Mul(sigs[i], l))
+	}
+	if sig.IsIdentity() {
+		return nil, ErrSigShare
+	}
+
+	return sig.MarshalBinaryCompress()
+}
+
+// keyGroup returns the group of the public keys.
+func keyGroup[K bls.KeyGroup]() group.Group {
+	switch any(*new(K)).(type) {
+	case bls.G1:
+		return group.BLS12381G1
+	case bls.G2:
+		return group.BLS12381G2
+	default:
+		panic(bls.ErrInvalid)
+	}
+}
+
+// signatureGroup returns the group of the signatures.
+func signatureGroup[K bls.KeyGroup]() group.Group {
+	switch any(*new(K)).(type) {
+	case bls.G1:
+		return group.BLS12381G2
+	case bls.G2:
+		return group.BLS12381G1
+	default:
+		panic(bls.ErrInvalid)
+	}
+}
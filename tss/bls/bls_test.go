@@ -0,0 +1,103 @@
+package bls_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/quantumcoinproject/circl/internal/test"
+	"github.com/quantumcoinproject/circl/sign/bls"
+	tbls "github.com/quantumcoinproject/circl/tss/bls"
+)
+
+func TestThreshold(t *testing.T) {
+	t.Run("G1", testThreshold[bls.G1])
+	t.Run("G2", testThreshold[bls.G2])
+}
+
+func testThreshold[K bls.KeyGroup](t *testing.T) {
+	const th, n = 2, 5
+	ikm := make([]byte, 32)
+	_, _ = rand.Read(ikm)
+	key, err := bls.KeyGen[K](ikm, nil, nil)
+	test.CheckNoErr(t, err, "key generation")
+	pub := key.PublicKey()
+	msg := []byte("message")
+
+	keyShares, err := tbls.Deal(rand.Reader, key, th, n)
+	test.CheckNoErr(t, err, "deal")
+	test.CheckOk(len(keyShares) == n, "bad number of key shares", t)
+
+	sigShares := make([]tbls.SignatureShare, n)
+	for i := range keyShares {
+		sigShares[i] = keyShares[i].Sign(msg)
+		pubShare := keyShares[i].Public()
+		if !pubShare.Verify(msg, sigShares[i]) {
+			t.Fatalf("verification of signature share %v failed", i)
+		}
+		if pubShare.Verify([]byte("other"), sigShares[i]) {
+			t.Fatalf("verification of signature share %v with another message succeeded", i)
+		}
+		if j := (i + 1) % n; pubShare.Verify(msg, sigShares[j]) {
+			t.Fatalf("verification of signature share %v with key share %v succeeded", j, i)
+		}
+	}
+
+	// Any t+1 shares produce the signature of the dealt key.
+	want := bls.Sign(key, msg)
+	for _, idx := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4, 0}, {0, 1, 2, 3, 4}} {
+		shares := make([]tbls.SignatureShare, len(idx))
+		for i, j := range idx {
+			shares[i] = sigShares[j]
+		}
+		sig, err := tbls.Combine[K](th, shares)
+		test.CheckNoErr(t, err, "combine")
+		if !bytes.Equal(sig, want) || !bls.Verify(pub, msg, sig) {
+			test.ReportError(t, sig, want, idx)
+		}
+	}
+
+	_, err = tbls.Combine[K](th, sigShares[:th])
+	if err != tbls.ErrNumShares {
+		test.ReportError(t, err, tbls.ErrNumShares)
+	}
+	_, err = tbls.Combine[K](th, []tbls.SignatureShare{sigShares[0], sigShares[1], sigShares[0]})
+	if err != tbls.ErrSigShare {
+		test.ReportError(t, err, tbls.ErrSigShare)
+	}
+
+	// A share of another message spoils the signature.
+	other := keyShares[2].Sign([]byte("other"))
+	sig, err := tbls.Combine[K](th, []tbls.SignatureShare{sigShares[0], sigShares[1], other})
+	test.CheckNoErr(t, err, "combine")
+	if bls.Verify(pub, msg, sig) {
+		t.Fatal("verification of a signature with an invalid share succeeded")
+	}
+
+	_, err = tbls.Deal(rand.Reader, key, n, n)
+	if err != tbls.ErrParams {
+		test.ReportError(t, err, tbls.ErrParams)
+	}
+}
+
+func BenchmarkThreshold(b *testing.B) {
+	const th, n = 2, 5
+	key, _ := bls.KeyGen[bls.G1](make([]byte, 32), nil, nil)
+	keyShares, _ := tbls.Deal(rand.Reader, key, th, n)
+	msg := []byte("message")
+	sigShares := make([]tbls.SignatureShare, n)
+	for i := range keyShares {
+		sigShares[i] = keyShares[i].Sign(msg)
+	}
+
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = keyShares[0].Sign(msg)
+		}
+	})
+	b.Run("Combine", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = tbls.Combine[bls.G1](th, sigShares)
+		}
+	})
+}